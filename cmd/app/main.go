@@ -11,6 +11,14 @@ import (
 	"github.com/iamaul/go-pokedex/pkg/utils"
 )
 
+// @title go-pokedex API
+// @version 1.0
+// @description Pokedex REST API for users, monsters and monster types
+// @BasePath /api/v1
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Bearer access token, e.g. "Bearer {token}"
 func main() {
 	log.Println("Starting API server")
 
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/backup"
+	"github.com/iamaul/go-pokedex/pkg/db/mongodb"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+const usage = `Usage:
+  backup dump    -out pokedex.tar.gz [-db pokedex]
+  backup restore -in pokedex.tar.gz  [-db target]
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	dbName := cmd.String("db", "pokedex", "database to dump from or restore into")
+	out := cmd.String("out", "", "archive path to write (dump)")
+	in := cmd.String("in", "", "archive path to read (restore)")
+	if err := cmd.Parse(os.Args[2:]); err != nil {
+		log.Fatal(err)
+	}
+
+	configPath := utils.GetConfigPath(os.Getenv("config"))
+
+	cfgFile, err := config.LoadConfig(configPath)
+	if err != nil {
+		log.Fatalf("LoadConfig: %v", err)
+	}
+
+	cfg, err := config.ParseConfig(cfgFile)
+	if err != nil {
+		log.Fatalf("ParseConfig: %v", err)
+	}
+
+	appLogger := logger.NewLogger(cfg)
+	appLogger.InitLogger()
+
+	mongoClient, err := mongodb.NewClient(cfg)
+	if err != nil {
+		appLogger.Fatalf("MongoDB init: %s", err)
+	}
+	defer mongoClient.Disconnect(context.Background())
+
+	db := mongoClient.Database(*dbName)
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "dump":
+		if *out == "" {
+			appLogger.Fatal("dump: -out is required")
+		}
+
+		file, err := os.Create(*out)
+		if err != nil {
+			appLogger.Fatalf("dump: %s", err)
+		}
+
+		manifest, err := backup.Dump(ctx, db, file, cfg.Server.AppVersion)
+		if err != nil {
+			file.Close()
+			os.Remove(*out)
+			appLogger.Fatalf("dump: %s", err)
+		}
+		if err := file.Close(); err != nil {
+			appLogger.Fatalf("dump: %s", err)
+		}
+
+		for _, c := range manifest.Collections {
+			appLogger.Infof("dumped %d documents from %s.%s", c.Count, manifest.Database, c.Name)
+		}
+		appLogger.Infof("backup written to %s", *out)
+	case "restore":
+		if *in == "" {
+			appLogger.Fatal("restore: -in is required")
+		}
+
+		file, err := os.Open(*in)
+		if err != nil {
+			appLogger.Fatalf("restore: %s", err)
+		}
+		defer file.Close()
+
+		manifest, err := backup.Restore(ctx, db, file)
+		if err != nil {
+			appLogger.Fatalf("restore: %s", err)
+		}
+
+		for _, c := range manifest.Collections {
+			appLogger.Infof("restored %d documents into %s.%s", c.Count, db.Name(), c.Name)
+		}
+		appLogger.Infof("backup from %s (%s) restored", manifest.CreatedAt, manifest.Database)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
@@ -5,23 +5,43 @@ import (
 	"log"
 	"time"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server  ServerConfig
-	MongoDB MongoDB
-	Logger  Logger
-	Session Session
-	Cookie  Cookie
+	Server   ServerConfig
+	MongoDB  MongoDB
+	Logger   Logger
+	Session  Session
+	Cookie   Cookie
+	CSRF     CSRF
+	JWT      JWT
+	Storage  Storage
+	Events   Events
+	Webhook  Webhook
+	API      API
+	GraphQL  GraphQL
+	Notifier Notifier
+	OIDC     OIDC
+	MFA      MFA
+	Lockout  Lockout
 }
 
 type ServerConfig struct {
-	AppVersion        string
-	Port              string
-	PprofPort         string
-	Mode              string
-	JwtSecretKey      string
+	AppVersion   string
+	Port         string
+	GrpcPort     string
+	PprofPort    string
+	Mode         string
+	JwtSecretKey string
+	// Key of the invite code HMAC, required and distinct from the other secrets
+	InviteSecret      string
+	AccessTokenTTL    time.Duration
+	RefreshTokenTTL   time.Duration
+	PasswordResetTTL  time.Duration
+	InviteTTL         time.Duration
+	APIKeyTTL         time.Duration
 	CookieName        string
 	ReadTimeout       time.Duration
 	WriteTimeout      time.Duration
@@ -29,6 +49,9 @@ type ServerConfig struct {
 	CtxDefaultTimeout time.Duration
 	CSRF              bool
 	Debug             bool
+	OpenAPIValidation bool
+	// Take the client IP from X-Forwarded-For, only behind a proxy that sets it
+	TrustProxy bool
 }
 
 type Logger struct {
@@ -45,10 +68,41 @@ type MongoDB struct {
 	Password string
 }
 
+// Server-side sessions, one per sign in. Name is the cookie, Prefix starts every session ID,
+// sessions expire after Expire seconds without use. Store is mongo, or memory for a single instance.
 type Session struct {
 	Prefix string
 	Name   string
 	Expire int
+	Store  string
+}
+
+// Access tokens are signed with the newest active key, RS256 for RSA and EdDSA for Ed25519 keys,
+// or HS256 with Server.JwtSecretKey when none is configured, which only development mode accepts.
+// A replaced key keeps verifying
+// tokens for Overlap, keys are published at /.well-known/jwks.json from Overlap before they sign.
+type JWT struct {
+	Issuer   string
+	Audience string
+	Overlap  time.Duration
+	Keys     []JWTKey
+}
+
+type JWTKey struct {
+	// The kid of the tokens it signs, the RFC 7638 thumbprint unless set
+	ID             string
+	PrivateKeyFile string
+	// When it starts signing, unique among the keys. Only one key may leave it unset.
+	ActiveFrom time.Time
+}
+
+// CSRF tokens are HMACs keyed by Secret, which is required. "session" mode binds them to the session cookie,
+// "double-submit" mode compares the header with the CookieName cookie for stateless deployments.
+type CSRF struct {
+	Secret     string
+	TTL        time.Duration
+	Mode       string
+	CookieName string
 }
 
 type Cookie struct {
@@ -58,6 +112,96 @@ type Cookie struct {
 	HttpOnly bool
 }
 
+// Uploads over MaxImageSize bytes are refused, as are images of more than MaxImagePixels
+// pixels, a small file can decode to a huge image
+type Storage struct {
+	Driver          string
+	LocalPath       string
+	PublicURL       string
+	MaxImageSize    int64
+	MaxImagePixels  int64
+	ThumbnailWidths []int
+	S3              S3
+}
+
+type Events struct {
+	BufferSize        int
+	HeartbeatInterval time.Duration
+	ChangeStreams     bool
+}
+
+type Webhook struct {
+	Workers      int
+	PollInterval time.Duration
+	Timeout      time.Duration
+	MaxAttempts  int
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+}
+
+// Versions are selected by path (/api/v2) or by the Accept header of unversioned /api requests
+type API struct {
+	DefaultVersion int
+	V1Deprecation  time.Time
+	V1Sunset       time.Time
+}
+
+// Queries nesting deeper than MaxDepth or selecting more than MaxComplexity fields are refused
+type GraphQL struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// Delivers messages to users, e.g. password reset tokens
+type Notifier struct {
+	Driver   string
+	FilePath string
+	ResetURL string
+}
+
+// Failed sign ins counted per username and per client IP. After the free attempts each failure
+// doubles the wait before the next attempt, reaching the threshold locks for the duration,
+// doubled on every lockout in a row. Counters are forgotten after the window without failures.
+type Lockout struct {
+	UsernameThreshold int
+	IPThreshold       int
+	FreeAttempts      int
+	Delay             time.Duration
+	Duration          time.Duration
+	MaxDuration       time.Duration
+	Window            time.Duration
+}
+
+// Two-factor authentication with TOTP authenticator apps
+type MFA struct {
+	Issuer           string
+	ChallengeTTL     time.Duration
+	RequireForAdmins bool
+}
+
+// OpenID Connect providers users can sign in with, keyed by the name used in their login URL
+type OIDC struct {
+	StateTTL  time.Duration
+	Providers map[string]OIDCProvider
+}
+
+type OIDCProvider struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+type S3 struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+}
+
 func LoadConfig(filename string) (*viper.Viper, error) {
 	v := viper.New()
 
@@ -77,7 +221,11 @@ func LoadConfig(filename string) (*viper.Viper, error) {
 func ParseConfig(v *viper.Viper) (*Config, error) {
 	var c Config
 
-	err := v.Unmarshal(&c)
+	err := v.Unmarshal(&c, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		mapstructure.StringToTimeHookFunc(time.RFC3339),
+	)))
 	if err != nil {
 		log.Printf("unable to decode into struct, %v", err)
 		return nil, err
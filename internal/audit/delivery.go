@@ -0,0 +1,9 @@
+package audit
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+type DeliveryHandlers interface {
+	ListAudit() echo.HandlerFunc
+}
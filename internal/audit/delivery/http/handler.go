@@ -0,0 +1,94 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/audit"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/render"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AuditHandler struct {
+	cfg          *config.Config
+	auditUsecase audit.Usecase
+	logger       logger.Logger
+}
+
+func NewAuditHandler(cfg *config.Config, auditUsecase audit.Usecase, log logger.Logger) audit.DeliveryHandlers {
+	return &AuditHandler{cfg: cfg, auditUsecase: auditUsecase, logger: log}
+}
+
+// ListAudit godoc
+// @Summary Get audit log
+// @Description entries newest first, filtered by actor, target and time range
+// @Tags Audit
+// @Param actor_id query string false "actor user id"
+// @Param target_type query string false "target type" Enums(monster, monster_type, user, username, role, invite, api_key, session, login_lock)
+// @Param target_id query string false "target id"
+// @Param from query string false "from, RFC3339, inclusive"
+// @Param to query string false "to, RFC3339, exclusive"
+// @Param page query int false "page"
+// @Param size query int false "size"
+// @Produce json
+// @Success 200 {object} domain.AuditList
+// @Router /audit/list [get]
+func (h *AuditHandler) ListAudit() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		filter, err := parseFilter(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		paginationQuery, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		auditList, err := h.auditUsecase.GetAuditList(c.Request().Context(), filter, paginationQuery)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, auditList)
+	}
+}
+
+func parseFilter(c echo.Context) (*domain.AuditFilter, error) {
+	filter := &domain.AuditFilter{
+		TargetType: c.QueryParam("target_type"),
+		TargetID:   c.QueryParam("target_id"),
+	}
+
+	if actor := c.QueryParam("actor_id"); actor != "" {
+		actorID, err := primitive.ObjectIDFromHex(actor)
+		if err != nil {
+			return nil, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, fmt.Sprintf("invalid actor_id %q", actor), nil)
+		}
+		filter.ActorID = &actorID
+	}
+
+	for param, bound := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.QueryParam(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, fmt.Sprintf("invalid %s %q, expected RFC3339", param, value), nil)
+		}
+		*bound = &t
+	}
+
+	return filter, nil
+}
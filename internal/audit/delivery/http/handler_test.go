@@ -0,0 +1,73 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+)
+
+func TestParseFilter(t *testing.T) {
+	actorID := primitive.NewObjectID()
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 2, 12, 30, 0, 0, time.FixedZone("", 2*60*60))
+
+	t.Run("every filter", func(t *testing.T) {
+		query := "?actor_id=" + actorID.Hex() + "&target_type=user&target_id=42&from=" + from.Format(time.RFC3339) + "&to=" + "2026-10-02T12:30:00%2B02:00"
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/audit/list"+query, nil), httptest.NewRecorder())
+
+		filter, err := parseFilter(c)
+		if err != nil {
+			t.Fatalf("parseFilter: %v", err)
+		}
+		if filter.ActorID == nil || *filter.ActorID != actorID {
+			t.Errorf("ActorID = %v, want %s", filter.ActorID, actorID.Hex())
+		}
+		if filter.TargetType != "user" || filter.TargetID != "42" {
+			t.Errorf("target = %s %s, want user 42", filter.TargetType, filter.TargetID)
+		}
+		if filter.From == nil || !filter.From.Equal(from) {
+			t.Errorf("From = %v, want %v", filter.From, from)
+		}
+		if filter.To == nil || !filter.To.Equal(to) {
+			t.Errorf("To = %v, want %v", filter.To, to)
+		}
+	})
+
+	t.Run("no filter", func(t *testing.T) {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/audit/list", nil), httptest.NewRecorder())
+
+		filter, err := parseFilter(c)
+		if err != nil {
+			t.Fatalf("parseFilter: %v", err)
+		}
+		if filter.ActorID != nil || filter.TargetType != "" || filter.TargetID != "" || filter.From != nil || filter.To != nil {
+			t.Errorf("filter = %+v, want none", filter)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "malformed actor", query: "?actor_id=ash"},
+		{name: "malformed from", query: "?from=yesterday"},
+		{name: "date without time", query: "?to=2026-10-02"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/audit/list"+tt.query, nil), httptest.NewRecorder())
+
+			_, err := parseFilter(c)
+			restErr, ok := err.(httpErr.RestErr)
+			if !ok || restErr.Status() != http.StatusBadRequest {
+				t.Errorf("parseFilter: %v, want status %d", err, http.StatusBadRequest)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package http
+
+import (
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/audit"
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func AuditRoutes(auditGroup *echo.Group, h audit.DeliveryHandlers, au auth.Usecase, cfg *config.Config, mw *middleware.MiddlewareManager) {
+	auditGroup.GET("/list", h.ListAudit(), mw.AuthJWTMiddleware(au, cfg), mw.RequirePermission(domain.PermAuditRead))
+}
@@ -0,0 +1,15 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+// The audit log is append-only, entries are never updated or deleted
+type Repository interface {
+	CreateIndexes(ctx context.Context) error
+	CreateAuditEntry(ctx context.Context, entry *domain.AuditEntry) (*domain.AuditEntry, error)
+	FetchAuditEntries(ctx context.Context, filter *domain.AuditFilter, pq *utils.PaginationQuery) (*domain.AuditList, error)
+}
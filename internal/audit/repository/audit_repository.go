@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/iamaul/go-pokedex/internal/audit"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AuditRepo struct {
+	db *mongo.Collection
+}
+
+func NewAuditRepo(db *mongo.Database) audit.Repository {
+	return &AuditRepo{
+		db: db.Collection("audit_log"),
+	}
+}
+
+// Entries are listed newest first, overall or per actor or target
+func (r *AuditRepo) CreateIndexes(ctx context.Context) error {
+	_, err := r.db.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.Indexes.CreateMany")
+	}
+
+	return nil
+}
+
+func (r *AuditRepo) CreateAuditEntry(ctx context.Context, entry *domain.AuditEntry) (*domain.AuditEntry, error) {
+	result, err := r.db.InsertOne(ctx, entry)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.InsertOne")
+	}
+
+	entry.ID = result.InsertedID.(primitive.ObjectID)
+
+	return entry, nil
+}
+
+func (r *AuditRepo) FetchAuditEntries(ctx context.Context, filter *domain.AuditFilter, pq *utils.PaginationQuery) (*domain.AuditList, error) {
+	query := auditQuery(filter)
+
+	totalCount, err := r.db.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.CountDocuments")
+	}
+
+	if totalCount == 0 {
+		return &domain.AuditList{
+			TotalCount: 0,
+			TotalPages: 0,
+			Page:       0,
+			Size:       0,
+			HasMore:    false,
+			Entries:    make([]*domain.AuditEntry, 0),
+		}, nil
+	}
+
+	limit := int64(pq.GetLimit())
+	skip := int64(pq.GetOffset())
+	cursor, err := r.db.Find(ctx, query, &options.FindOptions{
+		Limit: &limit,
+		Skip:  &skip,
+		Sort:  bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "db.Find")
+	}
+	defer cursor.Close(ctx)
+
+	entries := make([]*domain.AuditEntry, 0, pq.GetSize())
+	for cursor.Next(ctx) {
+		var entry domain.AuditEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, errors.Wrap(err, "cursor.Decode")
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, errors.Wrap(err, "cursor.Err")
+	}
+
+	return &domain.AuditList{
+		TotalCount: int(totalCount),
+		TotalPages: utils.GetTotalPages(int(totalCount), pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), int(totalCount), pq.GetSize()),
+		Entries:    entries,
+	}, nil
+}
+
+// Query matching the entries of the filter, From is inclusive and To exclusive
+func auditQuery(filter *domain.AuditFilter) bson.M {
+	query := bson.M{}
+	if filter.ActorID != nil {
+		query["actor_id"] = *filter.ActorID
+	}
+	if filter.TargetType != "" {
+		query["target_type"] = filter.TargetType
+	}
+	if filter.TargetID != "" {
+		query["target_id"] = filter.TargetID
+	}
+	if filter.From != nil || filter.To != nil {
+		createdAt := bson.M{}
+		if filter.From != nil {
+			createdAt["$gte"] = *filter.From
+		}
+		if filter.To != nil {
+			createdAt["$lt"] = *filter.To
+		}
+		query["created_at"] = createdAt
+	}
+
+	return query
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+)
+
+func TestAuditQuery(t *testing.T) {
+	actorID := primitive.NewObjectID()
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	tests := []struct {
+		name   string
+		filter *domain.AuditFilter
+		want   bson.M
+	}{
+		{name: "everything", filter: &domain.AuditFilter{}, want: bson.M{}},
+		{name: "actor", filter: &domain.AuditFilter{ActorID: &actorID}, want: bson.M{"actor_id": actorID}},
+		{name: "target type", filter: &domain.AuditFilter{TargetType: domain.AuditTargetMonster}, want: bson.M{"target_type": domain.AuditTargetMonster}},
+		{
+			name:   "target",
+			filter: &domain.AuditFilter{TargetType: domain.AuditTargetUser, TargetID: actorID.Hex()},
+			want:   bson.M{"target_type": domain.AuditTargetUser, "target_id": actorID.Hex()},
+		},
+		{name: "from inclusive", filter: &domain.AuditFilter{From: &from}, want: bson.M{"created_at": bson.M{"$gte": from}}},
+		{name: "to exclusive", filter: &domain.AuditFilter{To: &to}, want: bson.M{"created_at": bson.M{"$lt": to}}},
+		{
+			name:   "actor in a time range",
+			filter: &domain.AuditFilter{ActorID: &actorID, From: &from, To: &to},
+			want:   bson.M{"actor_id": actorID, "created_at": bson.M{"$gte": from, "$lt": to}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := auditQuery(tt.filter); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("auditQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
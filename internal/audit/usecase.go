@@ -0,0 +1,13 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+type Usecase interface {
+	Record(ctx context.Context, entry *domain.AuditEntry, before, after interface{})
+	GetAuditList(ctx context.Context, filter *domain.AuditFilter, pq *utils.PaginationQuery) (*domain.AuditList, error)
+}
@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/audit"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/middleware"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+type fakeAuditRepo struct {
+	audit.Repository
+	mu      sync.Mutex
+	entries []*domain.AuditEntry
+	err     error
+}
+
+func (r *fakeAuditRepo) CreateAuditEntry(ctx context.Context, entry *domain.AuditEntry) (*domain.AuditEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	entry.ID = primitive.NewObjectID()
+	stored := *entry
+	r.entries = append(r.entries, &stored)
+
+	return entry, nil
+}
+
+func (r *fakeAuditRepo) recorded(t *testing.T) *domain.AuditEntry {
+	t.Helper()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.entries) != 1 {
+		t.Fatalf("%d entries recorded, want 1", len(r.entries))
+	}
+	return r.entries[0]
+}
+
+func newTestAudit(t *testing.T) (audit.Usecase, *fakeAuditRepo) {
+	t.Helper()
+
+	cfg := &config.Config{Logger: config.Logger{Level: "fatal", Encoding: "console"}}
+	log := logger.NewLogger(cfg)
+	log.InitLogger()
+
+	repo := &fakeAuditRepo{}
+	return NewAuditUsecase(cfg, repo, log), repo
+}
+
+func TestRecordRedactsSecrets(t *testing.T) {
+	u, repo := newTestAudit(t)
+
+	before := map[string]interface{}{
+		"username":         "ash",
+		"password":         "pikachu",
+		"current_password": "pikachu",
+		"new_password":     "raichu",
+		"secret":           "JBSWY3DPEHPK3PXP",
+		"key":              "pk_live_0123456789",
+		"code":             "123456",
+		"mfa": map[string]interface{}{
+			"enabled": true,
+			"secret":  "JBSWY3DPEHPK3PXP",
+		},
+		"api_keys": []interface{}{
+			map[string]interface{}{"name": "batch", "key": "pk_live_0123456789"},
+		},
+	}
+	after := struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Code     string `json:"code"`
+	}{Username: "ash-ketchum", Password: "raichu", Code: "654321"}
+
+	u.Record(context.Background(), &domain.AuditEntry{Action: domain.AuditUserUpdated, TargetType: domain.AuditTargetUser}, before, after)
+	entry := repo.recorded(t)
+
+	for _, field := range []string{"password", "current_password", "new_password", "secret", "key", "code"} {
+		if _, ok := entry.Before[field]; ok {
+			t.Errorf("before keeps %s", field)
+		}
+	}
+	if _, ok := entry.Before["mfa"].(map[string]interface{})["secret"]; ok {
+		t.Error("before keeps the nested mfa secret")
+	}
+	if _, ok := entry.Before["api_keys"].([]interface{})[0].(map[string]interface{})["key"]; ok {
+		t.Error("before keeps the key of a listed API key")
+	}
+	if _, ok := entry.After["password"]; ok {
+		t.Error("after keeps the password")
+	}
+	if _, ok := entry.After["code"]; ok {
+		t.Error("after keeps the code")
+	}
+
+	// Everything else stays
+	if entry.Before["username"] != "ash" || entry.After["username"] != "ash-ketchum" {
+		t.Errorf("usernames %v and %v, want both kept", entry.Before["username"], entry.After["username"])
+	}
+	if entry.Before["mfa"].(map[string]interface{})["enabled"] != true {
+		t.Error("before lost the mfa state")
+	}
+	if entry.Before["api_keys"].([]interface{})[0].(map[string]interface{})["name"] != "batch" {
+		t.Error("before lost the name of the API key")
+	}
+}
+
+func TestRecordSnapshotsAsRendered(t *testing.T) {
+	u, repo := newTestAudit(t)
+
+	// Fields hidden from JSON never reach the log, whatever their name
+	user := &domain.User{ID: primitive.NewObjectID(), Username: "ash", Password: "pikachu"}
+	u.Record(context.Background(), &domain.AuditEntry{Action: domain.AuditUserCreated, TargetType: domain.AuditTargetUser}, nil, user)
+	entry := repo.recorded(t)
+
+	if entry.Before != nil {
+		t.Errorf("before = %v, want nil for a created target", entry.Before)
+	}
+	if entry.After["username"] != "ash" {
+		t.Errorf("after = %v, want the rendered user", entry.After)
+	}
+	for field, value := range entry.After {
+		if value == "pikachu" {
+			t.Errorf("after keeps the password as %s", field)
+		}
+	}
+}
+
+func TestRecordTakesTheRequestContext(t *testing.T) {
+	actor := &domain.User{ID: primitive.NewObjectID(), Username: "oak"}
+	key := &domain.APIKey{ID: primitive.NewObjectID()}
+
+	t.Run("actor, key, request and client", func(t *testing.T) {
+		u, repo := newTestAudit(t)
+
+		ctx := context.WithValue(context.Background(), utils.UserCtxKey{}, actor)
+		ctx = context.WithValue(ctx, utils.APIKeyCtxKey{}, key)
+		ctx = context.WithValue(ctx, utils.ReqIDCtxKey{}, "req-1")
+		ctx = context.WithValue(ctx, utils.ClientIPCtxKey{}, "203.0.113.7")
+		u.Record(ctx, &domain.AuditEntry{Action: domain.AuditMonsterDeleted, TargetType: domain.AuditTargetMonster, TargetID: "1"}, nil, nil)
+		entry := repo.recorded(t)
+
+		if entry.ActorID == nil || *entry.ActorID != actor.ID || entry.ActorUsername != "oak" {
+			t.Errorf("actor = %v %q, want %s oak", entry.ActorID, entry.ActorUsername, actor.ID.Hex())
+		}
+		if entry.APIKeyID == nil || *entry.APIKeyID != key.ID {
+			t.Errorf("APIKeyID = %v, want %s", entry.APIKeyID, key.ID.Hex())
+		}
+		if entry.RequestID != "req-1" {
+			t.Errorf("RequestID = %q, want req-1", entry.RequestID)
+		}
+		if entry.IPAddress != "203.0.113.7" {
+			t.Errorf("IPAddress = %q, want 203.0.113.7", entry.IPAddress)
+		}
+		if entry.CreatedAt.IsZero() {
+			t.Error("CreatedAt is not set")
+		}
+	})
+
+	t.Run("actor set by the caller", func(t *testing.T) {
+		u, repo := newTestAudit(t)
+
+		// A failed login is recorded for the user signing in, nobody is authenticated yet
+		signingIn := primitive.NewObjectID()
+		ctx := context.WithValue(context.Background(), utils.UserCtxKey{}, actor)
+		u.Record(ctx, &domain.AuditEntry{ActorID: &signingIn, ActorUsername: "ash", Action: domain.AuditUserLoginFailed}, nil, nil)
+		entry := repo.recorded(t)
+
+		if *entry.ActorID != signingIn || entry.ActorUsername != "ash" {
+			t.Errorf("actor = %s %q, want the one set", entry.ActorID.Hex(), entry.ActorUsername)
+		}
+	})
+
+	t.Run("request id of the HTTP request", func(t *testing.T) {
+		u, repo := newTestAudit(t)
+
+		e := echo.New()
+		e.Use(echoMiddleware.RequestID())
+		e.Use(middleware.NewMiddlewareManager(nil, nil, nil, nil).ClientIPMiddleware)
+		e.DELETE("/monster/:id", func(c echo.Context) error {
+			u.Record(c.Request().Context(), &domain.AuditEntry{Action: domain.AuditMonsterDeleted, TargetType: domain.AuditTargetMonster, TargetID: c.Param("id")}, nil, nil)
+			return c.NoContent(http.StatusNoContent)
+		})
+
+		for _, requestID := range []string{"", "batch-job-42"} {
+			repo.entries = nil
+			req := httptest.NewRequest(http.MethodDelete, "/monster/1", nil)
+			if requestID != "" {
+				req.Header.Set(echo.HeaderXRequestID, requestID)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			want := rec.Header().Get(echo.HeaderXRequestID)
+			if want == "" || requestID != "" && want != requestID {
+				t.Fatalf("responded with request id %q for %q", want, requestID)
+			}
+			if got := repo.recorded(t).RequestID; got != want {
+				t.Errorf("RequestID = %q, want %q", got, want)
+			}
+		}
+	})
+}
+
+func TestRecordFailureIsNotReturned(t *testing.T) {
+	u, repo := newTestAudit(t)
+	repo.err = errors.New("audit_log unavailable")
+
+	// The action already happened, recording it must neither panic nor block the caller
+	u.Record(context.Background(), &domain.AuditEntry{Action: domain.AuditUserLogout}, nil, nil)
+	if len(repo.entries) != 0 {
+		t.Errorf("%d entries recorded, want none", len(repo.entries))
+	}
+}
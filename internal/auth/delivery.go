@@ -7,7 +7,33 @@ import (
 type DeliveryHandlers interface {
 	Register() echo.HandlerFunc
 	Login() echo.HandlerFunc
+	RefreshToken() echo.HandlerFunc
+	Logout() echo.HandlerFunc
+	CSRFToken() echo.HandlerFunc
+	RevokeUserSessions() echo.HandlerFunc
+	ChangePassword() echo.HandlerFunc
+	RequestPasswordReset() echo.HandlerFunc
+	ResetPassword() echo.HandlerFunc
+	CreateRole() echo.HandlerFunc
+	ListRole() echo.HandlerFunc
+	AssignRole() echo.HandlerFunc
+	CreateInvite() echo.HandlerFunc
+	CreateAPIKey() echo.HandlerFunc
+	ListAPIKey() echo.HandlerFunc
+	RevokeAPIKey() echo.HandlerFunc
+	ListLoginLock() echo.HandlerFunc
+	ReleaseLoginLock() echo.HandlerFunc
+	ListSession() echo.HandlerFunc
+	RevokeSession() echo.HandlerFunc
+	OIDCLogin() echo.HandlerFunc
+	OIDCLink() echo.HandlerFunc
+	OIDCCallback() echo.HandlerFunc
+	EnrollMFA() echo.HandlerFunc
+	ConfirmMFA() echo.HandlerFunc
+	DisableMFA() echo.HandlerFunc
+	VerifyMFA() echo.HandlerFunc
 	UpdateUser() echo.HandlerFunc
+	PatchUser() echo.HandlerFunc
 	DeleteUser() echo.HandlerFunc
 	ListUser() echo.HandlerFunc
 	DetailUser() echo.HandlerFunc
@@ -0,0 +1,20 @@
+package grpc
+
+import (
+	"google.golang.org/grpc"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/interceptors"
+	"github.com/iamaul/go-pokedex/proto/pokedex"
+)
+
+func AuthRoutes(server *grpc.Server, s pokedex.AuthServiceServer, im *interceptors.InterceptorManager) {
+	pokedex.RegisterAuthServiceServer(server, s)
+
+	im.Protect(pokedex.AuthService_UpdateUser_FullMethodName)
+	im.Protect(pokedex.AuthService_DeleteUser_FullMethodName, domain.PermUserDelete)
+	im.Protect(pokedex.AuthService_ListUsers_FullMethodName)
+	im.Protect(pokedex.AuthService_GetUser_FullMethodName)
+	im.Protect(pokedex.AuthService_CatchMonster_FullMethodName)
+	im.Protect(pokedex.AuthService_Me_FullMethodName)
+}
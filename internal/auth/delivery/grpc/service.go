@@ -0,0 +1,228 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/grpcerr"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+	"github.com/iamaul/go-pokedex/proto/pokedex"
+)
+
+type AuthServer struct {
+	pokedex.UnimplementedAuthServiceServer
+	cfg         *config.Config
+	authUsecase auth.Usecase
+	logger      logger.Logger
+}
+
+func NewAuthServer(cfg *config.Config, authUsecase auth.Usecase, log logger.Logger) pokedex.AuthServiceServer {
+	return &AuthServer{cfg: cfg, authUsecase: authUsecase, logger: log}
+}
+
+func (s *AuthServer) Register(ctx context.Context, req *pokedex.RegisterRequest) (*pokedex.UserWithToken, error) {
+	// The role field of the request is ignored, privileged accounts are created from invites over HTTP
+	registration := &domain.UserRegistration{
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+	}
+	if err := utils.ValidateStruct(ctx, registration); err != nil {
+		return nil, s.error("Register", err)
+	}
+
+	createdUser, err := s.authUsecase.UserRegistration(ctx, registration)
+	if err != nil {
+		return nil, s.error("Register", err)
+	}
+
+	return userWithTokenToProto(createdUser), nil
+}
+
+func (s *AuthServer) Login(ctx context.Context, req *pokedex.LoginRequest) (*pokedex.UserWithToken, error) {
+	login := &domain.UserLogin{Username: req.GetUsername(), Password: req.GetPassword()}
+	if err := utils.ValidateStruct(ctx, login); err != nil {
+		return nil, s.error("Login", err)
+	}
+
+	userWithToken, err := s.authUsecase.UserAuthentication(ctx, &domain.User{
+		Username: login.Username,
+		Password: login.Password,
+	})
+	if err != nil {
+		return nil, s.error("Login", err)
+	}
+	// The response has no room for a two-factor challenge, those users sign in over HTTP
+	if userWithToken.MFARequired {
+		return nil, s.error("Login", httpErr.NewRestErrorWithMessage(http.StatusPreconditionFailed, httpErr.ErrMFASignInOverHTTP, nil))
+	}
+
+	return userWithTokenToProto(userWithToken), nil
+}
+
+func (s *AuthServer) UpdateUser(ctx context.Context, req *pokedex.UpdateUserRequest) (*pokedex.User, error) {
+	me, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, s.error("UpdateUser", httpErr.NewUnauthorizedError(err))
+	}
+
+	if _, err := s.authUsecase.UserUpdate(ctx, &domain.UserUpdate{
+		ID:       me.ID,
+		Username: req.GetUsername(),
+	}); err != nil {
+		return nil, s.error("UpdateUser", err)
+	}
+
+	user, err := s.authUsecase.GetByID(ctx, me.ID)
+	if err != nil {
+		return nil, s.error("UpdateUser", err)
+	}
+
+	return userToProto(user), nil
+}
+
+func (s *AuthServer) DeleteUser(ctx context.Context, req *pokedex.IDRequest) (*emptypb.Empty, error) {
+	userID, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, s.error("DeleteUser", httpErr.NewBadRequestError(err))
+	}
+
+	if err := s.authUsecase.UserDeletion(ctx, userID, nil); err != nil {
+		return nil, s.error("DeleteUser", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *AuthServer) ListUsers(ctx context.Context, req *pokedex.PaginationRequest) (*pokedex.UserList, error) {
+	usersList, err := s.authUsecase.UserList(ctx, paginationFromProto(req))
+	if err != nil {
+		return nil, s.error("ListUsers", err)
+	}
+
+	users := make([]*pokedex.User, 0, len(usersList.Users))
+	for _, user := range usersList.Users {
+		users = append(users, userToProto(user))
+	}
+
+	return &pokedex.UserList{
+		TotalCount: int32(usersList.TotalCount),
+		TotalPages: int32(usersList.TotalPages),
+		Page:       int32(usersList.Page),
+		Size:       int32(usersList.Size),
+		HasMore:    usersList.HasMore,
+		Users:      users,
+	}, nil
+}
+
+func (s *AuthServer) GetUser(ctx context.Context, req *pokedex.IDRequest) (*pokedex.User, error) {
+	userID, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, s.error("GetUser", httpErr.NewBadRequestError(err))
+	}
+
+	user, err := s.authUsecase.GetByID(ctx, userID)
+	if err != nil {
+		return nil, s.error("GetUser", err)
+	}
+
+	return userToProto(user), nil
+}
+
+// Monsters are caught for the caller, catching for another user takes the user write permission
+func (s *AuthServer) CatchMonster(ctx context.Context, req *pokedex.CatchMonsterRequest) (*emptypb.Empty, error) {
+	me, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, s.error("CatchMonster", httpErr.NewUnauthorizedError(err))
+	}
+
+	userID := me.ID
+	if req.GetUserId() != "" {
+		userID, err = primitive.ObjectIDFromHex(req.GetUserId())
+		if err != nil {
+			return nil, s.error("CatchMonster", httpErr.NewBadRequestError(err))
+		}
+	}
+	if userID != me.ID {
+		allowed, err := s.authUsecase.HasPermission(ctx, me, domain.PermUserWrite)
+		if err != nil {
+			return nil, s.error("CatchMonster", err)
+		}
+		if !allowed {
+			return nil, s.error("CatchMonster", httpErr.NewForbiddenError(httpErr.Forbidden))
+		}
+	}
+
+	monsterID, err := primitive.ObjectIDFromHex(req.GetMonsterId())
+	if err != nil {
+		return nil, s.error("CatchMonster", httpErr.NewBadRequestError(err))
+	}
+
+	if err := s.authUsecase.UserCatchMonster(ctx, userID, monsterID); err != nil {
+		return nil, s.error("CatchMonster", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *AuthServer) Me(ctx context.Context, _ *emptypb.Empty) (*pokedex.User, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, s.error("Me", httpErr.NewUnauthorizedError(err))
+	}
+
+	return userToProto(user), nil
+}
+
+func (s *AuthServer) error(method string, err error) error {
+	s.logger.Errorf("AuthServer.%s: %s", method, err)
+	return grpcerr.FromError(err)
+}
+
+func paginationFromProto(req *pokedex.PaginationRequest) *utils.PaginationQuery {
+	pq := &utils.PaginationQuery{
+		Size:    int(req.GetSize()),
+		Page:    int(req.GetPage()),
+		OrderBy: req.GetOrderBy(),
+	}
+	if pq.Size <= 0 {
+		_ = pq.SetSize("")
+	}
+	return pq
+}
+
+func userToProto(user *domain.User) *pokedex.User {
+	monsters := make([]string, 0, len(user.Monsters))
+	for _, id := range user.Monsters {
+		monsters = append(monsters, id.Hex())
+	}
+
+	role := ""
+	if user.Role != nil {
+		role = *user.Role
+	}
+
+	return &pokedex.User{
+		Id:        user.ID.Hex(),
+		Monsters:  monsters,
+		Username:  user.Username,
+		Role:      role,
+		CreatedAt: timestamppb.New(user.CreatedAt),
+		UpdatedAt: timestamppb.New(user.UpdatedAt),
+	}
+}
+
+func userWithTokenToProto(u *domain.UserWithToken) *pokedex.UserWithToken {
+	return &pokedex.UserWithToken{
+		User:  userToProto(u.User),
+		Token: u.Token,
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/iamaul/go-pokedex/internal/domain"
 	httpErr "github.com/iamaul/go-pokedex/pkg/error"
 	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/render"
 	"github.com/iamaul/go-pokedex/pkg/utils"
 	"github.com/labstack/echo/v4"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -29,40 +30,49 @@ func NewAuthHandler(cfg *config.Config, authUsecase auth.Usecase, log logger.Log
 // @Tags Auth
 // @Accept json
 // @Produce json
-// @Success 201 {object} domain.User
+// @Param user body domain.UserRegistration true "user"
+// @Success 201 {object} domain.UserWithToken
+// @Failure 400 {object} httpErr.RestError
 // @Router /auth/new [post]
 func (h *AuthHandler) Register() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		user := &domain.User{}
-		if err := utils.ReadRequest(c, user); err != nil {
+		registration := &domain.UserRegistration{}
+		if err := utils.ReadRequest(c, registration); err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
-		createdUser, err := h.authUsecase.UserRegistration(c.Request().Context(), user)
+		createdUser, err := h.authUsecase.UserRegistration(c.Request().Context(), registration)
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
-		return c.JSON(http.StatusCreated, createdUser)
+		utils.SetSessionCookie(c, h.cfg, createdUser.SessionID)
+
+		return render.Respond(c, http.StatusCreated, createdUser)
 	}
 }
 
 // Login godoc
 // @Summary user authentication
-// @Description returns user and token
+// @Description returns user and token, or a two-factor challenge to exchange at /auth/mfa/verify when mfa_required is set
 // @Tags Auth
 // @Accept json
 // @Produce json
-// @Success 200 {object} domain.User
+// @Param login body domain.UserLogin true "credentials"
+// @Success 200 {object} domain.UserWithToken
+// @Failure 400 {object} httpErr.RestError
+// @Failure 401 {object} httpErr.RestError
+// @Failure 429 {object} httpErr.RestError
+// @Header 429 {integer} Retry-After "seconds until sign ins are accepted again"
 // @Router /auth [post]
 func (h *AuthHandler) Login() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		login := &domain.UserLogin{}
 		if err := utils.ReadRequest(c, login); err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
 		userWithToken, err := h.authUsecase.UserAuthentication(c.Request().Context(), &domain.User{
@@ -71,22 +81,241 @@ func (h *AuthHandler) Login() echo.HandlerFunc {
 		})
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
+		utils.SetSessionCookie(c, h.cfg, userWithToken.SessionID)
 
-		return c.JSON(http.StatusOK, userWithToken)
+		return render.Respond(c, http.StatusOK, userWithToken)
+	}
+}
+
+// RefreshToken godoc
+// @Summary Refresh access token
+// @Description exchange a refresh token for a new access token and refresh token, the presented refresh token can not be used again
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param refresh body domain.RefreshRequest true "refresh token"
+// @Success 200 {object} domain.UserWithToken
+// @Failure 400 {object} httpErr.RestError
+// @Failure 401 {object} httpErr.RestError
+// @Router /auth/refresh [post]
+func (h *AuthHandler) RefreshToken() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := &domain.RefreshRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		userWithToken, err := h.authUsecase.TokenRefresh(c.Request().Context(), request.RefreshToken)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, userWithToken)
+	}
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description revoke the access token and session of the request and clear the jwt-token and session cookies
+// @Tags Auth
+// @Success 204
+// @Failure 401 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// Requests signed in with the session cookie carry no token, their session ends instead
+		if claims, ok := c.Get("claims").(*utils.Claims); ok {
+			if err := h.authUsecase.UserLogout(c.Request().Context(), claims); err != nil {
+				utils.LogResponseError(c, h.logger, err)
+				return render.Error(c, err)
+			}
+		} else {
+			me, ok := c.Get("user").(*domain.User)
+			session, hasSession := c.Get("session").(*domain.Session)
+			if !ok || !hasSession {
+				return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+			}
+
+			if err := h.authUsecase.SessionRevocation(c.Request().Context(), me, session.ID); err != nil {
+				utils.LogResponseError(c, h.logger, err)
+				return render.Error(c, err)
+			}
+		}
+		utils.DeleteSessionCookie(c, "jwt-token")
+		utils.DeleteSessionCookie(c, h.cfg.Session.Name)
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// CSRFToken godoc
+// @Summary Get CSRF token
+// @Description token to send in the X-CSRF-Token header of unsafe requests authenticated by cookie, bound to the session cookie or set in the CSRF cookie in double submit mode
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} domain.CSRFToken
+// @Failure 401 {object} httpErr.RestError
+// @Router /auth/csrf [get]
+func (h *AuthHandler) CSRFToken() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// Double submitted tokens are bound to no session
+		sid := ""
+		if !utils.CSRFDoubleSubmit(h.cfg) {
+			var ok bool
+			if sid, ok = c.Get("sid").(string); !ok {
+				return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.InvalidSession))
+			}
+		}
+
+		csrfToken, err := utils.GenerateCSRFToken(h.cfg, sid)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, httpErr.NewInternalServerError(err))
+		}
+		if utils.CSRFDoubleSubmit(h.cfg) {
+			utils.SetCSRFCookie(c, h.cfg, csrfToken)
+		}
+
+		return render.Respond(c, http.StatusOK, csrfToken)
+	}
+}
+
+// RevokeUserSessions godoc
+// @Summary Revoke user sessions
+// @Description revoke every access and refresh token issued to the user so far
+// @Tags Auth
+// @Param id path string true "user id"
+// @Success 204
+// @Failure 401 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Failure 404 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/{id}/sessions [delete]
+func (h *AuthHandler) RevokeUserSessions() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.UserSessionsRevocation(c.Request().Context(), userID); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// ChangePassword godoc
+// @Summary Change password
+// @Description change the password of the authenticated user, the other sessions are revoked and new tokens are returned
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param password body domain.PasswordChange true "current and new password"
+// @Success 200 {object} domain.UserWithToken
+// @Failure 400 {object} httpErr.RestError
+// @Failure 401 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/password [put]
+func (h *AuthHandler) ChangePassword() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		change := &domain.PasswordChange{}
+		if err := utils.ReadRequest(c, change); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		userWithToken, err := h.authUsecase.PasswordChange(c.Request().Context(), me.ID, change)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		utils.DeleteSessionCookie(c, "jwt-token")
+		utils.SetSessionCookie(c, h.cfg, userWithToken.SessionID)
+
+		return render.Respond(c, http.StatusOK, userWithToken)
+	}
+}
+
+// RequestPasswordReset godoc
+// @Summary Request password reset
+// @Description send a single-use password reset token to the user, accepted for unknown usernames too
+// @Tags Auth
+// @Accept json
+// @Param request body domain.PasswordResetRequest true "username"
+// @Success 202
+// @Failure 400 {object} httpErr.RestError
+// @Router /auth/password/reset [post]
+func (h *AuthHandler) RequestPasswordReset() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := &domain.PasswordResetRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.PasswordResetRequest(c.Request().Context(), request.Username); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusAccepted)
+	}
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description set a new password with a reset token, every session of the user is revoked
+// @Tags Auth
+// @Accept json
+// @Param reset body domain.PasswordReset true "reset token and new password"
+// @Success 204
+// @Failure 400 {object} httpErr.RestError
+// @Router /auth/password/reset/confirm [post]
+func (h *AuthHandler) ResetPassword() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		reset := &domain.PasswordReset{}
+		if err := utils.ReadRequest(c, reset); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.PasswordReset(c.Request().Context(), reset); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
 	}
 }
 
 // UpdateUser godoc
 // @Summary Update user
-// @Description update existing user
+// @Description update the authenticated user
 // @Tags Auth
 // @Accept json
-// @Param id path int true "id"
 // @Produce json
-// @Success 200 {object} domain.User
-// @Router /auth/{id} [put]
+// @Param If-Match header string false "entity tag the stored document must still have"
+// @Param user body domain.UserUpdate true "user"
+// @Success 200 {object} domain.UserUpdate
+// @Header 200 {string} ETag "entity tag of the updated document"
+// @Failure 401 {object} httpErr.RestError
+// @Failure 412 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth [put]
 func (h *AuthHandler) UpdateUser() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		me, ok := c.Get("user").(*domain.User)
@@ -95,19 +324,91 @@ func (h *AuthHandler) UpdateUser() echo.HandlerFunc {
 			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
 		}
 		user := &domain.UserUpdate{}
-		user.ID = me.ID
 		if err := utils.ReadRequest(c, user); err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
+		}
+		user.ID = me.ID
+
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		if version != nil {
+			user.Version = version
 		}
 
 		updatedUser, err := h.authUsecase.UserUpdate(c.Request().Context(), user)
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
+		}
+		c.Response().Header().Set(utils.HeaderETag, domain.VersionETag(*updatedUser.Version))
+
+		return render.Respond(c, http.StatusOK, updatedUser)
+	}
+}
+
+// PatchUser godoc
+// @Summary Patch user
+// @Description apply a JSON merge patch (RFC 7386) or JSON patch (RFC 6902) to the authenticated user
+// @Tags Auth
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Param If-Match header string false "entity tag the stored document must still have"
+// @Param patch body object true "merge patch document or list of patch operations"
+// @Success 200 {object} domain.UserUpdate
+// @Header 200 {string} ETag "entity tag of the updated document"
+// @Failure 400 {object} httpErr.RestError
+// @Failure 401 {object} httpErr.RestError
+// @Failure 409 {object} httpErr.RestError
+// @Failure 412 {object} httpErr.RestError
+// @Failure 415 {object} httpErr.RestError
+// @Failure 422 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth [patch]
+func (h *AuthHandler) PatchUser() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		ctx := c.Request().Context()
+		current, err := h.authUsecase.GetByID(ctx, me.ID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		if err := utils.MatchVersion(version, current.Version); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		user := &domain.UserUpdate{}
+		if err := utils.ReadPatch(c, current.UserUpdate(), user); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		// The patch was computed against the fetched document, only save it while that is still stored
+		user.ID = me.ID
+		user.Version = &current.Version
+
+		updatedUser, err := h.authUsecase.UserUpdate(ctx, user)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
 		}
+		c.Response().Header().Set(utils.HeaderETag, domain.VersionETag(*updatedUser.Version))
 
-		return c.JSON(http.StatusOK, updatedUser)
+		return render.Respond(c, http.StatusOK, updatedUser)
 	}
 }
 
@@ -115,52 +416,623 @@ func (h *AuthHandler) UpdateUser() echo.HandlerFunc {
 // @Summary Delete user
 // @Description delete existing user
 // @Tags Auth
-// @Accept json
-// @Param id path int true "id"
-// @Produce json
-// @Success 200 {string} string	"ok"
+// @Param id path string true "user id"
+// @Param If-Match header string false "entity tag the stored document must still have"
+// @Success 200
+// @Failure 401 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Failure 412 {object} httpErr.RestError
 // @Failure 500 {object} httpErr.RestError
+// @Security BearerAuth
 // @Router /auth/{id} [delete]
 func (h *AuthHandler) DeleteUser() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		userID, err := primitive.ObjectIDFromHex(c.Param("id"))
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
-		if err = h.authUsecase.UserDeletion(c.Request().Context(), userID); err != nil {
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
+		}
+
+		if err = h.authUsecase.UserDeletion(c.Request().Context(), userID, version); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
 		}
 
 		return c.NoContent(http.StatusOK)
 	}
 }
 
+// OIDCLogin godoc
+// @Summary Sign in with an OpenID Connect provider
+// @Description redirect to the provider's login page, the provider redirects back to the callback
+// @Tags Auth
+// @Param provider path string true "provider name"
+// @Success 302
+// @Failure 404 {object} httpErr.RestError
+// @Failure 502 {object} httpErr.RestError
+// @Router /auth/oidc/{provider}/login [get]
+func (h *AuthHandler) OIDCLogin() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		authorization, err := h.authUsecase.OIDCAuthorization(c.Request().Context(), c.Param("provider"), nil)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		utils.SetOIDCStateCookie(c, h.cfg, authorization.State)
+
+		return c.Redirect(http.StatusFound, authorization.AuthorizationURL)
+	}
+}
+
+// OIDCLink godoc
+// @Summary Link an OpenID Connect provider
+// @Description start signing in with the provider, the identity is linked to the current user on callback
+// @Tags Auth
+// @Produce json
+// @Param provider path string true "provider name"
+// @Success 200 {object} domain.OIDCAuthorization
+// @Failure 401 {object} httpErr.RestError
+// @Failure 404 {object} httpErr.RestError
+// @Failure 502 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/oidc/{provider}/link [post]
+func (h *AuthHandler) OIDCLink() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		authorization, err := h.authUsecase.OIDCAuthorization(c.Request().Context(), c.Param("provider"), &me.ID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		utils.SetOIDCStateCookie(c, h.cfg, authorization.State)
+
+		return render.Respond(c, http.StatusOK, authorization)
+	}
+}
+
+// OIDCCallback godoc
+// @Summary OpenID Connect callback
+// @Description the provider redirects here after signing in, returns user and token. Only accepted with the oidc-state cookie set when the flow was started in the same browser
+// @Tags Auth
+// @Produce json
+// @Param provider path string true "provider name"
+// @Param code query string false "authorization code"
+// @Param state query string true "state of the sign-in"
+// @Param error query string false "error returned by the provider"
+// @Success 200 {object} domain.UserWithToken
+// @Failure 400 {object} httpErr.RestError
+// @Failure 401 {object} httpErr.RestError
+// @Failure 409 {object} httpErr.RestError
+// @Router /auth/oidc/{provider}/callback [get]
+func (h *AuthHandler) OIDCCallback() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		callback := &domain.OIDCCallback{}
+		if err := utils.ReadRequest(c, callback); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		callback.BrowserState = utils.OIDCStateFromCookie(c)
+		// Every state is redeemed once, the cookie has served its purpose either way
+		utils.DeleteSessionCookie(c, utils.OIDCStateCookieName)
+
+		userWithToken, err := h.authUsecase.OIDCCallback(c.Request().Context(), c.Param("provider"), callback)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		utils.SetSessionCookie(c, h.cfg, userWithToken.SessionID)
+
+		return render.Respond(c, http.StatusOK, userWithToken)
+	}
+}
+
+// EnrollMFA godoc
+// @Summary Enroll two-factor authentication
+// @Description returns a TOTP secret and provisioning URI for an authenticator app, enabled once confirmed with a code
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} domain.MFAEnrollment
+// @Failure 400 {object} httpErr.RestError
+// @Failure 401 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/mfa/enroll [post]
+func (h *AuthHandler) EnrollMFA() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		enrollment, err := h.authUsecase.MFAEnrollment(c.Request().Context(), me)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, enrollment)
+	}
+}
+
+// ConfirmMFA godoc
+// @Summary Confirm two-factor authentication
+// @Description enable two-factor authentication with a first code, returns recovery codes that are only shown once
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param code body domain.MFACode true "code from the authenticator app"
+// @Success 200 {object} domain.MFARecoveryCodes
+// @Failure 400 {object} httpErr.RestError
+// @Failure 401 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/mfa/confirm [post]
+func (h *AuthHandler) ConfirmMFA() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		code := &domain.MFACode{}
+		if err := utils.ReadRequest(c, code); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		recoveryCodes, err := h.authUsecase.MFAConfirmation(c.Request().Context(), me.ID, code.Code)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, recoveryCodes)
+	}
+}
+
+// DisableMFA godoc
+// @Summary Disable two-factor authentication
+// @Description turn two-factor authentication off with a code or a recovery code
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param code body domain.MFACode true "code from the authenticator app or a recovery code"
+// @Success 204
+// @Failure 400 {object} httpErr.RestError
+// @Failure 401 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/mfa [delete]
+func (h *AuthHandler) DisableMFA() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		code := &domain.MFACode{}
+		if err := utils.ReadRequest(c, code); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.MFADisable(c.Request().Context(), me, code.Code); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// VerifyMFA godoc
+// @Summary Complete two-factor sign in
+// @Description exchange the challenge returned by the sign in and a code or recovery code for user and token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param verification body domain.MFAVerification true "challenge and code"
+// @Success 200 {object} domain.UserWithToken
+// @Failure 400 {object} httpErr.RestError
+// @Failure 401 {object} httpErr.RestError
+// @Router /auth/mfa/verify [post]
+func (h *AuthHandler) VerifyMFA() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		verification := &domain.MFAVerification{}
+		if err := utils.ReadRequest(c, verification); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		userWithToken, err := h.authUsecase.MFAVerification(c.Request().Context(), verification)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		utils.SetSessionCookie(c, h.cfg, userWithToken.SessionID)
+
+		return render.Respond(c, http.StatusOK, userWithToken)
+	}
+}
+
+// CreateRole godoc
+// @Summary Create role
+// @Description create a role granting the given permissions
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param role body domain.Role true "role"
+// @Success 201 {object} domain.Role
+// @Failure 400 {object} httpErr.RestError
+// @Failure 401 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/role [post]
+func (h *AuthHandler) CreateRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		role := &domain.Role{}
+		if err := utils.ReadRequest(c, role); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		createdRole, err := h.authUsecase.RoleCreation(c.Request().Context(), role)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusCreated, createdRole)
+	}
+}
+
+// ListRole godoc
+// @Summary Get role list
+// @Description list of roles and their permissions
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} domain.RoleList
+// @Failure 401 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/role/list [get]
+func (h *AuthHandler) ListRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		roleList, err := h.authUsecase.RoleList(c.Request().Context())
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, roleList)
+	}
+}
+
+// AssignRole godoc
+// @Summary Assign role
+// @Description assign a role to the user
+// @Tags Auth
+// @Accept json
+// @Param id path string true "user id"
+// @Param role body domain.RoleAssignment true "role name"
+// @Success 204
+// @Failure 400 {object} httpErr.RestError
+// @Failure 401 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/{id}/role [put]
+func (h *AuthHandler) AssignRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		assignment := &domain.RoleAssignment{}
+		if err := utils.ReadRequest(c, assignment); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.RoleAssignment(c.Request().Context(), userID, assignment.Role); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// CreateInvite godoc
+// @Summary Create invite
+// @Description create a single-use invite code to register with the given role
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param invite body domain.InviteRequest true "role of the invited user"
+// @Success 201 {object} domain.InviteWithCode
+// @Failure 400 {object} httpErr.RestError
+// @Failure 401 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/invite [post]
+func (h *AuthHandler) CreateInvite() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		request := &domain.InviteRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		invite, err := h.authUsecase.InviteCreation(c.Request().Context(), me.ID, request)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusCreated, invite)
+	}
+}
+
+// CreateAPIKey godoc
+// @Summary Create API key
+// @Description create a named API key acting as the current user within its scopes, sent in the X-API-Key header. The key is only returned once.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param apikey body domain.APIKeyRequest true "name, scopes and optional expiry"
+// @Success 201 {object} domain.APIKeyWithSecret
+// @Failure 400 {object} httpErr.RestError
+// @Failure 401 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/apikey [post]
+func (h *AuthHandler) CreateAPIKey() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		request := &domain.APIKeyRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		apiKey, err := h.authUsecase.APIKeyCreation(c.Request().Context(), me.ID, request)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusCreated, apiKey)
+	}
+}
+
+// ListAPIKey godoc
+// @Summary Get API key list
+// @Description list of the current user's API keys, including revoked and expired ones
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} domain.APIKeyList
+// @Failure 401 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/apikey/list [get]
+func (h *AuthHandler) ListAPIKey() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		apiKeyList, err := h.authUsecase.APIKeyList(c.Request().Context(), me.ID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, apiKeyList)
+	}
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke API key
+// @Description revoke an API key, keys of other users need the user:write permission
+// @Tags Auth
+// @Param id path string true "API key id"
+// @Success 204
+// @Failure 401 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Failure 404 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/apikey/{id} [delete]
+func (h *AuthHandler) RevokeAPIKey() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		keyID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.APIKeyRevocation(c.Request().Context(), me, keyID); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// ListLoginLock godoc
+// @Summary Get sign in lock list
+// @Description usernames and client IPs locked after too many failed sign ins
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} domain.LoginLockList
+// @Failure 401 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/lock/list [get]
+func (h *AuthHandler) ListLoginLock() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		loginLockList, err := h.authUsecase.LoginLockList(c.Request().Context())
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, loginLockList)
+	}
+}
+
+// ReleaseLoginLock godoc
+// @Summary Release sign in lock
+// @Description clear the lock and failed sign ins of a username or client IP
+// @Tags Auth
+// @Param id path string true "lock id"
+// @Success 204
+// @Failure 401 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Failure 404 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/lock/{id} [delete]
+func (h *AuthHandler) ReleaseLoginLock() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		lockID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.LoginLockRelease(c.Request().Context(), me, lockID); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// ListSession godoc
+// @Summary Get session list
+// @Description list the active sessions of the authenticated user
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} domain.SessionList
+// @Failure 401 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/session/list [get]
+func (h *AuthHandler) ListSession() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		sessionList, err := h.authUsecase.SessionList(c.Request().Context(), me.ID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, sessionList)
+	}
+}
+
+// RevokeSession godoc
+// @Summary Revoke session
+// @Description end a session of the authenticated user along with its tokens, ending another user's session requires the user:write permission
+// @Tags Auth
+// @Param id path string true "session id"
+// @Success 204
+// @Failure 401 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Failure 404 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /auth/session/{id} [delete]
+func (h *AuthHandler) RevokeSession() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		sessionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.SessionRevocation(c.Request().Context(), me, sessionID); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
 // ListUser godoc
 // @Summary Get user list
-// @Description list of users
+// @Description list of users, for authenticated users only
 // @Tags Auth
-// @Accept json
 // @Produce json
-// @Success 200 {object} domain.User
+// @Param page query int false "page number"
+// @Param size query int false "page size"
+// @Param orderBy query string false "sort field"
+// @Param If-None-Match header string false "entity tag of the cached copy"
+// @Success 200 {object} domain.UserList
+// @Header 200 {string} ETag "entity tag of the representation"
+// @Success 304
+// @Failure 400 {object} httpErr.RestError
+// @Failure 401 {object} httpErr.RestError
+// @Security BearerAuth
 // @Router /auth/user/list [get]
 func (h *AuthHandler) ListUser() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		paginationQuery, err := utils.GetPaginationFromCtx(c)
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
 		usersList, err := h.authUsecase.UserList(c.Request().Context(), paginationQuery)
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
-		return c.JSON(http.StatusOK, usersList)
+		if utils.NotModified(c, usersList.ETag()) {
+			return c.NoContent(http.StatusNotModified)
+		}
+
+		return render.Respond(c, http.StatusOK, usersList)
 	}
 }
 
@@ -168,69 +1040,83 @@ func (h *AuthHandler) ListUser() echo.HandlerFunc {
 // @Summary Detail user
 // @Description get user detail
 // @Tags Auth
-// @Accept json
-// @Param id path int true "id"
 // @Produce json
+// @Param id path string true "user id"
+// @Param If-None-Match header string false "entity tag of the cached copy"
 // @Success 200 {object} domain.User
+// @Header 200 {string} ETag "entity tag of the representation"
+// @Success 304
+// @Failure 401 {object} httpErr.RestError
+// @Failure 404 {object} httpErr.RestError
+// @Security BearerAuth
 // @Router /auth/{id} [get]
 func (h *AuthHandler) DetailUser() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		userID, err := primitive.ObjectIDFromHex(c.Param("id"))
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
 		user, err := h.authUsecase.GetByID(c.Request().Context(), userID)
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
+		}
+
+		if utils.NotModified(c, user.ETag()) {
+			return c.NoContent(http.StatusNotModified)
 		}
 
-		return c.JSON(http.StatusOK, user)
+		return render.Respond(c, http.StatusOK, user)
 	}
 }
 
 // CatchMonster godoc
 // @Summary Catch monster
-// @Description capture a monster
+// @Description capture a monster, catching for another user needs the user:write permission
 // @Tags Auth
 // @Accept json
-// @Param id path int true "id"
 // @Produce json
+// @Param id path string true "user id"
+// @Param monster body domain.UserMonsterBody true "monster"
 // @Success 200 {object} domain.UserMonsterBody
+// @Failure 401 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Failure 404 {object} httpErr.RestError
+// @Security BearerAuth
 // @Router /auth/{id} [post]
 func (h *AuthHandler) CatchMonster() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		userID, err := primitive.ObjectIDFromHex(c.Param("id"))
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
 		monster := &domain.UserMonsterBody{}
 		if err := utils.ReadRequest(c, monster); err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
 		if err := h.authUsecase.UserCatchMonster(c.Request().Context(), userID, monster.MonsterID); err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
-		return c.JSON(http.StatusOK, monster)
+		return render.Respond(c, http.StatusOK, monster)
 	}
 }
 
 // Me godoc
-// @Summary Get user by id
-// @Description Get current user by id
+// @Summary Get current user
+// @Description get the authenticated user
 // @Tags Auth
-// @Accept json
 // @Produce json
 // @Success 200 {object} domain.User
-// @Failure 500 {object} httpErr.RestError
+// @Failure 401 {object} httpErr.RestError
+// @Security BearerAuth
 // @Router /auth/me [get]
 func (h *AuthHandler) Me() echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -240,6 +1126,6 @@ func (h *AuthHandler) Me() echo.HandlerFunc {
 			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
 		}
 
-		return c.JSON(http.StatusOK, user)
+		return render.Respond(c, http.StatusOK, user)
 	}
 }
@@ -3,6 +3,7 @@ package http
 import (
 	"github.com/iamaul/go-pokedex/config"
 	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
 	"github.com/iamaul/go-pokedex/internal/middleware"
 	"github.com/labstack/echo/v4"
 )
@@ -10,10 +11,36 @@ import (
 func AuthRoutes(authGroup *echo.Group, h auth.DeliveryHandlers, au auth.Usecase, cfg *config.Config, mw *middleware.MiddlewareManager) {
 	authGroup.POST("/new", h.Register())
 	authGroup.POST("", h.Login())
-	authGroup.PUT("", h.UpdateUser(), mw.AuthJWTMiddleware(au, cfg))
-	authGroup.DELETE("/:id", h.DeleteUser(), mw.AuthJWTMiddleware(au, cfg), mw.RoleBasedAuthMiddleware([]string{"admin"}))
-	authGroup.GET("/user/list", h.ListUser())
+	authGroup.POST("/refresh", h.RefreshToken())
+	authGroup.POST("/logout", h.Logout(), mw.AuthJWTMiddleware(au, cfg))
+	authGroup.GET("/csrf", h.CSRFToken())
+	authGroup.DELETE("/:id/sessions", h.RevokeUserSessions(), mw.AuthJWTMiddleware(au, cfg), mw.RequireScope(domain.ScopeAdmin), mw.SelfOrPermission(domain.PermUserWrite))
+	authGroup.PUT("/password", h.ChangePassword(), mw.AuthJWTMiddleware(au, cfg), mw.RequireScope(domain.ScopeAdmin))
+	authGroup.POST("/password/reset", h.RequestPasswordReset())
+	authGroup.POST("/password/reset/confirm", h.ResetPassword())
+	authGroup.GET("/oidc/:provider/login", h.OIDCLogin())
+	authGroup.POST("/oidc/:provider/link", h.OIDCLink(), mw.AuthJWTMiddleware(au, cfg), mw.RequireScope(domain.ScopeAdmin))
+	authGroup.GET("/oidc/:provider/callback", h.OIDCCallback())
+	authGroup.POST("/mfa/enroll", h.EnrollMFA(), mw.AuthJWTMiddleware(au, cfg), mw.RequireScope(domain.ScopeAdmin))
+	authGroup.POST("/mfa/confirm", h.ConfirmMFA(), mw.AuthJWTMiddleware(au, cfg), mw.RequireScope(domain.ScopeAdmin))
+	authGroup.DELETE("/mfa", h.DisableMFA(), mw.AuthJWTMiddleware(au, cfg), mw.RequireScope(domain.ScopeAdmin))
+	authGroup.POST("/mfa/verify", h.VerifyMFA())
+	authGroup.PUT("", h.UpdateUser(), mw.AuthJWTMiddleware(au, cfg), mw.RequireScope(domain.ScopeAdmin))
+	authGroup.PATCH("", h.PatchUser(), mw.AuthJWTMiddleware(au, cfg), mw.RequireScope(domain.ScopeAdmin))
+	authGroup.DELETE("/:id", h.DeleteUser(), mw.AuthJWTMiddleware(au, cfg), mw.RequirePermission(domain.PermUserDelete))
+	authGroup.POST("/role", h.CreateRole(), mw.AuthJWTMiddleware(au, cfg), mw.RequirePermission(domain.PermRoleWrite))
+	authGroup.GET("/role/list", h.ListRole(), mw.AuthJWTMiddleware(au, cfg), mw.RequirePermission(domain.PermRoleRead))
+	authGroup.PUT("/:id/role", h.AssignRole(), mw.AuthJWTMiddleware(au, cfg), mw.RequirePermission(domain.PermRoleWrite))
+	authGroup.POST("/invite", h.CreateInvite(), mw.AuthJWTMiddleware(au, cfg), mw.RequirePermission(domain.PermRoleWrite))
+	authGroup.POST("/apikey", h.CreateAPIKey(), mw.AuthJWTMiddleware(au, cfg), mw.RequireScope(domain.ScopeAdmin))
+	authGroup.GET("/apikey/list", h.ListAPIKey(), mw.AuthJWTMiddleware(au, cfg), mw.RequireScope(domain.ScopeAdmin))
+	authGroup.DELETE("/apikey/:id", h.RevokeAPIKey(), mw.AuthJWTMiddleware(au, cfg), mw.RequireScope(domain.ScopeAdmin))
+	authGroup.GET("/lock/list", h.ListLoginLock(), mw.AuthJWTMiddleware(au, cfg), mw.RequirePermission(domain.PermUserWrite))
+	authGroup.DELETE("/lock/:id", h.ReleaseLoginLock(), mw.AuthJWTMiddleware(au, cfg), mw.RequirePermission(domain.PermUserWrite))
+	authGroup.GET("/session/list", h.ListSession(), mw.AuthJWTMiddleware(au, cfg), mw.RequireScope(domain.ScopeAdmin))
+	authGroup.DELETE("/session/:id", h.RevokeSession(), mw.AuthJWTMiddleware(au, cfg), mw.RequireScope(domain.ScopeAdmin))
+	authGroup.GET("/user/list", h.ListUser(), mw.AuthJWTMiddleware(au, cfg))
 	authGroup.GET("/:id", h.DetailUser(), mw.AuthJWTMiddleware(au, cfg))
-	authGroup.POST("/:id", h.CatchMonster(), mw.AuthJWTMiddleware(au, cfg))
+	authGroup.POST("/:id", h.CatchMonster(), mw.AuthJWTMiddleware(au, cfg), mw.RequireSelfScope(domain.ScopeCatch), mw.SelfOrPermission(domain.PermUserWrite))
 	authGroup.GET("/me", h.Me(), mw.AuthJWTMiddleware(au, cfg))
 }
@@ -0,0 +1,312 @@
+package v2
+
+import (
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+type User struct {
+	ID        string    `json:"id" xml:"id"`
+	Username  string    `json:"username" xml:"username"`
+	Role      string    `json:"role" xml:"role"`
+	Monsters  []string  `json:"monsters" xml:"monsters>monster"`
+	Version   int64     `json:"version" xml:"version"`
+	CreatedAt time.Time `json:"created_at" xml:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" xml:"updated_at"`
+}
+
+type Session struct {
+	AccessToken  string `json:"access_token" xml:"access_token"`
+	TokenType    string `json:"token_type" xml:"token_type"`
+	ExpiresIn    int64  `json:"expires_in" xml:"expires_in"`
+	RefreshToken string `json:"refresh_token" xml:"refresh_token"`
+	User         *User  `json:"user" xml:"user"`
+	MFARequired  bool   `json:"mfa_required,omitempty" xml:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty" xml:"mfa_token,omitempty"`
+}
+
+type RegisterRequest struct {
+	Username   string `json:"username" xml:"username" validate:"required"`
+	Password   string `json:"password" xml:"password" validate:"required,gte=6"`
+	InviteCode string `json:"invite_code,omitempty" xml:"invite_code,omitempty"`
+}
+
+type LoginRequest struct {
+	Username string `json:"username" xml:"username" validate:"required"`
+	Password string `json:"password" xml:"password" validate:"required,gte=6"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" xml:"refresh_token" validate:"required"`
+}
+
+type Role struct {
+	ID          string    `json:"id" xml:"id"`
+	Name        string    `json:"name" xml:"name"`
+	Permissions []string  `json:"permissions" xml:"permissions>permission"`
+	CreatedAt   time.Time `json:"created_at" xml:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" xml:"updated_at"`
+}
+
+type RoleRequest struct {
+	Name        string   `json:"name" xml:"name" validate:"required"`
+	Permissions []string `json:"permissions" xml:"permissions>permission" validate:"required,min=1"`
+}
+
+type RoleAssignmentRequest struct {
+	Role string `json:"role" xml:"role" validate:"required"`
+}
+
+type Invite struct {
+	ID        string    `json:"id" xml:"id"`
+	Code      string    `json:"code" xml:"code"`
+	Role      string    `json:"role" xml:"role"`
+	ExpiresAt time.Time `json:"expires_at" xml:"expires_at"`
+	CreatedAt time.Time `json:"created_at" xml:"created_at"`
+}
+
+type InviteRequest struct {
+	Role string `json:"role" xml:"role" validate:"required"`
+}
+
+type APIKey struct {
+	ID         string     `json:"id" xml:"id"`
+	Name       string     `json:"name" xml:"name"`
+	Prefix     string     `json:"prefix" xml:"prefix"`
+	Key        string     `json:"key,omitempty" xml:"key,omitempty"`
+	Scopes     []string   `json:"scopes" xml:"scopes>scope"`
+	ExpiresAt  time.Time  `json:"expires_at" xml:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at" xml:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at" xml:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" xml:"created_at"`
+}
+
+type APIKeyRequest struct {
+	Name      string     `json:"name" xml:"name" validate:"required"`
+	Scopes    []string   `json:"scopes" xml:"scopes>scope" validate:"required,min=1,dive,oneof=catalog:read catch admin"`
+	ExpiresAt *time.Time `json:"expires_at" xml:"expires_at"`
+}
+
+type LoginLock struct {
+	ID            string     `json:"id" xml:"id"`
+	Kind          string     `json:"kind" xml:"kind"`
+	Value         string     `json:"value" xml:"value"`
+	Lockouts      int        `json:"lockouts" xml:"lockouts"`
+	LastFailureAt time.Time  `json:"last_failure_at" xml:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until" xml:"locked_until"`
+}
+
+// Sessions listed to their user, the session token is only sent in its cookie
+type UserSession struct {
+	ID         string    `json:"id" xml:"id"`
+	IPAddress  string    `json:"ip_address" xml:"ip_address"`
+	LastSeenAt time.Time `json:"last_seen_at" xml:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at" xml:"expires_at"`
+	CreatedAt  time.Time `json:"created_at" xml:"created_at"`
+}
+
+type CSRFToken struct {
+	Token     string    `json:"csrf_token" xml:"csrf_token"`
+	ExpiresAt time.Time `json:"expires_at" xml:"expires_at"`
+}
+
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorization_url" xml:"authorization_url"`
+}
+
+type MFAEnrollment struct {
+	Secret          string `json:"secret" xml:"secret"`
+	ProvisioningURI string `json:"provisioning_uri" xml:"provisioning_uri"`
+}
+
+type MFARecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes" xml:"recovery_codes>recovery_code"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" xml:"code" validate:"required"`
+}
+
+type MFAVerificationRequest struct {
+	MFAToken string `json:"mfa_token" xml:"mfa_token" validate:"required"`
+	Code     string `json:"code" xml:"code" validate:"required"`
+}
+
+type PasswordChangeRequest struct {
+	CurrentPassword string `json:"current_password" xml:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" xml:"new_password" validate:"required,gte=6"`
+}
+
+type PasswordResetRequest struct {
+	Username string `json:"username" xml:"username" validate:"required"`
+}
+
+type PasswordResetConfirmRequest struct {
+	Token       string `json:"token" xml:"token" validate:"required"`
+	NewPassword string `json:"new_password" xml:"new_password" validate:"required,gte=6"`
+}
+
+type UserUpdateRequest struct {
+	Username string `json:"username" xml:"username" validate:"required"`
+}
+
+type CatchRequest struct {
+	MonsterID string `json:"monster_id" xml:"monster_id" validate:"required"`
+}
+
+func NewUser(u *domain.User) *User {
+	monsters := make([]string, 0, len(u.Monsters))
+	for _, id := range u.Monsters {
+		monsters = append(monsters, id.Hex())
+	}
+
+	user := &User{
+		ID:        u.ID.Hex(),
+		Username:  u.Username,
+		Monsters:  monsters,
+		Version:   u.Version,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+	if u.Role != nil {
+		user.Role = *u.Role
+	}
+
+	return user
+}
+
+// Editable fields of the user, the document PATCH requests are applied to
+func NewUserUpdateRequest(u *domain.User) *UserUpdateRequest {
+	return &UserUpdateRequest{Username: u.Username}
+}
+
+// Sessions waiting for a two-factor code have no user or tokens yet
+func NewSession(u *domain.UserWithToken) *Session {
+	if u.MFARequired {
+		return &Session{MFARequired: true, MFAToken: u.MFAToken}
+	}
+
+	return &Session{
+		AccessToken:  u.Token,
+		TokenType:    "Bearer",
+		ExpiresIn:    u.ExpiresIn,
+		RefreshToken: u.RefreshToken,
+		User:         NewUser(u.User),
+	}
+}
+
+func NewUserPage(l *domain.UserList) *utils.Page[*User] {
+	data := make([]*User, 0, len(l.Users))
+	for _, u := range l.Users {
+		data = append(data, NewUser(u))
+	}
+
+	return &utils.Page[*User]{
+		Data: data,
+		Meta: utils.PageMeta{TotalCount: l.TotalCount, TotalPages: l.TotalPages, Page: l.Page, Size: l.Size, HasMore: l.HasMore},
+	}
+}
+
+func NewRole(r *domain.Role) *Role {
+	return &Role{ID: r.ID.Hex(), Name: r.Name, Permissions: r.Permissions, CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt}
+}
+
+// Roles are few and not paginated, the page holds all of them
+func NewRolePage(l *domain.RoleList) *utils.Page[*Role] {
+	data := make([]*Role, 0, len(l.Roles))
+	for _, r := range l.Roles {
+		data = append(data, NewRole(r))
+	}
+
+	return &utils.Page[*Role]{
+		Data: data,
+		Meta: utils.PageMeta{TotalCount: len(data), TotalPages: 1, Page: 1, Size: len(data), HasMore: false},
+	}
+}
+
+func NewInvite(i *domain.InviteWithCode) *Invite {
+	return &Invite{ID: i.Invite.ID.Hex(), Code: i.Code, Role: i.Invite.Role, ExpiresAt: i.Invite.ExpiresAt, CreatedAt: i.Invite.CreatedAt}
+}
+
+func NewAPIKey(k *domain.APIKey) *APIKey {
+	return &APIKey{
+		ID:         k.ID.Hex(),
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+// API keys of a user are few and not paginated, the page holds all of them
+func NewAPIKeyPage(l *domain.APIKeyList) *utils.Page[*APIKey] {
+	data := make([]*APIKey, 0, len(l.APIKeys))
+	for _, k := range l.APIKeys {
+		data = append(data, NewAPIKey(k))
+	}
+
+	return &utils.Page[*APIKey]{
+		Data: data,
+		Meta: utils.PageMeta{TotalCount: len(data), TotalPages: 1, Page: 1, Size: len(data), HasMore: false},
+	}
+}
+
+func NewLoginLock(l *domain.LoginLock) *LoginLock {
+	return &LoginLock{
+		ID:            l.ID.Hex(),
+		Kind:          l.Kind,
+		Value:         l.Value,
+		Lockouts:      l.Lockouts,
+		LastFailureAt: l.LastFailureAt,
+		LockedUntil:   l.LockedUntil,
+	}
+}
+
+// Only current locks are listed, the page holds all of them
+func NewLoginLockPage(l *domain.LoginLockList) *utils.Page[*LoginLock] {
+	data := make([]*LoginLock, 0, len(l.LoginLocks))
+	for _, lock := range l.LoginLocks {
+		data = append(data, NewLoginLock(lock))
+	}
+
+	return &utils.Page[*LoginLock]{
+		Data: data,
+		Meta: utils.PageMeta{TotalCount: len(data), TotalPages: 1, Page: 1, Size: len(data), HasMore: false},
+	}
+}
+
+func NewCSRFToken(t *domain.CSRFToken) *CSRFToken {
+	return &CSRFToken{Token: t.Token, ExpiresAt: t.ExpiresAt}
+}
+
+func NewUserSession(s *domain.Session) *UserSession {
+	return &UserSession{
+		ID:         s.ID.Hex(),
+		IPAddress:  s.IPAddress,
+		LastSeenAt: s.LastSeenAt,
+		ExpiresAt:  s.ExpiresAt,
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+// Only active sessions are listed, the page holds all of them
+func NewUserSessionPage(l *domain.SessionList) *utils.Page[*UserSession] {
+	data := make([]*UserSession, 0, len(l.Sessions))
+	for _, session := range l.Sessions {
+		data = append(data, NewUserSession(session))
+	}
+
+	return &utils.Page[*UserSession]{
+		Data: data,
+		Meta: utils.PageMeta{TotalCount: len(data), TotalPages: 1, Page: 1, Size: len(data), HasMore: false},
+	}
+}
+
+func (r *RegisterRequest) Registration() *domain.UserRegistration {
+	return &domain.UserRegistration{Username: r.Username, Password: r.Password, InviteCode: r.InviteCode}
+}
@@ -0,0 +1,772 @@
+package v2
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/render"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+// AuthHandler serves the v2 representation of the auth routes
+type AuthHandler struct {
+	cfg         *config.Config
+	authUsecase auth.Usecase
+	logger      logger.Logger
+}
+
+func NewAuthHandler(cfg *config.Config, authUsecase auth.Usecase, log logger.Logger) auth.DeliveryHandlers {
+	return &AuthHandler{cfg: cfg, authUsecase: authUsecase, logger: log}
+}
+
+func (h *AuthHandler) Register() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := &RegisterRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		createdUser, err := h.authUsecase.UserRegistration(c.Request().Context(), request.Registration())
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		utils.SetSessionCookie(c, h.cfg, createdUser.SessionID)
+
+		return render.Respond(c, http.StatusCreated, NewSession(createdUser))
+	}
+}
+
+func (h *AuthHandler) Login() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := &LoginRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		userWithToken, err := h.authUsecase.UserAuthentication(c.Request().Context(), &domain.User{
+			Username: request.Username,
+			Password: request.Password,
+		})
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		utils.SetSessionCookie(c, h.cfg, userWithToken.SessionID)
+
+		return render.Respond(c, http.StatusOK, NewSession(userWithToken))
+	}
+}
+
+func (h *AuthHandler) RefreshToken() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := &RefreshRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		userWithToken, err := h.authUsecase.TokenRefresh(c.Request().Context(), request.RefreshToken)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, NewSession(userWithToken))
+	}
+}
+
+func (h *AuthHandler) Logout() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// Requests signed in with the session cookie carry no token, their session ends instead
+		if claims, ok := c.Get("claims").(*utils.Claims); ok {
+			if err := h.authUsecase.UserLogout(c.Request().Context(), claims); err != nil {
+				utils.LogResponseError(c, h.logger, err)
+				return render.Error(c, err)
+			}
+		} else {
+			me, ok := c.Get("user").(*domain.User)
+			session, hasSession := c.Get("session").(*domain.Session)
+			if !ok || !hasSession {
+				return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+			}
+
+			if err := h.authUsecase.SessionRevocation(c.Request().Context(), me, session.ID); err != nil {
+				utils.LogResponseError(c, h.logger, err)
+				return render.Error(c, err)
+			}
+		}
+		utils.DeleteSessionCookie(c, "jwt-token")
+		utils.DeleteSessionCookie(c, h.cfg.Session.Name)
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (h *AuthHandler) CSRFToken() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		// Double submitted tokens are bound to no session
+		sid := ""
+		if !utils.CSRFDoubleSubmit(h.cfg) {
+			var ok bool
+			if sid, ok = c.Get("sid").(string); !ok {
+				return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.InvalidSession))
+			}
+		}
+
+		csrfToken, err := utils.GenerateCSRFToken(h.cfg, sid)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, httpErr.NewInternalServerError(err))
+		}
+		if utils.CSRFDoubleSubmit(h.cfg) {
+			utils.SetCSRFCookie(c, h.cfg, csrfToken)
+		}
+
+		return render.Respond(c, http.StatusOK, NewCSRFToken(csrfToken))
+	}
+}
+
+func (h *AuthHandler) RevokeUserSessions() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.UserSessionsRevocation(c.Request().Context(), userID); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (h *AuthHandler) ChangePassword() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		request := &PasswordChangeRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		userWithToken, err := h.authUsecase.PasswordChange(c.Request().Context(), me.ID, &domain.PasswordChange{
+			CurrentPassword: request.CurrentPassword,
+			NewPassword:     request.NewPassword,
+		})
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		utils.DeleteSessionCookie(c, "jwt-token")
+		utils.SetSessionCookie(c, h.cfg, userWithToken.SessionID)
+
+		return render.Respond(c, http.StatusOK, NewSession(userWithToken))
+	}
+}
+
+func (h *AuthHandler) RequestPasswordReset() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := &PasswordResetRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.PasswordResetRequest(c.Request().Context(), request.Username); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusAccepted)
+	}
+}
+
+func (h *AuthHandler) ResetPassword() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := &PasswordResetConfirmRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.PasswordReset(c.Request().Context(), &domain.PasswordReset{
+			Token:       request.Token,
+			NewPassword: request.NewPassword,
+		}); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (h *AuthHandler) UpdateUser() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		request := &UserUpdateRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		ctx := c.Request().Context()
+		if _, err := h.authUsecase.UserUpdate(ctx, &domain.UserUpdate{ID: me.ID, Username: request.Username, Version: version}); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		user, err := h.authUsecase.GetByID(ctx, me.ID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		c.Response().Header().Set(utils.HeaderETag, user.ETag())
+
+		return render.Respond(c, http.StatusOK, NewUser(user))
+	}
+}
+
+func (h *AuthHandler) PatchUser() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		ctx := c.Request().Context()
+		current, err := h.authUsecase.GetByID(ctx, me.ID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		if err := utils.MatchVersion(version, current.Version); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		request := &UserUpdateRequest{}
+		if err := utils.ReadPatch(c, NewUserUpdateRequest(current), request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		// The patch was computed against the fetched document, only save it while that is still stored
+		if _, err := h.authUsecase.UserUpdate(ctx, &domain.UserUpdate{ID: me.ID, Username: request.Username, Version: &current.Version}); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		user, err := h.authUsecase.GetByID(ctx, me.ID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		c.Response().Header().Set(utils.HeaderETag, user.ETag())
+
+		return render.Respond(c, http.StatusOK, NewUser(user))
+	}
+}
+
+func (h *AuthHandler) DeleteUser() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.UserDeletion(c.Request().Context(), userID, version); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (h *AuthHandler) OIDCLogin() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		authorization, err := h.authUsecase.OIDCAuthorization(c.Request().Context(), c.Param("provider"), nil)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		utils.SetOIDCStateCookie(c, h.cfg, authorization.State)
+
+		return c.Redirect(http.StatusFound, authorization.AuthorizationURL)
+	}
+}
+
+func (h *AuthHandler) OIDCLink() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		authorization, err := h.authUsecase.OIDCAuthorization(c.Request().Context(), c.Param("provider"), &me.ID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		utils.SetOIDCStateCookie(c, h.cfg, authorization.State)
+
+		return render.Respond(c, http.StatusOK, &OIDCAuthorization{AuthorizationURL: authorization.AuthorizationURL})
+	}
+}
+
+func (h *AuthHandler) OIDCCallback() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		callback := &domain.OIDCCallback{}
+		if err := utils.ReadRequest(c, callback); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		callback.BrowserState = utils.OIDCStateFromCookie(c)
+		utils.DeleteSessionCookie(c, utils.OIDCStateCookieName)
+
+		userWithToken, err := h.authUsecase.OIDCCallback(c.Request().Context(), c.Param("provider"), callback)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		utils.SetSessionCookie(c, h.cfg, userWithToken.SessionID)
+
+		return render.Respond(c, http.StatusOK, NewSession(userWithToken))
+	}
+}
+
+func (h *AuthHandler) EnrollMFA() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		enrollment, err := h.authUsecase.MFAEnrollment(c.Request().Context(), me)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, &MFAEnrollment{Secret: enrollment.Secret, ProvisioningURI: enrollment.ProvisioningURI})
+	}
+}
+
+func (h *AuthHandler) ConfirmMFA() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		request := &MFACodeRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		recoveryCodes, err := h.authUsecase.MFAConfirmation(c.Request().Context(), me.ID, request.Code)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, &MFARecoveryCodes{RecoveryCodes: recoveryCodes.RecoveryCodes})
+	}
+}
+
+func (h *AuthHandler) DisableMFA() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		request := &MFACodeRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.MFADisable(c.Request().Context(), me, request.Code); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (h *AuthHandler) VerifyMFA() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := &MFAVerificationRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		userWithToken, err := h.authUsecase.MFAVerification(c.Request().Context(), &domain.MFAVerification{
+			MFAToken: request.MFAToken,
+			Code:     request.Code,
+		})
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		utils.SetSessionCookie(c, h.cfg, userWithToken.SessionID)
+
+		return render.Respond(c, http.StatusOK, NewSession(userWithToken))
+	}
+}
+
+func (h *AuthHandler) CreateRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := &RoleRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		createdRole, err := h.authUsecase.RoleCreation(c.Request().Context(), &domain.Role{Name: request.Name, Permissions: request.Permissions})
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusCreated, NewRole(createdRole))
+	}
+}
+
+func (h *AuthHandler) ListRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		roleList, err := h.authUsecase.RoleList(c.Request().Context())
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, NewRolePage(roleList))
+	}
+}
+
+func (h *AuthHandler) AssignRole() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		request := &RoleAssignmentRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.RoleAssignment(c.Request().Context(), userID, request.Role); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (h *AuthHandler) CreateInvite() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		request := &InviteRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		invite, err := h.authUsecase.InviteCreation(c.Request().Context(), me.ID, &domain.InviteRequest{Role: request.Role})
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusCreated, NewInvite(invite))
+	}
+}
+
+func (h *AuthHandler) CreateAPIKey() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		request := &APIKeyRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		created, err := h.authUsecase.APIKeyCreation(c.Request().Context(), me.ID, &domain.APIKeyRequest{
+			Name:      request.Name,
+			Scopes:    request.Scopes,
+			ExpiresAt: request.ExpiresAt,
+		})
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		apiKey := NewAPIKey(created.APIKey)
+		apiKey.Key = created.Key
+
+		return render.Respond(c, http.StatusCreated, apiKey)
+	}
+}
+
+func (h *AuthHandler) ListAPIKey() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		apiKeyList, err := h.authUsecase.APIKeyList(c.Request().Context(), me.ID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, NewAPIKeyPage(apiKeyList))
+	}
+}
+
+func (h *AuthHandler) RevokeAPIKey() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		keyID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.APIKeyRevocation(c.Request().Context(), me, keyID); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (h *AuthHandler) ListLoginLock() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		loginLockList, err := h.authUsecase.LoginLockList(c.Request().Context())
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, NewLoginLockPage(loginLockList))
+	}
+}
+
+func (h *AuthHandler) ReleaseLoginLock() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		lockID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.LoginLockRelease(c.Request().Context(), me, lockID); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (h *AuthHandler) ListSession() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		sessionList, err := h.authUsecase.SessionList(c.Request().Context(), me.ID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, NewUserSessionPage(sessionList))
+	}
+}
+
+func (h *AuthHandler) RevokeSession() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		me, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		sessionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.authUsecase.SessionRevocation(c.Request().Context(), me, sessionID); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (h *AuthHandler) ListUser() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		paginationQuery, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		usersList, err := h.authUsecase.UserList(c.Request().Context(), paginationQuery)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if utils.NotModified(c, usersList.ETag()) {
+			return c.NoContent(http.StatusNotModified)
+		}
+
+		return render.Respond(c, http.StatusOK, NewUserPage(usersList))
+	}
+}
+
+func (h *AuthHandler) DetailUser() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		user, err := h.authUsecase.GetByID(c.Request().Context(), userID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if utils.NotModified(c, user.ETag()) {
+			return c.NoContent(http.StatusNotModified)
+		}
+
+		return render.Respond(c, http.StatusOK, NewUser(user))
+	}
+}
+
+func (h *AuthHandler) CatchMonster() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		request := &CatchRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		monsterID, err := primitive.ObjectIDFromHex(request.MonsterID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, "invalid monster_id", err))
+		}
+
+		ctx := c.Request().Context()
+		if err := h.authUsecase.UserCatchMonster(ctx, userID, monsterID); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		user, err := h.authUsecase.GetByID(ctx, userID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, NewUser(user))
+	}
+}
+
+func (h *AuthHandler) Me() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return utils.ErrResponseWithLog(c, h.logger, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+		}
+
+		return render.Respond(c, http.StatusOK, NewUser(user))
+	}
+}
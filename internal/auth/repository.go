@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"time"
 
 	"github.com/iamaul/go-pokedex/internal/domain"
 	"github.com/iamaul/go-pokedex/pkg/utils"
@@ -9,11 +10,111 @@ import (
 )
 
 type Repository interface {
+	CreateIndexes(ctx context.Context) error
 	CreateUser(ctx context.Context, user *domain.User) (*domain.User, error)
 	UpdateUser(ctx context.Context, user *domain.UserUpdate) (*domain.UserUpdate, error)
-	DeleteUser(ctx context.Context, userID primitive.ObjectID) error
+	UpdatePassword(ctx context.Context, userID primitive.ObjectID, password string) error
+	UpdateRole(ctx context.Context, userID primitive.ObjectID, role string) error
+	DeleteUser(ctx context.Context, userID primitive.ObjectID, version *int64) error
 	FetchUsers(ctx context.Context, pq *utils.PaginationQuery) (*domain.UserList, error)
 	FindByID(ctx context.Context, userID primitive.ObjectID) (*domain.User, error)
 	FindByUsername(ctx context.Context, username string) (*domain.User, error)
+	FindByIdentity(ctx context.Context, provider, subject string) (*domain.User, error)
+	AddIdentity(ctx context.Context, userID primitive.ObjectID, identity *domain.UserIdentity) error
 	AddMonster(ctx context.Context, userID, monsterID primitive.ObjectID) error
 }
+
+type RefreshTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) (*domain.RefreshToken, error)
+	FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, tokenID primitive.ObjectID) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID primitive.ObjectID) error
+	RevokeUserRefreshTokens(ctx context.Context, userID primitive.ObjectID) error
+}
+
+type RoleRepository interface {
+	CreateIndexes(ctx context.Context) error
+	CreateRole(ctx context.Context, role *domain.Role) (*domain.Role, error)
+	EnsureRole(ctx context.Context, name string, permissions []string) error
+	FetchRoles(ctx context.Context) ([]*domain.Role, error)
+	FindByName(ctx context.Context, name string) (*domain.Role, error)
+	FindPermissions(ctx context.Context, name string) ([]string, error)
+}
+
+type InviteRepository interface {
+	CreateIndexes(ctx context.Context) error
+	CreateInvite(ctx context.Context, invite *domain.Invite) (*domain.Invite, error)
+	UseInvite(ctx context.Context, inviteID primitive.ObjectID, username string) (*domain.Invite, error)
+	ReleaseInvite(ctx context.Context, inviteID primitive.ObjectID, username string) error
+}
+
+type APIKeyRepository interface {
+	CreateIndexes(ctx context.Context) error
+	CreateAPIKey(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error)
+	FindAPIKeyByID(ctx context.Context, keyID primitive.ObjectID) (*domain.APIKey, error)
+	FindAPIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	FetchAPIKeys(ctx context.Context, userID primitive.ObjectID) ([]*domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, keyID primitive.ObjectID) error
+	TouchAPIKey(ctx context.Context, keyID primitive.ObjectID, usedAt time.Time) error
+}
+
+type OIDCStateRepository interface {
+	CreateIndexes(ctx context.Context) error
+	CreateOIDCState(ctx context.Context, state *domain.OIDCState) (*domain.OIDCState, error)
+	UseOIDCState(ctx context.Context, stateHash string) (*domain.OIDCState, error)
+}
+
+type MFARepository interface {
+	CreateIndexes(ctx context.Context) error
+	SaveMFA(ctx context.Context, mfa *domain.MFA) error
+	FindMFA(ctx context.Context, userID primitive.ObjectID) (*domain.MFA, error)
+	EnableMFA(ctx context.Context, userID primitive.ObjectID, recoveryCodes []string, step int64) error
+	UseStep(ctx context.Context, userID primitive.ObjectID, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID primitive.ObjectID, codeHash string) (bool, error)
+	DeleteMFA(ctx context.Context, userID primitive.ObjectID) error
+}
+
+type MFAChallengeRepository interface {
+	CreateIndexes(ctx context.Context) error
+	CreateMFAChallenge(ctx context.Context, challenge *domain.MFAChallenge) (*domain.MFAChallenge, error)
+	AttemptMFAChallenge(ctx context.Context, tokenHash string, maxAttempts int) (*domain.MFAChallenge, error)
+	DeleteMFAChallenge(ctx context.Context, challengeID primitive.ObjectID) error
+	DeleteUserMFAChallenges(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// Session store, kept in mongo or in memory for a single instance
+type SessionRepository interface {
+	CreateIndexes(ctx context.Context) error
+	CreateSession(ctx context.Context, session *domain.Session) (*domain.Session, error)
+	FindSessionByID(ctx context.Context, sessionID primitive.ObjectID) (*domain.Session, error)
+	FindSessionByHash(ctx context.Context, tokenHash string) (*domain.Session, error)
+	FetchSessions(ctx context.Context, userID primitive.ObjectID) ([]*domain.Session, error)
+	TouchSession(ctx context.Context, sessionID primitive.ObjectID, seenAt, expiresAt time.Time) error
+	RevokeSession(ctx context.Context, sessionID primitive.ObjectID) error
+	RevokeUserSessions(ctx context.Context, userID primitive.ObjectID) error
+}
+
+type LoginLockRepository interface {
+	CreateIndexes(ctx context.Context) error
+	FindLoginLock(ctx context.Context, kind, value string) (*domain.LoginLock, error)
+	RecordLoginFailure(ctx context.Context, kind, value string, failedAt, expiresAt time.Time) (*domain.LoginLock, error)
+	DelayLogin(ctx context.Context, lockID primitive.ObjectID, nextAttemptAt time.Time) error
+	LockLogin(ctx context.Context, lockID primitive.ObjectID, threshold int, lockedUntil, expiresAt time.Time) (bool, error)
+	ClearLoginFailures(ctx context.Context, kind, value string) error
+	FetchLoginLocks(ctx context.Context) ([]*domain.LoginLock, error)
+	DeleteLoginLock(ctx context.Context, lockID primitive.ObjectID) (*domain.LoginLock, error)
+}
+
+type PasswordResetRepository interface {
+	CreateIndexes(ctx context.Context) error
+	CreatePasswordResetToken(ctx context.Context, token *domain.PasswordResetToken) (*domain.PasswordResetToken, error)
+	FindPasswordResetTokenByHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error)
+	UsePasswordResetToken(ctx context.Context, tokenID primitive.ObjectID) (bool, error)
+	InvalidateUserPasswordResetTokens(ctx context.Context, userID primitive.ObjectID) error
+}
+
+type TokenDenylistRepository interface {
+	CreateIndexes(ctx context.Context) error
+	CreateRevokedToken(ctx context.Context, token *domain.RevokedToken) error
+	IsRevoked(ctx context.Context, jti string, userID primitive.ObjectID, issuedAt time.Time) (bool, error)
+}
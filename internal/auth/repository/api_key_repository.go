@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type APIKeyRepo struct {
+	db *mongo.Collection
+}
+
+func NewAPIKeyRepo(db *mongo.Database) auth.APIKeyRepository {
+	return &APIKeyRepo{
+		db: db.Collection("api_keys"),
+	}
+}
+
+// Keys are looked up by hash on every request and listed per user
+func (r *APIKeyRepo) CreateIndexes(ctx context.Context) error {
+	_, err := r.db.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.Indexes.CreateMany")
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepo) CreateAPIKey(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	result, err := r.db.InsertOne(ctx, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.InsertOne")
+	}
+
+	key.ID = result.InsertedID.(primitive.ObjectID)
+
+	return key, nil
+}
+
+func (r *APIKeyRepo) FindAPIKeyByID(ctx context.Context, keyID primitive.ObjectID) (*domain.APIKey, error) {
+	var key domain.APIKey
+
+	if err := r.db.FindOne(ctx, bson.M{"_id": keyID}).Decode(&key); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.Wrap(err, httpErr.ErrNotFound)
+		}
+
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r *APIKeyRepo) FindAPIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+
+	if err := r.db.FindOne(ctx, bson.M{"key_hash": keyHash}).Decode(&key); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.Wrap(err, httpErr.ErrNotFound)
+		}
+
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r *APIKeyRepo) FetchAPIKeys(ctx context.Context, userID primitive.ObjectID) ([]*domain.APIKey, error) {
+	cursor, err := r.db.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, errors.Wrap(err, "db.Find")
+	}
+	defer cursor.Close(ctx)
+
+	keys := make([]*domain.APIKey, 0)
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, errors.Wrap(err, "cursor.All")
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeyRepo) RevokeAPIKey(ctx context.Context, keyID primitive.ObjectID) error {
+	if _, err := r.db.UpdateOne(ctx, bson.M{"_id": keyID, "revoked_at": nil}, bson.M{
+		"$set": bson.M{"revoked_at": time.Now()},
+	}); err != nil {
+		return errors.Wrap(err, "db.UpdateOne")
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepo) TouchAPIKey(ctx context.Context, keyID primitive.ObjectID, usedAt time.Time) error {
+	if _, err := r.db.UpdateOne(ctx, bson.M{"_id": keyID}, bson.M{
+		"$set": bson.M{"last_used_at": usedAt},
+	}); err != nil {
+		return errors.Wrap(err, "db.UpdateOne")
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/iamaul/go-pokedex/internal/auth"
 	"github.com/iamaul/go-pokedex/internal/domain"
@@ -25,7 +26,24 @@ func NewAuthRepo(db *mongo.Database) auth.Repository {
 	}
 }
 
+// An identity at a provider belongs to one user at most
+func (r *AuthRepo) CreateIndexes(ctx context.Context) error {
+	_, err := r.db.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "identities.provider", Value: 1}, {Key: "identities.subject", Value: 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+			"identities.subject": bson.M{"$exists": true},
+		}),
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.Indexes.CreateOne")
+	}
+
+	return nil
+}
+
 func (r *AuthRepo) CreateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
+	user.Version = 1
+
 	result, err := r.db.InsertOne(ctx, user)
 	if mongodb.IsDuplicate(err) {
 		return nil, errors.Wrap(err, httpErr.ErrUserAlreadyExists)
@@ -37,20 +55,53 @@ func (r *AuthRepo) CreateUser(ctx context.Context, user *domain.User) (*domain.U
 }
 
 func (r *AuthRepo) UpdateUser(ctx context.Context, user *domain.UserUpdate) (*domain.UserUpdate, error) {
-	updateQuery := bson.M{}
+	updateQuery := bson.M{"updated_at": time.Now()}
 
 	if user.Username != "" {
 		updateQuery["username"] = user.Username
 	}
 
-	_, err := r.db.UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$set": updateQuery})
-	return user, err
+	version, err := mongodb.UpdateVersioned(ctx, r.db, user.ID, user.Version, bson.M{"$set": updateQuery})
+	if err != nil {
+		return nil, err
+	}
+	user.Version = &version
+
+	return user, nil
 }
 
-func (r *AuthRepo) DeleteUser(ctx context.Context, userID primitive.ObjectID) error {
-	_, err := r.db.DeleteOne(ctx, bson.M{"_id": userID})
+func (r *AuthRepo) UpdatePassword(ctx context.Context, userID primitive.ObjectID, password string) error {
+	result, err := r.db.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{
+		"$set": bson.M{"password": password, "updated_at": time.Now()},
+		"$inc": bson.M{"version": 1},
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.UpdateOne")
+	}
+	if result.MatchedCount == 0 {
+		return errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
 
-	return err
+	return nil
+}
+
+func (r *AuthRepo) UpdateRole(ctx context.Context, userID primitive.ObjectID, role string) error {
+	result, err := r.db.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{
+		"$set": bson.M{"role": role, "updated_at": time.Now()},
+		"$inc": bson.M{"version": 1},
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.UpdateOne")
+	}
+	if result.MatchedCount == 0 {
+		return errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) DeleteUser(ctx context.Context, userID primitive.ObjectID, version *int64) error {
+	return mongodb.DeleteVersioned(ctx, r.db, userID, version)
 }
 
 func (r *AuthRepo) FetchUsers(ctx context.Context, pq *utils.PaginationQuery) (*domain.UserList, error) {
@@ -127,8 +178,47 @@ func (r *AuthRepo) FindByUsername(ctx context.Context, username string) (*domain
 	return &user, err
 }
 
+// Returns nil when no user has linked the identity
+func (r *AuthRepo) FindByIdentity(ctx context.Context, provider, subject string) (*domain.User, error) {
+	var user domain.User
+
+	err := r.db.FindOne(ctx, bson.M{
+		"identities": bson.M{"$elemMatch": bson.M{"provider": provider, "subject": subject}},
+	}).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *AuthRepo) AddIdentity(ctx context.Context, userID primitive.ObjectID, identity *domain.UserIdentity) error {
+	result, err := r.db.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{
+		"$push": bson.M{"identities": identity},
+		"$set":  bson.M{"updated_at": time.Now()},
+		"$inc":  bson.M{"version": 1},
+	})
+	if mongodb.IsDuplicate(err) {
+		return errors.Wrap(err, httpErr.ErrIdentityAlreadyLinked)
+	}
+	if err != nil {
+		return errors.Wrap(err, "db.UpdateOne")
+	}
+	if result.MatchedCount == 0 {
+		return errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+
+	return nil
+}
+
 func (r *AuthRepo) AddMonster(ctx context.Context, userID, monsterID primitive.ObjectID) error {
-	_, err := r.db.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{"$push": bson.M{"monsters": monsterID}})
+	_, err := r.db.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{
+		"$push": bson.M{"monsters": monsterID},
+		"$inc":  bson.M{"version": 1},
+	})
 
 	return err
 }
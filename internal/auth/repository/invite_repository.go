@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type InviteRepo struct {
+	db *mongo.Collection
+}
+
+func NewInviteRepo(db *mongo.Database) auth.InviteRepository {
+	return &InviteRepo{
+		db: db.Collection("invites"),
+	}
+}
+
+// Mongo removes invites once their expires_at has passed
+func (r *InviteRepo) CreateIndexes(ctx context.Context) error {
+	_, err := r.db.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.Indexes.CreateOne")
+	}
+
+	return nil
+}
+
+func (r *InviteRepo) CreateInvite(ctx context.Context, invite *domain.Invite) (*domain.Invite, error) {
+	result, err := r.db.InsertOne(ctx, invite)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.InsertOne")
+	}
+
+	invite.ID = result.InsertedID.(primitive.ObjectID)
+
+	return invite, nil
+}
+
+// Mark an unused, unexpired invite as used in one step so it can't be redeemed twice
+func (r *InviteRepo) UseInvite(ctx context.Context, inviteID primitive.ObjectID, username string) (*domain.Invite, error) {
+	now := time.Now()
+
+	var invite domain.Invite
+	err := r.db.FindOneAndUpdate(ctx,
+		bson.M{"_id": inviteID, "used_at": nil, "expires_at": bson.M{"$gt": now}},
+		bson.M{"$set": bson.M{"used_at": now, "used_by": username}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&invite)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.Wrap(err, httpErr.ErrNotFound)
+		}
+
+		return nil, err
+	}
+
+	return &invite, nil
+}
+
+// Undo UseInvite for a registration that failed, only while the invite is still held by the username
+func (r *InviteRepo) ReleaseInvite(ctx context.Context, inviteID primitive.ObjectID, username string) error {
+	_, err := r.db.UpdateOne(ctx,
+		bson.M{"_id": inviteID, "used_by": username},
+		bson.M{"$unset": bson.M{"used_at": "", "used_by": ""}},
+	)
+	if err != nil {
+		return errors.Wrap(err, "db.UpdateOne")
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/db/mongodb"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LoginLockRepo struct {
+	db *mongo.Collection
+}
+
+func NewLoginLockRepo(db *mongo.Database) auth.LoginLockRepository {
+	return &LoginLockRepo{
+		db: db.Collection("login_locks"),
+	}
+}
+
+// One counter per username or IP, mongo removes it once its expires_at has passed
+func (r *LoginLockRepo) CreateIndexes(ctx context.Context) error {
+	_, err := r.db.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "kind", Value: 1}, {Key: "value", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+		{
+			Keys: bson.D{{Key: "locked_until", Value: -1}},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.Indexes.CreateMany")
+	}
+
+	return nil
+}
+
+// Returns nil when nothing failed within the window
+func (r *LoginLockRepo) FindLoginLock(ctx context.Context, kind, value string) (*domain.LoginLock, error) {
+	var lock domain.LoginLock
+
+	err := r.db.FindOne(ctx, bson.M{"kind": kind, "value": value, "expires_at": bson.M{"$gt": time.Now()}}).Decode(&lock)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &lock, nil
+}
+
+// Count a failure, starting over when the previous ones have expired. Concurrent
+// failures each see their own count.
+func (r *LoginLockRepo) RecordLoginFailure(ctx context.Context, kind, value string, failedAt, expiresAt time.Time) (*domain.LoginLock, error) {
+	// Expired counters may still be waiting for the TTL monitor
+	if _, err := r.db.DeleteOne(ctx, bson.M{"kind": kind, "value": value, "expires_at": bson.M{"$lte": failedAt}}); err != nil {
+		return nil, errors.Wrap(err, "db.DeleteOne")
+	}
+
+	update := bson.M{
+		"$inc":         bson.M{"failures": 1},
+		"$set":         bson.M{"last_failure_at": failedAt},
+		"$max":         bson.M{"expires_at": expiresAt},
+		"$setOnInsert": bson.M{"lockouts": 0},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var lock domain.LoginLock
+	err := r.db.FindOneAndUpdate(ctx, bson.M{"kind": kind, "value": value}, update, opts).Decode(&lock)
+	// Two first failures raced to insert the counter, the loser updates the winner's
+	if mongodb.IsDuplicate(err) {
+		err = r.db.FindOneAndUpdate(ctx, bson.M{"kind": kind, "value": value}, update, opts).Decode(&lock)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "db.FindOneAndUpdate")
+	}
+
+	return &lock, nil
+}
+
+func (r *LoginLockRepo) DelayLogin(ctx context.Context, lockID primitive.ObjectID, nextAttemptAt time.Time) error {
+	if _, err := r.db.UpdateOne(ctx, bson.M{"_id": lockID}, bson.M{
+		"$max": bson.M{"next_attempt_at": nextAttemptAt},
+	}); err != nil {
+		return errors.Wrap(err, "db.UpdateOne")
+	}
+
+	return nil
+}
+
+// Lock once the failures reached the threshold, reports whether this call locked it so
+// concurrent failures lock and get recorded only once
+func (r *LoginLockRepo) LockLogin(ctx context.Context, lockID primitive.ObjectID, threshold int, lockedUntil, expiresAt time.Time) (bool, error) {
+	result, err := r.db.UpdateOne(ctx, bson.M{"_id": lockID, "failures": bson.M{"$gte": threshold}}, bson.M{
+		"$set": bson.M{"failures": 0, "locked_until": lockedUntil},
+		"$inc": bson.M{"lockouts": 1},
+		"$max": bson.M{"expires_at": expiresAt},
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "db.UpdateOne")
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *LoginLockRepo) ClearLoginFailures(ctx context.Context, kind, value string) error {
+	if _, err := r.db.DeleteOne(ctx, bson.M{"kind": kind, "value": value}); err != nil {
+		return errors.Wrap(err, "db.DeleteOne")
+	}
+
+	return nil
+}
+
+// Usernames and IPs locked right now, the most recently locked first
+func (r *LoginLockRepo) FetchLoginLocks(ctx context.Context) ([]*domain.LoginLock, error) {
+	cursor, err := r.db.Find(ctx, bson.M{"locked_until": bson.M{"$gt": time.Now()}}, options.Find().SetSort(bson.D{{Key: "locked_until", Value: -1}}))
+	if err != nil {
+		return nil, errors.Wrap(err, "db.Find")
+	}
+	defer cursor.Close(ctx)
+
+	locks := make([]*domain.LoginLock, 0)
+	if err := cursor.All(ctx, &locks); err != nil {
+		return nil, errors.Wrap(err, "cursor.All")
+	}
+
+	return locks, nil
+}
+
+func (r *LoginLockRepo) DeleteLoginLock(ctx context.Context, lockID primitive.ObjectID) (*domain.LoginLock, error) {
+	var lock domain.LoginLock
+
+	if err := r.db.FindOneAndDelete(ctx, bson.M{"_id": lockID}).Decode(&lock); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.Wrap(err, httpErr.ErrNotFound)
+		}
+
+		return nil, errors.Wrap(err, "db.FindOneAndDelete")
+	}
+
+	return &lock, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MFAChallengeRepo struct {
+	db *mongo.Collection
+}
+
+func NewMFAChallengeRepo(db *mongo.Database) auth.MFAChallengeRepository {
+	return &MFAChallengeRepo{
+		db: db.Collection("mfa_challenges"),
+	}
+}
+
+// Mongo removes challenges once their expires_at has passed
+func (r *MFAChallengeRepo) CreateIndexes(ctx context.Context) error {
+	_, err := r.db.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.Indexes.CreateMany")
+	}
+
+	return nil
+}
+
+func (r *MFAChallengeRepo) CreateMFAChallenge(ctx context.Context, challenge *domain.MFAChallenge) (*domain.MFAChallenge, error) {
+	result, err := r.db.InsertOne(ctx, challenge)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.InsertOne")
+	}
+
+	challenge.ID = result.InsertedID.(primitive.ObjectID)
+
+	return challenge, nil
+}
+
+// Count an attempt at an unexpired challenge that has attempts left, in one step so
+// concurrent guesses can't exceed the limit
+func (r *MFAChallengeRepo) AttemptMFAChallenge(ctx context.Context, tokenHash string, maxAttempts int) (*domain.MFAChallenge, error) {
+	var challenge domain.MFAChallenge
+
+	err := r.db.FindOneAndUpdate(ctx,
+		bson.M{"token_hash": tokenHash, "expires_at": bson.M{"$gt": time.Now()}, "attempts": bson.M{"$lt": maxAttempts}},
+		bson.M{"$inc": bson.M{"attempts": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&challenge)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.Wrap(err, httpErr.ErrNotFound)
+		}
+
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+func (r *MFAChallengeRepo) DeleteMFAChallenge(ctx context.Context, challengeID primitive.ObjectID) error {
+	if _, err := r.db.DeleteOne(ctx, bson.M{"_id": challengeID}); err != nil {
+		return errors.Wrap(err, "db.DeleteOne")
+	}
+
+	return nil
+}
+
+func (r *MFAChallengeRepo) DeleteUserMFAChallenges(ctx context.Context, userID primitive.ObjectID) error {
+	if _, err := r.db.DeleteMany(ctx, bson.M{"user_id": userID}); err != nil {
+		return errors.Wrap(err, "db.DeleteMany")
+	}
+
+	return nil
+}
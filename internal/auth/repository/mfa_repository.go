@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MFARepo struct {
+	db *mongo.Collection
+}
+
+func NewMFARepo(db *mongo.Database) auth.MFARepository {
+	return &MFARepo{
+		db: db.Collection("mfa"),
+	}
+}
+
+// A user has one enrollment at most
+func (r *MFARepo) CreateIndexes(ctx context.Context) error {
+	_, err := r.db.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.Indexes.CreateOne")
+	}
+
+	return nil
+}
+
+// Start over a pending enrollment, enabled ones are left alone
+func (r *MFARepo) SaveMFA(ctx context.Context, mfa *domain.MFA) error {
+	_, err := r.db.ReplaceOne(ctx, bson.M{"user_id": mfa.UserID, "enabled": false}, mfa, options.Replace().SetUpsert(true))
+	if err != nil {
+		return errors.Wrap(err, "db.ReplaceOne")
+	}
+
+	return nil
+}
+
+// Returns nil when the user has not started enrolling
+func (r *MFARepo) FindMFA(ctx context.Context, userID primitive.ObjectID) (*domain.MFA, error) {
+	var mfa domain.MFA
+
+	err := r.db.FindOne(ctx, bson.M{"user_id": userID}).Decode(&mfa)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &mfa, nil
+}
+
+func (r *MFARepo) EnableMFA(ctx context.Context, userID primitive.ObjectID, recoveryCodes []string, step int64) error {
+	result, err := r.db.UpdateOne(ctx, bson.M{"user_id": userID, "enabled": false}, bson.M{
+		"$set": bson.M{"enabled": true, "recovery_codes": recoveryCodes, "last_step": step, "enabled_at": time.Now()},
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.UpdateOne")
+	}
+	if result.MatchedCount == 0 {
+		return errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+
+	return nil
+}
+
+// Reports whether the step was unused, codes are accepted once
+func (r *MFARepo) UseStep(ctx context.Context, userID primitive.ObjectID, step int64) (bool, error) {
+	result, err := r.db.UpdateOne(ctx, bson.M{"user_id": userID, "last_step": bson.M{"$lt": step}}, bson.M{
+		"$set": bson.M{"last_step": step},
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "db.UpdateOne")
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// Reports whether the recovery code was unused, it is removed either way
+func (r *MFARepo) UseRecoveryCode(ctx context.Context, userID primitive.ObjectID, codeHash string) (bool, error) {
+	result, err := r.db.UpdateOne(ctx, bson.M{"user_id": userID, "recovery_codes": codeHash}, bson.M{
+		"$pull": bson.M{"recovery_codes": codeHash},
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "db.UpdateOne")
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *MFARepo) DeleteMFA(ctx context.Context, userID primitive.ObjectID) error {
+	if _, err := r.db.DeleteOne(ctx, bson.M{"user_id": userID}); err != nil {
+		return errors.Wrap(err, "db.DeleteOne")
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OIDCStateRepo struct {
+	db *mongo.Collection
+}
+
+func NewOIDCStateRepo(db *mongo.Database) auth.OIDCStateRepository {
+	return &OIDCStateRepo{
+		db: db.Collection("oidc_states"),
+	}
+}
+
+// Mongo removes abandoned sign-ins once their expires_at has passed
+func (r *OIDCStateRepo) CreateIndexes(ctx context.Context) error {
+	_, err := r.db.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "state_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.Indexes.CreateMany")
+	}
+
+	return nil
+}
+
+func (r *OIDCStateRepo) CreateOIDCState(ctx context.Context, state *domain.OIDCState) (*domain.OIDCState, error) {
+	result, err := r.db.InsertOne(ctx, state)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.InsertOne")
+	}
+
+	state.ID = result.InsertedID.(primitive.ObjectID)
+
+	return state, nil
+}
+
+// Remove an unexpired state in one step so a callback can't be replayed
+func (r *OIDCStateRepo) UseOIDCState(ctx context.Context, stateHash string) (*domain.OIDCState, error) {
+	var state domain.OIDCState
+
+	err := r.db.FindOneAndDelete(ctx, bson.M{"state_hash": stateHash, "expires_at": bson.M{"$gt": time.Now()}}).Decode(&state)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.Wrap(err, httpErr.ErrNotFound)
+		}
+
+		return nil, err
+	}
+
+	return &state, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PasswordResetRepo struct {
+	db *mongo.Collection
+}
+
+func NewPasswordResetRepo(db *mongo.Database) auth.PasswordResetRepository {
+	return &PasswordResetRepo{
+		db: db.Collection("password_reset_tokens"),
+	}
+}
+
+// Mongo removes tokens once their expires_at has passed
+func (r *PasswordResetRepo) CreateIndexes(ctx context.Context) error {
+	_, err := r.db.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.Indexes.CreateMany")
+	}
+
+	return nil
+}
+
+func (r *PasswordResetRepo) CreatePasswordResetToken(ctx context.Context, token *domain.PasswordResetToken) (*domain.PasswordResetToken, error) {
+	result, err := r.db.InsertOne(ctx, token)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.InsertOne")
+	}
+
+	token.ID = result.InsertedID.(primitive.ObjectID)
+
+	return token, nil
+}
+
+func (r *PasswordResetRepo) FindPasswordResetTokenByHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	var token domain.PasswordResetToken
+
+	if err := r.db.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.Wrap(err, httpErr.ErrNotFound)
+		}
+
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Reports whether this call used the token, false when it was already used
+func (r *PasswordResetRepo) UsePasswordResetToken(ctx context.Context, tokenID primitive.ObjectID) (bool, error) {
+	result, err := r.db.UpdateOne(ctx, bson.M{"_id": tokenID, "used_at": nil}, bson.M{
+		"$set": bson.M{"used_at": time.Now()},
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "db.UpdateOne")
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *PasswordResetRepo) InvalidateUserPasswordResetTokens(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.db.UpdateMany(ctx, bson.M{"user_id": userID, "used_at": nil}, bson.M{
+		"$set": bson.M{"used_at": time.Now()},
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.UpdateMany")
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type RefreshTokenRepo struct {
+	db *mongo.Collection
+}
+
+func NewRefreshTokenRepo(db *mongo.Database) auth.RefreshTokenRepository {
+	return &RefreshTokenRepo{
+		db: db.Collection("refresh_tokens"),
+	}
+}
+
+func (r *RefreshTokenRepo) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) (*domain.RefreshToken, error) {
+	result, err := r.db.InsertOne(ctx, token)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.InsertOne")
+	}
+
+	token.ID = result.InsertedID.(primitive.ObjectID)
+
+	return token, nil
+}
+
+func (r *RefreshTokenRepo) FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+
+	if err := r.db.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.Wrap(err, httpErr.ErrNotFound)
+		}
+
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Reports whether this call revoked the token, false when it was already revoked
+// e.g. by a concurrent refresh with the same token
+func (r *RefreshTokenRepo) RevokeRefreshToken(ctx context.Context, tokenID primitive.ObjectID) (bool, error) {
+	result, err := r.db.UpdateOne(ctx, bson.M{"_id": tokenID, "revoked_at": nil}, bson.M{
+		"$set": bson.M{"revoked_at": time.Now()},
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "db.UpdateOne")
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *RefreshTokenRepo) RevokeRefreshTokenFamily(ctx context.Context, familyID primitive.ObjectID) error {
+	_, err := r.db.UpdateMany(ctx, bson.M{"family_id": familyID, "revoked_at": nil}, bson.M{
+		"$set": bson.M{"revoked_at": time.Now()},
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.UpdateMany")
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepo) RevokeUserRefreshTokens(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.db.UpdateMany(ctx, bson.M{"user_id": userID, "revoked_at": nil}, bson.M{
+		"$set": bson.M{"revoked_at": time.Now()},
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.UpdateMany")
+	}
+
+	return nil
+}
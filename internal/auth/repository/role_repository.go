@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/db/mongodb"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RoleRepo struct {
+	db *mongo.Collection
+}
+
+func NewRoleRepo(db *mongo.Database) auth.RoleRepository {
+	return &RoleRepo{
+		db: db.Collection("roles"),
+	}
+}
+
+func (r *RoleRepo) CreateIndexes(ctx context.Context) error {
+	_, err := r.db.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.Indexes.CreateOne")
+	}
+
+	return nil
+}
+
+func (r *RoleRepo) CreateRole(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	result, err := r.db.InsertOne(ctx, role)
+	if mongodb.IsDuplicate(err) {
+		return nil, errors.Wrap(err, httpErr.ErrRoleAlreadyExists)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "db.InsertOne")
+	}
+
+	role.ID = result.InsertedID.(primitive.ObjectID)
+
+	return role, nil
+}
+
+// Create the role or add the permissions it is missing
+func (r *RoleRepo) EnsureRole(ctx context.Context, name string, permissions []string) error {
+	now := time.Now()
+	_, err := r.db.UpdateOne(ctx, bson.M{"name": name}, bson.M{
+		"$addToSet":    bson.M{"permissions": bson.M{"$each": permissions}},
+		"$set":         bson.M{"updated_at": now},
+		"$setOnInsert": bson.M{"created_at": now},
+	}, options.Update().SetUpsert(true))
+	if err != nil {
+		return errors.Wrap(err, "db.UpdateOne")
+	}
+
+	return nil
+}
+
+func (r *RoleRepo) FetchRoles(ctx context.Context) ([]*domain.Role, error) {
+	cursor, err := r.db.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, errors.Wrap(err, "db.Find")
+	}
+	defer cursor.Close(ctx)
+
+	roles := make([]*domain.Role, 0)
+	if err := cursor.All(ctx, &roles); err != nil {
+		return nil, errors.Wrap(err, "cursor.All")
+	}
+
+	return roles, nil
+}
+
+func (r *RoleRepo) FindByName(ctx context.Context, name string) (*domain.Role, error) {
+	var role domain.Role
+
+	if err := r.db.FindOne(ctx, bson.M{"name": name}).Decode(&role); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.Wrap(err, httpErr.ErrNotFound)
+		}
+
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+// No permissions when no role has the name, e.g. a user still referencing a removed role
+func (r *RoleRepo) FindPermissions(ctx context.Context, name string) ([]string, error) {
+	role, err := r.FindByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return role.Permissions, nil
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+)
+
+func roleDoc(name string, permissions ...string) bson.M {
+	perms := make(bson.A, 0, len(permissions))
+	for _, p := range permissions {
+		perms = append(perms, p)
+	}
+	return bson.M{"_id": primitive.NewObjectID(), "name": name, "permissions": perms}
+}
+
+func storedPermissions(t *testing.T, s *fakeServer, name string) []string {
+	t.Helper()
+
+	for _, doc := range s.documents("roles") {
+		if doc["name"] != name {
+			continue
+		}
+		var permissions []string
+		for _, p := range doc["permissions"].(bson.A) {
+			permissions = append(permissions, p.(string))
+		}
+		return permissions
+	}
+
+	t.Fatalf("no role %s stored", name)
+	return nil
+}
+
+func TestRoleRepo(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("create", func(t *testing.T) {
+		s := newFakeServer(t)
+		repo := NewRoleRepo(s.database(t))
+
+		role := &domain.Role{Name: "gym-leader", Permissions: []string{domain.PermMonsterRead, domain.PermMonsterWrite}}
+		role.PrepareCreate()
+		created, err := repo.CreateRole(ctx, role)
+		if err != nil {
+			t.Fatalf("CreateRole: %v", err)
+		}
+		if created.ID.IsZero() {
+			t.Error("created role has no id")
+		}
+		if got := storedPermissions(t, s, "gym-leader"); !reflect.DeepEqual(got, role.Permissions) {
+			t.Errorf("stored permissions %v, want %v", got, role.Permissions)
+		}
+	})
+
+	t.Run("create a taken name", func(t *testing.T) {
+		s := newFakeServer(t)
+		s.insert(t, "roles", roleDoc(domain.AdminRole, domain.Permissions...))
+		repo := NewRoleRepo(s.database(t))
+
+		_, err := repo.CreateRole(ctx, &domain.Role{Name: domain.AdminRole, Permissions: []string{domain.PermMonsterRead}})
+		if !mongo.IsDuplicateKeyError(err) || !strings.HasPrefix(err.Error(), httpErr.ErrRoleAlreadyExists) {
+			t.Fatalf("CreateRole: %v, want %q", err, httpErr.ErrRoleAlreadyExists)
+		}
+		if got := storedPermissions(t, s, domain.AdminRole); !reflect.DeepEqual(got, domain.Permissions) {
+			t.Errorf("existing role changed to %v", got)
+		}
+	})
+
+	t.Run("ensure creates a missing role", func(t *testing.T) {
+		s := newFakeServer(t)
+		repo := NewRoleRepo(s.database(t))
+
+		if err := repo.EnsureRole(ctx, domain.DefaultRole, domain.DefaultPermissions); err != nil {
+			t.Fatalf("EnsureRole: %v", err)
+		}
+		if got := storedPermissions(t, s, domain.DefaultRole); !reflect.DeepEqual(got, domain.DefaultPermissions) {
+			t.Errorf("permissions = %v, want %v", got, domain.DefaultPermissions)
+		}
+	})
+
+	t.Run("ensure keeps granted permissions", func(t *testing.T) {
+		s := newFakeServer(t)
+		s.insert(t, "roles", roleDoc(domain.DefaultRole, domain.PermWebhookRead, domain.PermMonsterRead))
+		repo := NewRoleRepo(s.database(t))
+
+		if err := repo.EnsureRole(ctx, domain.DefaultRole, []string{domain.PermMonsterRead, domain.PermUserRead}); err != nil {
+			t.Fatalf("EnsureRole: %v", err)
+		}
+
+		want := []string{domain.PermWebhookRead, domain.PermMonsterRead, domain.PermUserRead}
+		if got := storedPermissions(t, s, domain.DefaultRole); !reflect.DeepEqual(got, want) {
+			t.Errorf("permissions = %v, want %v", got, want)
+		}
+		if n := len(s.documents("roles")); n != 1 {
+			t.Errorf("%d roles stored, want 1", n)
+		}
+	})
+
+	t.Run("fetch sorted by name", func(t *testing.T) {
+		s := newFakeServer(t)
+		s.insert(t, "roles", roleDoc(domain.DefaultRole, domain.DefaultPermissions...), roleDoc(domain.AdminRole, domain.Permissions...))
+		repo := NewRoleRepo(s.database(t))
+
+		roles, err := repo.FetchRoles(ctx)
+		if err != nil {
+			t.Fatalf("FetchRoles: %v", err)
+		}
+		if len(roles) != 2 {
+			t.Fatalf("fetched %d roles, want 2", len(roles))
+		}
+		if sort, ok := s.lastCommand("find").Lookup("sort", "name").AsInt64OK(); !ok || sort != 1 {
+			t.Errorf("find sorted by %s, want the name ascending", s.lastCommand("find").Lookup("sort"))
+		}
+	})
+
+	t.Run("fetch none", func(t *testing.T) {
+		s := newFakeServer(t)
+		repo := NewRoleRepo(s.database(t))
+
+		roles, err := repo.FetchRoles(ctx)
+		if err != nil {
+			t.Fatalf("FetchRoles: %v", err)
+		}
+		// Renders as an empty list rather than null
+		if roles == nil || len(roles) != 0 {
+			t.Errorf("roles = %#v, want an empty list", roles)
+		}
+	})
+
+	t.Run("find by name", func(t *testing.T) {
+		s := newFakeServer(t)
+		s.insert(t, "roles", roleDoc(domain.AdminRole, domain.Permissions...), roleDoc(domain.DefaultRole, domain.DefaultPermissions...))
+		repo := NewRoleRepo(s.database(t))
+
+		role, err := repo.FindByName(ctx, domain.DefaultRole)
+		if err != nil {
+			t.Fatalf("FindByName: %v", err)
+		}
+		if role.Name != domain.DefaultRole || !reflect.DeepEqual(role.Permissions, domain.DefaultPermissions) {
+			t.Errorf("found %+v, want the default role", role)
+		}
+	})
+
+	t.Run("find a missing name", func(t *testing.T) {
+		s := newFakeServer(t)
+		repo := NewRoleRepo(s.database(t))
+
+		_, err := repo.FindByName(ctx, "gym-leader")
+		if !errors.Is(err, mongo.ErrNoDocuments) || !strings.HasPrefix(err.Error(), httpErr.ErrNotFound) {
+			t.Errorf("FindByName: %v, want %q", err, httpErr.ErrNotFound)
+		}
+	})
+
+	t.Run("permissions", func(t *testing.T) {
+		s := newFakeServer(t)
+		s.insert(t, "roles", roleDoc(domain.AdminRole, domain.Permissions...))
+		repo := NewRoleRepo(s.database(t))
+
+		permissions, err := repo.FindPermissions(ctx, domain.AdminRole)
+		if err != nil {
+			t.Fatalf("FindPermissions: %v", err)
+		}
+		if !reflect.DeepEqual(permissions, domain.Permissions) {
+			t.Errorf("permissions = %v, want %v", permissions, domain.Permissions)
+		}
+	})
+
+	t.Run("permissions of a removed role", func(t *testing.T) {
+		s := newFakeServer(t)
+		repo := NewRoleRepo(s.database(t))
+
+		permissions, err := repo.FindPermissions(ctx, "gym-leader")
+		if err != nil || len(permissions) != 0 {
+			t.Errorf("FindPermissions = %v, %v, want no permissions and no error", permissions, err)
+		}
+	})
+
+	t.Run("permissions when the lookup fails", func(t *testing.T) {
+		s := newFakeServer(t)
+		s.insert(t, "roles", roleDoc(domain.AdminRole, domain.Permissions...))
+		s.failing["find"] = true
+		repo := NewRoleRepo(s.database(t))
+
+		if permissions, err := repo.FindPermissions(ctx, domain.AdminRole); err == nil {
+			t.Errorf("FindPermissions = %v, want the failed lookup", permissions)
+		}
+	})
+}
@@ -0,0 +1,341 @@
+package repository
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/wiremessage"
+)
+
+const testDatabase = "pokedex"
+
+// Standalone server speaking just enough of the wire protocol for the role repository, keeping
+// its collections in memory with a unique name per document
+type fakeServer struct {
+	listener net.Listener
+
+	mu          sync.Mutex
+	collections map[string][]bson.M
+	commands    []bsoncore.Document
+	// Commands answered with an error instead, by name
+	failing map[string]bool
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	s := &fakeServer{
+		listener:    listener,
+		collections: make(map[string][]bson.M),
+		failing:     make(map[string]bool),
+	}
+	go s.serve()
+
+	return s
+}
+
+func (s *fakeServer) database(t *testing.T) *mongo.Database {
+	t.Helper()
+
+	opts := options.Client().
+		ApplyURI("mongodb://" + s.listener.Addr().String()).
+		SetDirect(true).
+		SetServerAPIOptions(options.ServerAPI(options.ServerAPIVersion1))
+	client, err := mongo.Connect(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+
+	return client.Database(testDatabase)
+}
+
+func (s *fakeServer) insert(t *testing.T, coll string, docs ...bson.M) {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.collections[coll] = append(s.collections[coll], docs...)
+}
+
+func (s *fakeServer) documents(coll string) []bson.M {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]bson.M(nil), s.collections[coll]...)
+}
+
+// Last command received by the name, nil when there was none
+func (s *fakeServer) lastCommand(name string) bsoncore.Document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := len(s.commands) - 1; i >= 0; i-- {
+		if elem, err := s.commands[i].IndexErr(0); err == nil && elem.Key() == name {
+			return s.commands[i]
+		}
+	}
+	return nil
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return
+		}
+		msg := make([]byte, binary.LittleEndian.Uint32(size[:]))
+		copy(msg, size[:])
+		if _, err := io.ReadFull(conn, msg[4:]); err != nil {
+			return
+		}
+
+		_, requestID, _, opcode, rem, ok := wiremessage.ReadHeader(msg)
+		if !ok || opcode != wiremessage.OpMsg {
+			return
+		}
+		cmd, docs, ok := readMsg(rem)
+		if !ok {
+			return
+		}
+
+		reply, err := bson.Marshal(s.command(cmd, docs))
+		if err != nil {
+			return
+		}
+		idx, out := wiremessage.AppendHeaderStart(nil, wiremessage.NextRequestID(), requestID, wiremessage.OpMsg)
+		out = wiremessage.AppendMsgFlags(out, 0)
+		out = wiremessage.AppendMsgSectionType(out, wiremessage.SingleDocument)
+		out = append(out, reply...)
+		out = bsoncore.UpdateLength(out, idx, int32(len(out[idx:])))
+		if _, err := conn.Write(out); err != nil {
+			return
+		}
+	}
+}
+
+// Command document of an OP_MSG body and the documents of its sequence section
+func readMsg(rem []byte) (bsoncore.Document, []bsoncore.Document, bool) {
+	_, rem, ok := wiremessage.ReadMsgFlags(rem)
+	if !ok {
+		return nil, nil, false
+	}
+
+	var cmd bsoncore.Document
+	var docs []bsoncore.Document
+	for len(rem) > 0 {
+		var stype wiremessage.SectionType
+		if stype, rem, ok = wiremessage.ReadMsgSectionType(rem); !ok {
+			return nil, nil, false
+		}
+		switch stype {
+		case wiremessage.SingleDocument:
+			cmd, rem, ok = wiremessage.ReadMsgSectionSingleDocument(rem)
+		case wiremessage.DocumentSequence:
+			var sequence []bsoncore.Document
+			_, sequence, rem, ok = wiremessage.ReadMsgSectionDocumentSequence(rem)
+			docs = append(docs, sequence...)
+		default:
+			return nil, nil, false
+		}
+		if !ok {
+			return nil, nil, false
+		}
+	}
+
+	return cmd, docs, cmd != nil
+}
+
+// Documents of a command sent either in a sequence section or as an array of the command
+func statements(cmd bsoncore.Document, docs []bsoncore.Document, key string) []bsoncore.Document {
+	if docs != nil {
+		return docs
+	}
+	arr, ok := cmd.Lookup(key).ArrayOK()
+	if !ok {
+		return nil
+	}
+	values, _ := arr.Values()
+	for _, v := range values {
+		docs = append(docs, v.Document())
+	}
+	return docs
+}
+
+func (s *fakeServer) command(cmd bsoncore.Document, docs []bsoncore.Document) bson.D {
+	elems, err := cmd.Elements()
+	if err != nil || len(elems) == 0 {
+		return bson.D{{Key: "ok", Value: 0}, {Key: "errmsg", Value: "malformed command"}}
+	}
+	name := elems[0].Key()
+	coll, _ := elems[0].Value().StringValueOK()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch name {
+	case "hello", "isMaster", "ismaster":
+		return bson.D{
+			{Key: "helloOk", Value: true},
+			{Key: "isWritablePrimary", Value: true},
+			{Key: "maxBsonObjectSize", Value: 16 * 1024 * 1024},
+			{Key: "maxMessageSizeBytes", Value: 48000000},
+			{Key: "maxWriteBatchSize", Value: 100000},
+			{Key: "minWireVersion", Value: 0},
+			{Key: "maxWireVersion", Value: 17},
+			{Key: "ok", Value: 1},
+		}
+	case "endSessions":
+		return bson.D{{Key: "ok", Value: 1}}
+	}
+
+	s.commands = append(s.commands, append(bsoncore.Document(nil), cmd...))
+	if s.failing[name] {
+		return bson.D{{Key: "ok", Value: 0}, {Key: "errmsg", Value: "not authorized"}, {Key: "code", Value: 13}}
+	}
+
+	switch name {
+	case "find":
+		// Only an equality filter on the name is used
+		var batch bson.A
+		filter, filtered := cmd.Lookup("filter", "name").StringValueOK()
+		for _, doc := range s.collections[coll] {
+			if !filtered || doc["name"] == filter {
+				batch = append(batch, doc)
+			}
+		}
+		if batch == nil {
+			batch = bson.A{}
+		}
+		return bson.D{
+			{Key: "cursor", Value: bson.D{
+				{Key: "firstBatch", Value: batch},
+				{Key: "id", Value: int64(0)},
+				{Key: "ns", Value: testDatabase + "." + coll},
+			}},
+			{Key: "ok", Value: 1},
+		}
+	case "insert":
+		var n int
+		for i, raw := range statements(cmd, docs, "documents") {
+			var doc bson.M
+			if err := bson.Unmarshal(raw, &doc); err != nil {
+				return bson.D{{Key: "ok", Value: 0}, {Key: "errmsg", Value: err.Error()}}
+			}
+			if s.find(coll, doc["name"]) != nil {
+				return bson.D{
+					{Key: "n", Value: n},
+					{Key: "writeErrors", Value: bson.A{bson.D{
+						{Key: "index", Value: i},
+						{Key: "code", Value: 11000},
+						{Key: "errmsg", Value: "E11000 duplicate key error"},
+					}}},
+					{Key: "ok", Value: 1},
+				}
+			}
+			s.collections[coll] = append(s.collections[coll], doc)
+			n++
+		}
+		return bson.D{{Key: "n", Value: n}, {Key: "ok", Value: 1}}
+	case "update":
+		// Only $set, $setOnInsert and $addToSet with $each on a name filter are used
+		var n int
+		var upserted bson.A
+		for i, raw := range statements(cmd, docs, "updates") {
+			var statement struct {
+				Q      bson.M `bson:"q"`
+				U      bson.M `bson:"u"`
+				Upsert bool   `bson:"upsert"`
+			}
+			if err := bson.Unmarshal(raw, &statement); err != nil {
+				return bson.D{{Key: "ok", Value: 0}, {Key: "errmsg", Value: err.Error()}}
+			}
+
+			doc := s.find(coll, statement.Q["name"])
+			if doc == nil {
+				if !statement.Upsert {
+					continue
+				}
+				doc = bson.M{"_id": primitive.NewObjectID(), "name": statement.Q["name"]}
+				if onInsert, ok := statement.U["$setOnInsert"].(bson.M); ok {
+					for k, v := range onInsert {
+						doc[k] = v
+					}
+				}
+				s.collections[coll] = append(s.collections[coll], doc)
+				upserted = append(upserted, bson.D{{Key: "index", Value: i}, {Key: "_id", Value: doc["_id"]}})
+			}
+			if set, ok := statement.U["$set"].(bson.M); ok {
+				for k, v := range set {
+					doc[k] = v
+				}
+			}
+			if addToSet, ok := statement.U["$addToSet"].(bson.M); ok {
+				for k, v := range addToSet {
+					each, _ := v.(bson.M)["$each"].(bson.A)
+					current, _ := doc[k].(bson.A)
+					for _, item := range each {
+						if !containsItem(current, item) {
+							current = append(current, item)
+						}
+					}
+					doc[k] = current
+				}
+			}
+			n++
+		}
+		reply := bson.D{{Key: "n", Value: n}, {Key: "nModified", Value: n - len(upserted)}}
+		if upserted != nil {
+			reply = append(reply, bson.E{Key: "upserted", Value: upserted})
+		}
+		return append(reply, bson.E{Key: "ok", Value: 1})
+	default:
+		return bson.D{{Key: "ok", Value: 1}}
+	}
+}
+
+func (s *fakeServer) find(coll string, name interface{}) bson.M {
+	for _, doc := range s.collections[coll] {
+		if doc["name"] == name {
+			return doc
+		}
+	}
+	return nil
+}
+
+func containsItem(items bson.A, item interface{}) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Sessions kept in process, lost on restart and not shared between instances
+type SessionMemoryRepo struct {
+	mu       sync.Mutex
+	sessions map[primitive.ObjectID]*domain.Session
+	byHash   map[string]primitive.ObjectID
+}
+
+func NewSessionMemoryRepo() auth.SessionRepository {
+	return &SessionMemoryRepo{
+		sessions: make(map[primitive.ObjectID]*domain.Session),
+		byHash:   make(map[string]primitive.ObjectID),
+	}
+}
+
+func (r *SessionMemoryRepo) CreateIndexes(ctx context.Context) error {
+	return nil
+}
+
+func (r *SessionMemoryRepo) CreateSession(ctx context.Context, session *domain.Session) (*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune(time.Now())
+	if _, ok := r.byHash[session.TokenHash]; ok {
+		return nil, errors.New("SessionMemoryRepo.CreateSession: duplicate token hash")
+	}
+
+	stored := *session
+	r.sessions[session.ID] = &stored
+	r.byHash[session.TokenHash] = session.ID
+
+	return session, nil
+}
+
+func (r *SessionMemoryRepo) FindSessionByID(ctx context.Context, sessionID primitive.ObjectID) (*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[sessionID]
+	if !ok {
+		return nil, nil
+	}
+
+	found := *session
+	return &found, nil
+}
+
+func (r *SessionMemoryRepo) FindSessionByHash(ctx context.Context, tokenHash string) (*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sessionID, ok := r.byHash[tokenHash]
+	if !ok {
+		return nil, errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+
+	found := *r.sessions[sessionID]
+	return &found, nil
+}
+
+func (r *SessionMemoryRepo) FetchSessions(ctx context.Context, userID primitive.ObjectID) ([]*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sessions := make([]*domain.Session, 0)
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.IsActive() {
+			found := *session
+			sessions = append(sessions, &found)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+
+	return sessions, nil
+}
+
+func (r *SessionMemoryRepo) TouchSession(ctx context.Context, sessionID primitive.ObjectID, seenAt, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if session, ok := r.sessions[sessionID]; ok && session.RevokedAt == nil {
+		session.LastSeenAt = seenAt
+		session.ExpiresAt = expiresAt
+	}
+
+	return nil
+}
+
+func (r *SessionMemoryRepo) RevokeSession(ctx context.Context, sessionID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if session, ok := r.sessions[sessionID]; ok && session.RevokedAt == nil {
+		now := time.Now()
+		session.RevokedAt = &now
+	}
+
+	return nil
+}
+
+func (r *SessionMemoryRepo) RevokeUserSessions(ctx context.Context, userID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+		}
+	}
+
+	return nil
+}
+
+// Drop expired sessions, as the TTL index does in mongo
+func (r *SessionMemoryRepo) prune(now time.Time) {
+	for id, session := range r.sessions {
+		if !session.ExpiresAt.After(now) {
+			delete(r.byHash, session.TokenHash)
+			delete(r.sessions, id)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SessionRepo struct {
+	db *mongo.Collection
+}
+
+func NewSessionRepo(db *mongo.Database) auth.SessionRepository {
+	return &SessionRepo{
+		db: db.Collection("sessions"),
+	}
+}
+
+// Sessions are looked up by cookie hash and listed per user, mongo removes expired ones
+func (r *SessionRepo) CreateIndexes(ctx context.Context) error {
+	_, err := r.db.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "last_seen_at", Value: -1}},
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.Indexes.CreateMany")
+	}
+
+	return nil
+}
+
+func (r *SessionRepo) CreateSession(ctx context.Context, session *domain.Session) (*domain.Session, error) {
+	if _, err := r.db.InsertOne(ctx, session); err != nil {
+		return nil, errors.Wrap(err, "db.InsertOne")
+	}
+
+	return session, nil
+}
+
+// Returns nil when there is no such session, e.g. a refresh token family started before sessions
+func (r *SessionRepo) FindSessionByID(ctx context.Context, sessionID primitive.ObjectID) (*domain.Session, error) {
+	var session domain.Session
+
+	err := r.db.FindOne(ctx, bson.M{"_id": sessionID}).Decode(&session)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (r *SessionRepo) FindSessionByHash(ctx context.Context, tokenHash string) (*domain.Session, error) {
+	var session domain.Session
+
+	if err := r.db.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&session); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.Wrap(err, httpErr.ErrNotFound)
+		}
+
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// Active sessions of the user, the most recently used first
+func (r *SessionRepo) FetchSessions(ctx context.Context, userID primitive.ObjectID) ([]*domain.Session, error) {
+	cursor, err := r.db.Find(ctx,
+		bson.M{"user_id": userID, "revoked_at": nil, "expires_at": bson.M{"$gt": time.Now()}},
+		options.Find().SetSort(bson.D{{Key: "last_seen_at", Value: -1}}),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.Find")
+	}
+	defer cursor.Close(ctx)
+
+	sessions := make([]*domain.Session, 0)
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, errors.Wrap(err, "cursor.All")
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepo) TouchSession(ctx context.Context, sessionID primitive.ObjectID, seenAt, expiresAt time.Time) error {
+	if _, err := r.db.UpdateOne(ctx, bson.M{"_id": sessionID, "revoked_at": nil}, bson.M{
+		"$set": bson.M{"last_seen_at": seenAt, "expires_at": expiresAt},
+	}); err != nil {
+		return errors.Wrap(err, "db.UpdateOne")
+	}
+
+	return nil
+}
+
+func (r *SessionRepo) RevokeSession(ctx context.Context, sessionID primitive.ObjectID) error {
+	if _, err := r.db.UpdateOne(ctx, bson.M{"_id": sessionID, "revoked_at": nil}, bson.M{
+		"$set": bson.M{"revoked_at": time.Now()},
+	}); err != nil {
+		return errors.Wrap(err, "db.UpdateOne")
+	}
+
+	return nil
+}
+
+func (r *SessionRepo) RevokeUserSessions(ctx context.Context, userID primitive.ObjectID) error {
+	if _, err := r.db.UpdateMany(ctx, bson.M{"user_id": userID, "revoked_at": nil}, bson.M{
+		"$set": bson.M{"revoked_at": time.Now()},
+	}); err != nil {
+		return errors.Wrap(err, "db.UpdateMany")
+	}
+
+	return nil
+}
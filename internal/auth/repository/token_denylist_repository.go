@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TokenDenylistRepo struct {
+	db *mongo.Collection
+}
+
+func NewTokenDenylistRepo(db *mongo.Database) auth.TokenDenylistRepository {
+	return &TokenDenylistRepo{
+		db: db.Collection("revoked_tokens"),
+	}
+}
+
+// Mongo removes entries once their expires_at has passed
+func (r *TokenDenylistRepo) CreateIndexes(ctx context.Context) error {
+	_, err := r.db.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		{Keys: bson.D{{Key: "jti", Value: 1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}}},
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.Indexes.CreateMany")
+	}
+
+	return nil
+}
+
+func (r *TokenDenylistRepo) CreateRevokedToken(ctx context.Context, token *domain.RevokedToken) error {
+	if _, err := r.db.InsertOne(ctx, token); err != nil {
+		return errors.Wrap(err, "db.InsertOne")
+	}
+
+	return nil
+}
+
+// Revoked by its jti, or by revoking every token of the user at or after the second it was issued
+func (r *TokenDenylistRepo) IsRevoked(ctx context.Context, jti string, userID primitive.ObjectID, issuedAt time.Time) (bool, error) {
+	filter := bson.A{
+		bson.M{"user_id": userID, "jti": bson.M{"$exists": false}, "created_at": bson.M{"$gte": issuedAt}},
+	}
+	if jti != "" {
+		filter = append(filter, bson.M{"jti": jti})
+	}
+
+	count, err := r.db.CountDocuments(ctx, bson.M{"$or": filter}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, errors.Wrap(err, "db.CountDocuments")
+	}
+
+	return count > 0, nil
+}
@@ -9,11 +9,42 @@ import (
 )
 
 type Usecase interface {
-	UserRegistration(ctx context.Context, user *domain.User) (*domain.UserWithToken, error)
+	UserRegistration(ctx context.Context, registration *domain.UserRegistration) (*domain.UserWithToken, error)
 	UserAuthentication(ctx context.Context, user *domain.User) (*domain.UserWithToken, error)
 	UserUpdate(ctx context.Context, user *domain.UserUpdate) (*domain.UserUpdate, error)
-	UserDeletion(ctx context.Context, userID primitive.ObjectID) error
+	UserDeletion(ctx context.Context, userID primitive.ObjectID, version *int64) error
 	UserList(ctx context.Context, pq *utils.PaginationQuery) (*domain.UserList, error)
 	UserCatchMonster(ctx context.Context, userID, monsterID primitive.ObjectID) error
 	GetByID(ctx context.Context, userID primitive.ObjectID) (*domain.User, error)
+	TokenRefresh(ctx context.Context, refreshToken string) (*domain.UserWithToken, error)
+	UserLogout(ctx context.Context, claims *utils.Claims) error
+	UserSessionsRevocation(ctx context.Context, userID primitive.ObjectID) error
+	TokenValidation(tokenString string) (*utils.Claims, error)
+	TokenRevoked(ctx context.Context, claims *utils.Claims) (bool, error)
+	SessionAuthentication(ctx context.Context, sessionToken string) (*domain.Session, error)
+	SessionValidation(ctx context.Context, sessionID primitive.ObjectID) (*domain.Session, error)
+	TokenAuthentication(ctx context.Context, tokenString string) (*domain.User, *utils.Claims, *domain.Session, error)
+	SessionList(ctx context.Context, userID primitive.ObjectID) (*domain.SessionList, error)
+	SessionRevocation(ctx context.Context, user *domain.User, sessionID primitive.ObjectID) error
+	PasswordChange(ctx context.Context, userID primitive.ObjectID, change *domain.PasswordChange) (*domain.UserWithToken, error)
+	PasswordResetRequest(ctx context.Context, username string) error
+	PasswordReset(ctx context.Context, reset *domain.PasswordReset) error
+	RoleCreation(ctx context.Context, role *domain.Role) (*domain.Role, error)
+	RoleList(ctx context.Context) (*domain.RoleList, error)
+	RoleAssignment(ctx context.Context, userID primitive.ObjectID, role string) error
+	RoleBootstrap(ctx context.Context) error
+	InviteCreation(ctx context.Context, createdBy primitive.ObjectID, request *domain.InviteRequest) (*domain.InviteWithCode, error)
+	APIKeyCreation(ctx context.Context, userID primitive.ObjectID, request *domain.APIKeyRequest) (*domain.APIKeyWithSecret, error)
+	APIKeyList(ctx context.Context, userID primitive.ObjectID) (*domain.APIKeyList, error)
+	APIKeyRevocation(ctx context.Context, user *domain.User, keyID primitive.ObjectID) error
+	APIKeyAuthentication(ctx context.Context, key string) (*domain.User, *domain.APIKey, error)
+	OIDCAuthorization(ctx context.Context, providerName string, linkUserID *primitive.ObjectID) (*domain.OIDCAuthorization, error)
+	OIDCCallback(ctx context.Context, providerName string, callback *domain.OIDCCallback) (*domain.UserWithToken, error)
+	LoginLockList(ctx context.Context) (*domain.LoginLockList, error)
+	LoginLockRelease(ctx context.Context, releasedBy *domain.User, lockID primitive.ObjectID) error
+	MFAEnrollment(ctx context.Context, user *domain.User) (*domain.MFAEnrollment, error)
+	MFAConfirmation(ctx context.Context, userID primitive.ObjectID, code string) (*domain.MFARecoveryCodes, error)
+	MFADisable(ctx context.Context, user *domain.User, code string) error
+	MFAVerification(ctx context.Context, verification *domain.MFAVerification) (*domain.UserWithToken, error)
+	HasPermission(ctx context.Context, user *domain.User, permission string) (bool, error)
 }
@@ -2,86 +2,204 @@ package usecase
 
 import (
 	"context"
+	"crypto/subtle"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/audit"
 	"github.com/iamaul/go-pokedex/internal/auth"
 	"github.com/iamaul/go-pokedex/internal/domain"
 	"github.com/iamaul/go-pokedex/internal/monster"
 	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/events"
+	"github.com/iamaul/go-pokedex/pkg/keyring"
 	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/notifier"
+	"github.com/iamaul/go-pokedex/pkg/oidc"
+	"github.com/iamaul/go-pokedex/pkg/totp"
 	"github.com/iamaul/go-pokedex/pkg/utils"
 	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	// How stale the recorded last use of an API key or session may get
+	apiKeyTouchInterval  = time.Minute
+	sessionTouchInterval = time.Minute
+
+	// Codes a two-factor challenge accepts before it has to be started over with the password
+	mfaMaxAttempts = 5
+
+	recoveryCodeCount = 10
+)
+
 type AuthUsecase struct {
-	cfg         *config.Config
-	authRepo    auth.Repository
-	monsterRepo monster.MonsterRepository
-	logger      logger.Logger
+	cfg              *config.Config
+	authRepo         auth.Repository
+	refreshTokenRepo auth.RefreshTokenRepository
+	denylistRepo     auth.TokenDenylistRepository
+	resetRepo        auth.PasswordResetRepository
+	roleRepo         auth.RoleRepository
+	inviteRepo       auth.InviteRepository
+	apiKeyRepo       auth.APIKeyRepository
+	oidcStateRepo    auth.OIDCStateRepository
+	mfaRepo          auth.MFARepository
+	mfaChallengeRepo auth.MFAChallengeRepository
+	loginLockRepo    auth.LoginLockRepository
+	sessionRepo      auth.SessionRepository
+	monsterRepo      monster.MonsterRepository
+	notifier         notifier.Notifier
+	oidcProviders    oidc.Providers
+	keyRing          *keyring.KeyRing
+	auditUsecase     audit.Usecase
+	publisher        events.Publisher
+	logger           logger.Logger
+	revocations      *revocationCache
+}
+
+// Stores the auth usecase reads and writes
+type Repositories struct {
+	Auth         auth.Repository
+	RefreshToken auth.RefreshTokenRepository
+	Denylist     auth.TokenDenylistRepository
+	Reset        auth.PasswordResetRepository
+	Role         auth.RoleRepository
+	Invite       auth.InviteRepository
+	APIKey       auth.APIKeyRepository
+	OIDCState    auth.OIDCStateRepository
+	MFA          auth.MFARepository
+	MFAChallenge auth.MFAChallengeRepository
+	LoginLock    auth.LoginLockRepository
+	Session      auth.SessionRepository
+	Monster      monster.MonsterRepository
+}
+
+// Services the auth usecase hands work to
+type Services struct {
+	Notifier      notifier.Notifier
+	OIDCProviders oidc.Providers
+	KeyRing       *keyring.KeyRing
+	Audit         audit.Usecase
+	Publisher     events.Publisher
 }
 
-func NewAuthUsecase(cfg *config.Config, authRepo auth.Repository, monsterRepo monster.MonsterRepository, log logger.Logger) auth.Usecase {
-	return &AuthUsecase{cfg: cfg, authRepo: authRepo, monsterRepo: monsterRepo, logger: log}
+func NewAuthUsecase(cfg *config.Config, repos Repositories, services Services, log logger.Logger) auth.Usecase {
+	return &AuthUsecase{
+		cfg:              cfg,
+		authRepo:         repos.Auth,
+		refreshTokenRepo: repos.RefreshToken,
+		denylistRepo:     repos.Denylist,
+		resetRepo:        repos.Reset,
+		roleRepo:         repos.Role,
+		inviteRepo:       repos.Invite,
+		apiKeyRepo:       repos.APIKey,
+		oidcStateRepo:    repos.OIDCState,
+		mfaRepo:          repos.MFA,
+		mfaChallengeRepo: repos.MFAChallenge,
+		loginLockRepo:    repos.LoginLock,
+		sessionRepo:      repos.Session,
+		monsterRepo:      repos.Monster,
+		notifier:         services.Notifier,
+		oidcProviders:    services.OIDCProviders,
+		keyRing:          services.KeyRing,
+		auditUsecase:     services.Audit,
+		publisher:        services.Publisher,
+		logger:           log,
+		revocations:      newRevocationCache(utils.AccessTokenTTL(cfg)),
+	}
 }
 
-func (u *AuthUsecase) UserRegistration(ctx context.Context, user *domain.User) (*domain.UserWithToken, error) {
-	_, err := u.authRepo.FindByUsername(ctx, user.Username)
+func (u *AuthUsecase) UserRegistration(ctx context.Context, registration *domain.UserRegistration) (*domain.UserWithToken, error) {
+	_, err := u.authRepo.FindByUsername(ctx, registration.Username)
 	if err == nil {
 		return nil, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, httpErr.ErrUserAlreadyExists, err)
 	}
 
+	user := &domain.User{Username: registration.Username, Password: registration.Password}
 	if err := user.PrepareCreate(); err != nil {
 		return nil, httpErr.NewBadRequestError(errors.Wrap(err, "AuthUsecase.UserRegistration.PrepareCreate"))
 	}
 
+	// The role never comes from the request, only from an invite issued by an administrator.
+	// The invite is claimed before the insert so it can't be redeemed twice, and handed back
+	// when the insert fails.
+	role := domain.DefaultRole
+	var inviteID *primitive.ObjectID
+	if registration.InviteCode != "" {
+		id, err := utils.ParseInviteCode(registration.InviteCode, u.cfg)
+		if err != nil {
+			return nil, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, httpErr.InvalidInviteCode.Error(), err)
+		}
+
+		invite, err := u.inviteRepo.UseInvite(ctx, id, registration.Username)
+		if err != nil {
+			return nil, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, httpErr.InvalidInviteCode.Error(), err)
+		}
+		role = invite.Role
+		inviteID = &id
+	}
+	user.Role = &role
+
 	createdUser, err := u.authRepo.CreateUser(ctx, user)
 	if err != nil {
+		if inviteID != nil {
+			if err := u.inviteRepo.ReleaseInvite(ctx, *inviteID, registration.Username); err != nil {
+				u.logger.Errorf("AuthUsecase.UserRegistration.ReleaseInvite: %v", err)
+			}
+		}
 		return nil, err
 	}
 
 	// Set value of password payload to empty for a security reason
 	createdUser.SanitizePassword()
 
-	token, err := utils.GenerateJWTToken(createdUser, u.cfg)
-	if err != nil {
-		return nil, httpErr.NewInternalServerError(errors.Wrap(err, "AuthUsecase.UserRegistration.GenerateJWTToken"))
-	}
+	u.auditUsecase.Record(ctx, selfAuditEntry(createdUser, domain.AuditUserCreated), nil, createdUser)
 
-	return &domain.UserWithToken{
-		User:  createdUser,
-		Token: token,
-	}, nil
+	return u.startSession(ctx, createdUser)
 }
 
 func (u *AuthUsecase) UserAuthentication(ctx context.Context, user *domain.User) (*domain.UserWithToken, error) {
+	targets := loginTargets(ctx, user.Username)
+	if err := u.loginAllowed(ctx, targets); err != nil {
+		return nil, err
+	}
+
 	foundUser, err := u.authRepo.FindByUsername(ctx, user.Username)
 	if err != nil {
+		// Unknown usernames are counted as well, guessing them is not free either
+		u.loginFailed(ctx, targets)
 		return nil, err
 	}
 
 	if err = foundUser.ComparePasswords(user.Password); err != nil {
+		u.loginFailed(ctx, targets)
 		return nil, httpErr.NewUnauthorizedError(errors.Wrap(err, "AuthUsecase.UserAuthentication.ComparePasswords"))
 	}
 
 	// Set value of password payload to empty for a security reason
 	foundUser.SanitizePassword()
 
-	token, err := utils.GenerateJWTToken(foundUser, u.cfg)
+	userWithToken, err := u.completeAuthentication(ctx, foundUser)
 	if err != nil {
-		return nil, httpErr.NewInternalServerError(errors.Wrap(err, "AuthUsecase.UserAuthentication.GenerateJWTToken"))
+		return nil, err
 	}
 
-	return &domain.UserWithToken{
-		User:  foundUser,
-		Token: token,
-	}, nil
+	// With two-factor authentication the failures are kept until the code was accepted
+	if !userWithToken.MFARequired {
+		u.loginSucceeded(ctx, foundUser.Username)
+	}
+
+	return userWithToken, nil
 }
 
 func (u *AuthUsecase) UserUpdate(ctx context.Context, user *domain.UserUpdate) (*domain.UserUpdate, error) {
-	if err := user.PrepareUpdate(); err != nil {
-		return nil, httpErr.NewBadRequestError(errors.Wrap(err, "AuthUsecase.UserUpdate.PrepareUpdate"))
+	before, err := u.GetByID(ctx, user.ID)
+	if err != nil {
+		return nil, err
 	}
 
 	updatedUser, err := u.authRepo.UpdateUser(ctx, user)
@@ -89,14 +207,31 @@ func (u *AuthUsecase) UserUpdate(ctx context.Context, user *domain.UserUpdate) (
 		return nil, err
 	}
 
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditUserUpdated,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID.Hex(),
+	}, before, updatedUser)
+
 	return updatedUser, nil
 }
 
-func (u *AuthUsecase) UserDeletion(ctx context.Context, userID primitive.ObjectID) error {
-	if err := u.authRepo.DeleteUser(ctx, userID); err != nil {
+func (u *AuthUsecase) UserDeletion(ctx context.Context, userID primitive.ObjectID, version *int64) error {
+	before, err := u.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.authRepo.DeleteUser(ctx, userID, version); err != nil {
 		return err
 	}
 
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditUserDeleted,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userID.Hex(),
+	}, before, nil)
+
 	return nil
 }
 
@@ -113,6 +248,16 @@ func (u *AuthUsecase) UserCatchMonster(ctx context.Context, userID primitive.Obj
 	if err := u.authRepo.AddMonster(ctx, userID, monster.ID); err != nil {
 		return err
 	}
+
+	caught := &domain.MonsterCaught{UserID: userID, MonsterID: monster.ID}
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditUserMonsterCaught,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userID.Hex(),
+	}, nil, caught)
+
+	u.publisher.Publish(ctx, events.UserMonsterCaught, caught)
+
 	return nil
 }
 
@@ -127,3 +272,1269 @@ func (u *AuthUsecase) GetByID(ctx context.Context, userID primitive.ObjectID) (*
 
 	return user, nil
 }
+
+func (u *AuthUsecase) TokenRefresh(ctx context.Context, refreshToken string) (*domain.UserWithToken, error) {
+	token, err := u.refreshTokenRepo.FindRefreshTokenByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		return nil, httpErr.NewUnauthorizedError(errors.Wrap(err, "AuthUsecase.TokenRefresh.FindRefreshTokenByHash"))
+	}
+
+	// An expired token is refused as it is, it neither rotates nor counts as reuse
+	if token.IsExpired() {
+		return nil, httpErr.NewUnauthorizedError(errors.New("AuthUsecase.TokenRefresh.IsExpired"))
+	}
+
+	// Presenting a token that was already rotated means it leaked, only the first of
+	// concurrent refreshes with the same token wins the rotation
+	rotated := false
+	if token.RevokedAt == nil {
+		if rotated, err = u.refreshTokenRepo.RevokeRefreshToken(ctx, token.ID); err != nil {
+			return nil, err
+		}
+	}
+	if !rotated {
+		if err := u.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, token.FamilyID); err != nil {
+			return nil, err
+		}
+		u.logger.Warnf("refresh token reuse detected, revoked token family %s of user %s", token.FamilyID.Hex(), token.UserID.Hex())
+
+		return nil, httpErr.NewUnauthorizedError(httpErr.Unauthorized)
+	}
+
+	session, err := u.refreshSession(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := u.authRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return nil, httpErr.NewUnauthorizedError(errors.Wrap(err, "AuthUsecase.TokenRefresh.FindByID"))
+	}
+
+	// Set value of password payload to empty for a security reason
+	user.SanitizePassword()
+
+	return u.issueTokens(ctx, user, session)
+}
+
+// Verify an access token with the key its kid names, revocation is checked by TokenRevoked
+func (u *AuthUsecase) TokenValidation(tokenString string) (*utils.Claims, error) {
+	return utils.ParseJWTToken(tokenString, u.keyRing, u.cfg)
+}
+
+func (u *AuthUsecase) UserLogout(ctx context.Context, claims *utils.Claims) error {
+	if claims.TokenID() == "" {
+		return httpErr.NewBadRequestError(errors.New("AuthUsecase.UserLogout: token has no jti"))
+	}
+
+	userID, err := primitive.ObjectIDFromHex(claims.Subject)
+	if err != nil {
+		return httpErr.NewUnauthorizedError(errors.Wrap(err, "AuthUsecase.UserLogout.ObjectIDFromHex"))
+	}
+
+	expiresAt := time.Now().Add(utils.AccessTokenTTL(u.cfg))
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	if err := u.denylistRepo.CreateRevokedToken(ctx, &domain.RevokedToken{
+		JTI:       claims.TokenID(),
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return err
+	}
+	u.revocations.set(claims.TokenID(), true, expiresAt)
+
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditUserLogout,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userID.Hex(),
+	}, nil, nil)
+
+	// Tokens issued before sessions have none to end
+	if claims.SessionID == "" {
+		return nil
+	}
+
+	sessionID, err := primitive.ObjectIDFromHex(claims.SessionID)
+	if err != nil {
+		return httpErr.NewUnauthorizedError(errors.Wrap(err, "AuthUsecase.UserLogout.ObjectIDFromHex"))
+	}
+
+	return u.revokeSession(ctx, sessionID)
+}
+
+func (u *AuthUsecase) UserSessionsRevocation(ctx context.Context, userID primitive.ObjectID) error {
+	if _, err := u.authRepo.FindByID(ctx, userID); err != nil {
+		return err
+	}
+
+	// Every access token issued so far has expired once the access token lifetime has passed.
+	// Tokens only carry whole seconds, so the ones issued later in the same second are revoked too.
+	now := time.Now()
+	if err := u.denylistRepo.CreateRevokedToken(ctx, &domain.RevokedToken{
+		UserID:    userID,
+		ExpiresAt: now.Add(utils.AccessTokenTTL(u.cfg)),
+		CreatedAt: now,
+	}); err != nil {
+		return err
+	}
+	u.revocations.revokeUser(userID, now)
+
+	if err := u.sessionRepo.RevokeUserSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := u.refreshTokenRepo.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditUserSessionsRevoke,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userID.Hex(),
+	}, nil, nil)
+
+	return nil
+}
+
+func (u *AuthUsecase) TokenRevoked(ctx context.Context, claims *utils.Claims) (bool, error) {
+	userID, err := primitive.ObjectIDFromHex(claims.Subject)
+	if err != nil {
+		return false, err
+	}
+
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+
+	jti := claims.TokenID()
+	if revoked, ok := u.revocations.get(jti, userID, issuedAt); ok {
+		return revoked, nil
+	}
+
+	revoked, err := u.denylistRepo.IsRevoked(ctx, jti, userID, issuedAt)
+	if err != nil {
+		return false, err
+	}
+
+	// Tokens issued before jti was added can only be revoked per user, their verdict is not cached
+	if jti != "" {
+		expiresAt := time.Now().Add(revocationCacheTTL)
+		if claims.ExpiresAt != nil {
+			expiresAt = claims.ExpiresAt.Time
+		}
+		u.revocations.set(jti, revoked, expiresAt)
+	}
+
+	return revoked, nil
+}
+
+// Session of the session cookie, used at most once per interval is recorded
+func (u *AuthUsecase) SessionAuthentication(ctx context.Context, sessionToken string) (*domain.Session, error) {
+	session, err := u.sessionRepo.FindSessionByHash(ctx, utils.HashToken(sessionToken))
+	if err != nil {
+		return nil, httpErr.NewUnauthorizedError(errors.Wrap(err, "AuthUsecase.SessionAuthentication.FindSessionByHash"))
+	}
+
+	return u.useSession(ctx, session)
+}
+
+// Session an access token was issued for, its tokens stop working once it ends
+func (u *AuthUsecase) SessionValidation(ctx context.Context, sessionID primitive.ObjectID) (*domain.Session, error) {
+	session, err := u.sessionRepo.FindSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, httpErr.NewUnauthorizedError(httpErr.InvalidSession)
+	}
+
+	return u.useSession(ctx, session)
+}
+
+// User of a bearer access token, checked the same way for every transport. The token must verify,
+// not be revoked and its session, when it was issued for one, must still be active.
+func (u *AuthUsecase) TokenAuthentication(ctx context.Context, tokenString string) (*domain.User, *utils.Claims, *domain.Session, error) {
+	claims, err := u.TokenValidation(tokenString)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	userID, err := primitive.ObjectIDFromHex(claims.Subject)
+	if err != nil {
+		return nil, nil, nil, httpErr.NewUnauthorizedError(errors.Wrap(err, "AuthUsecase.TokenAuthentication.ObjectIDFromHex"))
+	}
+
+	revoked, err := u.TokenRevoked(ctx, claims)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if revoked {
+		return nil, nil, nil, httpErr.RevokedJWTToken
+	}
+
+	// Tokens end with the session they were issued for
+	var session *domain.Session
+	if claims.SessionID != "" {
+		sessionID, err := primitive.ObjectIDFromHex(claims.SessionID)
+		if err != nil {
+			return nil, nil, nil, httpErr.NewUnauthorizedError(httpErr.InvalidSession)
+		}
+
+		session, err = u.SessionValidation(ctx, sessionID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	user, err := u.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return user, claims, session, nil
+}
+
+func (u *AuthUsecase) SessionList(ctx context.Context, userID primitive.ObjectID) (*domain.SessionList, error) {
+	sessions, err := u.sessionRepo.FetchSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SessionList{Sessions: sessions}, nil
+}
+
+// Users end their own sessions, ending someone else's takes the user:write permission
+func (u *AuthUsecase) SessionRevocation(ctx context.Context, user *domain.User, sessionID primitive.ObjectID) error {
+	session, err := u.sessionRepo.FindSessionByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return httpErr.NewNotFoundError(errors.New("AuthUsecase.SessionRevocation: no such session"))
+	}
+
+	if session.UserID != user.ID {
+		allowed, err := u.HasPermission(ctx, user, domain.PermUserWrite)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return httpErr.NewForbiddenError(httpErr.PermissionDenied)
+		}
+	}
+
+	if err := u.revokeSession(ctx, session.ID); err != nil {
+		return err
+	}
+
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditSessionRevoked,
+		TargetType: domain.AuditTargetSession,
+		TargetID:   session.ID.Hex(),
+	}, session, nil)
+
+	return nil
+}
+
+func (u *AuthUsecase) PasswordChange(ctx context.Context, userID primitive.ObjectID, change *domain.PasswordChange) (*domain.UserWithToken, error) {
+	user, err := u.authRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := user.ComparePasswords(change.CurrentPassword); err != nil {
+		return nil, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, httpErr.ErrWrongCredentials, errors.Wrap(err, "AuthUsecase.PasswordChange.ComparePasswords"))
+	}
+
+	if err := u.setPassword(ctx, user, change.NewPassword); err != nil {
+		return nil, err
+	}
+
+	// The other sessions were revoked with the old password, this one continues with new tokens
+	user.SanitizePassword()
+
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditUserPasswordChange,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID.Hex(),
+	}, nil, nil)
+
+	return u.startSession(ctx, user)
+}
+
+func (u *AuthUsecase) PasswordResetRequest(ctx context.Context, username string) error {
+	// Unknown usernames are not reported so the endpoint can't be used to enumerate users
+	user, err := u.authRepo.FindByUsername(ctx, username)
+	if err != nil {
+		u.logger.Debug("password reset requested for an unknown user")
+		return nil
+	}
+
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return httpErr.NewInternalServerError(errors.Wrap(err, "AuthUsecase.PasswordResetRequest.GenerateOpaqueToken"))
+	}
+
+	now := time.Now()
+	resetToken, err := u.resetRepo.CreatePasswordResetToken(ctx, &domain.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: now.Add(utils.PasswordResetTTL(u.cfg)),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Use this token to reset your password: %s", token)
+	if u.cfg.Notifier.ResetURL != "" {
+		body = fmt.Sprintf("Reset your password at %s?token=%s", u.cfg.Notifier.ResetURL, url.QueryEscape(token))
+	}
+	body += fmt.Sprintf("\nThe link expires at %s.", resetToken.ExpiresAt.UTC().Format(time.RFC1123))
+
+	if err := u.notifier.Notify(ctx, &notifier.Message{To: user.Username, Subject: "Password reset", Body: body}); err != nil {
+		return httpErr.NewInternalServerError(errors.Wrap(err, "AuthUsecase.PasswordResetRequest.Notify"))
+	}
+
+	return nil
+}
+
+func (u *AuthUsecase) PasswordReset(ctx context.Context, reset *domain.PasswordReset) error {
+	invalidToken := httpErr.NewRestErrorWithMessage(http.StatusBadRequest, "invalid or expired reset token", nil)
+
+	token, err := u.resetRepo.FindPasswordResetTokenByHash(ctx, utils.HashToken(reset.Token))
+	if err != nil {
+		return invalidToken
+	}
+	if token.UsedAt != nil || token.IsExpired() {
+		return invalidToken
+	}
+
+	used, err := u.resetRepo.UsePasswordResetToken(ctx, token.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return invalidToken
+	}
+
+	user, err := u.authRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return invalidToken
+	}
+
+	if err := u.setPassword(ctx, user, reset.NewPassword); err != nil {
+		return err
+	}
+
+	// Holding the reset token is what proves the request comes from the user
+	u.auditUsecase.Record(ctx, selfAuditEntry(user, domain.AuditUserPasswordReset), nil, nil)
+
+	return nil
+}
+
+func (u *AuthUsecase) RoleCreation(ctx context.Context, role *domain.Role) (*domain.Role, error) {
+	role.PrepareCreate()
+
+	for _, permission := range role.Permissions {
+		if !domain.IsPermission(permission) {
+			return nil, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, fmt.Sprintf("unknown permission %q", permission), nil)
+		}
+	}
+
+	if _, err := u.roleRepo.FindByName(ctx, role.Name); err == nil {
+		return nil, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, httpErr.ErrRoleAlreadyExists, nil)
+	}
+
+	createdRole, err := u.roleRepo.CreateRole(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditRoleCreated,
+		TargetType: domain.AuditTargetRole,
+		TargetID:   createdRole.Name,
+	}, nil, createdRole)
+
+	return createdRole, nil
+}
+
+func (u *AuthUsecase) RoleList(ctx context.Context) (*domain.RoleList, error) {
+	roles, err := u.roleRepo.FetchRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.RoleList{Roles: roles}, nil
+}
+
+func (u *AuthUsecase) RoleAssignment(ctx context.Context, userID primitive.ObjectID, role string) error {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if _, err := u.roleRepo.FindByName(ctx, role); err != nil {
+		return httpErr.NewRestErrorWithMessage(http.StatusBadRequest, fmt.Sprintf("unknown role %q", role), err)
+	}
+
+	before, err := u.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.authRepo.UpdateRole(ctx, userID, role); err != nil {
+		return err
+	}
+
+	after := *before
+	after.Role = &role
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditRoleAssigned,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userID.Hex(),
+	}, before, &after)
+
+	return nil
+}
+
+// Every known permission is granted to the admin role, including ones added since it was created.
+// The default role is only created when missing, its permissions are left to the administrators.
+func (u *AuthUsecase) RoleBootstrap(ctx context.Context) error {
+	if err := u.roleRepo.EnsureRole(ctx, domain.AdminRole, domain.Permissions); err != nil {
+		return err
+	}
+
+	if _, err := u.roleRepo.FindByName(ctx, domain.DefaultRole); err == nil {
+		return nil
+	}
+
+	return u.roleRepo.EnsureRole(ctx, domain.DefaultRole, domain.DefaultPermissions)
+}
+
+func (u *AuthUsecase) InviteCreation(ctx context.Context, createdBy primitive.ObjectID, request *domain.InviteRequest) (*domain.InviteWithCode, error) {
+	role := strings.ToLower(strings.TrimSpace(request.Role))
+	if _, err := u.roleRepo.FindByName(ctx, role); err != nil {
+		return nil, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, fmt.Sprintf("unknown role %q", role), err)
+	}
+
+	now := time.Now()
+	invite, err := u.inviteRepo.CreateInvite(ctx, &domain.Invite{
+		Role:      role,
+		CreatedBy: createdBy,
+		ExpiresAt: now.Add(utils.InviteTTL(u.cfg)).Truncate(time.Second),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditInviteCreated,
+		TargetType: domain.AuditTargetInvite,
+		TargetID:   invite.ID.Hex(),
+	}, nil, invite)
+
+	return &domain.InviteWithCode{Invite: invite, Code: utils.SignInviteCode(invite.ID, invite.ExpiresAt, u.cfg)}, nil
+}
+
+func (u *AuthUsecase) APIKeyCreation(ctx context.Context, userID primitive.ObjectID, request *domain.APIKeyRequest) (*domain.APIKeyWithSecret, error) {
+	now := time.Now()
+	expiresAt := now.Add(utils.APIKeyTTL(u.cfg))
+	if request.ExpiresAt != nil {
+		if !request.ExpiresAt.After(now) {
+			return nil, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, "expires_at must be in the future", nil)
+		}
+		expiresAt = *request.ExpiresAt
+	}
+
+	key, prefix, err := utils.GenerateAPIKey()
+	if err != nil {
+		return nil, httpErr.NewInternalServerError(errors.Wrap(err, "AuthUsecase.APIKeyCreation.GenerateAPIKey"))
+	}
+
+	apiKey, err := u.apiKeyRepo.CreateAPIKey(ctx, &domain.APIKey{
+		UserID:    userID,
+		Name:      strings.TrimSpace(request.Name),
+		Prefix:    prefix,
+		KeyHash:   utils.HashToken(key),
+		Scopes:    request.Scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditAPIKeyCreated,
+		TargetType: domain.AuditTargetAPIKey,
+		TargetID:   apiKey.ID.Hex(),
+	}, nil, apiKey)
+
+	return &domain.APIKeyWithSecret{APIKey: apiKey, Key: key}, nil
+}
+
+func (u *AuthUsecase) APIKeyList(ctx context.Context, userID primitive.ObjectID) (*domain.APIKeyList, error) {
+	keys, err := u.apiKeyRepo.FetchAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.APIKeyList{APIKeys: keys}, nil
+}
+
+// Users revoke their own keys, revoking someone else's takes the user:write permission
+func (u *AuthUsecase) APIKeyRevocation(ctx context.Context, user *domain.User, keyID primitive.ObjectID) error {
+	key, err := u.apiKeyRepo.FindAPIKeyByID(ctx, keyID)
+	if err != nil {
+		return err
+	}
+
+	if key.UserID != user.ID {
+		allowed, err := u.HasPermission(ctx, user, domain.PermUserWrite)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return httpErr.NewForbiddenError(httpErr.PermissionDenied)
+		}
+	}
+
+	if err := u.apiKeyRepo.RevokeAPIKey(ctx, key.ID); err != nil {
+		return err
+	}
+
+	revokedAt := time.Now()
+	revoked := *key
+	revoked.RevokedAt = &revokedAt
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditAPIKeyRevoked,
+		TargetType: domain.AuditTargetAPIKey,
+		TargetID:   key.ID.Hex(),
+	}, key, &revoked)
+
+	return nil
+}
+
+func (u *AuthUsecase) APIKeyAuthentication(ctx context.Context, key string) (*domain.User, *domain.APIKey, error) {
+	apiKey, err := u.apiKeyRepo.FindAPIKeyByHash(ctx, utils.HashToken(key))
+	if err != nil {
+		return nil, nil, httpErr.NewUnauthorizedError(errors.Wrap(err, "AuthUsecase.APIKeyAuthentication.FindAPIKeyByHash"))
+	}
+	if !apiKey.IsActive() {
+		return nil, nil, httpErr.NewUnauthorizedError(httpErr.InvalidAPIKey)
+	}
+
+	user, err := u.GetByID(ctx, apiKey.UserID)
+	if err != nil {
+		return nil, nil, httpErr.NewUnauthorizedError(errors.Wrap(err, "AuthUsecase.APIKeyAuthentication.GetByID"))
+	}
+
+	// Batch jobs call often, the last use is only recorded once per interval
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > apiKeyTouchInterval {
+		if err := u.apiKeyRepo.TouchAPIKey(ctx, apiKey.ID, now); err != nil {
+			u.logger.Errorf("AuthUsecase.APIKeyAuthentication.TouchAPIKey: %v", err)
+		}
+		apiKey.LastUsedAt = &now
+	}
+
+	return user, apiKey, nil
+}
+
+// Start signing in with the provider, or linking it to the given user
+func (u *AuthUsecase) OIDCAuthorization(ctx context.Context, providerName string, linkUserID *primitive.ObjectID) (*domain.OIDCAuthorization, error) {
+	provider, err := u.oidcProviders.Get(providerName)
+	if err != nil {
+		return nil, httpErr.NewNotFoundError(err)
+	}
+
+	secrets := make([]string, 3)
+	for i := range secrets {
+		if secrets[i], err = utils.GenerateOpaqueToken(); err != nil {
+			return nil, httpErr.NewInternalServerError(errors.Wrap(err, "AuthUsecase.OIDCAuthorization.GenerateOpaqueToken"))
+		}
+	}
+	state, nonce, codeVerifier := secrets[0], secrets[1], secrets[2]
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		return nil, httpErr.NewRestErrorWithMessage(http.StatusBadGateway, "identity provider unavailable", err)
+	}
+
+	now := time.Now()
+	if _, err := u.oidcStateRepo.CreateOIDCState(ctx, &domain.OIDCState{
+		StateHash:    utils.HashToken(state),
+		Provider:     provider.Name(),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		LinkUserID:   linkUserID,
+		ExpiresAt:    now.Add(oidc.StateTTL(u.cfg)),
+		CreatedAt:    now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &domain.OIDCAuthorization{AuthorizationURL: authURL, State: state}, nil
+}
+
+// Finish signing in with the provider. The identity is linked to the user who started the flow,
+// or signs in the user it is linked to, or a new user is provisioned for it.
+func (u *AuthUsecase) OIDCCallback(ctx context.Context, providerName string, callback *domain.OIDCCallback) (*domain.UserWithToken, error) {
+	provider, err := u.oidcProviders.Get(providerName)
+	if err != nil {
+		return nil, httpErr.NewNotFoundError(err)
+	}
+
+	// A state redeemed in another browser than the one that started the flow would sign that
+	// browser in to the attacker's account, or link the attacker's identity to its user
+	if callback.BrowserState == "" || subtle.ConstantTimeCompare([]byte(callback.BrowserState), []byte(callback.State)) != 1 {
+		return nil, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, httpErr.InvalidOIDCState.Error(), nil)
+	}
+
+	pending, err := u.oidcStateRepo.UseOIDCState(ctx, utils.HashToken(callback.State))
+	if err != nil || pending.Provider != provider.Name() {
+		return nil, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, httpErr.InvalidOIDCState.Error(), err)
+	}
+
+	if callback.Error != "" || callback.Code == "" {
+		return nil, httpErr.NewUnauthorizedError(errors.Errorf("AuthUsecase.OIDCCallback: provider returned %q %s", callback.Error, callback.ErrorDescription))
+	}
+
+	identity, err := provider.Exchange(ctx, callback.Code, pending.CodeVerifier, pending.Nonce)
+	if err != nil {
+		return nil, httpErr.NewUnauthorizedError(errors.Wrap(err, "AuthUsecase.OIDCCallback.Exchange"))
+	}
+
+	linked, err := u.authRepo.FindByIdentity(ctx, provider.Name(), identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *domain.User
+	switch {
+	case pending.LinkUserID != nil:
+		if linked != nil && linked.ID != *pending.LinkUserID {
+			return nil, httpErr.NewRestErrorWithMessage(http.StatusConflict, httpErr.ErrIdentityAlreadyLinked, nil)
+		}
+		if linked == nil {
+			linkedIdentity := &domain.UserIdentity{
+				Provider: provider.Name(),
+				Subject:  identity.Subject,
+				LinkedAt: time.Now(),
+			}
+			if err := u.authRepo.AddIdentity(ctx, *pending.LinkUserID, linkedIdentity); err != nil {
+				return nil, err
+			}
+
+			actorID := *pending.LinkUserID
+			u.auditUsecase.Record(ctx, &domain.AuditEntry{
+				ActorID:    &actorID,
+				Action:     domain.AuditUserIdentityLinked,
+				TargetType: domain.AuditTargetUser,
+				TargetID:   actorID.Hex(),
+			}, nil, linkedIdentity)
+		}
+
+		if user, err = u.authRepo.FindByID(ctx, *pending.LinkUserID); err != nil {
+			return nil, err
+		}
+	case linked != nil:
+		user = linked
+	default:
+		if user, err = u.provisionUser(ctx, provider.Name(), identity); err != nil {
+			return nil, err
+		}
+	}
+
+	// Set value of password payload to empty for a security reason
+	user.SanitizePassword()
+
+	return u.completeAuthentication(ctx, user)
+}
+
+func (u *AuthUsecase) LoginLockList(ctx context.Context) (*domain.LoginLockList, error) {
+	locks, err := u.loginLockRepo.FetchLoginLocks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.LoginLockList{LoginLocks: locks}, nil
+}
+
+// Clear the lock and failures of a username or IP before the lock runs out
+func (u *AuthUsecase) LoginLockRelease(ctx context.Context, releasedBy *domain.User, lockID primitive.ObjectID) error {
+	lock, err := u.loginLockRepo.DeleteLoginLock(ctx, lockID)
+	if err != nil {
+		return err
+	}
+
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		ActorID:       &releasedBy.ID,
+		ActorUsername: releasedBy.Username,
+		Action:        domain.AuditLoginLockReleased,
+		TargetType:    domain.AuditTargetLoginLock,
+		TargetID:      lock.ID.Hex(),
+	}, lock, nil)
+
+	return nil
+}
+
+// Start enrolling an authenticator app, a pending enrollment is replaced
+func (u *AuthUsecase) MFAEnrollment(ctx context.Context, user *domain.User) (*domain.MFAEnrollment, error) {
+	mfa, err := u.mfaRepo.FindMFA(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa != nil && mfa.Enabled {
+		return nil, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, httpErr.ErrMFAAlreadyEnabled, nil)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, httpErr.NewInternalServerError(errors.Wrap(err, "AuthUsecase.MFAEnrollment.GenerateSecret"))
+	}
+
+	if err := u.mfaRepo.SaveMFA(ctx, &domain.MFA{
+		UserID:        user.ID,
+		Secret:        secret,
+		RecoveryCodes: []string{},
+		CreatedAt:     time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &domain.MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(utils.MFAIssuer(u.cfg), user.Username, secret),
+	}, nil
+}
+
+// Enable two-factor authentication once the app shows a valid code, the recovery codes are only returned here
+func (u *AuthUsecase) MFAConfirmation(ctx context.Context, userID primitive.ObjectID, code string) (*domain.MFARecoveryCodes, error) {
+	mfa, err := u.mfaRepo.FindMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil || mfa.Enabled {
+		return nil, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, httpErr.ErrMFANotPending, nil)
+	}
+
+	step, ok := totp.Validate(mfa.Secret, code, time.Now())
+	if !ok {
+		return nil, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, httpErr.InvalidMFACode.Error(), nil)
+	}
+
+	codes, err := totp.RecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, httpErr.NewInternalServerError(errors.Wrap(err, "AuthUsecase.MFAConfirmation.RecoveryCodes"))
+	}
+
+	hashes := make([]string, 0, len(codes))
+	for _, c := range codes {
+		hashes = append(hashes, utils.HashToken(totp.NormalizeRecoveryCode(c)))
+	}
+
+	if err := u.mfaRepo.EnableMFA(ctx, userID, hashes, step); err != nil {
+		return nil, err
+	}
+
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditMFAEnabled,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userID.Hex(),
+	}, nil, nil)
+
+	return &domain.MFARecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+// Turn two-factor authentication off, a current code or a recovery code is required
+func (u *AuthUsecase) MFADisable(ctx context.Context, user *domain.User, code string) error {
+	if u.mfaRequired(user) {
+		return httpErr.NewRestErrorWithMessage(http.StatusForbidden, httpErr.ErrMFARequired, nil)
+	}
+
+	mfa, err := u.mfaRepo.FindMFA(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if mfa == nil || !mfa.Enabled {
+		return httpErr.NewRestErrorWithMessage(http.StatusBadRequest, httpErr.ErrMFANotEnabled, nil)
+	}
+
+	ok, err := u.verifyMFACode(ctx, mfa, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return httpErr.NewRestErrorWithMessage(http.StatusBadRequest, httpErr.InvalidMFACode.Error(), nil)
+	}
+
+	if err := u.mfaRepo.DeleteMFA(ctx, user.ID); err != nil {
+		return err
+	}
+
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditMFADisabled,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.ID.Hex(),
+	}, nil, nil)
+
+	return nil
+}
+
+// Exchange the challenge of a password sign in and a code for tokens. Wrong codes count as
+// failed sign ins of the username, so new challenges don't reset the guessing.
+func (u *AuthUsecase) MFAVerification(ctx context.Context, verification *domain.MFAVerification) (*domain.UserWithToken, error) {
+	challenge, err := u.mfaChallengeRepo.AttemptMFAChallenge(ctx, utils.HashToken(verification.MFAToken), mfaMaxAttempts)
+	if err != nil {
+		return nil, httpErr.NewUnauthorizedError(httpErr.InvalidMFAChallenge)
+	}
+
+	user, err := u.authRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, httpErr.NewUnauthorizedError(errors.Wrap(err, "AuthUsecase.MFAVerification.FindByID"))
+	}
+
+	targets := loginTargets(ctx, user.Username)
+	if err := u.loginAllowed(ctx, targets); err != nil {
+		return nil, err
+	}
+
+	mfa, err := u.mfaRepo.FindMFA(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil || !mfa.Enabled {
+		return nil, httpErr.NewUnauthorizedError(httpErr.InvalidMFAChallenge)
+	}
+
+	ok, err := u.verifyMFACode(ctx, mfa, verification.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		u.loginFailed(ctx, targets)
+		return nil, httpErr.NewUnauthorizedError(httpErr.InvalidMFACode)
+	}
+
+	if err := u.mfaChallengeRepo.DeleteMFAChallenge(ctx, challenge.ID); err != nil {
+		return nil, err
+	}
+
+	u.loginSucceeded(ctx, user.Username)
+
+	// Set value of password payload to empty for a security reason
+	user.SanitizePassword()
+
+	return u.signIn(ctx, user)
+}
+
+// Whether the role of the user grants the permission. With MFA.RequireForAdmins set an admin who has
+// not enabled two-factor authentication yet has no permission at all, the check returns false without an error.
+func (u *AuthUsecase) HasPermission(ctx context.Context, user *domain.User, permission string) (bool, error) {
+	if user.Role == nil {
+		return false, nil
+	}
+
+	// Administrators who have to use two-factor authentication get no permissions until they enrolled
+	if u.mfaRequired(user) {
+		mfa, err := u.mfaRepo.FindMFA(ctx, user.ID)
+		if err != nil {
+			return false, err
+		}
+		if mfa == nil || !mfa.Enabled {
+			u.logger.Warnf("AuthUsecase.HasPermission: %s has not enabled two-factor authentication", user.Username)
+			return false, nil
+		}
+	}
+
+	permissions, err := u.roleRepo.FindPermissions(ctx, *user.Role)
+	if err != nil {
+		return false, err
+	}
+
+	for _, p := range permissions {
+		if p == permission {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Username and client IP a sign in is throttled by
+type loginTarget struct {
+	kind  string
+	value string
+}
+
+func loginTargets(ctx context.Context, username string) []loginTarget {
+	targets := []loginTarget{{kind: domain.LockKindUsername, value: username}}
+	if ip := utils.GetClientIPFromCtx(ctx); ip != "" {
+		targets = append(targets, loginTarget{kind: domain.LockKindIP, value: ip})
+	}
+
+	return targets
+}
+
+// Reject sign ins while the username or IP waits out a delay or lockout, the password is not checked
+func (u *AuthUsecase) loginAllowed(ctx context.Context, targets []loginTarget) error {
+	now := time.Now()
+
+	var retryAfter time.Duration
+	for _, target := range targets {
+		lock, err := u.loginLockRepo.FindLoginLock(ctx, target.kind, target.value)
+		if err != nil {
+			return err
+		}
+		if lock != nil && lock.RetryAfter(now) > retryAfter {
+			retryAfter = lock.RetryAfter(now)
+		}
+	}
+
+	if retryAfter > 0 {
+		return httpErr.NewTooManyRequestsError(httpErr.ErrTooManyLoginAttempts, retryAfter, nil)
+	}
+
+	return nil
+}
+
+// Count the failure, delaying the next attempt or locking once the threshold is reached.
+// The sign in failed already, errors are only logged.
+func (u *AuthUsecase) loginFailed(ctx context.Context, targets []loginTarget) {
+	now := time.Now()
+	window := utils.LockoutWindow(u.cfg)
+
+	// The username always comes first, the attempt is recorded against it
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditUserLoginFailed,
+		TargetType: domain.AuditTargetUsername,
+		TargetID:   targets[0].value,
+	}, nil, nil)
+
+	for _, target := range targets {
+		lock, err := u.loginLockRepo.RecordLoginFailure(ctx, target.kind, target.value, now, now.Add(window))
+		if err != nil {
+			u.logger.Errorf("AuthUsecase.loginFailed.RecordLoginFailure: %v", err)
+			continue
+		}
+
+		threshold := utils.LockoutUsernameThreshold(u.cfg)
+		if target.kind == domain.LockKindIP {
+			threshold = utils.LockoutIPThreshold(u.cfg)
+		}
+
+		if lock.Failures >= threshold {
+			lockedUntil := now.Add(utils.LockoutDuration(u.cfg, lock.Lockouts))
+			locked, err := u.loginLockRepo.LockLogin(ctx, lock.ID, threshold, lockedUntil, lockedUntil.Add(window))
+			if err != nil {
+				u.logger.Errorf("AuthUsecase.loginFailed.LockLogin: %v", err)
+				continue
+			}
+			if locked {
+				after := *lock
+				after.Lockouts++
+				after.LockedUntil = &lockedUntil
+				u.auditUsecase.Record(ctx, &domain.AuditEntry{
+					Action:     domain.AuditLoginLocked,
+					TargetType: domain.AuditTargetLoginLock,
+					TargetID:   lock.ID.Hex(),
+				}, lock, &after)
+			}
+			continue
+		}
+
+		if delay := utils.LoginDelay(u.cfg, lock.Failures); delay > 0 {
+			if err := u.loginLockRepo.DelayLogin(ctx, lock.ID, now.Add(delay)); err != nil {
+				u.logger.Errorf("AuthUsecase.loginFailed.DelayLogin: %v", err)
+			}
+		}
+	}
+}
+
+// Forget the failures of a username once its sign in completed. The IP keeps its failures,
+// signing in to an own account must not reset them.
+func (u *AuthUsecase) loginSucceeded(ctx context.Context, username string) {
+	if err := u.loginLockRepo.ClearLoginFailures(ctx, domain.LockKindUsername, username); err != nil {
+		u.logger.Errorf("AuthUsecase.loginSucceeded.ClearLoginFailures: %v", err)
+	}
+}
+
+// Issue tokens, or a challenge for users with two-factor authentication. A user has at most
+// one open challenge, a new one replaces those of earlier sign ins.
+func (u *AuthUsecase) completeAuthentication(ctx context.Context, user *domain.User) (*domain.UserWithToken, error) {
+	mfa, err := u.mfaRepo.FindMFA(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil || !mfa.Enabled {
+		return u.signIn(ctx, user)
+	}
+
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, httpErr.NewInternalServerError(errors.Wrap(err, "AuthUsecase.completeAuthentication.GenerateOpaqueToken"))
+	}
+
+	if err := u.mfaChallengeRepo.DeleteUserMFAChallenges(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, err := u.mfaChallengeRepo.CreateMFAChallenge(ctx, &domain.MFAChallenge{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: now.Add(utils.MFAChallengeTTL(u.cfg)),
+		CreatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &domain.UserWithToken{MFARequired: true, MFAToken: token}, nil
+}
+
+// Start the session of a completed sign in, the user signing in is the actor
+func (u *AuthUsecase) signIn(ctx context.Context, user *domain.User) (*domain.UserWithToken, error) {
+	userWithToken, err := u.startSession(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	u.auditUsecase.Record(ctx, selfAuditEntry(user, domain.AuditUserLogin), nil, nil)
+
+	return userWithToken, nil
+}
+
+// Check a TOTP code, each accepted once, or else use up a recovery code
+func (u *AuthUsecase) verifyMFACode(ctx context.Context, mfa *domain.MFA, code string) (bool, error) {
+	if step, ok := totp.Validate(mfa.Secret, code, time.Now()); ok {
+		return u.mfaRepo.UseStep(ctx, mfa.UserID, step)
+	}
+
+	return u.mfaRepo.UseRecoveryCode(ctx, mfa.UserID, utils.HashToken(totp.NormalizeRecoveryCode(code)))
+}
+
+func (u *AuthUsecase) mfaRequired(user *domain.User) bool {
+	return u.cfg.MFA.RequireForAdmins && user.Role != nil && *user.Role == domain.AdminRole
+}
+
+// Users signing in with a provider for the first time get an account with the default role.
+// Its password is random, the user can set one through the password reset.
+func (u *AuthUsecase) provisionUser(ctx context.Context, providerName string, identity *oidc.Identity) (*domain.User, error) {
+	password, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, httpErr.NewInternalServerError(errors.Wrap(err, "AuthUsecase.provisionUser.GenerateOpaqueToken"))
+	}
+
+	// Local accounts with the same name are not taken over, they link the identity after signing in
+	username := oidcUsername(providerName, identity)
+	if _, err := u.authRepo.FindByUsername(ctx, username); err == nil {
+		username = username + "-" + utils.HashToken(providerName + ":" + identity.Subject)[:8]
+	}
+
+	role := domain.DefaultRole
+	user := &domain.User{
+		Username: username,
+		Password: password,
+		Role:     &role,
+		Identities: []domain.UserIdentity{{
+			Provider: providerName,
+			Subject:  identity.Subject,
+			LinkedAt: time.Now(),
+		}},
+	}
+	if err := user.PrepareCreate(); err != nil {
+		return nil, httpErr.NewInternalServerError(errors.Wrap(err, "AuthUsecase.provisionUser.PrepareCreate"))
+	}
+
+	createdUser, err := u.authRepo.CreateUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	u.auditUsecase.Record(ctx, selfAuditEntry(createdUser, domain.AuditUserCreated), nil, createdUser)
+
+	return createdUser, nil
+}
+
+// Entry for an action on the user's own account taken before the request is authenticated
+func selfAuditEntry(user *domain.User, action string) *domain.AuditEntry {
+	actorID := user.ID
+	return &domain.AuditEntry{
+		ActorID:       &actorID,
+		ActorUsername: user.Username,
+		Action:        action,
+		TargetType:    domain.AuditTargetUser,
+		TargetID:      user.ID.Hex(),
+	}
+}
+
+func oidcUsername(providerName string, identity *oidc.Identity) string {
+	if username := strings.TrimSpace(identity.PreferredUsername); username != "" {
+		return username
+	}
+	if identity.EmailVerified {
+		if local, _, ok := strings.Cut(identity.Email, "@"); ok && local != "" {
+			return local
+		}
+	}
+
+	return providerName + "-" + identity.Subject
+}
+
+// Store the new password and sign out every session authenticated with the old one
+func (u *AuthUsecase) setPassword(ctx context.Context, user *domain.User, password string) error {
+	user.Password = strings.TrimSpace(password)
+	if err := user.HashPassword(); err != nil {
+		return httpErr.NewInternalServerError(errors.Wrap(err, "AuthUsecase.setPassword.HashPassword"))
+	}
+
+	if err := u.authRepo.UpdatePassword(ctx, user.ID, user.Password); err != nil {
+		return err
+	}
+
+	if err := u.resetRepo.InvalidateUserPasswordResetTokens(ctx, user.ID); err != nil {
+		return err
+	}
+
+	return u.UserSessionsRevocation(ctx, user.ID)
+}
+
+// Start a session for a sign in, its ID is returned for the session cookie
+func (u *AuthUsecase) startSession(ctx context.Context, user *domain.User) (*domain.UserWithToken, error) {
+	sessionToken, err := utils.GenerateSessionToken(u.cfg)
+	if err != nil {
+		return nil, httpErr.NewInternalServerError(errors.Wrap(err, "AuthUsecase.startSession.GenerateSessionToken"))
+	}
+
+	session, err := u.createSession(ctx, primitive.NewObjectID(), user.ID, sessionToken)
+	if err != nil {
+		return nil, err
+	}
+
+	userWithToken, err := u.issueTokens(ctx, user, session)
+	if err != nil {
+		return nil, err
+	}
+	userWithToken.SessionID = sessionToken
+
+	return userWithToken, nil
+}
+
+func (u *AuthUsecase) createSession(ctx context.Context, sessionID, userID primitive.ObjectID, sessionToken string) (*domain.Session, error) {
+	now := time.Now()
+	return u.sessionRepo.CreateSession(ctx, &domain.Session{
+		ID:         sessionID,
+		UserID:     userID,
+		TokenHash:  utils.HashToken(sessionToken),
+		IPAddress:  utils.GetClientIPFromCtx(ctx),
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(utils.SessionTTL(u.cfg)),
+		CreatedAt:  now,
+	})
+}
+
+// Session of a refresh token family. A refresh keeps its session alive, refreshing in an ended
+// session ends the family. Families started before sessions name none and get one without a
+// cookie, a family whose session expired or was removed has ended with it.
+func (u *AuthUsecase) refreshSession(ctx context.Context, token *domain.RefreshToken) (*domain.Session, error) {
+	sessionID := token.FamilyID
+	if token.SessionID != nil {
+		sessionID = *token.SessionID
+	}
+
+	session, err := u.sessionRepo.FindSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil && token.SessionID == nil {
+		sessionToken, err := utils.GenerateOpaqueToken()
+		if err != nil {
+			return nil, httpErr.NewInternalServerError(errors.Wrap(err, "AuthUsecase.refreshSession.GenerateOpaqueToken"))
+		}
+
+		return u.createSession(ctx, token.FamilyID, token.UserID, sessionToken)
+	}
+
+	if session == nil || !session.IsActive() {
+		if err := u.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, token.FamilyID); err != nil {
+			return nil, err
+		}
+
+		return nil, httpErr.NewUnauthorizedError(httpErr.InvalidSession)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(utils.SessionTTL(u.cfg))
+	if err := u.sessionRepo.TouchSession(ctx, session.ID, now, expiresAt); err != nil {
+		return nil, err
+	}
+	session.LastSeenAt, session.ExpiresAt = now, expiresAt
+
+	return session, nil
+}
+
+// Check the session is active and record its use, at most once per interval
+func (u *AuthUsecase) useSession(ctx context.Context, session *domain.Session) (*domain.Session, error) {
+	if !session.IsActive() {
+		return nil, httpErr.NewUnauthorizedError(httpErr.InvalidSession)
+	}
+
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) > sessionTouchInterval {
+		expiresAt := now.Add(utils.SessionTTL(u.cfg))
+		if err := u.sessionRepo.TouchSession(ctx, session.ID, now, expiresAt); err != nil {
+			u.logger.Errorf("AuthUsecase.useSession.TouchSession: %v", err)
+		} else {
+			session.LastSeenAt, session.ExpiresAt = now, expiresAt
+		}
+	}
+
+	return session, nil
+}
+
+// End the session and its refresh tokens, its access tokens are rejected with it
+func (u *AuthUsecase) revokeSession(ctx context.Context, sessionID primitive.ObjectID) error {
+	if err := u.sessionRepo.RevokeSession(ctx, sessionID); err != nil {
+		return err
+	}
+
+	return u.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, sessionID)
+}
+
+// Sign an access token and store a new refresh token in the family of the session
+func (u *AuthUsecase) issueTokens(ctx context.Context, user *domain.User, session *domain.Session) (*domain.UserWithToken, error) {
+	accessToken, err := utils.GenerateJWTToken(user, session.ID.Hex(), u.keyRing, u.cfg)
+	if err != nil {
+		return nil, httpErr.NewInternalServerError(errors.Wrap(err, "AuthUsecase.issueTokens.GenerateJWTToken"))
+	}
+
+	refreshToken, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, httpErr.NewInternalServerError(errors.Wrap(err, "AuthUsecase.issueTokens.GenerateOpaqueToken"))
+	}
+
+	now := time.Now()
+	if _, err := u.refreshTokenRepo.CreateRefreshToken(ctx, &domain.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  session.ID,
+		SessionID: &session.ID,
+		TokenHash: utils.HashToken(refreshToken),
+		ExpiresAt: now.Add(utils.RefreshTokenTTL(u.cfg)),
+		CreatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &domain.UserWithToken{
+		User:         user,
+		Token:        accessToken,
+		ExpiresIn:    int64(utils.AccessTokenTTL(u.cfg).Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	authHttpV2 "github.com/iamaul/go-pokedex/internal/auth/delivery/http/v2"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+// Request carrying a conditional header, none when value is empty
+func (ta *testAuth) conditionalRequest(e *echo.Echo, method, target, token, body, header, value string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if method == http.MethodPatch {
+		req.Header.Set(echo.HeaderContentType, utils.MIMEMergePatch)
+	}
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	if value != "" {
+		req.Header.Set(header, value)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestConditionalUserRequests(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("If-Match on updates", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			method  string
+			body    string
+			ifMatch func(current int64) string
+			want    int
+		}{
+			{name: "put with the current version", method: http.MethodPut, body: `{"username":"ash-ketchum"}`, ifMatch: func(v int64) string { return domain.VersionETag(v) }, want: http.StatusOK},
+			{name: "put with a stale version", method: http.MethodPut, body: `{"username":"ash-ketchum"}`, ifMatch: func(v int64) string { return domain.VersionETag(v + 1) }, want: http.StatusPreconditionFailed},
+			{name: "put with any version", method: http.MethodPut, body: `{"username":"ash-ketchum"}`, ifMatch: func(int64) string { return "*" }, want: http.StatusOK},
+			{name: "put without If-Match", method: http.MethodPut, body: `{"username":"ash-ketchum"}`, ifMatch: func(int64) string { return "" }, want: http.StatusOK},
+			{name: "put with a weak tag", method: http.MethodPut, body: `{"username":"ash-ketchum"}`, ifMatch: func(v int64) string { return "W/" + domain.VersionETag(v) }, want: http.StatusPreconditionFailed},
+			{name: "put with a malformed tag", method: http.MethodPut, body: `{"username":"ash-ketchum"}`, ifMatch: func(int64) string { return "version-1" }, want: http.StatusPreconditionFailed},
+			{name: "patch with the current version", method: http.MethodPatch, body: `{"username":"ash-ketchum"}`, ifMatch: func(v int64) string { return domain.VersionETag(v) }, want: http.StatusOK},
+			{name: "patch with a stale version", method: http.MethodPatch, body: `{"username":"ash-ketchum"}`, ifMatch: func(v int64) string { return domain.VersionETag(v + 1) }, want: http.StatusPreconditionFailed},
+			{name: "patch with a malformed tag", method: http.MethodPatch, body: `{"username":"ash-ketchum"}`, ifMatch: func(int64) string { return `"one"` }, want: http.StatusPreconditionFailed},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ta := newTestAuth(t, testConfig(), nil)
+				user := ta.addUser(t, "ash", domain.DefaultRole)
+				e := ta.routesOf(authHttpV2.NewAuthHandler(ta.cfg, ta.AuthUsecase, ta.logger))
+
+				rec := ta.conditionalRequest(e, tt.method, "/auth", ta.accessToken(t, user), tt.body, utils.HeaderIfMatch, tt.ifMatch(user.Version))
+				if rec.Code != tt.want {
+					t.Fatalf("status = %d (%s), want %d", rec.Code, rec.Body.String(), tt.want)
+				}
+
+				stored, err := ta.users.FindByID(ctx, user.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tt.want != http.StatusOK {
+					if stored.Username != "ash" || stored.Version != user.Version {
+						t.Errorf("refused update was applied: %s at version %d", stored.Username, stored.Version)
+					}
+					return
+				}
+				if stored.Username != "ash-ketchum" {
+					t.Errorf("username = %q, want the update applied", stored.Username)
+				}
+				if got := rec.Header().Get(utils.HeaderETag); got != stored.ETag() {
+					t.Errorf("ETag = %q, want the new version %s", got, stored.ETag())
+				}
+			})
+		}
+	})
+
+	t.Run("If-Match on deletion", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			ifMatch func(current int64) string
+			want    int
+		}{
+			{name: "current version", ifMatch: func(v int64) string { return domain.VersionETag(v) }, want: http.StatusNoContent},
+			{name: "stale version", ifMatch: func(v int64) string { return domain.VersionETag(v + 1) }, want: http.StatusPreconditionFailed},
+			{name: "malformed tag", ifMatch: func(int64) string { return "1" }, want: http.StatusPreconditionFailed},
+			{name: "unconditional", ifMatch: func(int64) string { return "" }, want: http.StatusNoContent},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ta := newTestAuth(t, testConfig(), nil)
+				admin := ta.addUser(t, "oak", domain.AdminRole)
+				target := ta.addUser(t, "ash", domain.DefaultRole)
+				e := ta.routesOf(authHttpV2.NewAuthHandler(ta.cfg, ta.AuthUsecase, ta.logger))
+
+				rec := ta.conditionalRequest(e, http.MethodDelete, "/auth/"+target.ID.Hex(), ta.accessToken(t, admin), "", utils.HeaderIfMatch, tt.ifMatch(target.Version))
+				if rec.Code != tt.want {
+					t.Fatalf("status = %d (%s), want %d", rec.Code, rec.Body.String(), tt.want)
+				}
+
+				_, err := ta.users.FindByID(ctx, target.ID)
+				if deleted := err != nil; deleted != (tt.want == http.StatusNoContent) {
+					t.Errorf("deleted = %v, want %v", deleted, tt.want == http.StatusNoContent)
+				}
+			})
+		}
+	})
+
+	t.Run("If-None-Match on reads", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			ifNoneMatch func(current int64) string
+			want        int
+		}{
+			{name: "current version", ifNoneMatch: func(v int64) string { return domain.VersionETag(v) }, want: http.StatusNotModified},
+			{name: "weak current version", ifNoneMatch: func(v int64) string { return "W/" + domain.VersionETag(v) }, want: http.StatusNotModified},
+			{name: "among other tags", ifNoneMatch: func(v int64) string { return `"41", ` + domain.VersionETag(v) }, want: http.StatusNotModified},
+			{name: "any version", ifNoneMatch: func(int64) string { return "*" }, want: http.StatusNotModified},
+			{name: "stale version", ifNoneMatch: func(v int64) string { return domain.VersionETag(v + 1) }, want: http.StatusOK},
+			{name: "malformed tag", ifNoneMatch: func(int64) string { return "version-1" }, want: http.StatusOK},
+			{name: "unconditional", ifNoneMatch: func(int64) string { return "" }, want: http.StatusOK},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ta := newTestAuth(t, testConfig(), nil)
+				user := ta.addUser(t, "ash", domain.DefaultRole)
+				e := ta.routesOf(authHttpV2.NewAuthHandler(ta.cfg, ta.AuthUsecase, ta.logger))
+
+				rec := ta.conditionalRequest(e, http.MethodGet, "/auth/"+user.ID.Hex(), ta.accessToken(t, user), "", utils.HeaderIfNoneMatch, tt.ifNoneMatch(user.Version))
+				if rec.Code != tt.want {
+					t.Fatalf("status = %d (%s), want %d", rec.Code, rec.Body.String(), tt.want)
+				}
+				if got := rec.Header().Get(utils.HeaderETag); got != user.ETag() {
+					t.Errorf("ETag = %q, want %q", got, user.ETag())
+				}
+				if tt.want == http.StatusNotModified && rec.Body.Len() != 0 {
+					t.Errorf("304 has a body: %q", rec.Body.String())
+				}
+			})
+		}
+	})
+}
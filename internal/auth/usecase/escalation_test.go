@@ -0,0 +1,291 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/iamaul/go-pokedex/internal/auth"
+	authHttp "github.com/iamaul/go-pokedex/internal/auth/delivery/http"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/middleware"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+// The roles a user ends up with through the auth routes, nothing a caller sends may raise them
+
+func TestRegistrationRole(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ignores a role in the request", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		e := ta.routes()
+
+		rec := ta.request(e, http.MethodPost, "/auth/new", "", `{"username":"ash","password":"pikachu","role":"admin"}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("got status %d: %s", rec.Code, rec.Body)
+		}
+
+		user, err := ta.users.FindByUsername(ctx, "ash")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Role == nil || *user.Role != domain.DefaultRole {
+			t.Errorf("registered with role %v, want %q", user.Role, domain.DefaultRole)
+		}
+	})
+
+	t.Run("grants the role of the invite", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		code := ta.addInvite(t, domain.AdminRole, time.Hour)
+
+		created, err := ta.UserRegistration(ctx, &domain.UserRegistration{Username: "ash", Password: "pikachu", InviteCode: code})
+		if err != nil {
+			t.Fatalf("UserRegistration: %v", err)
+		}
+		if *created.User.Role != domain.AdminRole {
+			t.Errorf("registered with role %q, want %q", *created.User.Role, domain.AdminRole)
+		}
+	})
+
+	tests := []struct {
+		name string
+		code func(t *testing.T, ta *testAuth) string
+	}{
+		{
+			name: "forged code",
+			code: func(t *testing.T, ta *testAuth) string {
+				code := ta.addInvite(t, domain.AdminRole, time.Hour)
+				payload, _, _ := strings.Cut(code, ".")
+				return payload + ".Zm9yZ2Vk"
+			},
+		},
+		{
+			name: "code signed with another secret",
+			code: func(t *testing.T, ta *testAuth) string {
+				invite := ta.storeInvite(t, domain.AdminRole, time.Hour)
+				cfg := testConfig()
+				cfg.Server.InviteSecret = "another-secret"
+				return utils.SignInviteCode(invite.ID, invite.ExpiresAt, cfg)
+			},
+		},
+		{
+			name: "expired code",
+			code: func(t *testing.T, ta *testAuth) string {
+				return ta.addInvite(t, domain.AdminRole, -time.Minute)
+			},
+		},
+		{
+			name: "code of an expired invite",
+			code: func(t *testing.T, ta *testAuth) string {
+				invite := ta.storeInvite(t, domain.AdminRole, -time.Minute)
+				// The code claims a later expiry than the stored invite has
+				return utils.SignInviteCode(invite.ID, time.Now().Add(time.Hour), ta.cfg)
+			},
+		},
+		{
+			name: "code without a stored invite",
+			code: func(t *testing.T, ta *testAuth) string {
+				return utils.SignInviteCode(primitive.NewObjectID(), time.Now().Add(time.Hour), ta.cfg)
+			},
+		},
+		{
+			name: "reused code",
+			code: func(t *testing.T, ta *testAuth) string {
+				code := ta.addInvite(t, domain.AdminRole, time.Hour)
+				if _, err := ta.UserRegistration(ctx, &domain.UserRegistration{Username: "misty", Password: "pikachu", InviteCode: code}); err != nil {
+					t.Fatalf("first use: %v", err)
+				}
+				return code
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run("rejects a "+tt.name, func(t *testing.T) {
+			ta := newTestAuth(t, testConfig(), nil)
+			code := tt.code(t, ta)
+
+			_, err := ta.UserRegistration(ctx, &domain.UserRegistration{Username: "ash", Password: "pikachu", InviteCode: code})
+			assertStatus(t, err, http.StatusBadRequest)
+
+			if _, err := ta.users.FindByUsername(ctx, "ash"); err == nil {
+				t.Error("user was registered")
+			}
+		})
+	}
+
+	t.Run("keeps the invite of a failed registration", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		code := ta.addInvite(t, domain.AdminRole, time.Hour)
+
+		ta.users.failCreate = true
+		if _, err := ta.UserRegistration(ctx, &domain.UserRegistration{Username: "ash", Password: "pikachu", InviteCode: code}); err == nil {
+			t.Fatal("registration did not fail")
+		}
+
+		created, err := ta.UserRegistration(ctx, &domain.UserRegistration{Username: "ash", Password: "pikachu", InviteCode: code})
+		if err != nil {
+			t.Fatalf("invite was burnt by the failed registration: %v", err)
+		}
+		if *created.User.Role != domain.AdminRole {
+			t.Errorf("registered with role %q, want %q", *created.User.Role, domain.AdminRole)
+		}
+	})
+}
+
+func TestUserUpdateRole(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		method string
+		status int
+		want   string
+	}{
+		// The role is not a field of the update, the username is saved without it
+		{method: http.MethodPut, status: http.StatusOK, want: "ash-ketchum"},
+		// Patches may only produce fields of the update, the whole patch is refused
+		{method: http.MethodPatch, status: http.StatusUnprocessableEntity, want: "ash"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			ta := newTestAuth(t, testConfig(), nil)
+			e := ta.routes()
+			user := ta.addUser(t, "ash", domain.DefaultRole)
+			token := ta.accessToken(t, user)
+
+			rec := ta.request(e, tt.method, "/auth", token, `{"username":"ash-ketchum","role":"admin"}`)
+			if rec.Code != tt.status {
+				t.Fatalf("got status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+
+			updated, err := ta.users.FindByID(ctx, user.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if updated.Username != tt.want {
+				t.Errorf("username %q, want %q", updated.Username, tt.want)
+			}
+			if *updated.Role != domain.DefaultRole {
+				t.Errorf("update changed the role to %q", *updated.Role)
+			}
+		})
+	}
+}
+
+func TestRoleAssignmentPermission(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		role   string
+		status int
+		want   string
+	}{
+		{name: "denies a trainer", role: domain.DefaultRole, status: http.StatusForbidden, want: domain.DefaultRole},
+		{name: "allows an admin", role: domain.AdminRole, status: http.StatusNoContent, want: domain.AdminRole},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestAuth(t, testConfig(), nil)
+			e := ta.routes()
+			caller := ta.addUser(t, "ash", tt.role)
+			target := ta.addUser(t, "brock", domain.DefaultRole)
+			token := ta.accessToken(t, caller)
+
+			rec := ta.request(e, http.MethodPut, "/auth/"+target.ID.Hex()+"/role", token, `{"role":"admin"}`)
+			if rec.Code != tt.status {
+				t.Fatalf("got status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+
+			found, err := ta.users.FindByID(ctx, target.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *found.Role != tt.want {
+				t.Errorf("target has role %q, want %q", *found.Role, tt.want)
+			}
+		})
+	}
+
+	t.Run("denies a trainer inviting an admin", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		e := ta.routes()
+		token := ta.accessToken(t, ta.addUser(t, "ash", domain.DefaultRole))
+
+		rec := ta.request(e, http.MethodPost, "/auth/invite", token, `{"role":"admin"}`)
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("got status %d: %s", rec.Code, rec.Body)
+		}
+	})
+}
+
+// The v1 auth routes served by the usecase under test
+func (ta *testAuth) routes() *echo.Echo {
+	return ta.routesOf(authHttp.NewAuthHandler(ta.cfg, ta.AuthUsecase, ta.logger))
+}
+
+// The auth routes with the handlers of an API version
+func (ta *testAuth) routesOf(h auth.DeliveryHandlers) *echo.Echo {
+	e := echo.New()
+	mw := middleware.NewMiddlewareManager(ta.AuthUsecase, ta.cfg, nil, ta.logger)
+	authHttp.AuthRoutes(e.Group("/auth"), h, ta.AuthUsecase, ta.cfg, mw)
+
+	return e
+}
+
+func (ta *testAuth) request(e *echo.Echo, method, target, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if method == http.MethodPatch {
+		req.Header.Set(echo.HeaderContentType, utils.MIMEMergePatch)
+	}
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func (ta *testAuth) accessToken(t *testing.T, user *domain.User) string {
+	t.Helper()
+
+	signedIn, err := ta.startSession(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signedIn.Token
+}
+
+// Store an invite expiring after ttl and return its code
+func (ta *testAuth) addInvite(t *testing.T, role string, ttl time.Duration) string {
+	t.Helper()
+
+	invite := ta.storeInvite(t, role, ttl)
+
+	return utils.SignInviteCode(invite.ID, invite.ExpiresAt, ta.cfg)
+}
+
+func (ta *testAuth) storeInvite(t *testing.T, role string, ttl time.Duration) *domain.Invite {
+	t.Helper()
+
+	now := time.Now()
+	invite, err := ta.invites.CreateInvite(context.Background(), &domain.Invite{
+		Role:      role,
+		ExpiresAt: now.Add(ttl).Truncate(time.Second),
+		CreatedAt: now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return invite
+}
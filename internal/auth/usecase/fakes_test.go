@@ -0,0 +1,930 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/audit"
+	"github.com/iamaul/go-pokedex/internal/auth"
+	authRepository "github.com/iamaul/go-pokedex/internal/auth/repository"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/monster"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/keyring"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/notifier"
+	"github.com/iamaul/go-pokedex/pkg/oidc"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+// In-memory stores behaving like the mongo repositories for what the usecase relies on
+
+type fakeUserRepo struct {
+	auth.Repository
+	mu    sync.Mutex
+	users map[primitive.ObjectID]*domain.User
+	// Fails the next CreateUser, as a duplicate username racing the pre-check would
+	failCreate bool
+}
+
+func (r *fakeUserRepo) CreateUser(ctx context.Context, user *domain.User) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.failCreate {
+		r.failCreate = false
+		return nil, errors.Wrap(errors.New("E11000 duplicate key"), httpErr.ErrUserAlreadyExists)
+	}
+	for _, u := range r.users {
+		if u.Username == user.Username {
+			return nil, errors.Wrap(errors.New("E11000 duplicate key"), httpErr.ErrUserAlreadyExists)
+		}
+	}
+
+	user.ID = primitive.NewObjectID()
+	user.Version = 1
+	stored := *user
+	r.users[user.ID] = &stored
+
+	return user, nil
+}
+
+func (r *fakeUserRepo) UpdateUser(ctx context.Context, user *domain.UserUpdate) (*domain.UserUpdate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[user.ID]
+	if !ok {
+		return nil, httpErr.NewNotFoundError(errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound))
+	}
+	if user.Version != nil && *user.Version != stored.Version {
+		return nil, errors.Wrapf(httpErr.PreconditionFailed, "version %d", *user.Version)
+	}
+	if user.Username != "" {
+		stored.Username = user.Username
+	}
+	stored.Version++
+	version := stored.Version
+	user.Version = &version
+
+	return user, nil
+}
+
+func (r *fakeUserRepo) DeleteUser(ctx context.Context, userID primitive.ObjectID, version *int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[userID]
+	if !ok {
+		return errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+	if version != nil && *version != stored.Version {
+		return errors.Wrapf(httpErr.PreconditionFailed, "version %d", *version)
+	}
+	delete(r.users, userID)
+
+	return nil
+}
+
+func (r *fakeUserRepo) UpdatePassword(ctx context.Context, userID primitive.ObjectID, password string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[userID]
+	if !ok {
+		return errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+	stored.Password = password
+
+	return nil
+}
+
+func (r *fakeUserRepo) UpdateRole(ctx context.Context, userID primitive.ObjectID, role string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[userID]
+	if !ok {
+		return errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+	stored.Role = &role
+
+	return nil
+}
+
+func (r *fakeUserRepo) FindByID(ctx context.Context, userID primitive.ObjectID) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[userID]
+	if !ok {
+		return &domain.User{}, errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+	found := *stored
+
+	return &found, nil
+}
+
+func (r *fakeUserRepo) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range r.users {
+		if u.Username == username {
+			found := *u
+			return &found, nil
+		}
+	}
+
+	return &domain.User{}, mongo.ErrNoDocuments
+}
+
+func (r *fakeUserRepo) FindByIdentity(ctx context.Context, provider, subject string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range r.users {
+		for _, identity := range u.Identities {
+			if identity.Provider == provider && identity.Subject == subject {
+				found := *u
+				return &found, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *fakeUserRepo) AddIdentity(ctx context.Context, userID primitive.ObjectID, identity *domain.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[userID]
+	if !ok {
+		return errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+	stored.Identities = append(stored.Identities, *identity)
+
+	return nil
+}
+
+func (r *fakeUserRepo) AddMonster(ctx context.Context, userID, monsterID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[userID]
+	if !ok {
+		return errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+	stored.Monsters = append(stored.Monsters, monsterID)
+
+	return nil
+}
+
+func (r *fakeUserRepo) FetchUsers(ctx context.Context, pq *utils.PaginationQuery) (*domain.UserList, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	users := make([]*domain.User, 0, len(r.users))
+	for _, u := range r.users {
+		found := *u
+		users = append(users, &found)
+	}
+
+	return &domain.UserList{TotalCount: len(users), TotalPages: 1, Page: 1, Size: len(users), Users: users}, nil
+}
+
+// Monster repository where every id names a monster
+type fakeMonsterRepo struct {
+	monster.MonsterRepository
+}
+
+func (fakeMonsterRepo) FindByID(ctx context.Context, monsterID primitive.ObjectID) (*domain.Monster, error) {
+	return &domain.Monster{ID: monsterID}, nil
+}
+
+type fakeRefreshTokenRepo struct {
+	auth.RefreshTokenRepository
+	mu     sync.Mutex
+	tokens map[primitive.ObjectID]*domain.RefreshToken
+}
+
+func (r *fakeRefreshTokenRepo) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) (*domain.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token.ID = primitive.NewObjectID()
+	stored := *token
+	r.tokens[token.ID] = &stored
+
+	return token, nil
+}
+
+func (r *fakeRefreshTokenRepo) FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			found := *t
+			return &found, nil
+		}
+	}
+
+	return nil, errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+}
+
+func (r *fakeRefreshTokenRepo) RevokeRefreshToken(ctx context.Context, tokenID primitive.ObjectID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.tokens[tokenID]
+	if !ok || t.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	t.RevokedAt = &now
+
+	return true, nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeRefreshTokenFamily(ctx context.Context, familyID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.FamilyID == familyID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeUserRefreshTokens(ctx context.Context, userID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.RevokedAt == nil {
+			t.RevokedAt = &now
+		}
+	}
+
+	return nil
+}
+
+// Tokens of the family that can still be refreshed
+func (r *fakeRefreshTokenRepo) activeInFamily(familyID primitive.ObjectID) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	active := 0
+	for _, t := range r.tokens {
+		if t.FamilyID == familyID && t.RevokedAt == nil {
+			active++
+		}
+	}
+
+	return active
+}
+
+type fakeDenylistRepo struct {
+	auth.TokenDenylistRepository
+	mu      sync.Mutex
+	revoked []domain.RevokedToken
+}
+
+func (r *fakeDenylistRepo) CreateRevokedToken(ctx context.Context, token *domain.RevokedToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.revoked = append(r.revoked, *token)
+
+	return nil
+}
+
+func (r *fakeDenylistRepo) IsRevoked(ctx context.Context, jti string, userID primitive.ObjectID, issuedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, t := range r.revoked {
+		if jti != "" && t.JTI == jti {
+			return true, nil
+		}
+		if t.JTI == "" && t.UserID == userID && !t.CreatedAt.Before(issuedAt) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+type fakePasswordResetRepo struct {
+	auth.PasswordResetRepository
+	mu     sync.Mutex
+	tokens map[primitive.ObjectID]*domain.PasswordResetToken
+}
+
+func (r *fakePasswordResetRepo) CreatePasswordResetToken(ctx context.Context, token *domain.PasswordResetToken) (*domain.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token.ID = primitive.NewObjectID()
+	stored := *token
+	r.tokens[token.ID] = &stored
+
+	return token, nil
+}
+
+func (r *fakePasswordResetRepo) FindPasswordResetTokenByHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			found := *t
+			return &found, nil
+		}
+	}
+
+	return nil, errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+}
+
+func (r *fakePasswordResetRepo) UsePasswordResetToken(ctx context.Context, tokenID primitive.ObjectID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.tokens[tokenID]
+	if !ok || t.UsedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	t.UsedAt = &now
+
+	return true, nil
+}
+
+func (r *fakePasswordResetRepo) InvalidateUserPasswordResetTokens(ctx context.Context, userID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.UsedAt == nil {
+			t.UsedAt = &now
+		}
+	}
+
+	return nil
+}
+
+// Keeps the messages it was asked to send
+type fakeNotifier struct {
+	mu       sync.Mutex
+	messages []notifier.Message
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, msg *notifier.Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.messages = append(n.messages, *msg)
+
+	return nil
+}
+
+func (n *fakeNotifier) sent() []notifier.Message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]notifier.Message(nil), n.messages...)
+}
+
+type fakeRoleRepo struct {
+	auth.RoleRepository
+	roles map[string][]string
+}
+
+func (r *fakeRoleRepo) FindByName(ctx context.Context, name string) (*domain.Role, error) {
+	permissions, ok := r.roles[name]
+	if !ok {
+		return nil, errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+
+	return &domain.Role{Name: name, Permissions: permissions}, nil
+}
+
+func (r *fakeRoleRepo) FindPermissions(ctx context.Context, name string) ([]string, error) {
+	return r.roles[name], nil
+}
+
+type fakeAPIKeyRepo struct {
+	auth.APIKeyRepository
+	mu   sync.Mutex
+	keys map[primitive.ObjectID]*domain.APIKey
+}
+
+func (r *fakeAPIKeyRepo) CreateAPIKey(ctx context.Context, key *domain.APIKey) (*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key.ID = primitive.NewObjectID()
+	stored := *key
+	r.keys[key.ID] = &stored
+
+	return key, nil
+}
+
+func (r *fakeAPIKeyRepo) FindAPIKeyByID(ctx context.Context, keyID primitive.ObjectID) (*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.keys[keyID]
+	if !ok {
+		return nil, errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+	found := *stored
+
+	return &found, nil
+}
+
+func (r *fakeAPIKeyRepo) FindAPIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stored := range r.keys {
+		if stored.KeyHash == keyHash {
+			found := *stored
+			return &found, nil
+		}
+	}
+
+	return nil, errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+}
+
+func (r *fakeAPIKeyRepo) FetchAPIKeys(ctx context.Context, userID primitive.ObjectID) ([]*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make([]*domain.APIKey, 0)
+	for _, stored := range r.keys {
+		if stored.UserID == userID {
+			found := *stored
+			keys = append(keys, &found)
+		}
+	}
+
+	return keys, nil
+}
+
+func (r *fakeAPIKeyRepo) RevokeAPIKey(ctx context.Context, keyID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.keys[keyID]
+	if !ok {
+		return errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+	now := time.Now()
+	stored.RevokedAt = &now
+
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) TouchAPIKey(ctx context.Context, keyID primitive.ObjectID, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, ok := r.keys[keyID]; ok {
+		stored.LastUsedAt = &usedAt
+	}
+
+	return nil
+}
+
+type fakeInviteRepo struct {
+	auth.InviteRepository
+	mu      sync.Mutex
+	invites map[primitive.ObjectID]*domain.Invite
+}
+
+func (r *fakeInviteRepo) CreateInvite(ctx context.Context, invite *domain.Invite) (*domain.Invite, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	invite.ID = primitive.NewObjectID()
+	stored := *invite
+	r.invites[invite.ID] = &stored
+
+	return invite, nil
+}
+
+func (r *fakeInviteRepo) UseInvite(ctx context.Context, inviteID primitive.ObjectID, username string) (*domain.Invite, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	invite, ok := r.invites[inviteID]
+	now := time.Now()
+	if !ok || invite.UsedAt != nil || !invite.ExpiresAt.After(now) {
+		return nil, errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+	invite.UsedAt = &now
+	invite.UsedBy = username
+	used := *invite
+
+	return &used, nil
+}
+
+func (r *fakeInviteRepo) ReleaseInvite(ctx context.Context, inviteID primitive.ObjectID, username string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if invite, ok := r.invites[inviteID]; ok && invite.UsedBy == username {
+		invite.UsedAt = nil
+		invite.UsedBy = ""
+	}
+
+	return nil
+}
+
+func (r *fakeInviteRepo) find(inviteID primitive.ObjectID) domain.Invite {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return *r.invites[inviteID]
+}
+
+type fakeMFARepo struct {
+	auth.MFARepository
+	mu  sync.Mutex
+	mfa map[primitive.ObjectID]*domain.MFA
+}
+
+func (r *fakeMFARepo) FindMFA(ctx context.Context, userID primitive.ObjectID) (*domain.MFA, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mfa, ok := r.mfa[userID]
+	if !ok {
+		return nil, nil
+	}
+	found := *mfa
+
+	return &found, nil
+}
+
+func (r *fakeMFARepo) UseStep(ctx context.Context, userID primitive.ObjectID, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mfa, ok := r.mfa[userID]
+	if !ok || mfa.LastStep >= step {
+		return false, nil
+	}
+	mfa.LastStep = step
+
+	return true, nil
+}
+
+func (r *fakeMFARepo) UseRecoveryCode(ctx context.Context, userID primitive.ObjectID, codeHash string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	mfa, ok := r.mfa[userID]
+	if !ok {
+		return false, nil
+	}
+	for i, hash := range mfa.RecoveryCodes {
+		if hash == codeHash {
+			mfa.RecoveryCodes = append(mfa.RecoveryCodes[:i:i], mfa.RecoveryCodes[i+1:]...)
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+type fakeMFAChallengeRepo struct {
+	auth.MFAChallengeRepository
+	mu         sync.Mutex
+	challenges map[string]*domain.MFAChallenge
+}
+
+func (r *fakeMFAChallengeRepo) CreateMFAChallenge(ctx context.Context, challenge *domain.MFAChallenge) (*domain.MFAChallenge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	challenge.ID = primitive.NewObjectID()
+	stored := *challenge
+	r.challenges[challenge.TokenHash] = &stored
+
+	return challenge, nil
+}
+
+func (r *fakeMFAChallengeRepo) AttemptMFAChallenge(ctx context.Context, tokenHash string, maxAttempts int) (*domain.MFAChallenge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	challenge, ok := r.challenges[tokenHash]
+	if !ok || !challenge.ExpiresAt.After(time.Now()) || challenge.Attempts >= maxAttempts {
+		return nil, errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+	challenge.Attempts++
+	found := *challenge
+
+	return &found, nil
+}
+
+func (r *fakeMFAChallengeRepo) DeleteMFAChallenge(ctx context.Context, challengeID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for hash, challenge := range r.challenges {
+		if challenge.ID == challengeID {
+			delete(r.challenges, hash)
+		}
+	}
+
+	return nil
+}
+
+func (r *fakeMFAChallengeRepo) DeleteUserMFAChallenges(ctx context.Context, userID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for hash, challenge := range r.challenges {
+		if challenge.UserID == userID {
+			delete(r.challenges, hash)
+		}
+	}
+
+	return nil
+}
+
+type fakeOIDCStateRepo struct {
+	auth.OIDCStateRepository
+	mu     sync.Mutex
+	states map[string]*domain.OIDCState
+}
+
+func (r *fakeOIDCStateRepo) CreateOIDCState(ctx context.Context, state *domain.OIDCState) (*domain.OIDCState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state.ID = primitive.NewObjectID()
+	stored := *state
+	r.states[state.StateHash] = &stored
+
+	return state, nil
+}
+
+func (r *fakeOIDCStateRepo) UseOIDCState(ctx context.Context, stateHash string) (*domain.OIDCState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state, ok := r.states[stateHash]
+	if !ok || !state.ExpiresAt.After(time.Now()) {
+		return nil, errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+	delete(r.states, stateHash)
+
+	return state, nil
+}
+
+// Push every pending state past its expiry
+func (r *fakeOIDCStateRepo) expireAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, state := range r.states {
+		state.ExpiresAt = time.Now().Add(-time.Second)
+	}
+}
+
+type fakeLoginLockRepo struct {
+	auth.LoginLockRepository
+	mu    sync.Mutex
+	locks map[string]*domain.LoginLock
+}
+
+func (r *fakeLoginLockRepo) FindLoginLock(ctx context.Context, kind, value string) (*domain.LoginLock, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lock, ok := r.locks[kind+":"+value]
+	if !ok || !lock.ExpiresAt.After(time.Now()) {
+		return nil, nil
+	}
+	found := *lock
+
+	return &found, nil
+}
+
+func (r *fakeLoginLockRepo) RecordLoginFailure(ctx context.Context, kind, value string, failedAt, expiresAt time.Time) (*domain.LoginLock, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lock, ok := r.locks[kind+":"+value]
+	if !ok || !lock.ExpiresAt.After(failedAt) {
+		lock = &domain.LoginLock{ID: primitive.NewObjectID(), Kind: kind, Value: value}
+		r.locks[kind+":"+value] = lock
+	}
+	lock.Failures++
+	lock.LastFailureAt = failedAt
+	if expiresAt.After(lock.ExpiresAt) {
+		lock.ExpiresAt = expiresAt
+	}
+	found := *lock
+
+	return &found, nil
+}
+
+func (r *fakeLoginLockRepo) DelayLogin(ctx context.Context, lockID primitive.ObjectID, nextAttemptAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, lock := range r.locks {
+		if lock.ID == lockID && (lock.NextAttemptAt == nil || nextAttemptAt.After(*lock.NextAttemptAt)) {
+			lock.NextAttemptAt = &nextAttemptAt
+		}
+	}
+
+	return nil
+}
+
+func (r *fakeLoginLockRepo) LockLogin(ctx context.Context, lockID primitive.ObjectID, threshold int, lockedUntil, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, lock := range r.locks {
+		if lock.ID == lockID && lock.Failures >= threshold {
+			lock.Failures = 0
+			lock.LockedUntil = &lockedUntil
+			lock.Lockouts++
+			if expiresAt.After(lock.ExpiresAt) {
+				lock.ExpiresAt = expiresAt
+			}
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *fakeLoginLockRepo) ClearLoginFailures(ctx context.Context, kind, value string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.locks, kind+":"+value)
+
+	return nil
+}
+
+func (r *fakeLoginLockRepo) find(kind, value string) (domain.LoginLock, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lock, ok := r.locks[kind+":"+value]
+	if !ok {
+		return domain.LoginLock{}, false
+	}
+
+	return *lock, true
+}
+
+type fakeAuditUsecase struct {
+	audit.Usecase
+	mu      sync.Mutex
+	entries []*domain.AuditEntry
+}
+
+func (u *fakeAuditUsecase) Record(ctx context.Context, entry *domain.AuditEntry, before, after interface{}) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.entries = append(u.entries, entry)
+}
+
+func (u *fakeAuditUsecase) actions() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	actions := make([]string, 0, len(u.entries))
+	for _, entry := range u.entries {
+		actions = append(actions, entry.Action)
+	}
+
+	return actions
+}
+
+type fakePublisher struct{}
+
+func (fakePublisher) Publish(ctx context.Context, eventType string, data interface{}) {}
+
+// Usecase over the fakes, with the admin and default roles in place
+type testAuth struct {
+	*AuthUsecase
+	cfg           *config.Config
+	users         *fakeUserRepo
+	refreshTokens *fakeRefreshTokenRepo
+	denylist      *fakeDenylistRepo
+	resets        *fakePasswordResetRepo
+	notifier      *fakeNotifier
+	invites       *fakeInviteRepo
+	apiKeys       *fakeAPIKeyRepo
+	oidcStates    *fakeOIDCStateRepo
+	loginLocks    *fakeLoginLockRepo
+	mfa           *fakeMFARepo
+	mfaChallenges *fakeMFAChallengeRepo
+	sessions      auth.SessionRepository
+	audit         *fakeAuditUsecase
+}
+
+func testConfig() *config.Config {
+	return &config.Config{
+		Server: config.ServerConfig{JwtSecretKey: "test-secret", InviteSecret: "test-invite-secret"},
+		Logger: config.Logger{Level: "fatal", Encoding: "console"},
+	}
+}
+
+func newTestAuth(t *testing.T, cfg *config.Config, providers oidc.Providers) *testAuth {
+	t.Helper()
+
+	keys, err := keyring.NewKeyRing(nil, cfg.Server.JwtSecretKey, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	log := logger.NewLogger(cfg)
+	log.InitLogger()
+
+	ta := &testAuth{
+		cfg:           cfg,
+		users:         &fakeUserRepo{users: make(map[primitive.ObjectID]*domain.User)},
+		refreshTokens: &fakeRefreshTokenRepo{tokens: make(map[primitive.ObjectID]*domain.RefreshToken)},
+		denylist:      &fakeDenylistRepo{},
+		resets:        &fakePasswordResetRepo{tokens: make(map[primitive.ObjectID]*domain.PasswordResetToken)},
+		notifier:      &fakeNotifier{},
+		invites:       &fakeInviteRepo{invites: make(map[primitive.ObjectID]*domain.Invite)},
+		apiKeys:       &fakeAPIKeyRepo{keys: make(map[primitive.ObjectID]*domain.APIKey)},
+		oidcStates:    &fakeOIDCStateRepo{states: make(map[string]*domain.OIDCState)},
+		loginLocks:    &fakeLoginLockRepo{locks: make(map[string]*domain.LoginLock)},
+		mfa:           &fakeMFARepo{mfa: make(map[primitive.ObjectID]*domain.MFA)},
+		mfaChallenges: &fakeMFAChallengeRepo{challenges: make(map[string]*domain.MFAChallenge)},
+		sessions:      authRepository.NewSessionMemoryRepo(),
+		audit:         &fakeAuditUsecase{},
+	}
+
+	ta.AuthUsecase = NewAuthUsecase(cfg, Repositories{
+		Auth:         ta.users,
+		RefreshToken: ta.refreshTokens,
+		Denylist:     ta.denylist,
+		Reset:        ta.resets,
+		Role: &fakeRoleRepo{roles: map[string][]string{
+			domain.AdminRole:   domain.Permissions,
+			domain.DefaultRole: domain.DefaultPermissions,
+		}},
+		Monster:      fakeMonsterRepo{},
+		Invite:       ta.invites,
+		APIKey:       ta.apiKeys,
+		OIDCState:    ta.oidcStates,
+		MFA:          ta.mfa,
+		MFAChallenge: ta.mfaChallenges,
+		LoginLock:    ta.loginLocks,
+		Session:      ta.sessions,
+	}, Services{
+		Notifier:      ta.notifier,
+		OIDCProviders: providers,
+		KeyRing:       keys,
+		Audit:         ta.audit,
+		Publisher:     fakePublisher{},
+	}, log).(*AuthUsecase)
+
+	return ta
+}
+
+// Register a user straight in the store, bypassing the usecase
+func (ta *testAuth) addUser(t *testing.T, username, role string) *domain.User {
+	t.Helper()
+
+	user := &domain.User{Username: username, Password: "password", Role: &role}
+	if err := user.PrepareCreate(); err != nil {
+		t.Fatal(err)
+	}
+	created, err := ta.users.CreateUser(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return created
+}
@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/totp"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+func lockoutConfig() *config.Config {
+	cfg := testConfig()
+	cfg.Lockout = config.Lockout{
+		UsernameThreshold: 3,
+		IPThreshold:       5,
+		FreeAttempts:      1,
+		Delay:             10,
+		Duration:          60,
+		MaxDuration:       600,
+		Window:            900,
+	}
+
+	return cfg
+}
+
+type signIn struct {
+	username string
+	ip       string
+}
+
+func (s signIn) ctx() context.Context {
+	if s.ip == "" {
+		return context.Background()
+	}
+	return context.WithValue(context.Background(), utils.ClientIPCtxKey{}, s.ip)
+}
+
+func (s signIn) targets() []loginTarget {
+	return loginTargets(s.ctx(), s.username)
+}
+
+func TestLoginLockout(t *testing.T) {
+	repeat := func(n int, attempt signIn) []signIn {
+		attempts := make([]signIn, n)
+		for i := range attempts {
+			attempts[i] = attempt
+		}
+		return attempts
+	}
+	usernames := func(n int, ip string) []signIn {
+		attempts := make([]signIn, n)
+		for i := range attempts {
+			attempts[i] = signIn{username: fmt.Sprintf("ghost-%d", i), ip: ip}
+		}
+		return attempts
+	}
+
+	tests := []struct {
+		name       string
+		failures   []signIn
+		next       signIn
+		retryAfter time.Duration
+		locked     []loginTarget
+	}{
+		{
+			name:     "free attempt of an unknown username",
+			failures: repeat(1, signIn{"ghost", "10.0.0.1"}),
+			next:     signIn{"ghost", "10.0.0.1"},
+		},
+		{
+			name:       "delay after the free attempts",
+			failures:   repeat(2, signIn{"ghost", "10.0.0.1"}),
+			next:       signIn{"ghost", "10.0.0.1"},
+			retryAfter: 10 * time.Second,
+		},
+		{
+			name:       "delay of a username applies from every IP",
+			failures:   repeat(2, signIn{"ghost", "10.0.0.1"}),
+			next:       signIn{"ghost", "10.0.0.2"},
+			retryAfter: 10 * time.Second,
+		},
+		{
+			name:       "unknown username locks at the threshold",
+			failures:   repeat(3, signIn{"ghost", "10.0.0.1"}),
+			next:       signIn{"ghost", "10.0.0.2"},
+			retryAfter: time.Minute,
+			locked:     []loginTarget{{kind: domain.LockKindUsername, value: "ghost"}},
+		},
+		{
+			name:       "IP counter spans usernames",
+			failures:   usernames(5, "10.0.0.1"),
+			next:       signIn{"misty", "10.0.0.1"},
+			retryAfter: time.Minute,
+			locked:     []loginTarget{{kind: domain.LockKindIP, value: "10.0.0.1"}},
+		},
+		{
+			name:     "lock of an IP leaves other IPs alone",
+			failures: usernames(5, "10.0.0.1"),
+			next:     signIn{"misty", "10.0.0.2"},
+			locked:   []loginTarget{{kind: domain.LockKindIP, value: "10.0.0.1"}},
+		},
+		{
+			name:       "IP below its threshold is only delayed",
+			failures:   usernames(4, "10.0.0.1"),
+			next:       signIn{"misty", "10.0.0.1"},
+			retryAfter: 40 * time.Second,
+		},
+		{
+			name:     "without a client IP only usernames are counted",
+			failures: usernames(5, ""),
+			next:     signIn{"misty", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestAuth(t, lockoutConfig(), nil)
+
+			for _, failure := range tt.failures {
+				ta.loginFailed(failure.ctx(), failure.targets())
+			}
+
+			err := ta.loginAllowed(tt.next.ctx(), tt.next.targets())
+			if tt.retryAfter == 0 {
+				if err != nil {
+					t.Fatalf("next sign in refused: %v", err)
+				}
+			} else {
+				assertStatus(t, err, http.StatusTooManyRequests)
+				retryAfter := err.(httpErr.RetryAfterError).RetryAfter
+				if retryAfter > tt.retryAfter || retryAfter < tt.retryAfter-2*time.Second {
+					t.Errorf("retry after %s, want %s", retryAfter, tt.retryAfter)
+				}
+			}
+
+			for _, target := range tt.locked {
+				lock, ok := ta.loginLocks.find(target.kind, target.value)
+				if !ok || lock.LockedUntil == nil || lock.Lockouts != 1 || lock.Failures != 0 {
+					t.Errorf("%s %s not locked: %+v", target.kind, target.value, lock)
+				}
+			}
+			if len(tt.locked) == 0 {
+				for _, failure := range tt.failures {
+					for _, target := range failure.targets() {
+						if lock, _ := ta.loginLocks.find(target.kind, target.value); lock.LockedUntil != nil {
+							t.Errorf("%s %s locked", target.kind, target.value)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestUserAuthenticationLockout(t *testing.T) {
+	cfg := lockoutConfig()
+	cfg.Lockout.FreeAttempts = 3
+
+	ta := newTestAuth(t, cfg, nil)
+	ta.addUser(t, "ash", domain.DefaultRole)
+	ip := "10.0.0.1"
+	ctx := signIn{"ash", ip}.ctx()
+
+	if _, err := ta.UserAuthentication(ctx, &domain.User{Username: "ghost", Password: "password"}); err == nil {
+		t.Fatal("unknown username signed in")
+	}
+	if lock, ok := ta.loginLocks.find(domain.LockKindUsername, "ghost"); !ok || lock.Failures != 1 {
+		t.Errorf("failure of an unknown username not counted: %+v", lock)
+	}
+
+	if _, err := ta.UserAuthentication(ctx, &domain.User{Username: "ash", Password: "wrong-password"}); err == nil {
+		t.Fatal("wrong password signed in")
+	}
+	if _, err := ta.UserAuthentication(ctx, &domain.User{Username: "ash", Password: "password"}); err != nil {
+		t.Fatalf("sign in after a free failure: %v", err)
+	}
+
+	if _, ok := ta.loginLocks.find(domain.LockKindUsername, "ash"); ok {
+		t.Error("failures of the username were kept after signing in")
+	}
+	if lock, ok := ta.loginLocks.find(domain.LockKindIP, ip); !ok || lock.Failures != 2 {
+		t.Errorf("failures of the IP were reset by signing in: %+v", lock)
+	}
+}
+
+func TestMFAVerificationLockout(t *testing.T) {
+	cfg := lockoutConfig()
+	cfg.Lockout.FreeAttempts = 3
+
+	ta := newTestAuth(t, cfg, nil)
+	ctx := signIn{"ash", "10.0.0.1"}.ctx()
+
+	enroll := func(username string) string {
+		user := ta.addUser(t, username, domain.DefaultRole)
+		secret, err := totp.GenerateSecret()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ta.mfa.mfa[user.ID] = &domain.MFA{UserID: user.ID, Secret: secret, Enabled: true}
+		return secret
+	}
+	challenge := func(username string) string {
+		t.Helper()
+		result, err := ta.UserAuthentication(ctx, &domain.User{Username: username, Password: "password"})
+		if err != nil {
+			t.Fatalf("password sign in: %v", err)
+		}
+		if !result.MFARequired || result.MFAToken == "" {
+			t.Fatalf("no challenge for %s", username)
+		}
+		return result.MFAToken
+	}
+	currentCode := func(secret string) string {
+		code, err := totp.Code(secret, totp.Step(time.Now()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	secret := enroll("ash")
+
+	replaced := challenge("ash")
+	open := challenge("ash")
+	_, err := ta.MFAVerification(ctx, &domain.MFAVerification{MFAToken: replaced, Code: "wrong-code"})
+	assertStatus(t, err, http.StatusUnauthorized)
+	if lock, ok := ta.loginLocks.find(domain.LockKindUsername, "ash"); ok {
+		t.Errorf("attempt at a replaced challenge counted: %+v", lock)
+	}
+
+	// Every wrong code counts against the username, however many challenges are opened
+	for i := 1; i <= cfg.Lockout.UsernameThreshold; i++ {
+		if i > 1 {
+			open = challenge("ash")
+		}
+		_, err := ta.MFAVerification(ctx, &domain.MFAVerification{MFAToken: open, Code: "wrong-code"})
+		assertStatus(t, err, http.StatusUnauthorized)
+
+		lock, _ := ta.loginLocks.find(domain.LockKindUsername, "ash")
+		if i < cfg.Lockout.UsernameThreshold && lock.Failures != i {
+			t.Fatalf("%d wrong codes counted as %d failures", i, lock.Failures)
+		}
+	}
+	if lock, _ := ta.loginLocks.find(domain.LockKindUsername, "ash"); lock.LockedUntil == nil {
+		t.Fatalf("username not locked after %d wrong codes: %+v", cfg.Lockout.UsernameThreshold, lock)
+	}
+
+	_, err = ta.MFAVerification(ctx, &domain.MFAVerification{MFAToken: open, Code: currentCode(secret)})
+	assertStatus(t, err, http.StatusTooManyRequests)
+	_, err = ta.UserAuthentication(ctx, &domain.User{Username: "ash", Password: "password"})
+	assertStatus(t, err, http.StatusTooManyRequests)
+
+	// A correct code completes the sign in and only then forgets the failures of the username.
+	// Another IP keeps the failures of the first one out of the way.
+	ctx = signIn{"misty", "10.0.0.2"}.ctx()
+	secret = enroll("misty")
+	_, err = ta.MFAVerification(ctx, &domain.MFAVerification{MFAToken: challenge("misty"), Code: "wrong-code"})
+	assertStatus(t, err, http.StatusUnauthorized)
+	token := challenge("misty")
+	if lock, ok := ta.loginLocks.find(domain.LockKindUsername, "misty"); !ok || lock.Failures != 1 {
+		t.Errorf("failures cleared by the password alone: %+v", lock)
+	}
+	if _, err := ta.MFAVerification(ctx, &domain.MFAVerification{MFAToken: token, Code: currentCode(secret)}); err != nil {
+		t.Fatalf("correct code refused: %v", err)
+	}
+	if _, ok := ta.loginLocks.find(domain.LockKindUsername, "misty"); ok {
+		t.Error("failures of the username kept after the code was accepted")
+	}
+}
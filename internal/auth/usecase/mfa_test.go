@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/totp"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+func TestVerifyMFACode(t *testing.T) {
+	ta := newTestAuth(t, testConfig(), nil)
+	user := ta.addUser(t, "ash", domain.DefaultRole)
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovery := "abcde-fghij"
+	ta.mfa.mfa[user.ID] = &domain.MFA{
+		UserID:        user.ID,
+		Secret:        secret,
+		Enabled:       true,
+		RecoveryCodes: []string{utils.HashToken(totp.NormalizeRecoveryCode(recovery))},
+	}
+
+	code := func(step int64) string {
+		c, err := totp.Code(secret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	// Cases run in order against the same enrollment, the steps stay in the skew window
+	// even if the clock crosses into the next step while they run
+	current := totp.Step(time.Now())
+
+	tests := []struct {
+		name string
+		code string
+		ok   bool
+	}{
+		{name: "current code", code: code(current), ok: true},
+		{name: "replayed code", code: code(current)},
+		{name: "code of an earlier step", code: code(current - 1)},
+		{name: "code of the next step", code: code(current + 1), ok: true},
+		{name: "code older than the last used", code: code(current)},
+		{name: "recovery code without case and separator", code: "ABCDEFGHIJ", ok: true},
+		{name: "recovery code used twice", code: recovery},
+		{name: "unknown recovery code", code: "zzzzz-zzzzz"},
+	}
+	for _, tt := range tests {
+		mfa, err := ta.mfa.FindMFA(context.Background(), user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ok, err := ta.verifyMFACode(context.Background(), mfa, tt.code)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if ok != tt.ok {
+			t.Errorf("%s: got %v, want %v", tt.name, ok, tt.ok)
+		}
+	}
+}
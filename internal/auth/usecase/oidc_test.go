@@ -0,0 +1,367 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/oidc"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+const (
+	idpProvider = "mock"
+	idpClientID = "pokedex"
+	idpKeyID    = "idp-key"
+)
+
+// Identity provider serving discovery, token and JWKS endpoints. Codes are handed out by
+// authorize for the nonce of an authorization URL, as the login page would after sign-in.
+type mockIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]idpGrant
+}
+
+type idpGrant struct {
+	subject string
+	nonce   string
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &mockIdP{key: key, codes: make(map[string]idpGrant)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": idpKeyID,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		idp.mu.Lock()
+		grant, ok := idp.codes[r.PostFormValue("code")]
+		delete(idp.codes, r.PostFormValue("code"))
+		idp.mu.Unlock()
+		if !ok {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, map[string]string{"id_token": idp.idToken(t, grant)})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+func (idp *mockIdP) providers() oidc.Providers {
+	return oidc.Providers{idpProvider: oidc.NewProvider(idpProvider, config.OIDCProvider{
+		Issuer:      idp.server.URL,
+		ClientID:    idpClientID,
+		RedirectURL: "http://localhost/auth/oidc/" + idpProvider + "/callback",
+	})}
+}
+
+// Sign the subject in for the authorization URL, returns the code and state to call back with
+func (idp *mockIdP) authorize(t *testing.T, authorizationURL, subject string) (code, state string) {
+	t.Helper()
+
+	query := authorizationURL[len(idp.server.URL+"/authorize?"):]
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return idp.grant(t, idpGrant{subject: subject, nonce: values.Get("nonce")}), values.Get("state")
+}
+
+func (idp *mockIdP) grant(t *testing.T, grant idpGrant) string {
+	t.Helper()
+
+	code, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.codes[code] = grant
+
+	return code
+}
+
+func (idp *mockIdP) idToken(t *testing.T, grant idpGrant) string {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   idp.server.URL,
+		"sub":   grant.subject,
+		"aud":   idpClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Minute).Unix(),
+		"nonce": grant.nonce,
+	})
+	token.Header["kid"] = idpKeyID
+
+	signed, err := token.SignedString(idp.key)
+	if err != nil {
+		t.Error(err)
+	}
+
+	return signed
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestOIDCCallback(t *testing.T) {
+	ctx := context.Background()
+
+	// Start a flow for the user to link to, nil for a sign-in, and sign the subject in at the provider
+	start := func(t *testing.T, ta *testAuth, idp *mockIdP, linkUserID *primitive.ObjectID, subject string) *domain.OIDCCallback {
+		t.Helper()
+
+		authorization, err := ta.OIDCAuthorization(ctx, idpProvider, linkUserID)
+		if err != nil {
+			t.Fatalf("OIDCAuthorization: %v", err)
+		}
+		code, state := idp.authorize(t, authorization.AuthorizationURL, subject)
+		if state != authorization.State {
+			t.Fatalf("authorization URL has state %q, want %q", state, authorization.State)
+		}
+
+		return &domain.OIDCCallback{Code: code, State: state, BrowserState: authorization.State}
+	}
+
+	t.Run("signs in a new identity", func(t *testing.T) {
+		idp := newMockIdP(t)
+		ta := newTestAuth(t, testConfig(), idp.providers())
+
+		signedIn, err := ta.OIDCCallback(ctx, idpProvider, start(t, ta, idp, nil, "subject-1"))
+		if err != nil {
+			t.Fatalf("OIDCCallback: %v", err)
+		}
+		if signedIn.Token == "" {
+			t.Error("no access token")
+		}
+
+		again, err := ta.OIDCCallback(ctx, idpProvider, start(t, ta, idp, nil, "subject-1"))
+		if err != nil {
+			t.Fatalf("second OIDCCallback: %v", err)
+		}
+		if again.User.ID != signedIn.User.ID {
+			t.Error("the identity was provisioned twice")
+		}
+	})
+
+	t.Run("rejects a state from another browser", func(t *testing.T) {
+		idp := newMockIdP(t)
+		ta := newTestAuth(t, testConfig(), idp.providers())
+
+		// The attacker started the flow and sends the victim the callback URL
+		attacker := start(t, ta, idp, nil, "attacker")
+		victim := start(t, ta, idp, nil, "victim")
+		attacker.BrowserState = victim.BrowserState
+
+		_, err := ta.OIDCCallback(ctx, idpProvider, attacker)
+		assertStatus(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("rejects a callback without the state cookie", func(t *testing.T) {
+		idp := newMockIdP(t)
+		ta := newTestAuth(t, testConfig(), idp.providers())
+
+		callback := start(t, ta, idp, nil, "subject-1")
+		callback.BrowserState = ""
+
+		_, err := ta.OIDCCallback(ctx, idpProvider, callback)
+		assertStatus(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("rejects an expired state", func(t *testing.T) {
+		idp := newMockIdP(t)
+		ta := newTestAuth(t, testConfig(), idp.providers())
+
+		callback := start(t, ta, idp, nil, "subject-1")
+		ta.oidcStates.expireAll()
+
+		_, err := ta.OIDCCallback(ctx, idpProvider, callback)
+		assertStatus(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("rejects a redeemed state", func(t *testing.T) {
+		idp := newMockIdP(t)
+		ta := newTestAuth(t, testConfig(), idp.providers())
+
+		callback := start(t, ta, idp, nil, "subject-1")
+		if _, err := ta.OIDCCallback(ctx, idpProvider, callback); err != nil {
+			t.Fatalf("OIDCCallback: %v", err)
+		}
+
+		callback.Code = idp.grant(t, idpGrant{subject: "subject-1"})
+		_, err := ta.OIDCCallback(ctx, idpProvider, callback)
+		assertStatus(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("rejects an ID token replayed from another flow", func(t *testing.T) {
+		idp := newMockIdP(t)
+		ta := newTestAuth(t, testConfig(), idp.providers())
+
+		authorization, err := ta.OIDCAuthorization(ctx, idpProvider, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values, _ := url.ParseQuery(authorization.AuthorizationURL[len(idp.server.URL+"/authorize?"):])
+		firstNonce := values.Get("nonce")
+
+		// The next flow gets a token issued for the nonce of the first
+		callback := start(t, ta, idp, nil, "subject-1")
+		callback.Code = idp.grant(t, idpGrant{subject: "subject-1", nonce: firstNonce})
+
+		_, err = ta.OIDCCallback(ctx, idpProvider, callback)
+		assertStatus(t, err, http.StatusUnauthorized)
+	})
+
+	t.Run("links an identity to the user who started the flow", func(t *testing.T) {
+		idp := newMockIdP(t)
+		ta := newTestAuth(t, testConfig(), idp.providers())
+		user := ta.addUser(t, "ash", domain.DefaultRole)
+
+		signedIn, err := ta.OIDCCallback(ctx, idpProvider, start(t, ta, idp, &user.ID, "subject-1"))
+		if err != nil {
+			t.Fatalf("OIDCCallback: %v", err)
+		}
+		if signedIn.User.ID != user.ID {
+			t.Errorf("signed in %s, want %s", signedIn.User.ID.Hex(), user.ID.Hex())
+		}
+
+		linked, _ := ta.users.FindByIdentity(ctx, idpProvider, "subject-1")
+		if linked == nil || linked.ID != user.ID {
+			t.Error("identity was not linked")
+		}
+	})
+
+	t.Run("refuses to link an identity linked to another user", func(t *testing.T) {
+		idp := newMockIdP(t)
+		ta := newTestAuth(t, testConfig(), idp.providers())
+		owner := ta.addUser(t, "ash", domain.DefaultRole)
+		other := ta.addUser(t, "gary", domain.DefaultRole)
+
+		if _, err := ta.OIDCCallback(ctx, idpProvider, start(t, ta, idp, &owner.ID, "subject-1")); err != nil {
+			t.Fatalf("OIDCCallback: %v", err)
+		}
+
+		_, err := ta.OIDCCallback(ctx, idpProvider, start(t, ta, idp, &other.ID, "subject-1"))
+		assertStatus(t, err, http.StatusConflict)
+
+		found, err := ta.users.FindByID(ctx, other.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found.Identities) != 0 {
+			t.Error("identity was linked to a second user")
+		}
+	})
+}
+
+func TestOIDCStateCookie(t *testing.T) {
+	idp := newMockIdP(t)
+	ta := newTestAuth(t, testConfig(), idp.providers())
+	e := ta.routes()
+
+	login := func(t *testing.T) (*http.Cookie, string, string) {
+		t.Helper()
+
+		rec := ta.request(e, http.MethodGet, "/auth/oidc/"+idpProvider+"/login", "", "")
+		if rec.Code != http.StatusFound {
+			t.Fatalf("got status %d: %s", rec.Code, rec.Body)
+		}
+
+		var cookie *http.Cookie
+		for _, c := range rec.Result().Cookies() {
+			if c.Name == utils.OIDCStateCookieName {
+				cookie = c
+			}
+		}
+		if cookie == nil {
+			t.Fatal("no state cookie was set")
+		}
+		if !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode {
+			t.Errorf("state cookie is not HttpOnly and SameSite=Lax: %+v", cookie)
+		}
+
+		code, state := idp.authorize(t, rec.Header().Get("Location"), "subject-1")
+		return cookie, code, state
+	}
+
+	callback := func(code, state string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/auth/oidc/"+idpProvider+"/callback?"+url.Values{
+			"code":  {code},
+			"state": {state},
+		}.Encode(), nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	t.Run("accepts the browser that started the flow", func(t *testing.T) {
+		cookie, code, state := login(t)
+
+		if rec := callback(code, state, cookie); rec.Code != http.StatusOK {
+			t.Fatalf("got status %d: %s", rec.Code, rec.Body)
+		}
+	})
+
+	t.Run("rejects another browser", func(t *testing.T) {
+		_, code, state := login(t)
+		otherCookie, _, _ := login(t)
+
+		if rec := callback(code, state, otherCookie); rec.Code != http.StatusBadRequest {
+			t.Fatalf("got status %d: %s", rec.Code, rec.Body)
+		}
+		if rec := callback(code, state, nil); rec.Code != http.StatusBadRequest {
+			t.Fatalf("without cookie got status %d: %s", rec.Code, rec.Body)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	authHttpV2 "github.com/iamaul/go-pokedex/internal/auth/delivery/http/v2"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+func TestPatchUserKeepsIDAndVersion(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		handler     func(ta *testAuth) *echo.Echo
+		contentType string
+		patch       func(other *domain.User) string
+		want        int
+	}{
+		{
+			name:        "v1 merge patch",
+			handler:     func(ta *testAuth) *echo.Echo { return ta.routes() },
+			contentType: utils.MIMEMergePatch,
+			patch: func(other *domain.User) string {
+				return `{"_id":"` + other.ID.Hex() + `","version":99,"username":"ash-ketchum"}`
+			},
+			want: http.StatusOK,
+		},
+		{
+			name:        "v1 JSON patch",
+			handler:     func(ta *testAuth) *echo.Echo { return ta.routes() },
+			contentType: utils.MIMEJSONPatch,
+			patch: func(other *domain.User) string {
+				return `[{"op":"replace","path":"/_id","value":"` + other.ID.Hex() + `"},{"op":"replace","path":"/version","value":99},{"op":"replace","path":"/username","value":"ash-ketchum"}]`
+			},
+			want: http.StatusOK,
+		},
+		{
+			name: "v2 merge patch of the id",
+			handler: func(ta *testAuth) *echo.Echo {
+				return ta.routesOf(authHttpV2.NewAuthHandler(ta.cfg, ta.AuthUsecase, ta.logger))
+			},
+			contentType: utils.MIMEMergePatch,
+			patch: func(other *domain.User) string {
+				return `{"id":"` + other.ID.Hex() + `","username":"ash-ketchum"}`
+			},
+			want: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "v2 merge patch of the version",
+			handler: func(ta *testAuth) *echo.Echo {
+				return ta.routesOf(authHttpV2.NewAuthHandler(ta.cfg, ta.AuthUsecase, ta.logger))
+			},
+			contentType: utils.MIMEMergePatch,
+			patch:       func(*domain.User) string { return `{"version":99,"username":"ash-ketchum"}` },
+			want:        http.StatusUnprocessableEntity,
+		},
+		{
+			name: "v2 JSON patch of the id",
+			handler: func(ta *testAuth) *echo.Echo {
+				return ta.routesOf(authHttpV2.NewAuthHandler(ta.cfg, ta.AuthUsecase, ta.logger))
+			},
+			contentType: utils.MIMEJSONPatch,
+			patch: func(other *domain.User) string {
+				return `[{"op":"add","path":"/id","value":"` + other.ID.Hex() + `"},{"op":"replace","path":"/username","value":"ash-ketchum"}]`
+			},
+			want: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "v2 merge patch of the username",
+			handler: func(ta *testAuth) *echo.Echo {
+				return ta.routesOf(authHttpV2.NewAuthHandler(ta.cfg, ta.AuthUsecase, ta.logger))
+			},
+			contentType: utils.MIMEMergePatch,
+			patch:       func(*domain.User) string { return `{"username":"ash-ketchum"}` },
+			want:        http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestAuth(t, testConfig(), nil)
+			me := ta.addUser(t, "ash", domain.DefaultRole)
+			other := ta.addUser(t, "gary", domain.DefaultRole)
+
+			req := httptest.NewRequest(http.MethodPatch, "/auth", strings.NewReader(tt.patch(other)))
+			req.Header.Set(echo.HeaderContentType, tt.contentType)
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+ta.accessToken(t, me))
+			rec := httptest.NewRecorder()
+			tt.handler(ta).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d (%s), want %d", rec.Code, rec.Body.String(), tt.want)
+			}
+
+			stored, err := ta.users.FindByID(ctx, me.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			untouched, err := ta.users.FindByID(ctx, other.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if untouched.Username != "gary" || untouched.Version != other.Version {
+				t.Errorf("the patch reached the other user: %s at version %d", untouched.Username, untouched.Version)
+			}
+
+			if tt.want != http.StatusOK {
+				if stored.Username != "ash" || stored.Version != me.Version {
+					t.Errorf("refused patch was applied: %s at version %d", stored.Username, stored.Version)
+				}
+				return
+			}
+			if stored.Username != "ash-ketchum" {
+				t.Errorf("username = %q, want the patch applied", stored.Username)
+			}
+			// The version only moves by the update itself
+			if stored.Version != me.Version+1 {
+				t.Errorf("version = %d, want %d", stored.Version, me.Version+1)
+			}
+			if got := rec.Header().Get(utils.HeaderETag); got != stored.ETag() {
+				t.Errorf("ETag = %q, want %q", got, stored.ETag())
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			id, _ := body["_id"].(string)
+			if id == "" {
+				id, _ = body["id"].(string)
+			}
+			if id != me.ID.Hex() {
+				t.Errorf("responded with id %q, want %s", id, me.ID.Hex())
+			}
+		})
+	}
+}
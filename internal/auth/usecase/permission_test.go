@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+)
+
+func TestHasPermission(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		role       string
+		requireMFA bool
+		mfa        *domain.MFA
+		permission string
+		want       bool
+	}{
+		{name: "admin", role: domain.AdminRole, permission: domain.PermUserDelete, want: true},
+		{name: "default role granted", role: domain.DefaultRole, permission: domain.PermMonsterRead, want: true},
+		{name: "default role refused", role: domain.DefaultRole, permission: domain.PermMonsterWrite},
+		{name: "no role", permission: domain.PermMonsterRead},
+		{name: "removed role", role: "gym-leader", permission: domain.PermMonsterRead},
+		{name: "unknown permission", role: domain.AdminRole, permission: "monster:evolve"},
+		{name: "admin without MFA when required", role: domain.AdminRole, requireMFA: true, permission: domain.PermMonsterRead},
+		{name: "admin with pending MFA when required", role: domain.AdminRole, requireMFA: true, mfa: &domain.MFA{}, permission: domain.PermMonsterRead},
+		{name: "admin with MFA when required", role: domain.AdminRole, requireMFA: true, mfa: &domain.MFA{Enabled: true}, permission: domain.PermUserDelete, want: true},
+		{name: "default role without MFA when required", role: domain.DefaultRole, requireMFA: true, permission: domain.PermMonsterRead, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.MFA.RequireForAdmins = tt.requireMFA
+			ta := newTestAuth(t, cfg, nil)
+
+			user := ta.addUser(t, "oak", tt.role)
+			if tt.role == "" {
+				user.Role = nil
+			}
+			if tt.mfa != nil {
+				tt.mfa.UserID = user.ID
+				ta.mfa.mfa[user.ID] = tt.mfa
+			}
+
+			got, err := ta.HasPermission(ctx, user, tt.permission)
+			if err != nil {
+				t.Fatalf("HasPermission: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HasPermission(%s) = %v, want %v", tt.permission, got, tt.want)
+			}
+		})
+	}
+}
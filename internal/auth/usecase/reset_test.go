@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+)
+
+// Token of the last reset message sent to the user
+func (ta *testAuth) resetToken(t *testing.T, username string) string {
+	t.Helper()
+
+	messages := ta.notifier.sent()
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].To != username {
+			continue
+		}
+		body := messages[i].Body
+		if link, _, ok := strings.Cut(body, "\n"); ok && strings.Contains(link, "?token=") {
+			_, token, _ := strings.Cut(link, "?token=")
+			unescaped, err := url.QueryUnescape(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return unescaped
+		}
+		_, token, _ := strings.Cut(body, "reset your password: ")
+		token, _, _ = strings.Cut(token, "\n")
+		return token
+	}
+
+	t.Fatalf("no reset message for %s", username)
+	return ""
+}
+
+func (ta *testAuth) assertPassword(t *testing.T, user *domain.User, password string) {
+	t.Helper()
+
+	stored, err := ta.users.FindByID(context.Background(), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stored.ComparePasswords(password); err != nil {
+		t.Errorf("password of %s is not %q", user.Username, password)
+	}
+}
+
+func TestPasswordReset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("token works once", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		user := ta.addUser(t, "ash", domain.DefaultRole)
+
+		if err := ta.PasswordResetRequest(ctx, "ash"); err != nil {
+			t.Fatalf("PasswordResetRequest: %v", err)
+		}
+		token := ta.resetToken(t, "ash")
+
+		if err := ta.PasswordReset(ctx, &domain.PasswordReset{Token: token, NewPassword: "pikachu"}); err != nil {
+			t.Fatalf("PasswordReset: %v", err)
+		}
+		ta.assertPassword(t, user, "pikachu")
+
+		err := ta.PasswordReset(ctx, &domain.PasswordReset{Token: token, NewPassword: "charmander"})
+		assertStatus(t, err, http.StatusBadRequest)
+		ta.assertPassword(t, user, "pikachu")
+	})
+
+	t.Run("expired token is refused", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		user := ta.addUser(t, "ash", domain.DefaultRole)
+
+		if err := ta.PasswordResetRequest(ctx, "ash"); err != nil {
+			t.Fatal(err)
+		}
+		token := ta.resetToken(t, "ash")
+		for _, stored := range ta.resets.tokens {
+			stored.ExpiresAt = time.Now().Add(-time.Second)
+		}
+
+		err := ta.PasswordReset(ctx, &domain.PasswordReset{Token: token, NewPassword: "pikachu"})
+		assertStatus(t, err, http.StatusBadRequest)
+		ta.assertPassword(t, user, "password")
+	})
+
+	t.Run("token expires after the configured lifetime", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Server.PasswordResetTTL = 60
+		ta := newTestAuth(t, cfg, nil)
+		ta.addUser(t, "ash", domain.DefaultRole)
+
+		before := time.Now()
+		if err := ta.PasswordResetRequest(ctx, "ash"); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, stored := range ta.resets.tokens {
+			if lifetime := stored.ExpiresAt.Sub(before); lifetime < time.Minute || lifetime > time.Minute+time.Second {
+				t.Errorf("token expires after %s, want a minute", lifetime)
+			}
+		}
+	})
+
+	t.Run("reset ends older tokens and sessions", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		user := ta.addUser(t, "ash", domain.DefaultRole)
+
+		signedIn, err := ta.startSession(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ta.PasswordResetRequest(ctx, "ash"); err != nil {
+			t.Fatal(err)
+		}
+		older := ta.resetToken(t, "ash")
+		if err := ta.PasswordResetRequest(ctx, "ash"); err != nil {
+			t.Fatal(err)
+		}
+		if err := ta.PasswordReset(ctx, &domain.PasswordReset{Token: ta.resetToken(t, "ash"), NewPassword: "pikachu"}); err != nil {
+			t.Fatal(err)
+		}
+
+		err = ta.PasswordReset(ctx, &domain.PasswordReset{Token: older, NewPassword: "charmander"})
+		assertStatus(t, err, http.StatusBadRequest)
+
+		if _, _, _, err := ta.TokenAuthentication(ctx, signedIn.Token); !errors.Is(err, httpErr.RevokedJWTToken) {
+			t.Errorf("TokenAuthentication after the reset: %v, want the token revoked", err)
+		}
+	})
+
+	t.Run("unknown token is refused like an expired one", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+
+		err := ta.PasswordReset(ctx, &domain.PasswordReset{Token: "not-a-token", NewPassword: "pikachu"})
+		assertStatus(t, err, http.StatusBadRequest)
+		if !strings.Contains(err.Error(), "invalid or expired reset token") {
+			t.Errorf("got %v, want the message of an expired token", err)
+		}
+	})
+
+	t.Run("link carries the escaped token", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Notifier.ResetURL = "https://pokedex.example.com/reset-password"
+		ta := newTestAuth(t, cfg, nil)
+		user := ta.addUser(t, "ash", domain.DefaultRole)
+
+		if err := ta.PasswordResetRequest(ctx, "ash"); err != nil {
+			t.Fatal(err)
+		}
+		if body := ta.notifier.sent()[0].Body; !strings.Contains(body, cfg.Notifier.ResetURL+"?token=") {
+			t.Fatalf("message %q has no reset link", body)
+		}
+
+		if err := ta.PasswordReset(ctx, &domain.PasswordReset{Token: ta.resetToken(t, "ash"), NewPassword: "pikachu"}); err != nil {
+			t.Fatalf("PasswordReset: %v", err)
+		}
+		ta.assertPassword(t, user, "pikachu")
+	})
+}
+
+func TestPasswordResetRequestDoesNotRevealUsers(t *testing.T) {
+	ta := newTestAuth(t, testConfig(), nil)
+	ta.addUser(t, "ash", domain.DefaultRole)
+	e := ta.routes()
+
+	known := ta.request(e, http.MethodPost, "/auth/password/reset", "", `{"username":"ash"}`)
+	unknown := ta.request(e, http.MethodPost, "/auth/password/reset", "", `{"username":"missingno"}`)
+
+	if known.Code != http.StatusAccepted || unknown.Code != known.Code {
+		t.Errorf("status = %d for a user and %d for nobody, want %d for both", known.Code, unknown.Code, http.StatusAccepted)
+	}
+	if known.Body.String() != unknown.Body.String() {
+		t.Errorf("bodies differ: %q and %q", known.Body.String(), unknown.Body.String())
+	}
+
+	messages := ta.notifier.sent()
+	if len(messages) != 1 || messages[0].To != "ash" {
+		t.Errorf("sent %+v, want a single message to ash", messages)
+	}
+	if len(ta.resets.tokens) != 1 {
+		t.Errorf("%d reset tokens stored, want 1", len(ta.resets.tokens))
+	}
+}
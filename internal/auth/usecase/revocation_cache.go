@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tokens found valid are only trusted for this long, revocations made by other
+// instances are picked up within this window
+const revocationCacheTTL = 30 * time.Second
+
+type revocationEntry struct {
+	revoked   bool
+	expiresAt time.Time
+}
+
+// In-memory cache of denylist lookups by jti, so each request does not hit Mongo
+type revocationCache struct {
+	mu        sync.Mutex
+	tokens    map[string]revocationEntry
+	users     map[primitive.ObjectID]time.Time
+	tokenTTL  time.Duration
+	lastSweep time.Time
+}
+
+func newRevocationCache(tokenTTL time.Duration) *revocationCache {
+	return &revocationCache{
+		tokenTTL:  tokenTTL,
+		tokens:    make(map[string]revocationEntry),
+		users:     make(map[primitive.ObjectID]time.Time),
+		lastSweep: time.Now(),
+	}
+}
+
+func (c *revocationCache) get(jti string, userID primitive.ObjectID, issuedAt time.Time) (revoked bool, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if revokedAt, found := c.users[userID]; found && !issuedAt.After(revokedAt) {
+		return true, true
+	}
+
+	entry, found := c.tokens[jti]
+	if !found || time.Now().After(entry.expiresAt) {
+		return false, false
+	}
+
+	return entry.revoked, true
+}
+
+func (c *revocationCache) set(jti string, revoked bool, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sweep()
+
+	if !revoked {
+		if limit := time.Now().Add(revocationCacheTTL); expiresAt.After(limit) {
+			expiresAt = limit
+		}
+	}
+	c.tokens[jti] = revocationEntry{revoked: revoked, expiresAt: expiresAt}
+}
+
+func (c *revocationCache) revokeUser(userID primitive.ObjectID, revokedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sweep()
+
+	// Checked before the cached verdicts, which the user's older tokens may still have
+	c.users[userID] = revokedAt
+}
+
+// Drop expired entries, at most once per cache TTL
+func (c *revocationCache) sweep() {
+	now := time.Now()
+	if now.Sub(c.lastSweep) < revocationCacheTTL {
+		return
+	}
+	c.lastSweep = now
+
+	for jti, entry := range c.tokens {
+		if now.After(entry.expiresAt) {
+			delete(c.tokens, jti)
+		}
+	}
+	for userID, revokedAt := range c.users {
+		if now.Sub(revokedAt) > c.tokenTTL {
+			delete(c.users, userID)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+// Mint a key for the user limited to the scopes
+func (ta *testAuth) apiKey(t *testing.T, user *domain.User, scopes ...string) string {
+	t.Helper()
+
+	created, err := ta.APIKeyCreation(context.Background(), user.ID, &domain.APIKeyRequest{Name: "batch", Scopes: scopes})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return created.Key
+}
+
+func (ta *testAuth) keyRequest(e *echo.Echo, method, target, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if method == http.MethodPatch {
+		req.Header.Set(echo.HeaderContentType, utils.MIMEMergePatch)
+	}
+	req.Header.Set(utils.HeaderAPIKey, key)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestAPIKeyScopesOnSelfEndpoints(t *testing.T) {
+	ctx := context.Background()
+
+	// Endpoints acting on the key's owner, :id is the owner's, they need the admin scope
+	selfEndpoints := []struct {
+		method string
+		target string
+		body   string
+	}{
+		{method: http.MethodPut, target: "/auth", body: `{"username":"ash-ketchum"}`},
+		{method: http.MethodPatch, target: "/auth", body: `{"username":"ash-ketchum"}`},
+		{method: http.MethodPut, target: "/auth/password", body: `{"current_password":"password","new_password":"pikachu"}`},
+		{method: http.MethodDelete, target: "/auth/:id/sessions"},
+		{method: http.MethodPost, target: "/auth/apikey", body: `{"name":"escalated","scopes":["admin"]}`},
+		{method: http.MethodGet, target: "/auth/apikey/list"},
+		{method: http.MethodPost, target: "/auth/mfa/enroll"},
+		{method: http.MethodGet, target: "/auth/session/list"},
+	}
+
+	for _, scope := range []string{domain.ScopeCatalogRead, domain.ScopeCatch} {
+		for _, endpoint := range selfEndpoints {
+			t.Run(scope+" key on "+endpoint.method+" "+endpoint.target, func(t *testing.T) {
+				ta := newTestAuth(t, testConfig(), nil)
+				me := ta.addUser(t, "ash", domain.DefaultRole)
+				key := ta.apiKey(t, me, scope)
+
+				target := strings.Replace(endpoint.target, ":id", me.ID.Hex(), 1)
+				rec := ta.keyRequest(ta.routes(), endpoint.method, target, key, endpoint.body)
+				if rec.Code != http.StatusForbidden {
+					t.Fatalf("status = %d (%s), want %d", rec.Code, rec.Body.String(), http.StatusForbidden)
+				}
+
+				stored, err := ta.users.FindByID(ctx, me.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if stored.Username != "ash" || stored.Version != me.Version {
+					t.Errorf("refused request changed the user to %s at version %d", stored.Username, stored.Version)
+				}
+				ta.assertPassword(t, me, "password")
+				if keys, _ := ta.apiKeys.FetchAPIKeys(ctx, me.ID); len(keys) != 1 {
+					t.Errorf("%d keys, want only the one used", len(keys))
+				}
+			})
+		}
+	}
+
+	t.Run("admin key on its owner", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		me := ta.addUser(t, "ash", domain.DefaultRole)
+		key := ta.apiKey(t, me, domain.ScopeAdmin)
+
+		rec := ta.keyRequest(ta.routes(), http.MethodPut, "/auth", key, `{"username":"ash-ketchum"}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d (%s), want %d", rec.Code, rec.Body.String(), http.StatusOK)
+		}
+		if stored, _ := ta.users.FindByID(ctx, me.ID); stored.Username != "ash-ketchum" {
+			t.Errorf("username = %q, want the update applied", stored.Username)
+		}
+	})
+
+	t.Run("admin key on another user", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		me := ta.addUser(t, "ash", domain.DefaultRole)
+		other := ta.addUser(t, "gary", domain.DefaultRole)
+		if _, err := ta.startSession(ctx, other); err != nil {
+			t.Fatal(err)
+		}
+		key := ta.apiKey(t, me, domain.ScopeAdmin)
+
+		// The scope does not lift the owner's own permissions
+		rec := ta.keyRequest(ta.routes(), http.MethodDelete, "/auth/"+other.ID.Hex()+"/sessions", key, "")
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("status = %d (%s), want %d", rec.Code, rec.Body.String(), http.StatusForbidden)
+		}
+		if sessions, _ := ta.sessions.FetchSessions(ctx, other.ID); len(sessions) != 1 {
+			t.Errorf("%d sessions of gary left, want 1", len(sessions))
+		}
+	})
+}
+
+func TestAPIKeyCatchScope(t *testing.T) {
+	ctx := context.Background()
+	body := `{"monster_id":"` + primitive.NewObjectID().Hex() + `"}`
+
+	tests := []struct {
+		name      string
+		ownerRole string
+		scope     string
+		onOther   bool
+		want      int
+	}{
+		{name: "catch key for its owner", ownerRole: domain.DefaultRole, scope: domain.ScopeCatch, want: http.StatusOK},
+		{name: "catch key for another user", ownerRole: domain.DefaultRole, scope: domain.ScopeCatch, onOther: true, want: http.StatusForbidden},
+		{name: "catch key of an admin for another user", ownerRole: domain.AdminRole, scope: domain.ScopeCatch, onOther: true, want: http.StatusForbidden},
+		{name: "catalog key for its owner", ownerRole: domain.DefaultRole, scope: domain.ScopeCatalogRead, want: http.StatusForbidden},
+		{name: "admin key for another user", ownerRole: domain.DefaultRole, scope: domain.ScopeAdmin, onOther: true, want: http.StatusForbidden},
+		{name: "admin key of an admin for another user", ownerRole: domain.AdminRole, scope: domain.ScopeAdmin, onOther: true, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestAuth(t, testConfig(), nil)
+			owner := ta.addUser(t, "ash", tt.ownerRole)
+			other := ta.addUser(t, "gary", domain.DefaultRole)
+			target := owner
+			if tt.onOther {
+				target = other
+			}
+
+			rec := ta.keyRequest(ta.routes(), http.MethodPost, "/auth/"+target.ID.Hex(), ta.apiKey(t, owner, tt.scope), body)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d (%s), want %d", rec.Code, rec.Body.String(), tt.want)
+			}
+
+			stored, err := ta.users.FindByID(ctx, target.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if caught := len(stored.Monsters) == 1; caught != (tt.want == http.StatusOK) {
+				t.Errorf("%s caught %d monsters after status %d", stored.Username, len(stored.Monsters), rec.Code)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuthentication(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("revoked key", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		me := ta.addUser(t, "ash", domain.DefaultRole)
+		key := ta.apiKey(t, me, domain.ScopeAdmin)
+
+		keys, _ := ta.apiKeys.FetchAPIKeys(ctx, me.ID)
+		if err := ta.APIKeyRevocation(ctx, me, keys[0].ID); err != nil {
+			t.Fatal(err)
+		}
+
+		if rec := ta.keyRequest(ta.routes(), http.MethodGet, "/auth/me", key, ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("expired key", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		me := ta.addUser(t, "ash", domain.DefaultRole)
+		key := ta.apiKey(t, me, domain.ScopeAdmin)
+		for _, stored := range ta.apiKeys.keys {
+			stored.ExpiresAt = time.Now().Add(-time.Second)
+		}
+
+		if rec := ta.keyRequest(ta.routes(), http.MethodGet, "/auth/me", key, ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+
+		if rec := ta.keyRequest(ta.routes(), http.MethodGet, "/auth/me", "pk_unknown", ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+}
@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+func TestTokenRefresh(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("keeps the session alive", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		user := ta.addUser(t, "ash", domain.DefaultRole)
+
+		signedIn, err := ta.startSession(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		refreshed, err := ta.TokenRefresh(ctx, signedIn.RefreshToken)
+		if err != nil {
+			t.Fatalf("TokenRefresh: %v", err)
+		}
+		if refreshed.RefreshToken == signedIn.RefreshToken {
+			t.Error("refresh token was not rotated")
+		}
+	})
+
+	t.Run("ends with a session that is gone", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		user := ta.addUser(t, "ash", domain.DefaultRole)
+
+		// The session expired without use and the TTL index removed it
+		familyID := primitive.NewObjectID()
+		token := addRefreshToken(t, ta, user, familyID, &familyID)
+
+		_, err := ta.TokenRefresh(ctx, token)
+		assertStatus(t, err, http.StatusUnauthorized)
+
+		if session, _ := ta.sessions.FindSessionByID(ctx, familyID); session != nil {
+			t.Error("refresh revived the removed session")
+		}
+		if active := ta.refreshTokens.activeInFamily(familyID); active != 0 {
+			t.Errorf("%d tokens of the family can still be refreshed", active)
+		}
+	})
+
+	t.Run("ends with a revoked session", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		user := ta.addUser(t, "ash", domain.DefaultRole)
+
+		signedIn, err := ta.startSession(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sessions, err := ta.sessions.FetchSessions(ctx, user.ID)
+		if err != nil || len(sessions) != 1 {
+			t.Fatalf("FetchSessions: %v, %d sessions", err, len(sessions))
+		}
+		if err := ta.sessions.RevokeSession(ctx, sessions[0].ID); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = ta.TokenRefresh(ctx, signedIn.RefreshToken)
+		assertStatus(t, err, http.StatusUnauthorized)
+	})
+
+	t.Run("refuses an expired token without rotating it", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		user := ta.addUser(t, "ash", domain.DefaultRole)
+
+		familyID := primitive.NewObjectID()
+		token := addRefreshToken(t, ta, user, familyID, nil)
+		for _, stored := range ta.refreshTokens.tokens {
+			stored.ExpiresAt = time.Now().Add(-time.Minute)
+		}
+
+		_, err := ta.TokenRefresh(ctx, token)
+		assertStatus(t, err, http.StatusUnauthorized)
+
+		if active := ta.refreshTokens.activeInFamily(familyID); active != 1 {
+			t.Errorf("%d tokens of the family are active, want the expired one untouched", active)
+		}
+	})
+
+	t.Run("gives families from before sessions one", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		user := ta.addUser(t, "ash", domain.DefaultRole)
+
+		familyID := primitive.NewObjectID()
+		token := addRefreshToken(t, ta, user, familyID, nil)
+
+		if _, err := ta.TokenRefresh(ctx, token); err != nil {
+			t.Fatalf("TokenRefresh: %v", err)
+		}
+
+		session, err := ta.sessions.FindSessionByID(ctx, familyID)
+		if err != nil || session == nil {
+			t.Fatalf("no session for the family: %v", err)
+		}
+	})
+}
+
+func TestTokenAuthentication(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("accepts a token of an active session", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		user := ta.addUser(t, "ash", domain.DefaultRole)
+
+		signedIn, err := ta.startSession(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, _, session, err := ta.TokenAuthentication(ctx, signedIn.Token)
+		if err != nil {
+			t.Fatalf("TokenAuthentication: %v", err)
+		}
+		if got.ID != user.ID {
+			t.Errorf("got user %s, want %s", got.ID.Hex(), user.ID.Hex())
+		}
+		if session == nil {
+			t.Error("no session for a token issued for one")
+		}
+	})
+
+	t.Run("rejects a token of a revoked session", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		user := ta.addUser(t, "ash", domain.DefaultRole)
+
+		signedIn, err := ta.startSession(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sessions, err := ta.sessions.FetchSessions(ctx, user.ID)
+		if err != nil || len(sessions) != 1 {
+			t.Fatalf("FetchSessions: %v, %d sessions", err, len(sessions))
+		}
+		if err := ta.sessions.RevokeSession(ctx, sessions[0].ID); err != nil {
+			t.Fatal(err)
+		}
+
+		_, _, _, err = ta.TokenAuthentication(ctx, signedIn.Token)
+		assertStatus(t, err, http.StatusUnauthorized)
+	})
+
+	t.Run("rejects a malformed token", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+
+		if _, _, _, err := ta.TokenAuthentication(ctx, "not-a-token"); err == nil {
+			t.Error("malformed token was accepted")
+		}
+	})
+}
+
+func TestUserSessionsRevocation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("revokes a token issued in the same second", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		user := ta.addUser(t, "ash", domain.DefaultRole)
+
+		signedIn, err := ta.startSession(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		claims, err := ta.TokenValidation(signedIn.Token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ta.UserSessionsRevocation(ctx, user.ID); err != nil {
+			t.Fatalf("UserSessionsRevocation: %v", err)
+		}
+
+		if revoked, err := ta.TokenRevoked(ctx, claims); err != nil || !revoked {
+			t.Errorf("TokenRevoked = %v, %v, want revoked", revoked, err)
+		}
+
+		// Another instance only has the denylist to go by
+		ta.revocations = newRevocationCache(utils.AccessTokenTTL(ta.cfg))
+		if revoked, err := ta.TokenRevoked(ctx, claims); err != nil || !revoked {
+			t.Errorf("TokenRevoked without the cache = %v, %v, want revoked", revoked, err)
+		}
+	})
+
+	t.Run("compares issue times with the full revocation time", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		user := ta.addUser(t, "ash", domain.DefaultRole)
+
+		if err := ta.UserSessionsRevocation(ctx, user.ID); err != nil {
+			t.Fatalf("UserSessionsRevocation: %v", err)
+		}
+		if len(ta.denylist.revoked) != 1 {
+			t.Fatalf("%d denylist entries, want 1", len(ta.denylist.revoked))
+		}
+		revokedAt := ta.denylist.revoked[0].CreatedAt
+
+		tests := []struct {
+			name     string
+			issuedAt time.Time
+			want     bool
+		}{
+			{name: "issued before", issuedAt: revokedAt.Add(-time.Minute), want: true},
+			{name: "issued in the same second", issuedAt: revokedAt.Truncate(time.Second), want: true},
+			{name: "issued at the revocation", issuedAt: revokedAt, want: true},
+			{name: "issued after", issuedAt: revokedAt.Truncate(time.Second).Add(time.Second), want: false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				claims := &utils.Claims{RegisteredClaims: jwt.RegisteredClaims{
+					Subject:  user.ID.Hex(),
+					IssuedAt: jwt.NewNumericDate(tt.issuedAt),
+				}}
+
+				if revoked, err := ta.TokenRevoked(ctx, claims); err != nil || revoked != tt.want {
+					t.Errorf("TokenRevoked = %v, %v, want %v", revoked, err, tt.want)
+				}
+
+				ta.revocations = newRevocationCache(utils.AccessTokenTTL(ta.cfg))
+				if revoked, err := ta.TokenRevoked(ctx, claims); err != nil || revoked != tt.want {
+					t.Errorf("TokenRevoked without the cache = %v, %v, want %v", revoked, err, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("ends the sessions and refresh tokens", func(t *testing.T) {
+		ta := newTestAuth(t, testConfig(), nil)
+		user := ta.addUser(t, "ash", domain.DefaultRole)
+
+		signedIn, err := ta.startSession(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ta.UserSessionsRevocation(ctx, user.ID); err != nil {
+			t.Fatalf("UserSessionsRevocation: %v", err)
+		}
+
+		_, err = ta.TokenRefresh(ctx, signedIn.RefreshToken)
+		assertStatus(t, err, http.StatusUnauthorized)
+
+		sessions, err := ta.sessions.FetchSessions(ctx, user.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, session := range sessions {
+			if session.IsActive() {
+				t.Errorf("session %s is still active", session.ID.Hex())
+			}
+		}
+	})
+}
+
+// Store a refresh token of the family straight in the repository
+func addRefreshToken(t *testing.T, ta *testAuth, user *domain.User, familyID primitive.ObjectID, sessionID *primitive.ObjectID) string {
+	t.Helper()
+
+	token, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	if _, err := ta.refreshTokens.CreateRefreshToken(context.Background(), &domain.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		SessionID: sessionID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: now.Add(time.Hour),
+		CreatedAt: now,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func assertStatus(t *testing.T, err error, status int) {
+	t.Helper()
+
+	if err == nil {
+		t.Fatalf("got no error, want status %d", status)
+	}
+	restErr, ok := err.(httpErr.RestErr)
+	if !ok {
+		t.Fatalf("got %v, want status %d", err, status)
+	}
+	if restErr.Status() != status {
+		t.Fatalf("got status %d (%v), want %d", restErr.Status(), err, status)
+	}
+}
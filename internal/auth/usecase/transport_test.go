@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iamaul/go-pokedex/internal/auth"
+	authGrpc "github.com/iamaul/go-pokedex/internal/auth/delivery/grpc"
+	authHttp "github.com/iamaul/go-pokedex/internal/auth/delivery/http"
+	authHttpV2 "github.com/iamaul/go-pokedex/internal/auth/delivery/http/v2"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+	"github.com/iamaul/go-pokedex/proto/pokedex"
+)
+
+// Catching for someone else needs user:write on every transport
+
+func TestCatchMonsterForAnotherUser(t *testing.T) {
+	tests := []struct {
+		name   string
+		role   string
+		self   bool
+		caught bool
+		status int
+		code   codes.Code
+	}{
+		{name: "trainer for themselves", role: domain.DefaultRole, self: true, caught: true, status: http.StatusOK, code: codes.OK},
+		{name: "trainer for another user", role: domain.DefaultRole, status: http.StatusForbidden, code: codes.PermissionDenied},
+		{name: "admin for another user", role: domain.AdminRole, caught: true, status: http.StatusOK, code: codes.OK},
+	}
+	for _, tt := range tests {
+		transports := map[string]func(ta *testAuth, caller, target *domain.User, monsterID primitive.ObjectID){
+			"v1": func(ta *testAuth, caller, target *domain.User, monsterID primitive.ObjectID) {
+				e := ta.routesOf(authHttp.NewAuthHandler(ta.cfg, ta.AuthUsecase, ta.logger))
+				rec := ta.request(e, http.MethodPost, "/auth/"+target.ID.Hex(), ta.accessToken(t, caller), `{"monster_id":"`+monsterID.Hex()+`"}`)
+				if rec.Code != tt.status {
+					t.Errorf("got status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+				}
+			},
+			"v2": func(ta *testAuth, caller, target *domain.User, monsterID primitive.ObjectID) {
+				e := ta.routesOf(authHttpV2.NewAuthHandler(ta.cfg, ta.AuthUsecase, ta.logger))
+				rec := ta.request(e, http.MethodPost, "/auth/"+target.ID.Hex(), ta.accessToken(t, caller), `{"monster_id":"`+monsterID.Hex()+`"}`)
+				if rec.Code != tt.status {
+					t.Errorf("got status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+				}
+			},
+			"gRPC": func(ta *testAuth, caller, target *domain.User, monsterID primitive.ObjectID) {
+				s := authGrpc.NewAuthServer(ta.cfg, ta.AuthUsecase, ta.logger)
+				ctx := context.WithValue(context.Background(), utils.UserCtxKey{}, caller)
+				_, err := s.CatchMonster(ctx, &pokedex.CatchMonsterRequest{UserId: target.ID.Hex(), MonsterId: monsterID.Hex()})
+				if code := status.Code(err); code != tt.code {
+					t.Errorf("got code %s, want %s: %v", code, tt.code, err)
+				}
+			},
+		}
+		for transport, catch := range transports {
+			t.Run(transport+" "+tt.name, func(t *testing.T) {
+				ta := newTestAuth(t, testConfig(), nil)
+				caller := ta.addUser(t, "ash", tt.role)
+				target := caller
+				if !tt.self {
+					target = ta.addUser(t, "brock", domain.DefaultRole)
+				}
+				monsterID := primitive.NewObjectID()
+
+				catch(ta, caller, target, monsterID)
+
+				found, err := ta.users.FindByID(context.Background(), target.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if caught := len(found.Monsters) == 1 && found.Monsters[0] == monsterID; caught != tt.caught {
+					t.Errorf("monster caught %v, want %v", caught, tt.caught)
+				}
+			})
+		}
+	}
+}
+
+func TestUserListRequiresUser(t *testing.T) {
+	ta := newTestAuth(t, testConfig(), nil)
+	token := ta.accessToken(t, ta.addUser(t, "ash", domain.DefaultRole))
+
+	for version, h := range map[string]auth.DeliveryHandlers{
+		"v1": authHttp.NewAuthHandler(ta.cfg, ta.AuthUsecase, ta.logger),
+		"v2": authHttpV2.NewAuthHandler(ta.cfg, ta.AuthUsecase, ta.logger),
+	} {
+		e := ta.routesOf(h)
+
+		if rec := ta.request(e, http.MethodGet, "/auth/user/list", "", ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: anonymous list got status %d: %s", version, rec.Code, rec.Body)
+		}
+		if rec := ta.request(e, http.MethodGet, "/auth/user/list", token, ""); rec.Code != http.StatusOK {
+			t.Errorf("%s: signed in list got status %d: %s", version, rec.Code, rec.Body)
+		}
+	}
+}
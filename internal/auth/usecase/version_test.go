@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	authHttp "github.com/iamaul/go-pokedex/internal/auth/delivery/http"
+	authHttpV2 "github.com/iamaul/go-pokedex/internal/auth/delivery/http/v2"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/middleware"
+)
+
+// The auth routes of both versions under /api, selected by path or Accept header
+func (ta *testAuth) versionedRoutes() *echo.Echo {
+	e := echo.New()
+	mw := middleware.NewMiddlewareManager(ta.AuthUsecase, ta.cfg, nil, ta.logger)
+	e.Pre(mw.APIVersionMiddleware)
+
+	authHttp.AuthRoutes(e.Group("/api/v1/auth"), authHttp.NewAuthHandler(ta.cfg, ta.AuthUsecase, ta.logger), ta.AuthUsecase, ta.cfg, mw)
+	authHttp.AuthRoutes(e.Group("/api/v2/auth"), authHttpV2.NewAuthHandler(ta.cfg, ta.AuthUsecase, ta.logger), ta.AuthUsecase, ta.cfg, mw)
+
+	return e
+}
+
+func TestAPIVersionShapes(t *testing.T) {
+	cfg := testConfig()
+	cfg.API.DefaultVersion = 1
+
+	tests := []struct {
+		name        string
+		target      string
+		accept      string
+		signIn      []string
+		signInUser  []string
+		notInSignIn []string
+		me          []string
+	}{
+		{
+			name:        "v1 path",
+			target:      "/api/v1/auth",
+			signIn:      []string{"user", "token", "expires_in", "refresh_token"},
+			signInUser:  []string{"_id", "username", "role", "monsters", "version"},
+			notInSignIn: []string{"access_token", "token_type"},
+			me:          []string{"_id", "username"},
+		},
+		{
+			name:        "v2 path",
+			target:      "/api/v2/auth",
+			signIn:      []string{"user", "access_token", "token_type", "expires_in", "refresh_token"},
+			signInUser:  []string{"id", "username", "role", "monsters", "version"},
+			notInSignIn: []string{"token"},
+			me:          []string{"id", "username"},
+		},
+		{
+			name:        "default version",
+			target:      "/api/auth",
+			signIn:      []string{"token"},
+			signInUser:  []string{"_id"},
+			notInSignIn: []string{"access_token"},
+			me:          []string{"_id"},
+		},
+		{
+			name:        "v2 by Accept",
+			target:      "/api/auth",
+			accept:      "application/vnd.pokedex.v2+json",
+			signIn:      []string{"access_token", "token_type"},
+			signInUser:  []string{"id"},
+			notInSignIn: []string{"token"},
+			me:          []string{"id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestAuth(t, cfg, nil)
+			ta.addUser(t, "ash", domain.DefaultRole)
+			e := ta.versionedRoutes()
+
+			send := func(method, target, token, body string) map[string]interface{} {
+				t.Helper()
+
+				req := httptest.NewRequest(method, target, strings.NewReader(body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				if tt.accept != "" {
+					req.Header.Set(echo.HeaderAccept, tt.accept)
+				}
+				if token != "" {
+					req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+				}
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, req)
+
+				if rec.Code != http.StatusOK {
+					t.Fatalf("%s %s: status %d: %s", method, target, rec.Code, rec.Body.String())
+				}
+				var fields map[string]interface{}
+				if err := json.Unmarshal(rec.Body.Bytes(), &fields); err != nil {
+					t.Fatal(err)
+				}
+				return fields
+			}
+
+			signedIn := send(http.MethodPost, tt.target, "", `{"username":"ash","password":"password"}`)
+			assertFields(t, "sign in", signedIn, tt.signIn, tt.notInSignIn)
+			user, _ := signedIn["user"].(map[string]interface{})
+			assertFields(t, "signed in user", user, tt.signInUser, []string{"password"})
+
+			token, _ := signedIn["token"].(string)
+			if token == "" {
+				token, _ = signedIn["access_token"].(string)
+			}
+			me := send(http.MethodGet, tt.target+"/me", token, "")
+			assertFields(t, "me", me, tt.me, []string{"password"})
+		})
+	}
+}
+
+func assertFields(t *testing.T, what string, fields map[string]interface{}, present, absent []string) {
+	t.Helper()
+
+	for _, key := range present {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("%s has no %q: %v", what, key, fields)
+		}
+	}
+	for _, key := range absent {
+		if _, ok := fields[key]; ok {
+			t.Errorf("%s has %q: %v", what, key, fields)
+		}
+	}
+}
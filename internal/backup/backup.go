@@ -0,0 +1,454 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// SchemaVersion is bumped whenever the archive layout or document shape changes
+	SchemaVersion = 1
+
+	manifestFile = "manifest.json"
+	insertBatch  = 500
+)
+
+// Collections included in every backup, in restore order
+var Collections = []string{"users", "monsters", "monster_types"}
+
+type CollectionManifest struct {
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Count  int64  `json:"count"`
+	SHA256 string `json:"sha256"`
+}
+
+type Manifest struct {
+	SchemaVersion int                  `json:"schema_version"`
+	AppVersion    string               `json:"app_version"`
+	Database      string               `json:"database"`
+	CreatedAt     time.Time            `json:"created_at"`
+	Collections   []CollectionManifest `json:"collections"`
+}
+
+type spooledCollection struct {
+	manifest CollectionManifest
+	file     *os.File
+	size     int64
+}
+
+// Dump writes a gzip compressed tar archive containing the manifest followed by
+// one canonical extended JSON document per line for each collection
+func Dump(ctx context.Context, db *mongo.Database, w io.Writer, appVersion string) (*Manifest, error) {
+	manifest := &Manifest{
+		SchemaVersion: SchemaVersion,
+		AppVersion:    appVersion,
+		Database:      db.Name(),
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	spooled := make([]*spooledCollection, 0, len(Collections))
+	defer func() {
+		for _, s := range spooled {
+			s.file.Close()
+			os.Remove(s.file.Name())
+		}
+	}()
+
+	for _, name := range Collections {
+		s, err := spoolCollection(ctx, db.Collection(name))
+		if err != nil {
+			return nil, err
+		}
+		spooled = append(spooled, s)
+		manifest.Collections = append(manifest.Collections, s.manifest)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "backup.Dump.MarshalManifest")
+	}
+
+	if err := writeEntry(tw, manifestFile, int64(len(manifestBytes)), manifest.CreatedAt, bytes.NewReader(manifestBytes)); err != nil {
+		return nil, err
+	}
+
+	for _, s := range spooled {
+		if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+			return nil, errors.Wrap(err, "backup.Dump.Seek")
+		}
+		if err := writeEntry(tw, s.manifest.File, s.size, manifest.CreatedAt, s.file); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, errors.Wrap(err, "backup.Dump.CloseTar")
+	}
+	if err := gz.Close(); err != nil {
+		return nil, errors.Wrap(err, "backup.Dump.CloseGzip")
+	}
+
+	return manifest, nil
+}
+
+// Restore validates the archive manifest, verifies every collection against it and only then
+// loads them into the target database. The target collections must be empty so a restore never
+// merges with live data. When an insert fails the documents of the archive are deleted again,
+// leaving the targets empty for the next attempt.
+func Restore(ctx context.Context, db *mongo.Database, r io.Reader) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "backup.Restore.NewReader")
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+
+	header, err := tr.Next()
+	if err != nil {
+		return nil, errors.Wrap(err, "backup.Restore.ReadManifest")
+	}
+	if header.Name != manifestFile {
+		return nil, errors.Errorf("backup.Restore: expected %s as first entry, got %s", manifestFile, header.Name)
+	}
+
+	manifest := &Manifest{}
+	if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+		return nil, errors.Wrap(err, "backup.Restore.DecodeManifest")
+	}
+
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, c := range manifest.Collections {
+		count, err := db.Collection(c.Name).CountDocuments(ctx, bson.D{})
+		if err != nil {
+			return nil, errors.Wrap(err, "backup.Restore.CountDocuments")
+		}
+		if count != 0 {
+			return nil, errors.Errorf("backup.Restore: target collection %s.%s is not empty", db.Name(), c.Name)
+		}
+	}
+
+	byFile := make(map[string]CollectionManifest, len(manifest.Collections))
+	for _, c := range manifest.Collections {
+		byFile[c.File] = c
+	}
+
+	// Every entry is spooled and verified before the first insert, so a corrupt or truncated
+	// archive never leaves a partial restore behind
+	spooled := make(map[string]*spooledCollection, len(manifest.Collections))
+	defer func() {
+		for _, s := range spooled {
+			s.file.Close()
+			os.Remove(s.file.Name())
+		}
+	}()
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "backup.Restore.Next")
+		}
+
+		c, ok := byFile[header.Name]
+		if !ok || spooled[c.Name] != nil {
+			return nil, errors.Errorf("backup.Restore: unexpected archive entry %s", header.Name)
+		}
+
+		s, err := verifyCollection(c, tr)
+		if err != nil {
+			return nil, err
+		}
+		spooled[c.Name] = s
+	}
+
+	for _, c := range manifest.Collections {
+		if spooled[c.Name] == nil {
+			return nil, errors.Errorf("backup.Restore: archive is missing %s", c.File)
+		}
+	}
+
+	for i, c := range manifest.Collections {
+		if err := restoreCollection(ctx, db.Collection(c.Name), spooled[c.Name]); err != nil {
+			for _, restored := range manifest.Collections[:i+1] {
+				if cleanupErr := removeCollection(ctx, db.Collection(restored.Name), spooled[restored.Name]); cleanupErr != nil {
+					return nil, errors.Wrapf(err, "backup.Restore: %s left partly restored (%v)", restored.Name, cleanupErr)
+				}
+			}
+			return nil, err
+		}
+	}
+
+	return manifest, nil
+}
+
+// Validate checks that the manifest was produced by a compatible version of this tool
+func (m *Manifest) Validate() error {
+	if m.SchemaVersion != SchemaVersion {
+		return errors.Errorf("backup: unsupported schema version %d, expected %d", m.SchemaVersion, SchemaVersion)
+	}
+
+	known := make(map[string]bool, len(Collections))
+	for _, name := range Collections {
+		known[name] = true
+	}
+
+	seen := make(map[string]bool, len(m.Collections))
+	for _, c := range m.Collections {
+		if !known[c.Name] {
+			return errors.Errorf("backup: unknown collection %s in manifest", c.Name)
+		}
+		if seen[c.Name] {
+			return errors.Errorf("backup: duplicate collection %s in manifest", c.Name)
+		}
+		if c.Count < 0 || c.File == "" || c.SHA256 == "" {
+			return errors.Errorf("backup: invalid manifest entry for %s", c.Name)
+		}
+		seen[c.Name] = true
+	}
+
+	for _, name := range Collections {
+		if !seen[name] {
+			return errors.Errorf("backup: manifest is missing collection %s", name)
+		}
+	}
+
+	return nil
+}
+
+func spoolCollection(ctx context.Context, coll *mongo.Collection) (*spooledCollection, error) {
+	file, err := os.CreateTemp("", "pokedex-"+coll.Name()+"-*.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "backup.spoolCollection.CreateTemp")
+	}
+
+	s, err := writeCollection(ctx, coll, file)
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func writeCollection(ctx context.Context, coll *mongo.Collection, file *os.File) (*spooledCollection, error) {
+	s := &spooledCollection{
+		manifest: CollectionManifest{Name: coll.Name(), File: coll.Name() + ".json"},
+		file:     file,
+	}
+
+	cursor, err := coll.Find(ctx, bson.D{})
+	if err != nil {
+		return nil, errors.Wrap(err, "backup.writeCollection.Find")
+	}
+	defer cursor.Close(ctx)
+
+	hash := sha256.New()
+	out := bufio.NewWriter(io.MultiWriter(file, hash))
+	for cursor.Next(ctx) {
+		line, err := bson.MarshalExtJSON(cursor.Current, true, false)
+		if err != nil {
+			return nil, errors.Wrap(err, "backup.writeCollection.MarshalExtJSON")
+		}
+		line = append(line, '\n')
+		if _, err := out.Write(line); err != nil {
+			return nil, errors.Wrap(err, "backup.writeCollection.Write")
+		}
+		s.manifest.Count++
+		s.size += int64(len(line))
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, errors.Wrap(err, "backup.writeCollection.cursor.Err")
+	}
+	if err := out.Flush(); err != nil {
+		return nil, errors.Wrap(err, "backup.writeCollection.Flush")
+	}
+
+	s.manifest.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	return s, nil
+}
+
+// Copy the archive entry to a temporary file, checking its checksum, document count and
+// that every line decodes
+func verifyCollection(c CollectionManifest, r io.Reader) (*spooledCollection, error) {
+	file, err := os.CreateTemp("", "pokedex-"+c.Name+"-*.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "backup.verifyCollection.CreateTemp")
+	}
+
+	s := &spooledCollection{manifest: c, file: file}
+	if err := spoolEntry(s, r); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func spoolEntry(s *spooledCollection, r io.Reader) error {
+	c := s.manifest
+	hash := sha256.New()
+	in := bufio.NewReader(io.TeeReader(r, hash))
+	out := bufio.NewWriter(s.file)
+
+	var count int64
+	err := readDocuments(in, c, func(line []byte, doc bson.D) error {
+		count++
+		s.size += int64(len(line))
+		if _, err := out.Write(line); err != nil {
+			return errors.Wrapf(err, "backup.spoolEntry.Write(%s)", c.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := out.Flush(); err != nil {
+		return errors.Wrapf(err, "backup.spoolEntry.Flush(%s)", c.Name)
+	}
+
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != c.SHA256 {
+		return errors.Errorf("backup: checksum mismatch for %s", c.File)
+	}
+	if count != c.Count {
+		return errors.Errorf("backup: archive holds %d documents for %s, manifest expects %d", count, c.Name, c.Count)
+	}
+
+	return nil
+}
+
+// Insert the verified documents of a spooled collection in batches
+func restoreCollection(ctx context.Context, coll *mongo.Collection, s *spooledCollection) error {
+	c := s.manifest
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return errors.Wrapf(err, "backup.restoreCollection.Seek(%s)", c.Name)
+	}
+
+	batch := make([]interface{}, 0, insertBatch)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if _, err := coll.InsertMany(ctx, batch); err != nil {
+			return errors.Wrapf(err, "backup.restoreCollection.InsertMany(%s)", c.Name)
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	err := readDocuments(bufio.NewReader(s.file), c, func(line []byte, doc bson.D) error {
+		batch = append(batch, doc)
+		if len(batch) == insertBatch {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+// Delete the documents of a spooled collection by their _id, undoing a failed restore
+func removeCollection(ctx context.Context, coll *mongo.Collection, s *spooledCollection) error {
+	c := s.manifest
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return errors.Wrapf(err, "backup.removeCollection.Seek(%s)", c.Name)
+	}
+
+	ids := make(bson.A, 0, insertBatch)
+	flush := func() error {
+		if len(ids) == 0 {
+			return nil
+		}
+		if _, err := coll.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+			return errors.Wrapf(err, "backup.removeCollection.DeleteMany(%s)", c.Name)
+		}
+		ids = ids[:0]
+		return nil
+	}
+
+	err := readDocuments(bufio.NewReader(s.file), c, func(line []byte, doc bson.D) error {
+		for _, e := range doc {
+			if e.Key == "_id" {
+				ids = append(ids, e.Value)
+				break
+			}
+		}
+		if len(ids) == insertBatch {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+// Decode one canonical extended JSON document per line, handing each to fn
+func readDocuments(in *bufio.Reader, c CollectionManifest, fn func(line []byte, doc bson.D) error) error {
+	for {
+		line, err := in.ReadBytes('\n')
+		if len(line) > 0 {
+			var doc bson.D
+			if err := bson.UnmarshalExtJSON(line, true, &doc); err != nil {
+				return errors.Wrapf(err, "backup.readDocuments.UnmarshalExtJSON(%s)", c.Name)
+			}
+			if err := fn(line, doc); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "backup.readDocuments.ReadBytes(%s)", c.Name)
+		}
+	}
+}
+
+func writeEntry(tw *tar.Writer, name string, size int64, modTime time.Time, r io.Reader) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    size,
+		ModTime: modTime,
+	}); err != nil {
+		return errors.Wrapf(err, "backup.writeEntry.WriteHeader(%s)", name)
+	}
+
+	if _, err := io.Copy(tw, r); err != nil {
+		return errors.Wrapf(err, "backup.writeEntry.Copy(%s)", name)
+	}
+
+	return nil
+}
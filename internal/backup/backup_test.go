@@ -0,0 +1,170 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestVerifyCollection(t *testing.T) {
+	entry := `{"_id":{"$oid":"64b7f1a2c3d4e5f6a7b8c9d0"},"name":"pikachu"}` + "\n" +
+		`{"_id":{"$oid":"64b7f1a2c3d4e5f6a7b8c9d1"},"name":"eevee"}` + "\n"
+	sum := sha256.Sum256([]byte(entry))
+	valid := CollectionManifest{Name: "monsters", File: "monsters.json", Count: 2, SHA256: hex.EncodeToString(sum[:])}
+
+	tests := []struct {
+		name     string
+		manifest func(c CollectionManifest) CollectionManifest
+		entry    string
+		ok       bool
+	}{
+		{name: "matching entry", entry: entry, ok: true},
+		{name: "checksum mismatch", entry: strings.Replace(entry, "eevee", "evee!", 1)},
+		{name: "truncated entry", entry: entry[:len(entry)/2]},
+		{
+			name:     "count mismatch",
+			manifest: func(c CollectionManifest) CollectionManifest { c.Count = 3; return c },
+			entry:    entry,
+		},
+		{
+			name:     "malformed document",
+			manifest: func(c CollectionManifest) CollectionManifest { c.SHA256 = ""; return c },
+			entry:    "{not json}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid
+			if tt.manifest != nil {
+				c = tt.manifest(c)
+			}
+
+			s, err := verifyCollection(c, strings.NewReader(tt.entry))
+			if !tt.ok {
+				if err == nil {
+					t.Error("accepted")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("verifyCollection: %v", err)
+			}
+			defer func() {
+				s.file.Close()
+				os.Remove(s.file.Name())
+			}()
+
+			spooled, err := os.ReadFile(s.file.Name())
+			if err != nil || string(spooled) != entry || s.size != int64(len(entry)) {
+				t.Errorf("spooled %q (%d bytes), want %q", spooled, s.size, entry)
+			}
+		})
+	}
+}
+
+// Server holding a few users and monster types and more monsters than fit in one insert batch
+func seededServer(t *testing.T) *fakeServer {
+	t.Helper()
+
+	s := newFakeServer(t)
+	s.insert(t, "users",
+		bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "username", Value: "ash"}, {Key: "version", Value: int64(3)}},
+		bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "username", Value: "misty"}, {Key: "created_at", Value: primitive.NewDateTimeFromTime(time.Now())}},
+	)
+	for i := 0; i < insertBatch+1; i++ {
+		s.insert(t, "monsters", bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "name", Value: fmt.Sprintf("monster-%d", i)}, {Key: "number", Value: int32(i)}})
+	}
+	s.insert(t, "monster_types", bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "name", Value: "electric"}})
+
+	return s
+}
+
+func dump(t *testing.T, s *fakeServer) []byte {
+	t.Helper()
+
+	var archive bytes.Buffer
+	if _, err := Dump(context.Background(), s.database(t), &archive, "test"); err != nil {
+		t.Fatalf("Dump: %v", err)
+	}
+
+	return archive.Bytes()
+}
+
+func assertCollections(t *testing.T, got, want *fakeServer) {
+	t.Helper()
+
+	for _, name := range Collections {
+		gotDocs, wantDocs := got.documents(name), want.documents(name)
+		if len(gotDocs) != len(wantDocs) {
+			t.Errorf("%s holds %d documents, want %d", name, len(gotDocs), len(wantDocs))
+			continue
+		}
+		for i := range gotDocs {
+			if !bytes.Equal(gotDocs[i], wantDocs[i]) {
+				t.Errorf("%s document %d is %s, want %s", name, i, gotDocs[i], wantDocs[i])
+			}
+		}
+	}
+}
+
+func TestDumpRestoreRoundTrip(t *testing.T) {
+	source := seededServer(t)
+	archive := dump(t, source)
+
+	target := newFakeServer(t)
+	manifest, err := Restore(context.Background(), target.database(t), bytes.NewReader(archive))
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if manifest.Database != testDatabase || len(manifest.Collections) != len(Collections) {
+		t.Errorf("manifest %+v", manifest)
+	}
+	assertCollections(t, target, source)
+
+	if _, err := Restore(context.Background(), target.database(t), bytes.NewReader(archive)); err == nil {
+		t.Error("restored into collections that are not empty")
+	}
+	assertCollections(t, target, source)
+}
+
+func TestRestoreRemovesPartialRestore(t *testing.T) {
+	archive := dump(t, seededServer(t))
+
+	tests := []struct {
+		name       string
+		collection string
+		insert     int
+	}{
+		{name: "first collection", collection: "users", insert: 1},
+		{name: "second batch of a collection", collection: "monsters", insert: 2},
+		{name: "last collection", collection: "monster_types", insert: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := newFakeServer(t)
+			target.failInsert[tt.collection] = tt.insert
+
+			if _, err := Restore(context.Background(), target.database(t), bytes.NewReader(archive)); err == nil {
+				t.Fatal("restore with a failing insert succeeded")
+			}
+			for _, name := range Collections {
+				if docs := target.documents(name); len(docs) != 0 {
+					t.Errorf("%s left with %d documents", name, len(docs))
+				}
+			}
+
+			if _, err := Restore(context.Background(), target.database(t), bytes.NewReader(archive)); err != nil {
+				t.Errorf("restore after the failure: %v", err)
+			}
+		})
+	}
+}
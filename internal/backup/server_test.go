@@ -0,0 +1,263 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/wiremessage"
+)
+
+const testDatabase = "pokedex"
+
+// Standalone server speaking just enough of the wire protocol for Dump and Restore, keeping
+// its collections in memory
+type fakeServer struct {
+	listener net.Listener
+
+	mu          sync.Mutex
+	collections map[string][]bsoncore.Document
+	inserts     map[string]int
+	// Collection mapped to the insert command into it that fails
+	failInsert map[string]int
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	s := &fakeServer{
+		listener:    listener,
+		collections: make(map[string][]bsoncore.Document),
+		inserts:     make(map[string]int),
+		failInsert:  make(map[string]int),
+	}
+	go s.serve()
+
+	return s
+}
+
+func (s *fakeServer) database(t *testing.T) *mongo.Database {
+	t.Helper()
+
+	opts := options.Client().
+		ApplyURI("mongodb://" + s.listener.Addr().String()).
+		SetDirect(true).
+		SetServerAPIOptions(options.ServerAPI(options.ServerAPIVersion1))
+	client, err := mongo.Connect(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+
+	return client.Database(testDatabase)
+}
+
+func (s *fakeServer) insert(t *testing.T, coll string, docs ...bson.D) {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, doc := range docs {
+		raw, err := bson.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.collections[coll] = append(s.collections[coll], raw)
+	}
+}
+
+func (s *fakeServer) documents(coll string) []bsoncore.Document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]bsoncore.Document(nil), s.collections[coll]...)
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return
+		}
+		msg := make([]byte, binary.LittleEndian.Uint32(size[:]))
+		copy(msg, size[:])
+		if _, err := io.ReadFull(conn, msg[4:]); err != nil {
+			return
+		}
+
+		_, requestID, _, opcode, rem, ok := wiremessage.ReadHeader(msg)
+		if !ok || opcode != wiremessage.OpMsg {
+			return
+		}
+		cmd, docs, ok := readMsg(rem)
+		if !ok {
+			return
+		}
+
+		reply, err := bson.Marshal(s.command(cmd, docs))
+		if err != nil {
+			return
+		}
+		idx, out := wiremessage.AppendHeaderStart(nil, wiremessage.NextRequestID(), requestID, wiremessage.OpMsg)
+		out = wiremessage.AppendMsgFlags(out, 0)
+		out = wiremessage.AppendMsgSectionType(out, wiremessage.SingleDocument)
+		out = append(out, reply...)
+		out = bsoncore.UpdateLength(out, idx, int32(len(out[idx:])))
+		if _, err := conn.Write(out); err != nil {
+			return
+		}
+	}
+}
+
+// Command document of an OP_MSG body and the documents of its sequence section
+func readMsg(rem []byte) (bsoncore.Document, []bsoncore.Document, bool) {
+	_, rem, ok := wiremessage.ReadMsgFlags(rem)
+	if !ok {
+		return nil, nil, false
+	}
+
+	var cmd bsoncore.Document
+	var docs []bsoncore.Document
+	for len(rem) > 0 {
+		var stype wiremessage.SectionType
+		if stype, rem, ok = wiremessage.ReadMsgSectionType(rem); !ok {
+			return nil, nil, false
+		}
+		switch stype {
+		case wiremessage.SingleDocument:
+			cmd, rem, ok = wiremessage.ReadMsgSectionSingleDocument(rem)
+		case wiremessage.DocumentSequence:
+			var sequence []bsoncore.Document
+			_, sequence, rem, ok = wiremessage.ReadMsgSectionDocumentSequence(rem)
+			docs = append(docs, sequence...)
+		default:
+			return nil, nil, false
+		}
+		if !ok {
+			return nil, nil, false
+		}
+	}
+
+	return cmd, docs, cmd != nil
+}
+
+func (s *fakeServer) command(cmd bsoncore.Document, docs []bsoncore.Document) bson.D {
+	elems, err := cmd.Elements()
+	if err != nil || len(elems) == 0 {
+		return bson.D{{Key: "ok", Value: 0}, {Key: "errmsg", Value: "malformed command"}}
+	}
+	coll, _ := elems[0].Value().StringValueOK()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch elems[0].Key() {
+	case "hello", "isMaster", "ismaster":
+		return bson.D{
+			{Key: "helloOk", Value: true},
+			{Key: "isWritablePrimary", Value: true},
+			{Key: "maxBsonObjectSize", Value: 16 * 1024 * 1024},
+			{Key: "maxMessageSizeBytes", Value: 48000000},
+			{Key: "maxWriteBatchSize", Value: 100000},
+			{Key: "minWireVersion", Value: 0},
+			{Key: "maxWireVersion", Value: 17},
+			{Key: "ok", Value: 1},
+		}
+	case "find":
+		return s.cursor(coll, s.collections[coll])
+	case "aggregate":
+		// Only the $match/$group pipeline of CountDocuments is used
+		var batch []bsoncore.Document
+		if n := len(s.collections[coll]); n > 0 {
+			batch = append(batch, bsoncore.NewDocumentBuilder().AppendInt64("n", int64(n)).Build())
+		}
+		return s.cursor(coll, batch)
+	case "insert":
+		if docs == nil {
+			if arr, ok := cmd.Lookup("documents").ArrayOK(); ok {
+				values, _ := arr.Values()
+				for _, v := range values {
+					docs = append(docs, v.Document())
+				}
+			}
+		}
+		s.inserts[coll]++
+		if s.inserts[coll] == s.failInsert[coll] {
+			return bson.D{{Key: "ok", Value: 0}, {Key: "errmsg", Value: "insert refused"}, {Key: "code", Value: 2}}
+		}
+		for _, doc := range docs {
+			s.collections[coll] = append(s.collections[coll], append(bsoncore.Document(nil), doc...))
+		}
+		return bson.D{{Key: "n", Value: len(docs)}, {Key: "ok", Value: 1}}
+	case "delete":
+		// Only the {_id: {$in: [...]}} filter of a failed restore is used
+		var n int
+		for _, statement := range docs {
+			ids, _ := statement.Lookup("q", "_id", "$in").Array().Values()
+			kept := s.collections[coll][:0]
+			for _, doc := range s.collections[coll] {
+				if containsValue(ids, doc.Lookup("_id")) {
+					n++
+					continue
+				}
+				kept = append(kept, doc)
+			}
+			s.collections[coll] = kept
+		}
+		return bson.D{{Key: "n", Value: n}, {Key: "ok", Value: 1}}
+	default:
+		return bson.D{{Key: "ok", Value: 1}}
+	}
+}
+
+func (s *fakeServer) cursor(coll string, batch []bsoncore.Document) bson.D {
+	firstBatch := make(bson.A, 0, len(batch))
+	for _, doc := range batch {
+		firstBatch = append(firstBatch, bson.Raw(doc))
+	}
+
+	return bson.D{
+		{Key: "cursor", Value: bson.D{
+			{Key: "firstBatch", Value: firstBatch},
+			{Key: "id", Value: int64(0)},
+			{Key: "ns", Value: testDatabase + "." + coll},
+		}},
+		{Key: "ok", Value: 1},
+	}
+}
+
+func containsValue(values []bsoncore.Value, v bsoncore.Value) bool {
+	for _, value := range values {
+		if value.Type == v.Type && bytes.Equal(value.Data, v.Data) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,268 @@
+package changestream
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/db/mongodb"
+	"github.com/iamaul/go-pokedex/pkg/events"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+)
+
+const (
+	tokenCollection = "change_stream_tokens"
+	retryDelay      = 5 * time.Second
+)
+
+// A watched collection and the domain events its changes translate to
+type collection struct {
+	name    string
+	created string
+	updated string
+	deleted string
+	decode  func(raw bson.Raw) (interface{}, error)
+}
+
+var collections = []collection{
+	{
+		name: "monsters", created: events.MonsterCreated, updated: events.MonsterUpdated, deleted: events.MonsterDeleted,
+		decode: func(raw bson.Raw) (interface{}, error) {
+			monster := &domain.Monster{}
+			return monster, bson.Unmarshal(raw, monster)
+		},
+	},
+	{
+		name: "monster_types", created: events.MonsterTypeCreated, updated: events.MonsterTypeUpdated, deleted: events.MonsterTypeDeleted,
+		decode: func(raw bson.Raw) (interface{}, error) {
+			monsterType := &domain.MonsterType{}
+			return monsterType, bson.Unmarshal(raw, monsterType)
+		},
+	},
+	{
+		name: "users", created: events.UserCreated, updated: events.UserUpdated, deleted: events.UserDeleted,
+		decode: func(raw bson.Raw) (interface{}, error) {
+			user := &domain.User{}
+			if err := bson.Unmarshal(raw, user); err != nil {
+				return nil, err
+			}
+			return &domain.UserEvent{ID: user.ID, Username: user.Username, Version: user.Version}, nil
+		},
+	},
+}
+
+type changeEvent struct {
+	OperationType string `bson:"operationType"`
+	DocumentKey   struct {
+		ID primitive.ObjectID `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument bson.Raw `bson:"fullDocument"`
+}
+
+type resumeToken struct {
+	Collection string    `bson:"_id"`
+	Token      bson.Raw  `bson:"token"`
+	UpdatedAt  time.Time `bson:"updated_at"`
+}
+
+// Watcher turns change stream events on the catalog and user collections into domain
+// events, so writes made outside the API (imports, shell fixes) reach every consumer.
+// It resumes from the last persisted token after a restart.
+type Watcher struct {
+	db        *mongo.Database
+	tokens    *mongo.Collection
+	publisher events.Publisher
+	logger    logger.Logger
+	// Open streams by collection name
+	open map[string]*atomic.Int32
+}
+
+// Change stream watcher constructor
+func NewWatcher(db *mongo.Database, publisher events.Publisher, log logger.Logger) *Watcher {
+	open := make(map[string]*atomic.Int32, len(collections))
+	for _, c := range collections {
+		open[c.name] = &atomic.Int32{}
+	}
+
+	return &Watcher{db: db, tokens: db.Collection(tokenCollection), publisher: publisher, logger: log, open: open}
+}
+
+// Publish forwards usecase events to the consumers, except the create/update/delete events
+// the open change stream of their collection already reports for the same write
+func (w *Watcher) Publish(ctx context.Context, eventType string, data interface{}) {
+	if c, ok := watchedBy(eventType); ok && w.active(c.name) {
+		return
+	}
+	w.publisher.Publish(ctx, eventType, data)
+}
+
+// Run watches every collection until ctx is cancelled. On deployments without change
+// streams (standalone servers) it logs once and returns, leaving the usecases to publish.
+func (w *Watcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, c := range collections {
+		wg.Add(1)
+		go func(c collection) {
+			defer wg.Done()
+			w.run(ctx, c)
+		}(c)
+	}
+	wg.Wait()
+}
+
+func (w *Watcher) run(ctx context.Context, c collection) {
+	for {
+		err := w.watch(ctx, c)
+		if ctx.Err() != nil {
+			return
+		}
+
+		switch {
+		case mongodb.IsChangeStreamUnsupported(err):
+			w.logger.Warnf("Watcher: change streams are not supported by this deployment, %s changes made outside the API will not be published", c.name)
+			return
+		case mongodb.IsResumeTokenInvalid(err):
+			w.logger.Warnf("Watcher: resume token for %s is no longer valid, restarting from now: %s", c.name, err)
+			if err := w.deleteToken(ctx, c.name); err != nil {
+				w.logger.Errorf("Watcher.deleteToken collection: %s, error: %s", c.name, err)
+			}
+			continue
+		case err != nil:
+			w.logger.Errorf("Watcher.watch collection: %s, error: %s", c.name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+func (w *Watcher) watch(ctx context.Context, c collection) error {
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+
+	token, err := w.loadToken(ctx, c.name)
+	if err != nil {
+		return err
+	}
+	if token != nil {
+		opts.SetResumeAfter(token)
+	}
+
+	stream, err := w.db.Collection(c.name).Watch(ctx, mongo.Pipeline{}, opts)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(context.Background())
+
+	w.open[c.name].Add(1)
+	defer w.open[c.name].Add(-1)
+
+	ctx = events.WithSource(ctx, events.SourceChangeStream)
+
+	for stream.Next(ctx) {
+		var change changeEvent
+		if err := stream.Decode(&change); err != nil {
+			return errors.Wrap(err, "Watcher.watch.Decode")
+		}
+
+		if change.OperationType == "invalidate" {
+			// The collection was dropped or renamed, the old token cannot be resumed
+			return w.deleteToken(ctx, c.name)
+		}
+
+		if err := w.publish(ctx, c, &change); err != nil {
+			w.logger.Errorf("Watcher.publish collection: %s, id: %s, error: %s", c.name, change.DocumentKey.ID.Hex(), err)
+		}
+
+		if err := w.saveToken(ctx, c.name, stream.ResumeToken()); err != nil {
+			return err
+		}
+	}
+
+	return stream.Err()
+}
+
+func (w *Watcher) publish(ctx context.Context, c collection, change *changeEvent) error {
+	switch change.OperationType {
+	case "insert", "update", "replace":
+		// An update looked up after the document was deleted has no full document
+		if len(change.FullDocument) == 0 {
+			return nil
+		}
+
+		data, err := c.decode(change.FullDocument)
+		if err != nil {
+			return err
+		}
+
+		eventType := c.updated
+		if change.OperationType == "insert" {
+			eventType = c.created
+		}
+		w.publisher.Publish(ctx, eventType, data)
+	case "delete":
+		w.publisher.Publish(ctx, c.deleted, &domain.DeletedEntity{ID: change.DocumentKey.ID})
+	}
+
+	return nil
+}
+
+// Active while the collection has an open stream
+func (w *Watcher) active(name string) bool {
+	return w.open[name].Load() > 0
+}
+
+func (w *Watcher) loadToken(ctx context.Context, name string) (bson.Raw, error) {
+	var token resumeToken
+
+	err := w.tokens.FindOne(ctx, bson.M{"_id": name}).Decode(&token)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Watcher.loadToken.FindOne")
+	}
+
+	return token.Token, nil
+}
+
+func (w *Watcher) saveToken(ctx context.Context, name string, token bson.Raw) error {
+	_, err := w.tokens.ReplaceOne(ctx,
+		bson.M{"_id": name},
+		resumeToken{Collection: name, Token: token, UpdatedAt: time.Now()},
+		options.Replace().SetUpsert(true),
+	)
+	if err != nil {
+		return errors.Wrap(err, "Watcher.saveToken.ReplaceOne")
+	}
+
+	return nil
+}
+
+func (w *Watcher) deleteToken(ctx context.Context, name string) error {
+	if _, err := w.tokens.DeleteOne(ctx, bson.M{"_id": name}); err != nil {
+		return errors.Wrap(err, "Watcher.deleteToken.DeleteOne")
+	}
+
+	return nil
+}
+
+// Collection whose change stream reports the event type
+func watchedBy(eventType string) (collection, bool) {
+	for _, c := range collections {
+		if eventType == c.created || eventType == c.updated || eventType == c.deleted {
+			return c, true
+		}
+	}
+	return collection{}, false
+}
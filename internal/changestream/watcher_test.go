@@ -0,0 +1,124 @@
+package changestream
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/events"
+)
+
+type published struct {
+	eventType string
+	data      interface{}
+}
+
+// Publisher keeping what it was handed
+type recordingPublisher struct {
+	published []published
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, eventType string, data interface{}) {
+	p.published = append(p.published, published{eventType: eventType, data: data})
+}
+
+// Watcher of a client that never connects, publishing to a recording publisher
+func newTestWatcher(t *testing.T) (*Watcher, *recordingPublisher) {
+	t.Helper()
+
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+
+	publisher := &recordingPublisher{}
+
+	return NewWatcher(client.Database("pokedex"), publisher, nil), publisher
+}
+
+func TestPublishSkipsEventsOfOpenStreams(t *testing.T) {
+	tests := []struct {
+		name      string
+		open      []string
+		eventType string
+		forwarded bool
+	}{
+		{name: "no open streams", eventType: events.MonsterCreated, forwarded: true},
+		{name: "stream of the collection open", open: []string{"monsters"}, eventType: events.MonsterUpdated},
+		{name: "only another stream open", open: []string{"users", "monster_types"}, eventType: events.MonsterDeleted, forwarded: true},
+		{name: "monster type stream open", open: []string{"monster_types"}, eventType: events.MonsterTypeCreated},
+		{name: "event no stream reports", open: []string{"monsters", "monster_types", "users"}, eventType: events.UserMonsterCaught, forwarded: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, publisher := newTestWatcher(t)
+			for _, name := range tt.open {
+				w.open[name].Add(1)
+			}
+
+			w.Publish(context.Background(), tt.eventType, &domain.DeletedEntity{ID: primitive.NewObjectID()})
+
+			if forwarded := len(publisher.published) == 1; forwarded != tt.forwarded {
+				t.Errorf("forwarded %v, want %v", forwarded, tt.forwarded)
+			}
+		})
+	}
+}
+
+func TestPublishRedactsUsers(t *testing.T) {
+	role := domain.AdminRole
+	user := &domain.User{
+		ID:         primitive.NewObjectID(),
+		Monsters:   []primitive.ObjectID{primitive.NewObjectID()},
+		Username:   "ash",
+		Password:   "$2a$10$hash",
+		Role:       &role,
+		Identities: []domain.UserIdentity{{Provider: "google", Subject: "1234"}},
+		Version:    4,
+	}
+	raw, err := bson.Marshal(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w, publisher := newTestWatcher(t)
+	users, _ := watchedBy(events.UserCreated)
+
+	for _, operation := range []string{"insert", "update", "replace"} {
+		change := &changeEvent{OperationType: operation, FullDocument: raw}
+		change.DocumentKey.ID = user.ID
+		if err := w.publish(context.Background(), users, change); err != nil {
+			t.Fatalf("%s: %v", operation, err)
+		}
+	}
+
+	if len(publisher.published) != 3 {
+		t.Fatalf("published %d events", len(publisher.published))
+	}
+	for _, event := range publisher.published {
+		data, err := json.Marshal(event.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"_id":"` + user.ID.Hex() + `","username":"ash","version":4}`
+		if string(data) != want {
+			t.Errorf("%s published %s, want %s", event.eventType, data, want)
+		}
+		for _, secret := range []string{"hash", role, "google", "1234", user.Monsters[0].Hex()} {
+			if strings.Contains(string(data), secret) {
+				t.Errorf("%s leaks %q", event.eventType, secret)
+			}
+		}
+	}
+	if publisher.published[0].eventType != events.UserCreated || publisher.published[1].eventType != events.UserUpdated {
+		t.Errorf("published %s and %s", publisher.published[0].eventType, publisher.published[1].eventType)
+	}
+}
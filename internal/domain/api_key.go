@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Scopes an API key can be limited to, on top of the permissions of its owner's role
+const (
+	ScopeCatalogRead = "catalog:read"
+	ScopeCatch       = "catch"
+	ScopeAdmin       = "admin"
+)
+
+// Named key for service-to-service access, acting as the user who created it.
+// Only the SHA-256 of the key is stored, the prefix is kept to tell keys apart.
+type APIKey struct {
+	ID         primitive.ObjectID `json:"_id" xml:"_id" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"user_id" xml:"user_id" bson:"user_id"`
+	Name       string             `json:"name" xml:"name" bson:"name"`
+	Prefix     string             `json:"prefix" xml:"prefix" bson:"prefix"`
+	KeyHash    string             `json:"-" xml:"-" bson:"key_hash"`
+	Scopes     []string           `json:"scopes" xml:"scopes>scope" bson:"scopes"`
+	ExpiresAt  time.Time          `json:"expires_at" xml:"expires_at" bson:"expires_at"`
+	LastUsedAt *time.Time         `json:"last_used_at,omitempty" xml:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+	RevokedAt  *time.Time         `json:"revoked_at,omitempty" xml:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	CreatedAt  time.Time          `json:"created_at" xml:"created_at" bson:"created_at"`
+}
+
+type APIKeyRequest struct {
+	Name      string     `json:"name" xml:"name" validate:"required"`
+	Scopes    []string   `json:"scopes" xml:"scopes>scope" validate:"required,min=1,dive,oneof=catalog:read catch admin"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" xml:"expires_at,omitempty" extensions:"x-nullable"`
+}
+
+// The key itself is only returned once, when it is created
+type APIKeyWithSecret struct {
+	APIKey *APIKey `json:"api_key" xml:"api_key"`
+	Key    string  `json:"key" xml:"key"`
+}
+
+type APIKeyList struct {
+	APIKeys []*APIKey `json:"api_keys" xml:"api_keys>api_key"`
+}
+
+func (k *APIKey) IsActive() bool {
+	return k.RevokedAt == nil && time.Now().Before(k.ExpiresAt)
+}
+
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether the scopes let the key use a permission of its owner's role
+func (k *APIKey) Grants(permission string) bool {
+	if k.HasScope(ScopeAdmin) {
+		return true
+	}
+
+	return permission == PermMonsterRead && k.HasScope(ScopeCatalogRead)
+}
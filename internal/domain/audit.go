@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Actions recorded in the audit log
+const (
+	AuditMonsterCreated     = "monster.created"
+	AuditMonsterUpdated     = "monster.updated"
+	AuditMonsterDeleted     = "monster.deleted"
+	AuditMonsterTypeCreated = "monster_type.created"
+	AuditMonsterTypeUpdated = "monster_type.updated"
+	AuditMonsterTypeDeleted = "monster_type.deleted"
+	AuditUserCreated        = "user.created"
+	AuditUserUpdated        = "user.updated"
+	AuditUserDeleted        = "user.deleted"
+	AuditUserMonsterCaught  = "user.monster_caught"
+	AuditUserLogin          = "user.login"
+	AuditUserLoginFailed    = "user.login_failed"
+	AuditUserLogout         = "user.logout"
+	AuditUserPasswordChange = "user.password_changed"
+	AuditUserPasswordReset  = "user.password_reset"
+	AuditUserSessionsRevoke = "user.sessions_revoked"
+	AuditUserIdentityLinked = "user.identity_linked"
+	AuditRoleCreated        = "role.created"
+	AuditRoleAssigned       = "role.assigned"
+	AuditInviteCreated      = "invite.created"
+	AuditAPIKeyCreated      = "api_key.created"
+	AuditAPIKeyRevoked      = "api_key.revoked"
+	AuditMFAEnabled         = "mfa.enabled"
+	AuditMFADisabled        = "mfa.disabled"
+	AuditSessionRevoked     = "session.revoked"
+	AuditLoginLocked        = "login_lock.locked"
+	AuditLoginLockReleased  = "login_lock.released"
+)
+
+// What an audited action was done to
+const (
+	AuditTargetMonster     = "monster"
+	AuditTargetMonsterType = "monster_type"
+	AuditTargetUser        = "user"
+	AuditTargetUsername    = "username"
+	AuditTargetRole        = "role"
+	AuditTargetInvite      = "invite"
+	AuditTargetAPIKey      = "api_key"
+	AuditTargetSession     = "session"
+	AuditTargetLoginLock   = "login_lock"
+)
+
+// An entry of the append-only audit log. The actor is the authenticated user of the request,
+// or the user signing in. Snapshots are taken as the API renders the target, without secrets.
+type AuditEntry struct {
+	ID            primitive.ObjectID     `json:"_id" xml:"_id" bson:"_id,omitempty"`
+	ActorID       *primitive.ObjectID    `json:"actor_id,omitempty" xml:"actor_id,omitempty" bson:"actor_id,omitempty"`
+	ActorUsername string                 `json:"actor_username,omitempty" xml:"actor_username,omitempty" bson:"actor_username,omitempty"`
+	APIKeyID      *primitive.ObjectID    `json:"api_key_id,omitempty" xml:"api_key_id,omitempty" bson:"api_key_id,omitempty"`
+	Action        string                 `json:"action" xml:"action" bson:"action"`
+	TargetType    string                 `json:"target_type" xml:"target_type" bson:"target_type"`
+	TargetID      string                 `json:"target_id" xml:"target_id" bson:"target_id"`
+	Before        map[string]interface{} `json:"before,omitempty" xml:"-" bson:"before,omitempty" swaggertype:"object"`
+	After         map[string]interface{} `json:"after,omitempty" xml:"-" bson:"after,omitempty" swaggertype:"object"`
+	RequestID     string                 `json:"request_id,omitempty" xml:"request_id,omitempty" bson:"request_id,omitempty"`
+	IPAddress     string                 `json:"ip_address,omitempty" xml:"ip_address,omitempty" bson:"ip_address,omitempty"`
+	CreatedAt     time.Time              `json:"created_at" xml:"created_at" bson:"created_at"`
+}
+
+// Entries matching every set field are listed, From is inclusive and To exclusive
+type AuditFilter struct {
+	ActorID    *primitive.ObjectID
+	TargetType string
+	TargetID   string
+	From       *time.Time
+	To         *time.Time
+}
+
+type AuditList struct {
+	TotalCount int           `json:"total_count" xml:"total_count"`
+	TotalPages int           `json:"total_pages" xml:"total_pages"`
+	Page       int           `json:"page" xml:"page"`
+	Size       int           `json:"size" xml:"size"`
+	HasMore    bool          `json:"has_more" xml:"has_more"`
+	Entries    []*AuditEntry `json:"entries" xml:"entries>entry"`
+}
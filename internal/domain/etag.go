@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Strong entity tag of a stored document version
+func VersionETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// Version named by an entity tag built with VersionETag
+func ParseVersionETag(etag string) (int64, bool) {
+	value := strings.TrimSpace(etag)
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return 0, false
+	}
+
+	version, err := strconv.ParseInt(value[1:len(value)-1], 10, 64)
+	if err != nil || version < 0 {
+		return 0, false
+	}
+
+	return version, true
+}
+
+func (m *Monster) ETag() string {
+	return VersionETag(m.Version)
+}
+
+func (t *MonsterType) ETag() string {
+	return VersionETag(t.Version)
+}
+
+func (u *User) ETag() string {
+	return VersionETag(u.Version)
+}
+
+func (l *MonsterList) ETag() string {
+	hash := listHash(l.TotalCount, l.Page, l.Size)
+	for _, m := range l.Monsters {
+		writeVersion(hash, m.ID, m.Version)
+	}
+	return listETag(hash)
+}
+
+func (l *MonsterTypeList) ETag() string {
+	hash := listHash(l.TotalCount, l.Page, l.Size)
+	for _, t := range l.MonsterTypes {
+		writeVersion(hash, t.ID, t.Version)
+	}
+	return listETag(hash)
+}
+
+func (l *UserList) ETag() string {
+	hash := listHash(l.TotalCount, l.Page, l.Size)
+	for _, u := range l.Users {
+		writeVersion(hash, u.ID, u.Version)
+	}
+	return listETag(hash)
+}
+
+// A list page changes when its position or any document on it does
+func listHash(totalCount, page, size int) hash.Hash {
+	h := sha1.New()
+	fmt.Fprintf(h, "%d:%d:%d", totalCount, page, size)
+	return h
+}
+
+func writeVersion(h hash.Hash, id primitive.ObjectID, version int64) {
+	fmt.Fprintf(h, "|%s:%d", id.Hex(), version)
+}
+
+func listETag(h hash.Hash) string {
+	return `"list-` + hex.EncodeToString(h.Sum(nil)) + `"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Payload of *.deleted events
+type DeletedEntity struct {
+	ID primitive.ObjectID `json:"_id" xml:"_id"`
+}
+
+// Payload of user.monster_caught events
+type MonsterCaught struct {
+	UserID    primitive.ObjectID `json:"user_id" xml:"user_id"`
+	MonsterID primitive.ObjectID `json:"monster_id" xml:"monster_id"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Single-use invitation to register with a role other than the default one.
+// The code handed out is signed, the invite itself records whether it was used.
+type Invite struct {
+	ID        primitive.ObjectID `json:"_id" xml:"_id" bson:"_id,omitempty"`
+	Role      string             `json:"role" xml:"role" bson:"role"`
+	CreatedBy primitive.ObjectID `json:"created_by" xml:"created_by" bson:"created_by"`
+	ExpiresAt time.Time          `json:"expires_at" xml:"expires_at" bson:"expires_at"`
+	UsedAt    *time.Time         `json:"used_at,omitempty" xml:"used_at,omitempty" bson:"used_at,omitempty"`
+	UsedBy    string             `json:"used_by,omitempty" xml:"used_by,omitempty" bson:"used_by,omitempty"`
+	CreatedAt time.Time          `json:"created_at" xml:"created_at" bson:"created_at"`
+}
+
+type InviteRequest struct {
+	Role string `json:"role" xml:"role" validate:"required"`
+}
+
+type InviteWithCode struct {
+	Invite *Invite `json:"invite" xml:"invite"`
+	Code   string  `json:"code" xml:"code"`
+}
+
+func (i *Invite) IsExpired() bool {
+	return time.Now().After(i.ExpiresAt)
+}
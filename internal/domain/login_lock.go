@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// What failed sign ins are counted by
+const (
+	LockKindUsername = "username"
+	LockKindIP       = "ip"
+)
+
+// Failed sign ins of a username or client IP. Attempts before the next attempt time are
+// rejected without checking the password, a lockout sets the locked until time.
+type LoginLock struct {
+	ID            primitive.ObjectID `json:"_id" xml:"_id" bson:"_id,omitempty"`
+	Kind          string             `json:"kind" xml:"kind" bson:"kind"`
+	Value         string             `json:"value" xml:"value" bson:"value"`
+	Failures      int                `json:"failures" xml:"failures" bson:"failures"`
+	Lockouts      int                `json:"lockouts" xml:"lockouts" bson:"lockouts"`
+	LastFailureAt time.Time          `json:"last_failure_at" xml:"last_failure_at" bson:"last_failure_at"`
+	NextAttemptAt *time.Time         `json:"next_attempt_at,omitempty" xml:"next_attempt_at,omitempty" bson:"next_attempt_at,omitempty"`
+	LockedUntil   *time.Time         `json:"locked_until,omitempty" xml:"locked_until,omitempty" bson:"locked_until,omitempty"`
+	ExpiresAt     time.Time          `json:"expires_at" xml:"expires_at" bson:"expires_at"`
+}
+
+type LoginLockList struct {
+	LoginLocks []*LoginLock `json:"login_locks" xml:"login_locks>login_lock"`
+}
+
+// Time until sign ins are accepted again, zero when they are
+func (l *LoginLock) RetryAfter(now time.Time) time.Duration {
+	var until time.Time
+	if l.NextAttemptAt != nil {
+		until = *l.NextAttemptAt
+	}
+	if l.LockedUntil != nil && l.LockedUntil.After(until) {
+		until = *l.LockedUntil
+	}
+	if !until.After(now) {
+		return 0
+	}
+
+	return until.Sub(now)
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TOTP enrollment of a user, pending until confirmed with a first code.
+// Recovery codes are only stored as their SHA-256, the last step keeps codes from being replayed.
+type MFA struct {
+	ID            primitive.ObjectID `json:"_id" xml:"_id" bson:"_id,omitempty"`
+	UserID        primitive.ObjectID `json:"user_id" xml:"user_id" bson:"user_id"`
+	Secret        string             `json:"-" xml:"-" bson:"secret"`
+	Enabled       bool               `json:"enabled" xml:"enabled" bson:"enabled"`
+	RecoveryCodes []string           `json:"-" xml:"-" bson:"recovery_codes"`
+	LastStep      int64              `json:"-" xml:"-" bson:"last_step"`
+	EnabledAt     *time.Time         `json:"enabled_at,omitempty" xml:"enabled_at,omitempty" bson:"enabled_at,omitempty"`
+	CreatedAt     time.Time          `json:"created_at" xml:"created_at" bson:"created_at"`
+}
+
+// Password was verified, a code is still needed before tokens are issued
+type MFAChallenge struct {
+	ID        primitive.ObjectID `json:"_id" xml:"_id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id" xml:"user_id" bson:"user_id"`
+	TokenHash string             `json:"-" xml:"-" bson:"token_hash"`
+	Attempts  int                `json:"attempts" xml:"attempts" bson:"attempts"`
+	ExpiresAt time.Time          `json:"expires_at" xml:"expires_at" bson:"expires_at"`
+	CreatedAt time.Time          `json:"created_at" xml:"created_at" bson:"created_at"`
+}
+
+type MFAEnrollment struct {
+	Secret          string `json:"secret" xml:"secret"`
+	ProvisioningURI string `json:"provisioning_uri" xml:"provisioning_uri"`
+}
+
+// TOTP code, or a recovery code where one is accepted
+type MFACode struct {
+	Code string `json:"code" xml:"code" validate:"required"`
+}
+
+type MFAVerification struct {
+	MFAToken string `json:"mfa_token" xml:"mfa_token" validate:"required"`
+	Code     string `json:"code" xml:"code" validate:"required"`
+}
+
+// Shown once when two-factor authentication is enabled
+type MFARecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes" xml:"recovery_codes>recovery_code"`
+}
@@ -1,48 +1,122 @@
 package domain
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Monster struct {
-	ID           primitive.ObjectID   `json:"_id" bson:"_id,omitempty"`
-	MonsterTypes []primitive.ObjectID `json:"monster_types" bson:"monster_types"`
-	Name         string               `json:"name" bson:"name"`
-	ImageUrl     string               `json:"image_url" bson:"image_url"`
-	Description  string               `json:"description" bson:"description"`
-	Size         float32              `json:"size" bson:"size"`
-	Weight       float32              `json:"weight" bson:"weight"`
-	Hp           int32                `json:"hp" bson:"hp"`
-	Attack       int32                `json:"attack" bson:"attack"`
-	Defense      int32                `json:"defense" bson:"defense"`
-	Speed        int32                `json:"speed" bson:"speed"`
-	CreatedAt    time.Time            `json:"created_at" bson:"created_at"`
-	UpdatedAt    time.Time            `json:"updated_at" bson:"updated_at"`
+	ID           primitive.ObjectID   `json:"_id" xml:"_id" bson:"_id,omitempty"`
+	MonsterTypes []primitive.ObjectID `json:"monster_types" xml:"monster_types" bson:"monster_types" extensions:"x-nullable"`
+	Name         string               `json:"name" xml:"name" bson:"name"`
+	ImageUrl     string               `json:"image_url" xml:"image_url" bson:"image_url"`
+	ImageKey     string               `json:"-" xml:"-" bson:"image_key,omitempty"`
+	Thumbnails   []MonsterThumbnail   `json:"thumbnails" xml:"thumbnails" bson:"thumbnails,omitempty" extensions:"x-nullable"`
+	Description  string               `json:"description" xml:"description" bson:"description"`
+	Size         float32              `json:"size" xml:"size" bson:"size"`
+	Weight       float32              `json:"weight" xml:"weight" bson:"weight"`
+	Hp           int32                `json:"hp" xml:"hp" bson:"hp"`
+	Attack       int32                `json:"attack" xml:"attack" bson:"attack"`
+	Defense      int32                `json:"defense" xml:"defense" bson:"defense"`
+	Speed        int32                `json:"speed" xml:"speed" bson:"speed"`
+	Version      int64                `json:"version" xml:"version" bson:"version"`
+	CreatedAt    time.Time            `json:"created_at" xml:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at" xml:"updated_at" bson:"updated_at"`
+}
+
+type MonsterThumbnail struct {
+	Width int    `json:"width" xml:"width" bson:"width"`
+	Url   string `json:"url" xml:"url" bson:"url"`
+	Key   string `json:"-" xml:"-" bson:"key"`
 }
 
 type MonsterUpdate struct {
-	ID          primitive.ObjectID `json:"_id,omitempty"`
-	Name        string             `json:"name"`
-	Description string             `json:"description"`
-	Size        float32            `json:"size"`
-	Weight      float32            `json:"weight"`
-	Hp          int32              `json:"hp"`
-	Attack      int32              `json:"attack"`
-	Defense     int32              `json:"defense"`
-	Speed       int32              `json:"speed"`
+	ID          primitive.ObjectID `json:"_id,omitempty" xml:"_id,omitempty"`
+	Name        string             `json:"name" xml:"name" validate:"required"`
+	Description string             `json:"description" xml:"description"`
+	Size        float32            `json:"size" xml:"size"`
+	Weight      float32            `json:"weight" xml:"weight"`
+	Hp          int32              `json:"hp" xml:"hp"`
+	Attack      int32              `json:"attack" xml:"attack"`
+	Defense     int32              `json:"defense" xml:"defense"`
+	Speed       int32              `json:"speed" xml:"speed"`
+	Version     *int64             `json:"version,omitempty" xml:"version,omitempty" extensions:"x-nullable"`
 }
 
 type MonsterTypeBody struct {
-	MonsterTypeID primitive.ObjectID `json:"monster_type_id"`
+	MonsterTypeID primitive.ObjectID `json:"monster_type_id" xml:"monster_type_id"`
 }
 
 type MonsterList struct {
-	TotalCount int        `json:"total_count"`
-	TotalPages int        `json:"total_pages"`
-	Page       int        `json:"page"`
-	Size       int        `json:"size"`
-	HasMore    bool       `json:"has_more"`
-	Monsters   []*Monster `json:"monsters"`
+	TotalCount int        `json:"total_count" xml:"total_count"`
+	TotalPages int        `json:"total_pages" xml:"total_pages"`
+	Page       int        `json:"page" xml:"page"`
+	Size       int        `json:"size" xml:"size"`
+	HasMore    bool       `json:"has_more" xml:"has_more"`
+	Monsters   []*Monster `json:"monsters" xml:"monsters"`
+}
+
+// Editable fields of the monster, the document PATCH requests are applied to
+func (m *Monster) MonsterUpdate() *MonsterUpdate {
+	return &MonsterUpdate{
+		ID:          m.ID,
+		Name:        m.Name,
+		Description: m.Description,
+		Size:        m.Size,
+		Weight:      m.Weight,
+		Hp:          m.Hp,
+		Attack:      m.Attack,
+		Defense:     m.Defense,
+		Speed:       m.Speed,
+		Version:     &m.Version,
+	}
+}
+
+// Keys of every blob stored for the monster image
+func (m *Monster) ImageKeys() []string {
+	keys := make([]string, 0, len(m.Thumbnails)+1)
+	if m.ImageKey != "" {
+		keys = append(keys, m.ImageKey)
+	}
+	for _, t := range m.Thumbnails {
+		keys = append(keys, t.Key)
+	}
+	return keys
+}
+
+// Render monster list as CSV rows
+func (l *MonsterList) MarshalCSV() ([]string, [][]string) {
+	header := []string{"_id", "name", "description", "size", "weight", "hp", "attack", "defense", "speed", "monster_types", "image_url", "created_at", "updated_at"}
+
+	records := make([][]string, 0, len(l.Monsters))
+	for _, m := range l.Monsters {
+		records = append(records, []string{
+			m.ID.Hex(),
+			m.Name,
+			m.Description,
+			strconv.FormatFloat(float64(m.Size), 'f', -1, 32),
+			strconv.FormatFloat(float64(m.Weight), 'f', -1, 32),
+			strconv.Itoa(int(m.Hp)),
+			strconv.Itoa(int(m.Attack)),
+			strconv.Itoa(int(m.Defense)),
+			strconv.Itoa(int(m.Speed)),
+			joinObjectIDs(m.MonsterTypes),
+			m.ImageUrl,
+			m.CreatedAt.Format(time.RFC3339),
+			m.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return header, records
+}
+
+func joinObjectIDs(ids []primitive.ObjectID) string {
+	hex := make([]string, 0, len(ids))
+	for _, id := range ids {
+		hex = append(hex, id.Hex())
+	}
+	return strings.Join(hex, ";")
 }
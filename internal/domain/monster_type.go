@@ -7,22 +7,46 @@ import (
 )
 
 type MonsterType struct {
-	ID        primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
-	Name      string             `json:"name" bson:"name" validate:"required,lte=4"`
-	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+	ID        primitive.ObjectID `json:"_id" xml:"_id" bson:"_id,omitempty"`
+	Name      string             `json:"name" xml:"name" bson:"name" validate:"required,lte=4"`
+	Version   int64              `json:"version" xml:"version" bson:"version"`
+	CreatedAt time.Time          `json:"created_at" xml:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" xml:"updated_at" bson:"updated_at"`
 }
 
 type MonsterTypeUpdate struct {
-	ID   primitive.ObjectID `json:"_id,omitempty"`
-	Name string             `json:"name"`
+	ID      primitive.ObjectID `json:"_id,omitempty" xml:"_id,omitempty"`
+	Name    string             `json:"name" xml:"name" validate:"required,lte=4"`
+	Version *int64             `json:"version,omitempty" xml:"version,omitempty" extensions:"x-nullable"`
 }
 
 type MonsterTypeList struct {
-	TotalCount   int            `json:"total_count"`
-	TotalPages   int            `json:"total_pages"`
-	Page         int            `json:"page"`
-	Size         int            `json:"size"`
-	HasMore      bool           `json:"has_more"`
-	MonsterTypes []*MonsterType `json:"monster_types"`
+	TotalCount   int            `json:"total_count" xml:"total_count"`
+	TotalPages   int            `json:"total_pages" xml:"total_pages"`
+	Page         int            `json:"page" xml:"page"`
+	Size         int            `json:"size" xml:"size"`
+	HasMore      bool           `json:"has_more" xml:"has_more"`
+	MonsterTypes []*MonsterType `json:"monster_types" xml:"monster_types"`
+}
+
+// Editable fields of the monster type, the document PATCH requests are applied to
+func (t *MonsterType) MonsterTypeUpdate() *MonsterTypeUpdate {
+	return &MonsterTypeUpdate{ID: t.ID, Name: t.Name, Version: &t.Version}
+}
+
+// Render monster type list as CSV rows
+func (l *MonsterTypeList) MarshalCSV() ([]string, [][]string) {
+	header := []string{"_id", "name", "created_at", "updated_at"}
+
+	records := make([][]string, 0, len(l.MonsterTypes))
+	for _, t := range l.MonsterTypes {
+		records = append(records, []string{
+			t.ID.Hex(),
+			t.Name,
+			t.CreatedAt.Format(time.RFC3339),
+			t.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return header, records
 }
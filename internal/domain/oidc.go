@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Account at an OpenID Connect provider, users are matched by the subject of verified ID tokens
+type UserIdentity struct {
+	Provider string    `json:"provider" xml:"provider" bson:"provider"`
+	Subject  string    `json:"subject" xml:"subject" bson:"subject"`
+	LinkedAt time.Time `json:"linked_at" xml:"linked_at" bson:"linked_at"`
+}
+
+// Sign-in started with a provider, redeemed once when the user is redirected back.
+// Only the SHA-256 of the state is stored, the verifier and nonce never leave the server.
+type OIDCState struct {
+	ID           primitive.ObjectID  `json:"_id" xml:"_id" bson:"_id,omitempty"`
+	StateHash    string              `json:"-" xml:"-" bson:"state_hash"`
+	Provider     string              `json:"provider" xml:"provider" bson:"provider"`
+	Nonce        string              `json:"-" xml:"-" bson:"nonce"`
+	CodeVerifier string              `json:"-" xml:"-" bson:"code_verifier"`
+	LinkUserID   *primitive.ObjectID `json:"link_user_id,omitempty" xml:"link_user_id,omitempty" bson:"link_user_id,omitempty"`
+	ExpiresAt    time.Time           `json:"expires_at" xml:"expires_at" bson:"expires_at"`
+	CreatedAt    time.Time           `json:"created_at" xml:"created_at" bson:"created_at"`
+}
+
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorization_url" xml:"authorization_url"`
+	// Kept in the state cookie of the browser that started the sign-in
+	State string `json:"-" xml:"-"`
+}
+
+// Query parameters the provider redirects back with
+type OIDCCallback struct {
+	Code             string `query:"code"`
+	State            string `query:"state" validate:"required"`
+	Error            string `query:"error"`
+	ErrorDescription string `query:"error_description"`
+	// State from the cookie of the browser the callback arrived in, it must be the one returned
+	BrowserState string `query:"-"`
+}
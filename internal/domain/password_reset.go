@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Single-use password reset token, only its SHA-256 is stored
+type PasswordResetToken struct {
+	ID        primitive.ObjectID `json:"_id" xml:"_id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id" xml:"user_id" bson:"user_id"`
+	TokenHash string             `json:"-" xml:"-" bson:"token_hash"`
+	ExpiresAt time.Time          `json:"expires_at" xml:"expires_at" bson:"expires_at"`
+	UsedAt    *time.Time         `json:"used_at,omitempty" xml:"used_at,omitempty" bson:"used_at,omitempty"`
+	CreatedAt time.Time          `json:"created_at" xml:"created_at" bson:"created_at"`
+}
+
+type PasswordChange struct {
+	CurrentPassword string `json:"current_password" xml:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" xml:"new_password" validate:"required,gte=6"`
+}
+
+type PasswordResetRequest struct {
+	Username string `json:"username" xml:"username" validate:"required"`
+}
+
+type PasswordReset struct {
+	Token       string `json:"token" xml:"token" validate:"required"`
+	NewPassword string `json:"new_password" xml:"new_password" validate:"required,gte=6"`
+}
+
+func (t *PasswordResetToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
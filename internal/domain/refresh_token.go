@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Opaque refresh token, only its SHA-256 is stored. Every rotation stays in the family of
+// the login it descends from, so presenting an already rotated token revokes the whole chain.
+type RefreshToken struct {
+	ID       primitive.ObjectID `json:"_id" xml:"_id" bson:"_id,omitempty"`
+	UserID   primitive.ObjectID `json:"user_id" xml:"user_id" bson:"user_id"`
+	FamilyID primitive.ObjectID `json:"family_id" xml:"family_id" bson:"family_id"`
+	// Session the family belongs to, unset for families started before sessions
+	SessionID *primitive.ObjectID `json:"session_id,omitempty" xml:"session_id,omitempty" bson:"session_id,omitempty"`
+	TokenHash string              `json:"-" xml:"-" bson:"token_hash"`
+	ExpiresAt time.Time           `json:"expires_at" xml:"expires_at" bson:"expires_at"`
+	RevokedAt *time.Time          `json:"revoked_at,omitempty" xml:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	CreatedAt time.Time           `json:"created_at" xml:"created_at" bson:"created_at"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" xml:"refresh_token" validate:"required"`
+}
+
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
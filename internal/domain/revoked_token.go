@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Denylist entry for the access token with the given JTI, or without one for every token
+// issued to the user before the entry was created. Entries are dropped once ExpiresAt passes,
+// by then the tokens they revoke have expired anyway.
+type RevokedToken struct {
+	ID        primitive.ObjectID `json:"_id" xml:"_id" bson:"_id,omitempty"`
+	JTI       string             `json:"jti,omitempty" xml:"jti,omitempty" bson:"jti,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id" xml:"user_id" bson:"user_id"`
+	ExpiresAt time.Time          `json:"expires_at" xml:"expires_at" bson:"expires_at"`
+	CreatedAt time.Time          `json:"created_at" xml:"created_at" bson:"created_at"`
+}
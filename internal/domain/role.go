@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Permissions checked by the deliveries, roles grant a set of them
+const (
+	PermMonsterRead  = "monster:read"
+	PermMonsterWrite = "monster:write"
+	PermUserRead     = "user:read"
+	PermUserWrite    = "user:write"
+	PermUserDelete   = "user:delete"
+	PermRoleRead     = "role:read"
+	PermRoleWrite    = "role:write"
+	PermWebhookRead  = "webhook:read"
+	PermWebhookWrite = "webhook:write"
+	PermAuditRead    = "audit:read"
+)
+
+// Roles created on startup, the admin role with every permission and the default role users register with
+const (
+	AdminRole   = "admin"
+	DefaultRole = "trainer"
+)
+
+// Every permission known to the API
+var Permissions = []string{
+	PermMonsterRead,
+	PermMonsterWrite,
+	PermUserRead,
+	PermUserWrite,
+	PermUserDelete,
+	PermRoleRead,
+	PermRoleWrite,
+	PermWebhookRead,
+	PermWebhookWrite,
+	PermAuditRead,
+}
+
+// Permissions the default role starts with
+var DefaultPermissions = []string{PermMonsterRead}
+
+// Users reference roles by name
+type Role struct {
+	ID          primitive.ObjectID `json:"_id" xml:"_id" bson:"_id,omitempty"`
+	Name        string             `json:"name" xml:"name" bson:"name" validate:"required"`
+	Permissions []string           `json:"permissions" xml:"permissions>permission" bson:"permissions" validate:"required,min=1"`
+	CreatedAt   time.Time          `json:"created_at" xml:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" xml:"updated_at" bson:"updated_at"`
+}
+
+type RoleList struct {
+	Roles []*Role `json:"roles" xml:"roles>role"`
+}
+
+type RoleAssignment struct {
+	Role string `json:"role" xml:"role" validate:"required"`
+}
+
+func (r *Role) PrepareCreate() {
+	r.Name = strings.ToLower(strings.TrimSpace(r.Name))
+	r.CreatedAt = time.Now()
+	r.UpdatedAt = r.CreatedAt
+}
+
+func IsPermission(permission string) bool {
+	for _, p := range Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
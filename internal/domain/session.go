@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// A sign in, shared by the refresh token family it started (same ID) and the session cookie.
+// Only the SHA-256 of the session ID in the cookie is stored.
+type Session struct {
+	ID         primitive.ObjectID `json:"_id" xml:"_id" bson:"_id"`
+	UserID     primitive.ObjectID `json:"user_id" xml:"user_id" bson:"user_id"`
+	TokenHash  string             `json:"-" xml:"-" bson:"token_hash"`
+	IPAddress  string             `json:"ip_address,omitempty" xml:"ip_address,omitempty" bson:"ip_address,omitempty"`
+	LastSeenAt time.Time          `json:"last_seen_at" xml:"last_seen_at" bson:"last_seen_at"`
+	ExpiresAt  time.Time          `json:"expires_at" xml:"expires_at" bson:"expires_at"`
+	RevokedAt  *time.Time         `json:"revoked_at,omitempty" xml:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	CreatedAt  time.Time          `json:"created_at" xml:"created_at" bson:"created_at"`
+}
+
+type SessionList struct {
+	Sessions []*Session `json:"sessions" xml:"sessions>session"`
+}
+
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// Token to send back in the X-CSRF-Token header of unsafe requests authenticated by cookie
+type CSRFToken struct {
+	Token     string    `json:"csrf_token" xml:"csrf_token"`
+	ExpiresAt time.Time `json:"expires_at" xml:"expires_at"`
+}
@@ -9,42 +9,96 @@ import (
 )
 
 type User struct {
-	ID        primitive.ObjectID   `json:"_id" bson:"_id,omitempty"`
-	Monsters  []primitive.ObjectID `json:"monsters" bson:"monsters"`
-	Username  string               `json:"username" bson:"username" validate:"required"`
-	Password  string               `json:"password,omitempty" bson:"password" validate:"required,gte=6"`
-	Role      *string              `json:"role" bson:"role" validate:"required"`
-	CreatedAt time.Time            `json:"created_at" bson:"created_at"`
-	UpdatedAt time.Time            `json:"updated_at" bson:"updated_at"`
+	ID         primitive.ObjectID   `json:"_id" xml:"_id" bson:"_id,omitempty"`
+	Monsters   []primitive.ObjectID `json:"monsters" xml:"monsters" bson:"monsters" extensions:"x-nullable"`
+	Username   string               `json:"username" xml:"username" bson:"username" validate:"required"`
+	Password   string               `json:"password,omitempty" xml:"password,omitempty" bson:"password" validate:"required,gte=6" extensions:"x-write-only"`
+	Role       *string              `json:"role" xml:"role" bson:"role" extensions:"x-nullable"`
+	Identities []UserIdentity       `json:"identities,omitempty" xml:"identities>identity,omitempty" bson:"identities,omitempty"`
+	Version    int64                `json:"version" xml:"version" bson:"version"`
+	CreatedAt  time.Time            `json:"created_at" xml:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time            `json:"updated_at" xml:"updated_at" bson:"updated_at"`
 }
 
+// Published for user changes in place of the document, which holds the role, linked
+// identities and caught monsters
+type UserEvent struct {
+	ID       primitive.ObjectID `json:"_id" xml:"_id"`
+	Username string             `json:"username" xml:"username"`
+	Version  int64              `json:"version" xml:"version"`
+}
+
+// Roles are changed through role assignment only
 type UserUpdate struct {
-	ID       primitive.ObjectID `json:"_id,omitempty"`
-	Username string             `json:"username"`
-	Role     *string            `json:"role"`
+	ID       primitive.ObjectID `json:"_id,omitempty" xml:"_id,omitempty"`
+	Username string             `json:"username" xml:"username" validate:"required"`
+	Version  *int64             `json:"version,omitempty" xml:"version,omitempty" extensions:"x-nullable"`
+}
+
+// Users register as trainers, an invite code grants the role it was issued for
+type UserRegistration struct {
+	Username   string `json:"username" xml:"username" validate:"required"`
+	Password   string `json:"password" xml:"password" validate:"required,gte=6"`
+	InviteCode string `json:"invite_code,omitempty" xml:"invite_code,omitempty"`
 }
 
 type UserMonsterBody struct {
-	MonsterID primitive.ObjectID `json:"monster_id"`
+	MonsterID primitive.ObjectID `json:"monster_id" xml:"monster_id"`
 }
 
 type UserLogin struct {
-	Username string `json:"username" validate:"required"`
-	Password string `json:"password" validate:"required,gte=6"`
+	Username string `json:"username" xml:"username" validate:"required"`
+	Password string `json:"password" xml:"password" validate:"required,gte=6"`
 }
 
 type UserList struct {
-	TotalCount int     `json:"total_count"`
-	TotalPages int     `json:"total_pages"`
-	Page       int     `json:"page"`
-	Size       int     `json:"size"`
-	HasMore    bool    `json:"has_more"`
-	Users      []*User `json:"users"`
+	TotalCount int     `json:"total_count" xml:"total_count"`
+	TotalPages int     `json:"total_pages" xml:"total_pages"`
+	Page       int     `json:"page" xml:"page"`
+	Size       int     `json:"size" xml:"size"`
+	HasMore    bool    `json:"has_more" xml:"has_more"`
+	Users      []*User `json:"users" xml:"users"`
 }
 
 type UserWithToken struct {
-	User  *User  `json:"user"`
-	Token string `json:"token"`
+	User         *User  `json:"user" xml:"user"`
+	Token        string `json:"token" xml:"token"`
+	ExpiresIn    int64  `json:"expires_in" xml:"expires_in"`
+	RefreshToken string `json:"refresh_token" xml:"refresh_token"`
+	// Set instead of the tokens for users with two-factor authentication, exchanged with a code
+	MFARequired bool   `json:"mfa_required,omitempty" xml:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty" xml:"mfa_token,omitempty"`
+	// Sent in the session cookie only
+	SessionID string `json:"-" xml:"-"`
+}
+
+// Render user list as CSV rows
+func (l *UserList) MarshalCSV() ([]string, [][]string) {
+	header := []string{"_id", "username", "role", "monsters", "created_at", "updated_at"}
+
+	records := make([][]string, 0, len(l.Users))
+	for _, u := range l.Users {
+		role := ""
+		if u.Role != nil {
+			role = *u.Role
+		}
+
+		records = append(records, []string{
+			u.ID.Hex(),
+			u.Username,
+			role,
+			joinObjectIDs(u.Monsters),
+			u.CreatedAt.Format(time.RFC3339),
+			u.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return header, records
+}
+
+// Editable fields of the user, the document PATCH requests are applied to
+func (u *User) UserUpdate() *UserUpdate {
+	return &UserUpdate{ID: u.ID, Username: u.Username, Version: &u.Version}
 }
 
 func (u *User) HashPassword() error {
@@ -79,10 +133,3 @@ func (u *User) PrepareCreate() error {
 	}
 	return nil
 }
-
-func (u *UserUpdate) PrepareUpdate() error {
-	if u.Role != nil {
-		*u.Role = strings.ToLower(strings.TrimSpace(*u.Role))
-	}
-	return nil
-}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+type Webhook struct {
+	ID          primitive.ObjectID `json:"_id" xml:"_id" bson:"_id,omitempty"`
+	URL         string             `json:"url" xml:"url" bson:"url" validate:"required,url"`
+	Description string             `json:"description" xml:"description" bson:"description"`
+	Events      []string           `json:"events" xml:"events" bson:"events" validate:"required,min=1"`
+	Secret      string             `json:"secret,omitempty" xml:"secret,omitempty" bson:"secret"`
+	Active      bool               `json:"active" xml:"active" bson:"active"`
+	CreatedAt   time.Time          `json:"created_at" xml:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" xml:"updated_at" bson:"updated_at"`
+}
+
+type WebhookUpdate struct {
+	ID          primitive.ObjectID `json:"_id,omitempty" xml:"_id,omitempty"`
+	URL         string             `json:"url" xml:"url" validate:"omitempty,url"`
+	Description *string            `json:"description" xml:"description" extensions:"x-nullable"`
+	Events      []string           `json:"events" xml:"events" extensions:"x-nullable"`
+	Active      *bool              `json:"active" xml:"active" extensions:"x-nullable"`
+}
+
+type WebhookList struct {
+	TotalCount int        `json:"total_count" xml:"total_count"`
+	TotalPages int        `json:"total_pages" xml:"total_pages"`
+	Page       int        `json:"page" xml:"page"`
+	Size       int        `json:"size" xml:"size"`
+	HasMore    bool       `json:"has_more" xml:"has_more"`
+	Webhooks   []*Webhook `json:"webhooks" xml:"webhooks"`
+}
+
+// Body posted to webhook endpoints
+type WebhookEvent struct {
+	ID        primitive.ObjectID `json:"id" xml:"id"`
+	Type      string             `json:"type" xml:"type"`
+	Source    string             `json:"source" xml:"source"`
+	Data      json.RawMessage    `json:"data" xml:"-" swaggertype:"object"`
+	CreatedAt time.Time          `json:"created_at" xml:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID            primitive.ObjectID       `json:"_id" xml:"_id" bson:"_id,omitempty"`
+	WebhookID     primitive.ObjectID       `json:"webhook_id" xml:"webhook_id" bson:"webhook_id"`
+	EventID       primitive.ObjectID       `json:"event_id" xml:"event_id" bson:"event_id"`
+	EventType     string                   `json:"event_type" xml:"event_type" bson:"event_type"`
+	Payload       string                   `json:"payload" xml:"payload" bson:"payload"`
+	Status        string                   `json:"status" xml:"status" bson:"status"`
+	RetryCount    int                      `json:"retry_count" xml:"retry_count" bson:"retry_count"`
+	NextAttemptAt time.Time                `json:"next_attempt_at" xml:"next_attempt_at" bson:"next_attempt_at"`
+	Attempts      []WebhookDeliveryAttempt `json:"attempts" xml:"attempts" bson:"attempts" extensions:"x-nullable"`
+	CreatedAt     time.Time                `json:"created_at" xml:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time                `json:"updated_at" xml:"updated_at" bson:"updated_at"`
+}
+
+type WebhookDeliveryAttempt struct {
+	StatusCode int           `json:"status_code" xml:"status_code" bson:"status_code"`
+	Error      string        `json:"error,omitempty" xml:"error,omitempty" bson:"error,omitempty"`
+	Duration   time.Duration `json:"duration" xml:"duration" bson:"duration" swaggertype:"integer"`
+	At         time.Time     `json:"at" xml:"at" bson:"at"`
+}
+
+type WebhookDeliveryList struct {
+	TotalCount int                `json:"total_count" xml:"total_count"`
+	TotalPages int                `json:"total_pages" xml:"total_pages"`
+	Page       int                `json:"page" xml:"page"`
+	Size       int                `json:"size" xml:"size"`
+	HasMore    bool               `json:"has_more" xml:"has_more"`
+	Deliveries []*WebhookDelivery `json:"deliveries" xml:"deliveries"`
+}
+
+// Hide the signing secret, it is only returned once on creation
+func (w *Webhook) SanitizeSecret() {
+	w.Secret = ""
+}
@@ -0,0 +1,143 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/parser"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/monster"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+func TestCheckQueryLimits(t *testing.T) {
+	cfg := &config.Config{GraphQL: config.GraphQL{MaxDepth: 3, MaxComplexity: 10}}
+
+	tests := []struct {
+		name  string
+		query string
+		ok    bool
+	}{
+		{name: "within the limits", query: `{ me { username monsters { name } } }`, ok: true},
+		{name: "over the depth limit", query: `{ users { users { monsters { name } } } }`, ok: false},
+		{name: "at the depth limit", query: `{ users { users { username } } }`, ok: true},
+		{name: "too deep through a fragment", query: `{ users { ...U } } fragment U on UserList { users { monsters { name } } }`, ok: false},
+		{name: "too deep through an inline fragment", query: `{ users { ... on UserList { users { monsters { name } } } } }`, ok: false},
+		{name: "too many aliased fields", query: `{ a: me { id } b: me { id } c: me { id } d: me { id } e: me { id } f: me { id } }`, ok: false},
+		{
+			name:  "fragments spread over and over",
+			query: `{ me { ...A } } fragment A on User { ...B ...B ...B } fragment B on User { ...C ...C ...C } fragment C on User { id username }`,
+			ok:    false,
+		},
+		{name: "fragment cycle", query: `{ me { ...A } } fragment A on User { id ...A }`, ok: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = checkQueryLimits(cfg, document)
+			if tt.ok && err != nil {
+				t.Errorf("refused: %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Error("accepted")
+			}
+		})
+	}
+}
+
+type fakeAuthUsecase struct {
+	auth.Usecase
+	mu          sync.Mutex
+	permissions map[string]int
+	user        *domain.User
+}
+
+func (u *fakeAuthUsecase) HasPermission(ctx context.Context, user *domain.User, permission string) (bool, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.permissions[permission]++
+	return true, nil
+}
+
+func (u *fakeAuthUsecase) UserList(ctx context.Context, pq *utils.PaginationQuery) (*domain.UserList, error) {
+	return &domain.UserList{Users: []*domain.User{u.user, u.user, u.user}}, nil
+}
+
+type fakeMonsterUsecase struct {
+	monster.MonsterUsecase
+}
+
+func (fakeMonsterUsecase) GetByIDs(ctx context.Context, monsterIDs []primitive.ObjectID) ([]*domain.Monster, error) {
+	monsters := make([]*domain.Monster, 0, len(monsterIDs))
+	for _, id := range monsterIDs {
+		monsters = append(monsters, &domain.Monster{ID: id, Name: "pikachu"})
+	}
+	return monsters, nil
+}
+
+type fakeMonsterTypeUsecase struct {
+	monster.MonsterTypeUsecase
+}
+
+func (fakeMonsterTypeUsecase) GetByIDs(ctx context.Context, monsterTypeIDs []primitive.ObjectID) ([]*domain.MonsterType, error) {
+	return nil, nil
+}
+
+func execute(t *testing.T, authUsecase auth.Usecase, user *domain.User, query string) *graphql.Result {
+	t.Helper()
+
+	cfg := &config.Config{Logger: config.Logger{Level: "fatal"}}
+	log := logger.NewLogger(cfg)
+	log.InitLogger()
+
+	monsterTypeUsecase, monsterUsecase := fakeMonsterTypeUsecase{}, fakeMonsterUsecase{}
+	resolver := NewResolver(cfg, authUsecase, monsterTypeUsecase, monsterUsecase, log)
+	schema, err := NewSchema(resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := withLoaders(context.Background(), newLoaders(monsterTypeUsecase, monsterUsecase))
+	if user != nil {
+		ctx = context.WithValue(ctx, utils.UserCtxKey{}, user)
+	}
+
+	return graphql.Do(graphql.Params{Schema: schema, RequestString: query, Context: ctx})
+}
+
+func TestPermissionsOncePerRequest(t *testing.T) {
+	role := domain.DefaultRole
+	user := &domain.User{ID: primitive.NewObjectID(), Username: "ash", Role: &role, Monsters: []primitive.ObjectID{primitive.NewObjectID()}}
+	authUsecase := &fakeAuthUsecase{permissions: make(map[string]int), user: user}
+
+	result := execute(t, authUsecase, user, `{ users { users { monsters { name } } } }`)
+	if result.HasErrors() {
+		t.Fatalf("errors: %v", result.Errors)
+	}
+
+	if checks := authUsecase.permissions[domain.PermMonsterRead]; checks != 1 {
+		t.Errorf("%s was checked %d times for three users", domain.PermMonsterRead, checks)
+	}
+}
+
+func TestUsersRequiresUser(t *testing.T) {
+	authUsecase := &fakeAuthUsecase{permissions: make(map[string]int), user: &domain.User{}}
+
+	result := execute(t, authUsecase, nil, `{ users { totalCount } }`)
+	if !result.HasErrors() || result.Errors[0].Extensions["status"] != http.StatusUnauthorized {
+		t.Errorf("anonymous users query got %v", result.Errors)
+	}
+}
@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/labstack/echo/v4"
+
+	"github.com/iamaul/go-pokedex/pkg/render"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Handler executes GraphQL requests sent as a JSON or MessagePack POST body
+func (r *Resolver) Handler(schema graphql.Schema) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := &request{}
+		if err := render.Bind(c, req); err != nil {
+			utils.LogResponseError(c, r.logger, err)
+			return render.Error(c, err)
+		}
+
+		// Queries that do not parse are left to graphql.Do, which reports the syntax error
+		if document, err := parser.Parse(parser.ParseParams{Source: req.Query}); err == nil {
+			if err := checkQueryLimits(r.cfg, document); err != nil {
+				r.logger.Errorf("GraphQL query refused, RequestID: %s, error: %s", utils.GetRequestID(c), err)
+				return c.JSON(http.StatusOK, &graphql.Result{Errors: []gqlerrors.FormattedError{{
+					Message:    err.Error(),
+					Locations:  []location.SourceLocation{},
+					Extensions: map[string]interface{}{"status": http.StatusBadRequest},
+				}}})
+			}
+		}
+
+		ctx := withLoaders(c.Request().Context(), newLoaders(r.monsterTypeUsecase, r.monsterUsecase))
+
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			VariableValues: req.Variables,
+			OperationName:  req.OperationName,
+			Context:        ctx,
+		})
+
+		return c.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,104 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/iamaul/go-pokedex/config"
+)
+
+// Deepest selection a query may nest, deep enough for the introspection query of GraphQL clients
+func maxDepth(cfg *config.Config) int {
+	if cfg.GraphQL.MaxDepth <= 0 {
+		return 15
+	}
+	return cfg.GraphQL.MaxDepth
+}
+
+// Fields a query may select in total, counted with every fragment spread expanded
+func maxComplexity(cfg *config.Config) int {
+	if cfg.GraphQL.MaxComplexity <= 0 {
+		return 500
+	}
+	return cfg.GraphQL.MaxComplexity
+}
+
+// Measures the operations of a parsed query, so costly queries are refused before anything resolves
+type queryCost struct {
+	fragments map[string]*ast.FragmentDefinition
+	// Fragments being expanded, a spread of one of them is a cycle that validation reports
+	expanding map[string]bool
+	fields    int
+	// Walking stops once more fields than this were counted, fragments spread over and over
+	// again would otherwise take exponential time to expand
+	maxFields int
+}
+
+// Check every operation of the document against the configured depth and complexity
+func checkQueryLimits(cfg *config.Config, document *ast.Document) error {
+	cost := &queryCost{
+		fragments: make(map[string]*ast.FragmentDefinition),
+		expanding: make(map[string]bool),
+		maxFields: maxComplexity(cfg),
+	}
+	for _, definition := range document.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok && fragment.Name != nil {
+			cost.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		depth := cost.depth(operation.SelectionSet)
+		if cost.fields > cost.maxFields {
+			return fmt.Errorf("query selects more than the limit of %d fields", cost.maxFields)
+		}
+		if depth > maxDepth(cfg) {
+			return fmt.Errorf("query depth %d exceeds the limit of %d", depth, maxDepth(cfg))
+		}
+	}
+
+	return nil
+}
+
+// Depth of the selection set, counting the fields selected on the way
+func (c *queryCost) depth(selectionSet *ast.SelectionSet) int {
+	if selectionSet == nil {
+		return 0
+	}
+
+	deepest := 0
+	for _, selection := range selectionSet.Selections {
+		if c.fields > c.maxFields {
+			break
+		}
+
+		depth := 0
+		switch selection := selection.(type) {
+		case *ast.Field:
+			c.fields++
+			depth = 1 + c.depth(selection.SelectionSet)
+		case *ast.InlineFragment:
+			depth = c.depth(selection.SelectionSet)
+		case *ast.FragmentSpread:
+			fragment, ok := c.fragments[selection.Name.Value]
+			if !ok || c.expanding[selection.Name.Value] {
+				continue
+			}
+			c.expanding[selection.Name.Value] = true
+			depth = c.depth(fragment.SelectionSet)
+			delete(c.expanding, selection.Name.Value)
+		}
+
+		if depth > deepest {
+			deepest = depth
+		}
+	}
+
+	return deepest
+}
@@ -0,0 +1,135 @@
+package graph
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/monster"
+)
+
+type loadersCtxKey struct{}
+
+// Loaders batch the id lookups of a single request so nested lists cost one query per depth
+type Loaders struct {
+	Monsters     *loader[*domain.Monster]
+	MonsterTypes *loader[*domain.MonsterType]
+
+	// Permissions of the request's user, nested fields check them once per request
+	// rather than once per parent
+	mu          sync.Mutex
+	permissions map[string]bool
+}
+
+func newLoaders(monsterTypeUsecase monster.MonsterTypeUsecase, monsterUsecase monster.MonsterUsecase) *Loaders {
+	return &Loaders{
+		Monsters: newLoader(monsterUsecase.GetByIDs, func(m *domain.Monster) primitive.ObjectID {
+			return m.ID
+		}),
+		MonsterTypes: newLoader(monsterTypeUsecase.GetByIDs, func(t *domain.MonsterType) primitive.ObjectID {
+			return t.ID
+		}),
+		permissions: make(map[string]bool),
+	}
+}
+
+// Verdict of check for the permission, remembered for the rest of the request unless it failed
+func (l *Loaders) HasPermission(permission string, check func() (bool, error)) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if allowed, ok := l.permissions[permission]; ok {
+		return allowed, nil
+	}
+
+	allowed, err := check()
+	if err != nil {
+		return false, err
+	}
+	l.permissions[permission] = allowed
+
+	return allowed, nil
+}
+
+func withLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, loadersCtxKey{}, loaders)
+}
+
+func loadersFromCtx(ctx context.Context) *Loaders {
+	return ctx.Value(loadersCtxKey{}).(*Loaders)
+}
+
+// loader queues ids until the first thunk is resolved, then fetches every queued id at once.
+// graphql-go resolves thunks breadth first, so all siblings are queued before that happens.
+type loader[T any] struct {
+	mu      sync.Mutex
+	fetch   func(ctx context.Context, ids []primitive.ObjectID) ([]T, error)
+	key     func(T) primitive.ObjectID
+	pending []primitive.ObjectID
+	queued  map[primitive.ObjectID]bool
+	cache   map[primitive.ObjectID]T
+	errs    map[primitive.ObjectID]error
+}
+
+func newLoader[T any](fetch func(ctx context.Context, ids []primitive.ObjectID) ([]T, error), key func(T) primitive.ObjectID) *loader[T] {
+	return &loader[T]{
+		fetch:  fetch,
+		key:    key,
+		queued: make(map[primitive.ObjectID]bool),
+		cache:  make(map[primitive.ObjectID]T),
+		errs:   make(map[primitive.ObjectID]error),
+	}
+}
+
+// LoadMany queues ids and returns a thunk resolving to the found items in ids order, unknown ids are skipped
+func (l *loader[T]) LoadMany(ctx context.Context, ids []primitive.ObjectID) func() (interface{}, error) {
+	l.mu.Lock()
+	for _, id := range ids {
+		if !l.queued[id] {
+			l.queued[id] = true
+			l.pending = append(l.pending, id)
+		}
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.dispatch(ctx)
+
+		items := make([]T, 0, len(ids))
+		for _, id := range ids {
+			if err := l.errs[id]; err != nil {
+				return nil, err
+			}
+			if item, ok := l.cache[id]; ok {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	}
+}
+
+func (l *loader[T]) dispatch(ctx context.Context) {
+	if len(l.pending) == 0 {
+		return
+	}
+
+	batch := l.pending
+	l.pending = nil
+
+	items, err := l.fetch(ctx, batch)
+	if err != nil {
+		for _, id := range batch {
+			l.errs[id] = err
+		}
+		return
+	}
+
+	for _, item := range items {
+		l.cache[l.key(item)] = item
+	}
+}
@@ -0,0 +1,464 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/monster"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+// Resolver binds schema fields to the auth and monster usecases
+type Resolver struct {
+	cfg                *config.Config
+	authUsecase        auth.Usecase
+	monsterTypeUsecase monster.MonsterTypeUsecase
+	monsterUsecase     monster.MonsterUsecase
+	logger             logger.Logger
+}
+
+// Resolver constructor
+func NewResolver(cfg *config.Config, authUsecase auth.Usecase, monsterTypeUsecase monster.MonsterTypeUsecase, monsterUsecase monster.MonsterUsecase, log logger.Logger) *Resolver {
+	return &Resolver{cfg: cfg, authUsecase: authUsecase, monsterTypeUsecase: monsterTypeUsecase, monsterUsecase: monsterUsecase, logger: log}
+}
+
+// Error carries the RestErr status to the client in the GraphQL error extensions
+type Error struct {
+	restErr httpErr.RestErr
+	message string
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+func (e *Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{"status": e.restErr.Status()}
+}
+
+func (r *Resolver) me(p graphql.ResolveParams) (interface{}, error) {
+	user, err := requireUser(p.Context)
+	if err != nil {
+		return nil, r.error("me", err)
+	}
+
+	return user, nil
+}
+
+func (r *Resolver) user(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := requireUser(p.Context); err != nil {
+		return nil, r.error("user", err)
+	}
+
+	userID, err := objectID(p.Args["id"])
+	if err != nil {
+		return nil, r.error("user", err)
+	}
+
+	user, err := r.authUsecase.GetByID(p.Context, userID)
+	if err != nil {
+		return nil, r.error("user", err)
+	}
+
+	return user, nil
+}
+
+func (r *Resolver) users(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := requireUser(p.Context); err != nil {
+		return nil, r.error("users", err)
+	}
+
+	userList, err := r.authUsecase.UserList(p.Context, paginationQuery(p.Args))
+	if err != nil {
+		return nil, r.error("users", err)
+	}
+
+	return userList, nil
+}
+
+func (r *Resolver) userMonsters(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := r.requirePermission(p.Context, domain.PermMonsterRead); err != nil {
+		return nil, r.error("User.monsters", err)
+	}
+
+	user := p.Source.(*domain.User)
+
+	return r.thunk("User.monsters", loadersFromCtx(p.Context).Monsters.LoadMany(p.Context, user.Monsters)), nil
+}
+
+func (r *Resolver) monster(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := r.requirePermission(p.Context, domain.PermMonsterRead); err != nil {
+		return nil, r.error("monster", err)
+	}
+
+	monsterID, err := objectID(p.Args["id"])
+	if err != nil {
+		return nil, r.error("monster", err)
+	}
+
+	m, err := r.monsterUsecase.GetByID(p.Context, monsterID)
+	if err != nil {
+		return nil, r.error("monster", err)
+	}
+
+	return m, nil
+}
+
+func (r *Resolver) monsters(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := r.requirePermission(p.Context, domain.PermMonsterRead); err != nil {
+		return nil, r.error("monsters", err)
+	}
+
+	monsterList, err := r.monsterUsecase.GetMonsterList(p.Context, paginationQuery(p.Args))
+	if err != nil {
+		return nil, r.error("monsters", err)
+	}
+
+	return monsterList, nil
+}
+
+func (r *Resolver) monsterMonsterTypes(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := r.requirePermission(p.Context, domain.PermMonsterRead); err != nil {
+		return nil, r.error("Monster.monsterTypes", err)
+	}
+
+	m := p.Source.(*domain.Monster)
+
+	return r.thunk("Monster.monsterTypes", loadersFromCtx(p.Context).MonsterTypes.LoadMany(p.Context, m.MonsterTypes)), nil
+}
+
+func (r *Resolver) monsterType(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := r.requirePermission(p.Context, domain.PermMonsterRead); err != nil {
+		return nil, r.error("monsterType", err)
+	}
+
+	monsterTypeID, err := objectID(p.Args["id"])
+	if err != nil {
+		return nil, r.error("monsterType", err)
+	}
+
+	monsterType, err := r.monsterTypeUsecase.GetByID(p.Context, monsterTypeID)
+	if err != nil {
+		return nil, r.error("monsterType", err)
+	}
+
+	return monsterType, nil
+}
+
+func (r *Resolver) monsterTypes(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := r.requirePermission(p.Context, domain.PermMonsterRead); err != nil {
+		return nil, r.error("monsterTypes", err)
+	}
+
+	monsterTypeList, err := r.monsterTypeUsecase.GetMonsterTypeList(p.Context, paginationQuery(p.Args))
+	if err != nil {
+		return nil, r.error("monsterTypes", err)
+	}
+
+	return monsterTypeList, nil
+}
+
+func (r *Resolver) createMonsterType(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := r.requirePermission(p.Context, domain.PermMonsterWrite); err != nil {
+		return nil, r.error("createMonsterType", err)
+	}
+
+	monsterType := &domain.MonsterType{Name: p.Args["name"].(string)}
+	if err := utils.ValidateStruct(p.Context, monsterType); err != nil {
+		return nil, r.error("createMonsterType", err)
+	}
+
+	createdMonsterType, err := r.monsterTypeUsecase.MonsterTypeCreate(p.Context, monsterType)
+	if err != nil {
+		return nil, r.error("createMonsterType", err)
+	}
+
+	return createdMonsterType, nil
+}
+
+func (r *Resolver) updateMonsterType(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := r.requirePermission(p.Context, domain.PermMonsterWrite); err != nil {
+		return nil, r.error("updateMonsterType", err)
+	}
+
+	monsterTypeID, err := objectID(p.Args["id"])
+	if err != nil {
+		return nil, r.error("updateMonsterType", err)
+	}
+
+	if _, err := r.monsterTypeUsecase.MonsterTypeUpdate(p.Context, &domain.MonsterTypeUpdate{
+		ID:   monsterTypeID,
+		Name: p.Args["name"].(string),
+	}); err != nil {
+		return nil, r.error("updateMonsterType", err)
+	}
+
+	monsterType, err := r.monsterTypeUsecase.GetByID(p.Context, monsterTypeID)
+	if err != nil {
+		return nil, r.error("updateMonsterType", err)
+	}
+
+	return monsterType, nil
+}
+
+func (r *Resolver) deleteMonsterType(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := r.requirePermission(p.Context, domain.PermMonsterWrite); err != nil {
+		return nil, r.error("deleteMonsterType", err)
+	}
+
+	monsterTypeID, err := objectID(p.Args["id"])
+	if err != nil {
+		return nil, r.error("deleteMonsterType", err)
+	}
+
+	if err := r.monsterTypeUsecase.MonsterTypeDeletion(p.Context, monsterTypeID, nil); err != nil {
+		return nil, r.error("deleteMonsterType", err)
+	}
+
+	return true, nil
+}
+
+func (r *Resolver) createMonster(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := r.requirePermission(p.Context, domain.PermMonsterWrite); err != nil {
+		return nil, r.error("createMonster", err)
+	}
+
+	m := &domain.Monster{}
+	monsterFromInput(m, p.Args["input"].(map[string]interface{}))
+	if err := utils.ValidateStruct(p.Context, m); err != nil {
+		return nil, r.error("createMonster", err)
+	}
+
+	createdMonster, err := r.monsterUsecase.MonsterCreate(p.Context, m)
+	if err != nil {
+		return nil, r.error("createMonster", err)
+	}
+
+	return createdMonster, nil
+}
+
+func (r *Resolver) updateMonster(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := r.requirePermission(p.Context, domain.PermMonsterWrite); err != nil {
+		return nil, r.error("updateMonster", err)
+	}
+
+	monsterID, err := objectID(p.Args["id"])
+	if err != nil {
+		return nil, r.error("updateMonster", err)
+	}
+
+	input := &domain.Monster{}
+	monsterFromInput(input, p.Args["input"].(map[string]interface{}))
+
+	if _, err := r.monsterUsecase.MonsterUpdate(p.Context, &domain.MonsterUpdate{
+		ID:          monsterID,
+		Name:        input.Name,
+		Description: input.Description,
+		Size:        input.Size,
+		Weight:      input.Weight,
+		Hp:          input.Hp,
+		Attack:      input.Attack,
+		Defense:     input.Defense,
+		Speed:       input.Speed,
+	}); err != nil {
+		return nil, r.error("updateMonster", err)
+	}
+
+	m, err := r.monsterUsecase.GetByID(p.Context, monsterID)
+	if err != nil {
+		return nil, r.error("updateMonster", err)
+	}
+
+	return m, nil
+}
+
+func (r *Resolver) deleteMonster(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := r.requirePermission(p.Context, domain.PermMonsterWrite); err != nil {
+		return nil, r.error("deleteMonster", err)
+	}
+
+	monsterID, err := objectID(p.Args["id"])
+	if err != nil {
+		return nil, r.error("deleteMonster", err)
+	}
+
+	if err := r.monsterUsecase.MonsterDeletion(p.Context, monsterID, nil); err != nil {
+		return nil, r.error("deleteMonster", err)
+	}
+
+	return true, nil
+}
+
+func (r *Resolver) attachMonsterType(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := r.requirePermission(p.Context, domain.PermMonsterWrite); err != nil {
+		return nil, r.error("attachMonsterType", err)
+	}
+
+	monsterID, err := objectID(p.Args["monsterId"])
+	if err != nil {
+		return nil, r.error("attachMonsterType", err)
+	}
+
+	monsterTypeID, err := objectID(p.Args["monsterTypeId"])
+	if err != nil {
+		return nil, r.error("attachMonsterType", err)
+	}
+
+	if err := r.monsterUsecase.AttachMonsterType(p.Context, monsterID, monsterTypeID); err != nil {
+		return nil, r.error("attachMonsterType", err)
+	}
+
+	m, err := r.monsterUsecase.GetByID(p.Context, monsterID)
+	if err != nil {
+		return nil, r.error("attachMonsterType", err)
+	}
+
+	return m, nil
+}
+
+func (r *Resolver) deleteUser(p graphql.ResolveParams) (interface{}, error) {
+	if _, err := r.requirePermission(p.Context, domain.PermUserDelete); err != nil {
+		return nil, r.error("deleteUser", err)
+	}
+
+	userID, err := objectID(p.Args["id"])
+	if err != nil {
+		return nil, r.error("deleteUser", err)
+	}
+
+	if err := r.authUsecase.UserDeletion(p.Context, userID, nil); err != nil {
+		return nil, r.error("deleteUser", err)
+	}
+
+	return true, nil
+}
+
+// Convert usecase errors once the batched thunk resolves
+func (r *Resolver) thunk(field string, load func() (interface{}, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		items, err := load()
+		if err != nil {
+			return nil, r.error(field, err)
+		}
+		return items, nil
+	}
+}
+
+func (r *Resolver) error(field string, err error) error {
+	r.logger.Errorf("GraphQL resolver field: %s, error: %s", field, err)
+
+	restErr := httpErr.ParseErrors(err)
+
+	message := http.StatusText(restErr.Status())
+	if e, ok := restErr.(httpErr.RestError); ok && e.ErrError != "" {
+		message = e.ErrError
+	}
+
+	return &Error{restErr: restErr, message: message}
+}
+
+// Same check as the JWT middleware
+func requireUser(ctx context.Context) (*domain.User, error) {
+	user, err := utils.GetUserFromCtx(ctx)
+	if err != nil {
+		return nil, httpErr.NewUnauthorizedError(httpErr.Unauthorized)
+	}
+
+	return user, nil
+}
+
+// Same checks as the JWT and permission middlewares, the user's permissions are looked up once per request
+func (r *Resolver) requirePermission(ctx context.Context, permission string) (*domain.User, error) {
+	user, err := requireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, ok := utils.GetAPIKeyFromCtx(ctx); ok && !key.Grants(permission) {
+		return nil, httpErr.NewForbiddenError(httpErr.PermissionDenied)
+	}
+
+	allowed, err := loadersFromCtx(ctx).HasPermission(permission, func() (bool, error) {
+		return r.authUsecase.HasPermission(ctx, user, permission)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, httpErr.NewForbiddenError(httpErr.PermissionDenied)
+	}
+
+	return user, nil
+}
+
+func resolveID(p graphql.ResolveParams) (interface{}, error) {
+	switch source := p.Source.(type) {
+	case *domain.User:
+		return source.ID.Hex(), nil
+	case *domain.Monster:
+		return source.ID.Hex(), nil
+	case *domain.MonsterType:
+		return source.ID.Hex(), nil
+	}
+	return nil, nil
+}
+
+func objectID(arg interface{}) (primitive.ObjectID, error) {
+	id, _ := arg.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, httpErr.NewBadRequestError(err)
+	}
+
+	return objectID, nil
+}
+
+func paginationQuery(args map[string]interface{}) *utils.PaginationQuery {
+	pq := &utils.PaginationQuery{}
+
+	if size, ok := args["size"].(int); ok && size > 0 {
+		pq.Size = size
+	} else {
+		_ = pq.SetSize("")
+	}
+	if page, ok := args["page"].(int); ok {
+		pq.Page = page
+	}
+	if orderBy, ok := args["orderBy"].(string); ok {
+		pq.SetOrderBy(orderBy)
+	}
+
+	return pq
+}
+
+func monsterFromInput(m *domain.Monster, input map[string]interface{}) {
+	m.Name, _ = input["name"].(string)
+	m.Description, _ = input["description"].(string)
+	if size, ok := input["size"].(float64); ok {
+		m.Size = float32(size)
+	}
+	if weight, ok := input["weight"].(float64); ok {
+		m.Weight = float32(weight)
+	}
+	if hp, ok := input["hp"].(int); ok {
+		m.Hp = int32(hp)
+	}
+	if attack, ok := input["attack"].(int); ok {
+		m.Attack = int32(attack)
+	}
+	if defense, ok := input["defense"].(int); ok {
+		m.Defense = int32(defense)
+	}
+	if speed, ok := input["speed"].(int); ok {
+		m.Speed = int32(speed)
+	}
+}
@@ -0,0 +1,155 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// Pagination arguments mirroring utils.PaginationQuery
+var paginationArgs = graphql.FieldConfigArgument{
+	"page":    &graphql.ArgumentConfig{Type: graphql.Int},
+	"size":    &graphql.ArgumentConfig{Type: graphql.Int},
+	"orderBy": &graphql.ArgumentConfig{Type: graphql.String},
+}
+
+var idArgs = graphql.FieldConfigArgument{
+	"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+}
+
+var monsterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "MonsterInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"description": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"size":        &graphql.InputObjectFieldConfig{Type: graphql.Float},
+		"weight":      &graphql.InputObjectFieldConfig{Type: graphql.Float},
+		"hp":          &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"attack":      &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"defense":     &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"speed":       &graphql.InputObjectFieldConfig{Type: graphql.Int},
+	},
+})
+
+// Build the schema over monsters, monster types and users
+func NewSchema(r *Resolver) (graphql.Schema, error) {
+	monsterTypeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "MonsterType",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: resolveID},
+			"name":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"createdAt": &graphql.Field{Type: graphql.DateTime},
+			"updatedAt": &graphql.Field{Type: graphql.DateTime},
+		},
+	})
+
+	thumbnailType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "MonsterThumbnail",
+		Fields: graphql.Fields{
+			"width": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"url":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		},
+	})
+
+	monsterType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Monster",
+		Fields: graphql.Fields{
+			"id":           &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: resolveID},
+			"name":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"description":  &graphql.Field{Type: graphql.String},
+			"imageUrl":     &graphql.Field{Type: graphql.String},
+			"thumbnails":   &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(thumbnailType))},
+			"size":         &graphql.Field{Type: graphql.Float},
+			"weight":       &graphql.Field{Type: graphql.Float},
+			"hp":           &graphql.Field{Type: graphql.Int},
+			"attack":       &graphql.Field{Type: graphql.Int},
+			"defense":      &graphql.Field{Type: graphql.Int},
+			"speed":        &graphql.Field{Type: graphql.Int},
+			"monsterTypes": &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(monsterTypeType)), Resolve: r.monsterMonsterTypes},
+			"createdAt":    &graphql.Field{Type: graphql.DateTime},
+			"updatedAt":    &graphql.Field{Type: graphql.DateTime},
+		},
+	})
+
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: resolveID},
+			"username":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"role":      &graphql.Field{Type: graphql.String},
+			"monsters":  &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(monsterType)), Resolve: r.userMonsters},
+			"createdAt": &graphql.Field{Type: graphql.DateTime},
+			"updatedAt": &graphql.Field{Type: graphql.DateTime},
+		},
+	})
+
+	listType := func(name, field string, item *graphql.Object) *graphql.Object {
+		return graphql.NewObject(graphql.ObjectConfig{
+			Name: name,
+			Fields: graphql.Fields{
+				"totalCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"totalPages": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"page":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"size":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+				"hasMore":    &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+				field:        &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(item)))},
+			},
+		})
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"me":           &graphql.Field{Type: graphql.NewNonNull(userType), Resolve: r.me},
+			"user":         &graphql.Field{Type: userType, Args: idArgs, Resolve: r.user},
+			"users":        &graphql.Field{Type: graphql.NewNonNull(listType("UserList", "users", userType)), Args: paginationArgs, Resolve: r.users},
+			"monster":      &graphql.Field{Type: monsterType, Args: idArgs, Resolve: r.monster},
+			"monsters":     &graphql.Field{Type: graphql.NewNonNull(listType("MonsterList", "monsters", monsterType)), Args: paginationArgs, Resolve: r.monsters},
+			"monsterType":  &graphql.Field{Type: monsterTypeType, Args: idArgs, Resolve: r.monsterType},
+			"monsterTypes": &graphql.Field{Type: graphql.NewNonNull(listType("MonsterTypeList", "monsterTypes", monsterTypeType)), Args: paginationArgs, Resolve: r.monsterTypes},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createMonsterType": &graphql.Field{
+				Type:    graphql.NewNonNull(monsterTypeType),
+				Args:    graphql.FieldConfigArgument{"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)}},
+				Resolve: r.createMonsterType,
+			},
+			"updateMonsterType": &graphql.Field{
+				Type: graphql.NewNonNull(monsterTypeType),
+				Args: graphql.FieldConfigArgument{
+					"id":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: r.updateMonsterType,
+			},
+			"deleteMonsterType": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Args: idArgs, Resolve: r.deleteMonsterType},
+			"createMonster": &graphql.Field{
+				Type:    graphql.NewNonNull(monsterType),
+				Args:    graphql.FieldConfigArgument{"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(monsterInput)}},
+				Resolve: r.createMonster,
+			},
+			"updateMonster": &graphql.Field{
+				Type: graphql.NewNonNull(monsterType),
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(monsterInput)},
+				},
+				Resolve: r.updateMonster,
+			},
+			"deleteMonster": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Args: idArgs, Resolve: r.deleteMonster},
+			"attachMonsterType": &graphql.Field{
+				Type: graphql.NewNonNull(monsterType),
+				Args: graphql.FieldConfigArgument{
+					"monsterId":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"monsterTypeId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: r.attachMonsterType,
+			},
+			"deleteUser": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Args: idArgs, Resolve: r.deleteUser},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
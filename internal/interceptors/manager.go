@@ -0,0 +1,117 @@
+package interceptors
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+const authorizationHeader = "authorization"
+
+type InterceptorManager struct {
+	authUsecase auth.Usecase
+	cfg         *config.Config
+	logger      logger.Logger
+	policies    map[string][]string
+}
+
+// Interceptor manager constructor
+func NewInterceptorManager(authUsecase auth.Usecase, cfg *config.Config, logger logger.Logger) *InterceptorManager {
+	return &InterceptorManager{authUsecase: authUsecase, cfg: cfg, logger: logger, policies: make(map[string][]string)}
+}
+
+// Require an authenticated caller for the full method name, optionally holding every permission
+func (im *InterceptorManager) Protect(fullMethod string, permissions ...string) {
+	im.policies[fullMethod] = permissions
+}
+
+// Log every unary call with its duration and resulting code
+func (im *InterceptorManager) Logger(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	im.logger.Infof("gRPC method: %s, code: %s, time: %s", info.FullMethod, status.Code(err), time.Since(start))
+	return resp, err
+}
+
+// Carry the peer IP into the context, usecases throttle and record by it
+func (im *InterceptorManager) ClientIP(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		ip := p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+		ctx = context.WithValue(ctx, utils.ClientIPCtxKey{}, ip)
+	}
+
+	return handler(ctx, req)
+}
+
+// Authenticate the Bearer token from metadata and enforce the registered method policy
+func (im *InterceptorManager) Auth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	user, err := im.authenticate(ctx)
+	if err != nil {
+		im.logger.Errorf("gRPC auth interceptor method: %s, error: %s", info.FullMethod, err)
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	permissions, protected := im.policies[info.FullMethod]
+	if protected {
+		if user == nil {
+			return nil, status.Error(codes.Unauthenticated, "unauthorized")
+		}
+		for _, permission := range permissions {
+			allowed, err := im.authUsecase.HasPermission(ctx, user, permission)
+			if err != nil {
+				im.logger.Errorf("gRPC auth interceptor method: %s, error: %s", info.FullMethod, err)
+				return nil, status.Error(codes.Internal, "internal error")
+			}
+			if !allowed {
+				return nil, status.Error(codes.PermissionDenied, "permission denied")
+			}
+		}
+	}
+
+	if user != nil {
+		ctx = context.WithValue(ctx, utils.UserCtxKey{}, user)
+	}
+
+	return handler(ctx, req)
+}
+
+// Returns nil user when no token was sent
+func (im *InterceptorManager) authenticate(ctx context.Context) (*domain.User, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	values := md.Get(authorizationHeader)
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	headerParts := strings.Split(values[0], " ")
+	if len(headerParts) != 2 || !strings.EqualFold(headerParts[0], "bearer") {
+		return nil, status.Error(codes.Unauthenticated, "malformed authorization metadata")
+	}
+
+	user, _, _, err := im.authUsecase.TokenAuthentication(ctx, headerParts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
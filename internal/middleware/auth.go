@@ -2,13 +2,10 @@ package middleware
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"strings"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 
 	"github.com/iamaul/go-pokedex/config"
@@ -18,13 +15,21 @@ import (
 	"github.com/iamaul/go-pokedex/pkg/utils"
 )
 
-// Authentication based JWT
+// Authentication based JWT, service clients may send an API key instead.
+// Browsers authenticated by cookie need a CSRF token for unsafe requests.
 func (mw *MiddlewareManager) AuthJWTMiddleware(authUsecase auth.Usecase, cfg *config.Config) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			bearerHeader := c.Request().Header.Get("Authorization")
+			if apiKey := c.Request().Header.Get(utils.HeaderAPIKey); apiKey != "" {
+				if err := mw.validateAPIKey(apiKey, authUsecase, c); err != nil {
+					mw.logger.Error("middleware validateAPIKey", zap.String("headerAPIKey", err.Error()))
+					return c.JSON(http.StatusUnauthorized, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+				}
 
-			mw.logger.Infof("auth middleware bearerHeader %s", bearerHeader)
+				return next(c)
+			}
+
+			bearerHeader := c.Request().Header.Get("Authorization")
 
 			if bearerHeader != "" {
 				headerParts := strings.Split(bearerHeader, " ")
@@ -45,6 +50,16 @@ func (mw *MiddlewareManager) AuthJWTMiddleware(authUsecase auth.Usecase, cfg *co
 
 			cookie, err := c.Cookie("jwt-token")
 			if err != nil {
+				// Browsers signed in with the session cookie send no token
+				if session, ok := c.Get("session").(*domain.Session); ok {
+					if err := mw.validateSession(session, authUsecase, c); err != nil {
+						mw.logger.Error("middleware validateSession", zap.String("sessionCookie", err.Error()))
+						return c.JSON(http.StatusUnauthorized, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+					}
+
+					return mw.CSRF(next)(c)
+				}
+
 				mw.logger.Errorf("c.Cookie", err.Error())
 				return c.JSON(http.StatusUnauthorized, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
 			}
@@ -53,24 +68,13 @@ func (mw *MiddlewareManager) AuthJWTMiddleware(authUsecase auth.Usecase, cfg *co
 				mw.logger.Errorf("validateJWTToken", err.Error())
 				return c.JSON(http.StatusUnauthorized, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
 			}
-			return next(c)
+			return mw.CSRF(next)(c)
 		}
 	}
 }
 
-// Admin permission role
-func (mw *MiddlewareManager) AdminMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		user, ok := c.Get("user").(*domain.User)
-		if !ok || *user.Role != "admin" {
-			return c.JSON(http.StatusForbidden, httpErr.NewUnauthorizedError(httpErr.PermissionDenied))
-		}
-		return next(c)
-	}
-}
-
-// Role based auth middleware using ctx user
-func (mw *MiddlewareManager) AuthenticatedOrAdminMiddleware() echo.MiddlewareFunc {
+// Permission based auth middleware using ctx user, the permissions are granted by the user's role
+func (mw *MiddlewareManager) RequirePermission(permission string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			user, ok := c.Get("user").(*domain.User)
@@ -79,48 +83,63 @@ func (mw *MiddlewareManager) AuthenticatedOrAdminMiddleware() echo.MiddlewareFun
 				return c.JSON(http.StatusUnauthorized, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
 			}
 
-			if *user.Role == "admin" {
-				return next(c)
-			}
-
-			if user.ID.String() != c.Param("id") {
-				mw.logger.Errorf("Error c.Get(user) RequestID: %s, UserID: %s, Error: %s,",
+			// API keys only get the permissions of their owner their scopes allow
+			if key, ok := c.Get("api_key").(*domain.APIKey); ok && !key.Grants(permission) {
+				mw.logger.Errorf("Error c.Get(api_key) RequestID: %s, APIKeyID: %s, ERROR: %s,",
 					utils.GetRequestID(c),
-					user.ID.String(),
-					"invalid user ctx",
+					key.ID.Hex(),
+					"scopes do not grant "+permission,
 				)
-				return c.JSON(http.StatusForbidden, httpErr.NewForbiddenError(httpErr.Forbidden))
+				return c.JSON(http.StatusForbidden, httpErr.NewForbiddenError(httpErr.PermissionDenied))
+			}
+
+			allowed, err := mw.authUsecase.HasPermission(c.Request().Context(), user, permission)
+			if err != nil {
+				utils.LogResponseError(c, mw.logger, err)
+				return c.JSON(http.StatusInternalServerError, httpErr.NewInternalServerError(err))
+			}
+			if allowed {
+				return next(c)
 			}
 
-			return next(c)
+			mw.logger.Errorf("Error c.Get(user) RequestID: %s, UserID: %s, ERROR: %s,",
+				utils.GetRequestID(c),
+				user.ID.String(),
+				"missing permission "+permission,
+			)
+
+			return c.JSON(http.StatusForbidden, httpErr.NewForbiddenError(httpErr.PermissionDenied))
 		}
 	}
 }
 
-// Role based auth middleware using ctx user
-func (mw *MiddlewareManager) RoleBasedAuthMiddleware(roles []string) echo.MiddlewareFunc {
+// Let the user act on their own resource, others need the permission
+func (mw *MiddlewareManager) SelfOrPermission(permission string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			user, ok := c.Get("user").(*domain.User)
-			if !ok {
-				mw.logger.Errorf("Error c.Get(user) RequestID: %s, UserID: %s, ERROR: %s,",
-					utils.GetRequestID(c),
-					user.ID.String(),
-					"invalid user ctx",
-				)
-				return c.JSON(http.StatusUnauthorized, httpErr.NewUnauthorizedError(httpErr.Unauthorized))
+			if ok && user.ID.Hex() == c.Param("id") {
+				return next(c)
 			}
 
-			for _, role := range roles {
-				if role == *user.Role {
-					return next(c)
-				}
+			return mw.RequirePermission(permission)(next)(c)
+		}
+	}
+}
+
+// Requests authenticated with an API key need the scope, JWT sessions are not limited by scopes
+func (mw *MiddlewareManager) RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key, ok := c.Get("api_key").(*domain.APIKey)
+			if !ok || key.HasScope(scope) {
+				return next(c)
 			}
 
-			mw.logger.Errorf("Error c.Get(user) RequestID: %s, UserID: %s, ERROR: %s,",
+			mw.logger.Errorf("Error c.Get(api_key) RequestID: %s, APIKeyID: %s, ERROR: %s,",
 				utils.GetRequestID(c),
-				user.ID.String(),
-				"invalid user ctx",
+				key.ID.Hex(),
+				"missing scope "+scope,
 			)
 
 			return c.JSON(http.StatusForbidden, httpErr.NewForbiddenError(httpErr.PermissionDenied))
@@ -128,46 +147,86 @@ func (mw *MiddlewareManager) RoleBasedAuthMiddleware(roles []string) echo.Middle
 	}
 }
 
-func (mw *MiddlewareManager) validateJWTToken(tokenString string, authUsecase auth.Usecase, c echo.Context, cfg *config.Config) error {
-	if tokenString == "" {
-		return httpErr.InvalidJWTToken
-	}
+// API keys with the scope may only act on their owner's resource, admin keys on any the owner can
+func (mw *MiddlewareManager) RequireSelfScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key, ok := c.Get("api_key").(*domain.APIKey)
+			if !ok || key.HasScope(domain.ScopeAdmin) {
+				return next(c)
+			}
+			if key.HasScope(scope) && key.UserID.Hex() == c.Param("id") {
+				return next(c)
+			}
 
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signin method %v", token.Header["alg"])
+			mw.logger.Errorf("Error c.Get(api_key) RequestID: %s, APIKeyID: %s, ERROR: %s,",
+				utils.GetRequestID(c),
+				key.ID.Hex(),
+				"missing scope "+scope+" for user "+c.Param("id"),
+			)
+
+			return c.JSON(http.StatusForbidden, httpErr.NewForbiddenError(httpErr.PermissionDenied))
 		}
-		secret := []byte(cfg.Server.JwtSecretKey)
-		return secret, nil
-	})
+	}
+}
+
+func (mw *MiddlewareManager) validateAPIKey(apiKey string, authUsecase auth.Usecase, c echo.Context) error {
+	u, key, err := authUsecase.APIKeyAuthentication(c.Request().Context(), apiKey)
 	if err != nil {
 		return err
 	}
 
-	if !token.Valid {
-		return httpErr.InvalidJWTToken
+	c.Set("user", u)
+	c.Set("api_key", key)
+
+	ctx := context.WithValue(c.Request().Context(), utils.UserCtxKey{}, u)
+	ctx = context.WithValue(ctx, utils.APIKeyCtxKey{}, key)
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	return nil
+}
+
+func (mw *MiddlewareManager) validateJWTToken(tokenString string, authUsecase auth.Usecase, c echo.Context, cfg *config.Config) error {
+	u, claims, session, err := authUsecase.TokenAuthentication(c.Request().Context(), tokenString)
+	if err != nil {
+		return err
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID, ok := claims["id"].(string)
-		if !ok {
-			return httpErr.InvalidJWTClaims
-		}
+	if session != nil {
+		c.Set("session", session)
+		c.Set("sid", session.ID.Hex())
+	}
 
-		userUUID, err := primitive.ObjectIDFromHex(userID)
-		if err != nil {
-			return err
-		}
+	c.Set("user", u)
+	c.Set("claims", claims)
 
-		u, err := authUsecase.GetByID(c.Request().Context(), userUUID)
-		if err != nil {
-			return err
-		}
+	ctx := context.WithValue(c.Request().Context(), utils.UserCtxKey{}, u)
+	c.SetRequest(c.Request().WithContext(ctx))
 
-		c.Set("user", u)
+	return nil
+}
 
-		ctx := context.WithValue(c.Request().Context(), utils.UserCtxKey{}, u)
-		c.SetRequest(c.Request().WithContext(ctx))
+func (mw *MiddlewareManager) validateSession(session *domain.Session, authUsecase auth.Usecase, c echo.Context) error {
+	u, err := authUsecase.GetByID(c.Request().Context(), session.UserID)
+	if err != nil {
+		return err
 	}
+
+	c.Set("user", u)
+
+	ctx := context.WithValue(c.Request().Context(), utils.UserCtxKey{}, u)
+	c.SetRequest(c.Request().WithContext(ctx))
+
 	return nil
 }
+
+// Browsers cannot set headers on EventSource and WebSocket connections,
+// accept the bearer token from the access_token query parameter instead
+func (mw *MiddlewareManager) TokenFromQueryMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if token := c.QueryParam("access_token"); token != "" && c.Request().Header.Get(echo.HeaderAuthorization) == "" {
+			c.Request().Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		}
+		return next(c)
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+// Carry the client IP and request ID into the request context, usecases throttle and audit by them
+func (mw *MiddlewareManager) ClientIPMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := context.WithValue(c.Request().Context(), utils.ClientIPCtxKey{}, utils.GetIPAddress(c))
+		ctx = context.WithValue(ctx, utils.ReqIDCtxKey{}, utils.GetRequestID(c))
+		c.SetRequest(c.Request().WithContext(ctx))
+		return next(c)
+	}
+}
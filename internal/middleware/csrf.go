@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
@@ -10,31 +11,56 @@ import (
 	"github.com/iamaul/go-pokedex/pkg/utils"
 )
 
-// CSRF Middleware
+// CSRF Middleware, AuthJWTMiddleware runs it for requests authenticated by cookie.
+// Safe methods do not change state and pass without a token.
 func (mw *MiddlewareManager) CSRF(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(ctx echo.Context) error {
 		if !mw.cfg.Server.CSRF {
 			return next(ctx)
 		}
 
+		switch ctx.Request().Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			return next(ctx)
+		}
+
 		token := ctx.Request().Header.Get(csrf.CSRFHeader)
 		if token == "" {
-			mw.logger.Errorf("CSRF Middleware get CSRF header, Token: %s, Error: %s, RequestId: %s",
-				token,
-				"empty CSRF token",
+			mw.logger.Errorf("CSRF Middleware get CSRF header, Error: %s, RequestId: %s",
+				httpErr.CSRFNotPresented,
 				utils.GetRequestID(ctx),
 			)
-			return ctx.JSON(http.StatusForbidden, httpErr.NewRestError(http.StatusForbidden, "Invalid CSRF Token", "no CSRF Token"))
+			return ctx.JSON(http.StatusForbidden, httpErr.NewRestError(http.StatusForbidden, "Invalid CSRF Token", httpErr.CSRFNotPresented.Error()))
+		}
+
+		// Double submitted tokens are bound to no session, the cookie holds the same token instead
+		sid := ""
+		if utils.CSRFDoubleSubmit(mw.cfg) {
+			cookie, err := ctx.Cookie(utils.CSRFCookieName(mw.cfg))
+			if err != nil || !csrf.Equal(token, cookie.Value) {
+				mw.logger.Errorf("CSRF Middleware csrf.Equal, Error: %s, RequestId: %s",
+					httpErr.WrongCSRFToken,
+					utils.GetRequestID(ctx),
+				)
+				return ctx.JSON(http.StatusForbidden, httpErr.NewRestError(http.StatusForbidden, "Invalid CSRF Token", httpErr.WrongCSRFToken.Error()))
+			}
+		} else {
+			var ok bool
+			if sid, ok = ctx.Get("sid").(string); !ok {
+				mw.logger.Errorf("CSRF Middleware get sid, Error: %s, RequestId: %s",
+					"no session",
+					utils.GetRequestID(ctx),
+				)
+				return ctx.JSON(http.StatusForbidden, httpErr.NewRestError(http.StatusForbidden, "Invalid CSRF Token", httpErr.WrongCSRFToken.Error()))
+			}
 		}
 
-		sid, ok := ctx.Get("sid").(string)
-		if !csrf.ValidateToken(token, sid, mw.logger) || !ok {
-			mw.logger.Errorf("CSRF Middleware csrf.ValidateToken Token: %s, Error: %s, RequestId: %s",
-				token,
-				"empty token",
+		if err := csrf.ValidateToken(utils.CSRFSecret(mw.cfg), token, sid, time.Now()); err != nil {
+			mw.logger.Errorf("CSRF Middleware csrf.ValidateToken, Error: %s, RequestId: %s",
+				err,
 				utils.GetRequestID(ctx),
 			)
-			return ctx.JSON(http.StatusForbidden, httpErr.NewRestError(http.StatusForbidden, "Invalid CSRF Token", "no CSRF Token"))
+			return ctx.JSON(http.StatusForbidden, httpErr.NewRestError(http.StatusForbidden, "Invalid CSRF Token", err.Error()))
 		}
 
 		return next(ctx)
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"net/url"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -8,6 +9,9 @@ import (
 	"github.com/iamaul/go-pokedex/pkg/utils"
 )
 
+// Query parameters carrying credentials, their values never reach the logs
+var redactedParams = []string{"access_token", "code", "token"}
+
 func (mw *MiddlewareManager) RequestLoggerMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(ctx echo.Context) error {
 		start := time.Now()
@@ -21,8 +25,27 @@ func (mw *MiddlewareManager) RequestLoggerMiddleware(next echo.HandlerFunc) echo
 		requestID := utils.GetRequestID(ctx)
 
 		mw.logger.Infof("RequestID: %s, method: %s, URI: %s, status: %v, size: %v, time: %s",
-			requestID, req.Method, req.URL, status, size, s,
+			requestID, req.Method, redactURI(req.URL), status, size, s,
 		)
 		return err
 	}
 }
+
+func redactURI(u *url.URL) string {
+	query := u.Query()
+
+	redacted := false
+	for _, param := range redactedParams {
+		if query.Has(param) {
+			query.Set(param, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.RequestURI()
+	}
+
+	clean := *u
+	clean.RawQuery = query.Encode()
+	return clean.RequestURI()
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+)
+
+// Logger keeping the lines logged at info level
+type recordingLogger struct {
+	logger.Logger
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) Infof(template string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.lines = append(l.lines, fmt.Sprintf(template, args...))
+}
+
+func TestRequestLoggerRedactsTokens(t *testing.T) {
+	const secret = "eyJhbGciOi.eyJzdWIi.c2lnbmF0dXJl"
+
+	tests := []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{name: "plain query", target: "/api/v1/monster?page=2", want: "URI: /api/v1/monster?page=2,"},
+		{name: "event stream", target: "/api/v1/events?access_token=" + secret + "&topics=monster", want: "URI: /api/v1/events?access_token=REDACTED&topics=monster,"},
+		{name: "event stream of v2", target: "/api/v2/events?access_token=" + secret, want: "URI: /api/v2/events?access_token=REDACTED,"},
+		{name: "websocket", target: "/api/v1/events/ws?access_token=" + secret + "&access_token=" + secret, want: "URI: /api/v1/events/ws?access_token=REDACTED,"},
+		{name: "OIDC callback", target: "/api/v1/auth/oidc/google/callback?code=" + secret + "&state=abc", want: "URI: /api/v1/auth/oidc/google/callback?code=REDACTED&state=abc,"},
+		{name: "password reset link", target: "/reset-password?token=" + secret, want: "URI: /reset-password?token=REDACTED,"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &recordingLogger{}
+			mw := NewMiddlewareManager(nil, &config.Config{}, nil, log)
+
+			e := echo.New()
+			e.Use(mw.RequestLoggerMiddleware)
+			ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+			for _, version := range []string{"/api/v1", "/api/v2"} {
+				e.GET(version+"/monster", ok)
+				e.GET(version+"/events", ok)
+				e.GET(version+"/events/ws", ok)
+				e.GET(version+"/auth/oidc/:provider/callback", ok)
+			}
+			e.GET("/reset-password", ok)
+
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if len(log.lines) != 1 {
+				t.Fatalf("logged %d lines", len(log.lines))
+			}
+			if line := log.lines[0]; !strings.Contains(line, tt.want) || strings.Contains(line, secret) {
+				t.Errorf("logged %q, want %q", line, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iamaul/go-pokedex/pkg/openapi"
+	"github.com/iamaul/go-pokedex/pkg/render"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+// Validate requests against the OpenAPI description, responding 400 with the violations.
+// In development mode JSON responses are validated too and replaced by a 500 when they drift from the spec.
+func (mw *MiddlewareManager) OpenAPIValidatorMiddleware(validator *openapi.Validator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			input, err := validator.ValidateRequest(c.Request())
+			if err != nil {
+				utils.LogResponseError(c, mw.logger, err)
+				return render.Error(c, err)
+			}
+
+			if input == nil || mw.cfg.Server.Mode != "development" {
+				return next(c)
+			}
+
+			res := c.Response()
+			writer := &bufferedWriter{ResponseWriter: res.Writer, header: make(http.Header)}
+			res.Writer = writer
+			defer func() { res.Writer = writer.ResponseWriter }()
+
+			if err := next(c); err != nil {
+				return err
+			}
+			if writer.streaming {
+				return nil
+			}
+
+			if strings.HasPrefix(writer.header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+				if err := validator.ValidateResponse(c.Request().Context(), input, writer.status, writer.header, writer.body.Bytes()); err != nil {
+					utils.LogResponseError(c, mw.logger, err)
+					res.Writer = writer.ResponseWriter
+					res.Committed = false
+					res.Size = 0
+					return render.Error(c, err)
+				}
+			}
+
+			return writer.flush()
+		}
+	}
+}
+
+// bufferedWriter holds the response until it has been validated, streaming
+// responses (SSE, hijacked WebSocket connections) are passed straight through
+type bufferedWriter struct {
+	http.ResponseWriter
+	header    http.Header
+	status    int
+	body      bytes.Buffer
+	streaming bool
+}
+
+func (w *bufferedWriter) Header() http.Header {
+	if w.streaming {
+		return w.ResponseWriter.Header()
+	}
+	return w.header
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	if w.streaming {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.status = status
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	if w.streaming {
+		return w.ResponseWriter.Write(b)
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) Flush() {
+	if !w.streaming {
+		w.streaming = true
+		if err := w.flush(); err != nil {
+			return
+		}
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *bufferedWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.streaming = true
+	w.copyHeader()
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *bufferedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Write the buffered response to the underlying writer
+func (w *bufferedWriter) flush() error {
+	w.copyHeader()
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	_, err := w.ResponseWriter.Write(w.body.Bytes())
+	w.body.Reset()
+	return err
+}
+
+func (w *bufferedWriter) copyHeader() {
+	header := w.ResponseWriter.Header()
+	for key, values := range w.header {
+		header[key] = values
+	}
+}
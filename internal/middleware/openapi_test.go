@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iamaul/go-pokedex/cmd/app/docs"
+	"github.com/iamaul/go-pokedex/config"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/openapi"
+)
+
+// Echo validating /api/v1 against the generated spec, handlers respond with status and body
+func newValidatedEcho(t *testing.T, mode string, status int, body string) (*echo.Echo, *bool) {
+	t.Helper()
+
+	spec, err := openapi.Load([]byte(docs.SwaggerInfo.ReadDoc()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator, err := openapi.NewValidator(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Server: config.ServerConfig{Mode: mode},
+		Logger: config.Logger{Level: "fatal", Encoding: "console"},
+	}
+	log := logger.NewLogger(cfg)
+	log.InitLogger()
+	mw := NewMiddlewareManager(nil, cfg, nil, log)
+
+	called := false
+	handler := func(c echo.Context) error {
+		called = true
+		return c.JSONBlob(status, []byte(body))
+	}
+
+	e := echo.New()
+	v1 := e.Group("/api/v1", mw.OpenAPIValidatorMiddleware(validator))
+	v1.POST("/auth", handler)
+	v1.GET("/audit/list", handler)
+	v1.GET("/undocumented", handler)
+
+	return e, &called
+}
+
+func TestOpenAPIValidatorMiddleware(t *testing.T) {
+	const unauthorized = `{"status":401,"error":"unauthorized"}`
+
+	t.Run("rejects a body that does not match the spec", func(t *testing.T) {
+		e, called := newValidatedEcho(t, "production", http.StatusUnauthorized, unauthorized)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth", strings.NewReader(`{"username":"ash","password":"short"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if *called {
+			t.Error("handler was called with an invalid body")
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+		var body httpErr.ViolationError
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if len(body.Violations) != 1 || body.Violations[0].In != "body" || body.Violations[0].Field != "password" {
+			t.Errorf("violations = %+v, want the password of the body", body.Violations)
+		}
+	})
+
+	t.Run("reports every invalid parameter", func(t *testing.T) {
+		e, called := newValidatedEcho(t, "production", http.StatusOK, `{}`)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/audit/list?target_type=pokeball&page=first", nil))
+
+		if *called {
+			t.Error("handler was called with invalid parameters")
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+		var body httpErr.ViolationError
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		fields := make(map[string]bool)
+		for _, v := range body.Violations {
+			if v.In == "query" {
+				fields[v.Field] = true
+			}
+		}
+		if len(body.Violations) != 2 || !fields["target_type"] || !fields["page"] {
+			t.Errorf("violations = %+v, want target_type and page of the query", body.Violations)
+		}
+	})
+
+	t.Run("passes a valid request through", func(t *testing.T) {
+		e, called := newValidatedEcho(t, "production", http.StatusUnauthorized, unauthorized)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth", strings.NewReader(`{"username":"ash","password":"pikachu"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if !*called {
+			t.Error("handler was not called")
+		}
+		if rec.Code != http.StatusUnauthorized || rec.Body.String() != unauthorized {
+			t.Errorf("got %d %q, want the handler's response", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("leaves bodies of other formats to the handler", func(t *testing.T) {
+		e, called := newValidatedEcho(t, "production", http.StatusUnauthorized, unauthorized)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth", strings.NewReader("\x81\xa8username\xa3ash"))
+		req.Header.Set(echo.HeaderContentType, "application/msgpack")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if !*called {
+			t.Error("handler was not called")
+		}
+	})
+
+	t.Run("passes a route the spec does not describe through", func(t *testing.T) {
+		e, called := newValidatedEcho(t, "production", http.StatusTeapot, `{"bogus":true}`)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/undocumented", nil))
+
+		if !*called {
+			t.Error("handler was not called")
+		}
+		if rec.Code != http.StatusTeapot {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusTeapot)
+		}
+	})
+
+	t.Run("checks responses in development", func(t *testing.T) {
+		tests := []struct {
+			name string
+			mode string
+			body string
+			want int
+		}{
+			{name: "documented response", mode: "development", body: unauthorized, want: http.StatusUnauthorized},
+			{name: "drifted response", mode: "development", body: `{"status":"401"}`, want: http.StatusInternalServerError},
+			{name: "drifted response outside development", mode: "production", body: `{"status":"401"}`, want: http.StatusUnauthorized},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				e, _ := newValidatedEcho(t, tt.mode, http.StatusUnauthorized, tt.body)
+
+				req := httptest.NewRequest(http.MethodPost, "/api/v1/auth", strings.NewReader(`{"username":"ash","password":"pikachu"}`))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, req)
+
+				if rec.Code != tt.want {
+					t.Errorf("status = %d (%s), want %d", rec.Code, rec.Body.String(), tt.want)
+				}
+			})
+		}
+	})
+}
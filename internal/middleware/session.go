@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+// Resolve the session cookie of the request, AuthJWTMiddleware signs the session's user in
+// when no token is sent. An ended or unknown session only clears the cookie.
+func (mw *MiddlewareManager) SessionMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		cookie, err := c.Cookie(mw.cfg.Session.Name)
+		if err != nil || cookie.Value == "" {
+			return next(c)
+		}
+
+		session, err := mw.authUsecase.SessionAuthentication(c.Request().Context(), cookie.Value)
+		if err != nil {
+			mw.logger.Error("middleware SessionAuthentication", zap.String("sessionCookie", err.Error()))
+			utils.DeleteSessionCookie(c, mw.cfg.Session.Name)
+			return next(c)
+		}
+
+		c.Set("session", session)
+		c.Set("sid", session.ID.Hex())
+
+		// Keep the cookie alive as long as the session is
+		utils.SetSessionCookie(c, mw.cfg, cookie.Value)
+
+		return next(c)
+	}
+}
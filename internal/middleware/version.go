@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	apiPrefix         = "/api/"
+	vendorMediaPrefix = "application/vnd.pokedex.v"
+)
+
+var versionedPath = regexp.MustCompile(`^/api/v\d+(/|$)`)
+
+// Route unversioned /api requests to the version asked for in the Accept header, either as a
+// parameter (application/json; version=2) or a vendor type (application/vnd.pokedex.v2+json).
+// Must run before routing, register it with echo.Pre.
+func (mw *MiddlewareManager) APIVersionMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		if !strings.HasPrefix(req.URL.Path, apiPrefix) || versionedPath.MatchString(req.URL.Path) {
+			return next(c)
+		}
+
+		version, ok := acceptVersion(req.Header.Get(echo.HeaderAccept))
+		if !ok {
+			version = mw.cfg.API.DefaultVersion
+		}
+
+		prefix := fmt.Sprintf("/api/v%d/", version)
+		req.URL.Path = prefix + strings.TrimPrefix(req.URL.Path, apiPrefix)
+		if req.URL.RawPath != "" {
+			req.URL.RawPath = prefix + strings.TrimPrefix(req.URL.RawPath, apiPrefix)
+		}
+		c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+
+		return next(c)
+	}
+}
+
+// Announce the deprecation (RFC 9745) and removal (RFC 8594) of an API version, zero times are omitted
+func (mw *MiddlewareManager) DeprecationMiddleware(deprecation, sunset time.Time, successor string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+			if !deprecation.IsZero() {
+				header.Set("Deprecation", fmt.Sprintf("@%d", deprecation.Unix()))
+			}
+			if !sunset.IsZero() {
+				header.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+			header.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+
+			return next(c)
+		}
+	}
+}
+
+func acceptVersion(accept string) (int, bool) {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		value, ok := params["version"]
+		if !ok && strings.HasPrefix(mediaType, vendorMediaPrefix) {
+			value = strings.TrimPrefix(mediaType, vendorMediaPrefix)
+			value, _, _ = strings.Cut(value, "+")
+			ok = true
+		}
+		if !ok {
+			continue
+		}
+
+		if version, err := strconv.Atoi(value); err == nil && version > 0 {
+			return version, true
+		}
+	}
+
+	return 0, false
+}
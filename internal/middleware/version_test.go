@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iamaul/go-pokedex/config"
+)
+
+func TestAPIVersionMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		accept string
+		want   string
+	}{
+		{name: "default version", target: "/api/monster/list", want: "v1 /api/v1/monster/list"},
+		{name: "version parameter", target: "/api/monster/list", accept: "application/json; version=2", want: "v2 /api/v2/monster/list"},
+		{name: "vendor type", target: "/api/monster/list", accept: "application/vnd.pokedex.v2+json", want: "v2 /api/v2/monster/list"},
+		{name: "first range naming a version", target: "/api/monster/list", accept: "text/html, application/vnd.pokedex.v2+xml, application/json; version=1", want: "v2 /api/v2/monster/list"},
+		{name: "invalid version", target: "/api/monster/list", accept: "application/json; version=two", want: "v1 /api/v1/monster/list"},
+		{name: "versioned path is kept", target: "/api/v1/monster/list", accept: "application/json; version=2", want: "v1 /api/v1/monster/list"},
+		{name: "query is kept", target: "/api/monster/list?page=2", accept: "application/json; version=2", want: "v2 /api/v2/monster/list"},
+		{name: "outside the api", target: "/swagger/index.html", accept: "application/json; version=2", want: "swagger"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mw := NewMiddlewareManager(nil, &config.Config{API: config.API{DefaultVersion: 1}}, nil, nil)
+
+			e := echo.New()
+			e.Pre(mw.APIVersionMiddleware)
+			for _, version := range []string{"v1", "v2"} {
+				version := version
+				e.GET("/api/"+version+"/monster/list", func(c echo.Context) error {
+					return c.String(http.StatusOK, version+" "+c.Request().URL.Path)
+				})
+			}
+			e.GET("/swagger/*", func(c echo.Context) error { return c.String(http.StatusOK, "swagger") })
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set(echo.HeaderAccept, tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Body.String() != tt.want {
+				t.Errorf("served %q, want %q", rec.Body.String(), tt.want)
+			}
+		})
+	}
+
+	t.Run("varies unversioned paths by Accept", func(t *testing.T) {
+		mw := NewMiddlewareManager(nil, &config.Config{API: config.API{DefaultVersion: 2}}, nil, nil)
+
+		e := echo.New()
+		e.Pre(mw.APIVersionMiddleware)
+		e.GET("/api/v2/health", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/health", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want the configured default version", rec.Code)
+		}
+		if got := rec.Header().Get(echo.HeaderVary); got != echo.HeaderAccept {
+			t.Errorf("Vary = %q, want %q", got, echo.HeaderAccept)
+		}
+	})
+}
+
+func TestDeprecationMiddleware(t *testing.T) {
+	deprecation := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		deprecation     time.Time
+		sunset          time.Time
+		wantDeprecation string
+		wantSunset      string
+	}{
+		{name: "both announced", deprecation: deprecation, sunset: sunset, wantDeprecation: "@1790812800", wantSunset: "Thu, 01 Apr 2027 00:00:00 GMT"},
+		{name: "none announced", wantDeprecation: "", wantSunset: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mw := NewMiddlewareManager(nil, &config.Config{}, nil, nil)
+
+			e := echo.New()
+			e.GET("/api/v1/health", func(c echo.Context) error { return c.NoContent(http.StatusOK) }, mw.DeprecationMiddleware(tt.deprecation, tt.sunset, "/api/v2"))
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/health", nil))
+
+			if got := rec.Header().Get("Deprecation"); got != tt.wantDeprecation {
+				t.Errorf("Deprecation = %q, want %q", got, tt.wantDeprecation)
+			}
+			if got := rec.Header().Get("Sunset"); got != tt.wantSunset {
+				t.Errorf("Sunset = %q, want %q", got, tt.wantSunset)
+			}
+			if got := rec.Header().Get("Link"); got != `</api/v2>; rel="successor-version"` {
+				t.Errorf("Link = %q", got)
+			}
+		})
+	}
+}
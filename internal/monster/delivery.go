@@ -7,13 +7,16 @@ import (
 type DeliveryHandlers interface {
 	CreateMonsterType() echo.HandlerFunc
 	UpdateMonsterType() echo.HandlerFunc
+	PatchMonsterType() echo.HandlerFunc
 	DeleteMonsterType() echo.HandlerFunc
 	ListMonsterType() echo.HandlerFunc
 	DetailMonsterType() echo.HandlerFunc
 	CreateMonster() echo.HandlerFunc
 	UpdateMonster() echo.HandlerFunc
+	PatchMonster() echo.HandlerFunc
 	DeleteMonster() echo.HandlerFunc
 	ListMonster() echo.HandlerFunc
 	DetailMonster() echo.HandlerFunc
 	AddMonsterType() echo.HandlerFunc
+	UploadMonsterImage() echo.HandlerFunc
 }
@@ -0,0 +1,34 @@
+package grpc
+
+import (
+	"google.golang.org/grpc"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/interceptors"
+	"github.com/iamaul/go-pokedex/proto/pokedex"
+)
+
+func MonsterRoutes(server *grpc.Server, s pokedex.MonsterServiceServer, im *interceptors.InterceptorManager) {
+	pokedex.RegisterMonsterServiceServer(server, s)
+
+	for _, method := range []string{
+		pokedex.MonsterService_ListMonsterTypes_FullMethodName,
+		pokedex.MonsterService_GetMonsterType_FullMethodName,
+		pokedex.MonsterService_ListMonsters_FullMethodName,
+		pokedex.MonsterService_GetMonster_FullMethodName,
+	} {
+		im.Protect(method, domain.PermMonsterRead)
+	}
+
+	for _, method := range []string{
+		pokedex.MonsterService_CreateMonsterType_FullMethodName,
+		pokedex.MonsterService_UpdateMonsterType_FullMethodName,
+		pokedex.MonsterService_DeleteMonsterType_FullMethodName,
+		pokedex.MonsterService_CreateMonster_FullMethodName,
+		pokedex.MonsterService_UpdateMonster_FullMethodName,
+		pokedex.MonsterService_DeleteMonster_FullMethodName,
+		pokedex.MonsterService_AttachMonsterType_FullMethodName,
+	} {
+		im.Protect(method, domain.PermMonsterWrite)
+	}
+}
@@ -0,0 +1,285 @@
+package grpc
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/monster"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/grpcerr"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+	"github.com/iamaul/go-pokedex/proto/pokedex"
+)
+
+type MonsterServer struct {
+	pokedex.UnimplementedMonsterServiceServer
+	cfg                *config.Config
+	monsterTypeUsecase monster.MonsterTypeUsecase
+	monsterUsecase     monster.MonsterUsecase
+	logger             logger.Logger
+}
+
+func NewMonsterServer(cfg *config.Config, monsterTypeUsecase monster.MonsterTypeUsecase, monsterUsecase monster.MonsterUsecase, log logger.Logger) pokedex.MonsterServiceServer {
+	return &MonsterServer{cfg: cfg, monsterTypeUsecase: monsterTypeUsecase, monsterUsecase: monsterUsecase, logger: log}
+}
+
+func (s *MonsterServer) CreateMonsterType(ctx context.Context, req *pokedex.CreateMonsterTypeRequest) (*pokedex.MonsterType, error) {
+	monsterType := &domain.MonsterType{Name: req.GetName()}
+	if err := utils.ValidateStruct(ctx, monsterType); err != nil {
+		return nil, s.error("CreateMonsterType", err)
+	}
+
+	createdMonsterType, err := s.monsterTypeUsecase.MonsterTypeCreate(ctx, monsterType)
+	if err != nil {
+		return nil, s.error("CreateMonsterType", err)
+	}
+
+	return monsterTypeToProto(createdMonsterType), nil
+}
+
+func (s *MonsterServer) UpdateMonsterType(ctx context.Context, req *pokedex.UpdateMonsterTypeRequest) (*pokedex.MonsterType, error) {
+	monsterTypeID, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, s.error("UpdateMonsterType", httpErr.NewBadRequestError(err))
+	}
+
+	if _, err := s.monsterTypeUsecase.MonsterTypeUpdate(ctx, &domain.MonsterTypeUpdate{
+		ID:   monsterTypeID,
+		Name: req.GetName(),
+	}); err != nil {
+		return nil, s.error("UpdateMonsterType", err)
+	}
+
+	monsterType, err := s.monsterTypeUsecase.GetByID(ctx, monsterTypeID)
+	if err != nil {
+		return nil, s.error("UpdateMonsterType", err)
+	}
+
+	return monsterTypeToProto(monsterType), nil
+}
+
+func (s *MonsterServer) DeleteMonsterType(ctx context.Context, req *pokedex.IDRequest) (*emptypb.Empty, error) {
+	monsterTypeID, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, s.error("DeleteMonsterType", httpErr.NewBadRequestError(err))
+	}
+
+	if err := s.monsterTypeUsecase.MonsterTypeDeletion(ctx, monsterTypeID, nil); err != nil {
+		return nil, s.error("DeleteMonsterType", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *MonsterServer) ListMonsterTypes(ctx context.Context, req *pokedex.PaginationRequest) (*pokedex.MonsterTypeList, error) {
+	monsterTypeList, err := s.monsterTypeUsecase.GetMonsterTypeList(ctx, paginationFromProto(req))
+	if err != nil {
+		return nil, s.error("ListMonsterTypes", err)
+	}
+
+	monsterTypes := make([]*pokedex.MonsterType, 0, len(monsterTypeList.MonsterTypes))
+	for _, monsterType := range monsterTypeList.MonsterTypes {
+		monsterTypes = append(monsterTypes, monsterTypeToProto(monsterType))
+	}
+
+	return &pokedex.MonsterTypeList{
+		TotalCount:   int32(monsterTypeList.TotalCount),
+		TotalPages:   int32(monsterTypeList.TotalPages),
+		Page:         int32(monsterTypeList.Page),
+		Size:         int32(monsterTypeList.Size),
+		HasMore:      monsterTypeList.HasMore,
+		MonsterTypes: monsterTypes,
+	}, nil
+}
+
+func (s *MonsterServer) GetMonsterType(ctx context.Context, req *pokedex.IDRequest) (*pokedex.MonsterType, error) {
+	monsterTypeID, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, s.error("GetMonsterType", httpErr.NewBadRequestError(err))
+	}
+
+	monsterType, err := s.monsterTypeUsecase.GetByID(ctx, monsterTypeID)
+	if err != nil {
+		return nil, s.error("GetMonsterType", err)
+	}
+
+	return monsterTypeToProto(monsterType), nil
+}
+
+func (s *MonsterServer) CreateMonster(ctx context.Context, req *pokedex.CreateMonsterRequest) (*pokedex.Monster, error) {
+	m := &domain.Monster{
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		Size:        req.GetSize(),
+		Weight:      req.GetWeight(),
+		Hp:          req.GetHp(),
+		Attack:      req.GetAttack(),
+		Defense:     req.GetDefense(),
+		Speed:       req.GetSpeed(),
+	}
+	if err := utils.ValidateStruct(ctx, m); err != nil {
+		return nil, s.error("CreateMonster", err)
+	}
+
+	createdMonster, err := s.monsterUsecase.MonsterCreate(ctx, m)
+	if err != nil {
+		return nil, s.error("CreateMonster", err)
+	}
+
+	return monsterToProto(createdMonster), nil
+}
+
+func (s *MonsterServer) UpdateMonster(ctx context.Context, req *pokedex.UpdateMonsterRequest) (*pokedex.Monster, error) {
+	monsterID, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, s.error("UpdateMonster", httpErr.NewBadRequestError(err))
+	}
+
+	if _, err := s.monsterUsecase.MonsterUpdate(ctx, &domain.MonsterUpdate{
+		ID:          monsterID,
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		Size:        req.GetSize(),
+		Weight:      req.GetWeight(),
+		Hp:          req.GetHp(),
+		Attack:      req.GetAttack(),
+		Defense:     req.GetDefense(),
+		Speed:       req.GetSpeed(),
+	}); err != nil {
+		return nil, s.error("UpdateMonster", err)
+	}
+
+	m, err := s.monsterUsecase.GetByID(ctx, monsterID)
+	if err != nil {
+		return nil, s.error("UpdateMonster", err)
+	}
+
+	return monsterToProto(m), nil
+}
+
+func (s *MonsterServer) DeleteMonster(ctx context.Context, req *pokedex.IDRequest) (*emptypb.Empty, error) {
+	monsterID, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, s.error("DeleteMonster", httpErr.NewBadRequestError(err))
+	}
+
+	if err := s.monsterUsecase.MonsterDeletion(ctx, monsterID, nil); err != nil {
+		return nil, s.error("DeleteMonster", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *MonsterServer) ListMonsters(ctx context.Context, req *pokedex.PaginationRequest) (*pokedex.MonsterList, error) {
+	monsterList, err := s.monsterUsecase.GetMonsterList(ctx, paginationFromProto(req))
+	if err != nil {
+		return nil, s.error("ListMonsters", err)
+	}
+
+	monsters := make([]*pokedex.Monster, 0, len(monsterList.Monsters))
+	for _, m := range monsterList.Monsters {
+		monsters = append(monsters, monsterToProto(m))
+	}
+
+	return &pokedex.MonsterList{
+		TotalCount: int32(monsterList.TotalCount),
+		TotalPages: int32(monsterList.TotalPages),
+		Page:       int32(monsterList.Page),
+		Size:       int32(monsterList.Size),
+		HasMore:    monsterList.HasMore,
+		Monsters:   monsters,
+	}, nil
+}
+
+func (s *MonsterServer) GetMonster(ctx context.Context, req *pokedex.IDRequest) (*pokedex.Monster, error) {
+	monsterID, err := primitive.ObjectIDFromHex(req.GetId())
+	if err != nil {
+		return nil, s.error("GetMonster", httpErr.NewBadRequestError(err))
+	}
+
+	m, err := s.monsterUsecase.GetByID(ctx, monsterID)
+	if err != nil {
+		return nil, s.error("GetMonster", err)
+	}
+
+	return monsterToProto(m), nil
+}
+
+func (s *MonsterServer) AttachMonsterType(ctx context.Context, req *pokedex.AttachMonsterTypeRequest) (*emptypb.Empty, error) {
+	monsterID, err := primitive.ObjectIDFromHex(req.GetMonsterId())
+	if err != nil {
+		return nil, s.error("AttachMonsterType", httpErr.NewBadRequestError(err))
+	}
+
+	monsterTypeID, err := primitive.ObjectIDFromHex(req.GetMonsterTypeId())
+	if err != nil {
+		return nil, s.error("AttachMonsterType", httpErr.NewBadRequestError(err))
+	}
+
+	if err := s.monsterUsecase.AttachMonsterType(ctx, monsterID, monsterTypeID); err != nil {
+		return nil, s.error("AttachMonsterType", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *MonsterServer) error(method string, err error) error {
+	s.logger.Errorf("MonsterServer.%s: %s", method, err)
+	return grpcerr.FromError(err)
+}
+
+func paginationFromProto(req *pokedex.PaginationRequest) *utils.PaginationQuery {
+	pq := &utils.PaginationQuery{
+		Size:    int(req.GetSize()),
+		Page:    int(req.GetPage()),
+		OrderBy: req.GetOrderBy(),
+	}
+	if pq.Size <= 0 {
+		_ = pq.SetSize("")
+	}
+	return pq
+}
+
+func monsterTypeToProto(monsterType *domain.MonsterType) *pokedex.MonsterType {
+	return &pokedex.MonsterType{
+		Id:        monsterType.ID.Hex(),
+		Name:      monsterType.Name,
+		CreatedAt: timestamppb.New(monsterType.CreatedAt),
+		UpdatedAt: timestamppb.New(monsterType.UpdatedAt),
+	}
+}
+
+func monsterToProto(m *domain.Monster) *pokedex.Monster {
+	monsterTypes := make([]string, 0, len(m.MonsterTypes))
+	for _, id := range m.MonsterTypes {
+		monsterTypes = append(monsterTypes, id.Hex())
+	}
+
+	thumbnails := make([]*pokedex.MonsterThumbnail, 0, len(m.Thumbnails))
+	for _, t := range m.Thumbnails {
+		thumbnails = append(thumbnails, &pokedex.MonsterThumbnail{Width: int32(t.Width), Url: t.Url})
+	}
+
+	return &pokedex.Monster{
+		Id:           m.ID.Hex(),
+		MonsterTypes: monsterTypes,
+		Name:         m.Name,
+		ImageUrl:     m.ImageUrl,
+		Thumbnails:   thumbnails,
+		Description:  m.Description,
+		Size:         m.Size,
+		Weight:       m.Weight,
+		Hp:           m.Hp,
+		Attack:       m.Attack,
+		Defense:      m.Defense,
+		Speed:        m.Speed,
+		CreatedAt:    timestamppb.New(m.CreatedAt),
+		UpdatedAt:    timestamppb.New(m.UpdatedAt),
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/iamaul/go-pokedex/internal/monster"
 	httpErr "github.com/iamaul/go-pokedex/pkg/error"
 	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/render"
 	"github.com/iamaul/go-pokedex/pkg/utils"
 	"github.com/labstack/echo/v4"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -27,84 +28,177 @@ func NewMonsterHandler(cfg *config.Config, monsterTypeUsecase monster.MonsterTyp
 // CreateMonsterType godoc
 // @Summary Create a new monster type
 // @Description returns monster type
-// @Tags Auth
+// @Tags MonsterType
 // @Accept json
 // @Produce json
+// @Param monsterType body domain.MonsterType true "monster type"
 // @Success 201 {object} domain.MonsterType
+// @Failure 400 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Security BearerAuth
 // @Router /monster/type [post]
 func (h *MonsterHandler) CreateMonsterType() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		monsterType := &domain.MonsterType{}
 		if err := utils.ReadRequest(c, monsterType); err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
 		createdMonsterType, err := h.monsterTypeUsecase.MonsterTypeCreate(c.Request().Context(), monsterType)
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
-		return c.JSON(http.StatusCreated, createdMonsterType)
+		return render.Respond(c, http.StatusCreated, createdMonsterType)
 	}
 }
 
 // UpdateMonsterType godoc
 // @Summary Update monster type
 // @Description update existing monster type
-// @Tags Auth
+// @Tags MonsterType
 // @Accept json
-// @Param id path int true "id"
 // @Produce json
+// @Param id path string true "monster type id"
+// @Param If-Match header string false "entity tag the stored document must still have"
+// @Param monsterType body domain.MonsterTypeUpdate true "monster type"
 // @Success 200 {object} domain.MonsterTypeUpdate
+// @Header 200 {string} ETag "entity tag of the updated document"
+// @Failure 403 {object} httpErr.RestError
+// @Failure 412 {object} httpErr.RestError
+// @Security BearerAuth
 // @Router /monster/type/{id} [put]
 func (h *MonsterHandler) UpdateMonsterType() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		monsterTypeID, err := primitive.ObjectIDFromHex(c.Param("id"))
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
 		monsterType := &domain.MonsterTypeUpdate{}
-		monsterType.ID = monsterTypeID
 		if err := utils.ReadRequest(c, monsterType); err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
+		}
+		monsterType.ID = monsterTypeID
+
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		if version != nil {
+			monsterType.Version = version
 		}
 
 		updatedMonsterType, err := h.monsterTypeUsecase.MonsterTypeUpdate(c.Request().Context(), monsterType)
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
+		}
+		c.Response().Header().Set(utils.HeaderETag, domain.VersionETag(*updatedMonsterType.Version))
+
+		return render.Respond(c, http.StatusOK, updatedMonsterType)
+	}
+}
+
+// PatchMonsterType godoc
+// @Summary Patch monster type
+// @Description apply a JSON merge patch (RFC 7386) or JSON patch (RFC 6902) to the monster type
+// @Tags MonsterType
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Param id path string true "monster type id"
+// @Param If-Match header string false "entity tag the stored document must still have"
+// @Param patch body object true "merge patch document or list of patch operations"
+// @Success 200 {object} domain.MonsterTypeUpdate
+// @Header 200 {string} ETag "entity tag of the updated document"
+// @Failure 400 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Failure 404 {object} httpErr.RestError
+// @Failure 409 {object} httpErr.RestError
+// @Failure 412 {object} httpErr.RestError
+// @Failure 415 {object} httpErr.RestError
+// @Failure 422 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /monster/type/{id} [patch]
+func (h *MonsterHandler) PatchMonsterType() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		monsterTypeID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		ctx := c.Request().Context()
+		current, err := h.monsterTypeUsecase.GetByID(ctx, monsterTypeID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		if err := utils.MatchVersion(version, current.Version); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		monsterType := &domain.MonsterTypeUpdate{}
+		if err := utils.ReadPatch(c, current.MonsterTypeUpdate(), monsterType); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		// The patch was computed against the fetched document, only save it while that is still stored
+		monsterType.ID = monsterTypeID
+		monsterType.Version = &current.Version
+
+		updatedMonsterType, err := h.monsterTypeUsecase.MonsterTypeUpdate(ctx, monsterType)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
 		}
+		c.Response().Header().Set(utils.HeaderETag, domain.VersionETag(*updatedMonsterType.Version))
 
-		return c.JSON(http.StatusOK, updatedMonsterType)
+		return render.Respond(c, http.StatusOK, updatedMonsterType)
 	}
 }
 
 // DeleteMonsterType godoc
 // @Summary Delete monster type
 // @Description delete existing monster type
-// @Tags Auth
-// @Accept json
-// @Param id path int true "id"
-// @Produce json
-// @Success 200 {string} string	"ok"
+// @Tags MonsterType
+// @Param id path string true "monster type id"
+// @Param If-Match header string false "entity tag the stored document must still have"
+// @Success 200
+// @Failure 403 {object} httpErr.RestError
+// @Failure 412 {object} httpErr.RestError
 // @Failure 500 {object} httpErr.RestError
-// @Router /monster/type/{id} [put]
+// @Security BearerAuth
+// @Router /monster/type/{id} [delete]
 func (h *MonsterHandler) DeleteMonsterType() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		monsterTypeID, err := primitive.ObjectIDFromHex(c.Param("id"))
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
+		}
+
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
 		}
 
-		if err = h.monsterTypeUsecase.MonsterTypeDeletion(c.Request().Context(), monsterTypeID); err != nil {
+		if err = h.monsterTypeUsecase.MonsterTypeDeletion(c.Request().Context(), monsterTypeID, version); err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
 		return c.NoContent(http.StatusOK)
@@ -114,137 +208,250 @@ func (h *MonsterHandler) DeleteMonsterType() echo.HandlerFunc {
 // ListMonsterType godoc
 // @Summary Get monster type list
 // @Description list of monster types
-// @Tags Auth
-// @Accept json
+// @Tags MonsterType
 // @Produce json
+// @Param page query int false "page number"
+// @Param size query int false "page size"
+// @Param orderBy query string false "sort field"
+// @Param If-None-Match header string false "entity tag of the cached copy"
 // @Success 200 {object} domain.MonsterTypeList
+// @Header 200 {string} ETag "entity tag of the representation"
+// @Success 304
+// @Failure 403 {object} httpErr.RestError
+// @Security BearerAuth
 // @Router /monster/type/list [get]
 func (h *MonsterHandler) ListMonsterType() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		paginationQuery, err := utils.GetPaginationFromCtx(c)
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
 		monsterTypeList, err := h.monsterTypeUsecase.GetMonsterTypeList(c.Request().Context(), paginationQuery)
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
+		}
+
+		if utils.NotModified(c, monsterTypeList.ETag()) {
+			return c.NoContent(http.StatusNotModified)
 		}
 
-		return c.JSON(http.StatusOK, monsterTypeList)
+		return render.Respond(c, http.StatusOK, monsterTypeList)
 	}
 }
 
 // DetailMonsterType godoc
 // @Summary Detail monster type
 // @Description Get monster type detail
-// @Tags Auth
-// @Accept json
-// @Param id path int true "id"
+// @Tags MonsterType
 // @Produce json
+// @Param id path string true "monster type id"
+// @Param If-None-Match header string false "entity tag of the cached copy"
 // @Success 200 {object} domain.MonsterType
+// @Header 200 {string} ETag "entity tag of the representation"
+// @Success 304
+// @Failure 403 {object} httpErr.RestError
+// @Failure 404 {object} httpErr.RestError
+// @Security BearerAuth
 // @Router /monster/type/{id} [get]
 func (h *MonsterHandler) DetailMonsterType() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		monsterTypeID, err := primitive.ObjectIDFromHex(c.Param("id"))
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
 		monsterType, err := h.monsterTypeUsecase.GetByID(c.Request().Context(), monsterTypeID)
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
+		}
+
+		if utils.NotModified(c, monsterType.ETag()) {
+			return c.NoContent(http.StatusNotModified)
 		}
 
-		return c.JSON(http.StatusOK, monsterType)
+		return render.Respond(c, http.StatusOK, monsterType)
 	}
 }
 
 // CreateMonster godoc
 // @Summary Create a new monster
 // @Description returns monster
-// @Tags Auth
+// @Tags Monster
 // @Accept json
 // @Produce json
+// @Param monster body domain.Monster true "monster"
 // @Success 201 {object} domain.Monster
+// @Failure 400 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Security BearerAuth
 // @Router /monster [post]
 func (h *MonsterHandler) CreateMonster() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		monster := &domain.Monster{}
 		if err := utils.ReadRequest(c, monster); err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
 		createdMonster, err := h.monsterUsecase.MonsterCreate(c.Request().Context(), monster)
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
-		return c.JSON(http.StatusCreated, createdMonster)
+		return render.Respond(c, http.StatusCreated, createdMonster)
 	}
 }
 
 // UpdateMonster godoc
 // @Summary Update monster
 // @Description update existing monster
-// @Tags Auth
+// @Tags Monster
 // @Accept json
-// @Param id path int true "id"
 // @Produce json
+// @Param id path string true "monster id"
+// @Param If-Match header string false "entity tag the stored document must still have"
+// @Param monster body domain.MonsterUpdate true "monster"
 // @Success 200 {object} domain.MonsterUpdate
+// @Header 200 {string} ETag "entity tag of the updated document"
+// @Failure 403 {object} httpErr.RestError
+// @Failure 412 {object} httpErr.RestError
+// @Security BearerAuth
 // @Router /monster/{id} [put]
 func (h *MonsterHandler) UpdateMonster() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		monsterID, err := primitive.ObjectIDFromHex(c.Param("id"))
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
 		monster := &domain.MonsterUpdate{}
-		monster.ID = monsterID
 		if err := utils.ReadRequest(c, monster); err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
+		}
+		monster.ID = monsterID
+
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		if version != nil {
+			monster.Version = version
 		}
 
 		updatedMonster, err := h.monsterUsecase.MonsterUpdate(c.Request().Context(), monster)
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
+		c.Response().Header().Set(utils.HeaderETag, domain.VersionETag(*updatedMonster.Version))
 
-		return c.JSON(http.StatusOK, updatedMonster)
+		return render.Respond(c, http.StatusOK, updatedMonster)
+	}
+}
+
+// PatchMonster godoc
+// @Summary Patch monster
+// @Description apply a JSON merge patch (RFC 7386) or JSON patch (RFC 6902) to the monster
+// @Tags Monster
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Param id path string true "monster id"
+// @Param If-Match header string false "entity tag the stored document must still have"
+// @Param patch body object true "merge patch document or list of patch operations"
+// @Success 200 {object} domain.MonsterUpdate
+// @Header 200 {string} ETag "entity tag of the updated document"
+// @Failure 400 {object} httpErr.RestError
+// @Failure 403 {object} httpErr.RestError
+// @Failure 404 {object} httpErr.RestError
+// @Failure 409 {object} httpErr.RestError
+// @Failure 412 {object} httpErr.RestError
+// @Failure 415 {object} httpErr.RestError
+// @Failure 422 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /monster/{id} [patch]
+func (h *MonsterHandler) PatchMonster() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		monsterID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		ctx := c.Request().Context()
+		current, err := h.monsterUsecase.GetByID(ctx, monsterID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		if err := utils.MatchVersion(version, current.Version); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		monster := &domain.MonsterUpdate{}
+		if err := utils.ReadPatch(c, current.MonsterUpdate(), monster); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		// The patch was computed against the fetched document, only save it while that is still stored
+		monster.ID = monsterID
+		monster.Version = &current.Version
+
+		updatedMonster, err := h.monsterUsecase.MonsterUpdate(ctx, monster)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		c.Response().Header().Set(utils.HeaderETag, domain.VersionETag(*updatedMonster.Version))
+
+		return render.Respond(c, http.StatusOK, updatedMonster)
 	}
 }
 
 // DeleteMonster godoc
 // @Summary Delete monster
 // @Description delete existing monster
-// @Tags Auth
-// @Accept json
-// @Param id path int true "id"
-// @Produce json
-// @Success 200 {string} string	"ok"
+// @Tags Monster
+// @Param id path string true "monster id"
+// @Param If-Match header string false "entity tag the stored document must still have"
+// @Success 200
+// @Failure 403 {object} httpErr.RestError
+// @Failure 412 {object} httpErr.RestError
 // @Failure 500 {object} httpErr.RestError
-// @Router /monster/{id} [put]
+// @Security BearerAuth
+// @Router /monster/{id} [delete]
 func (h *MonsterHandler) DeleteMonster() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		monsterID, err := primitive.ObjectIDFromHex(c.Param("id"))
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
-		if err = h.monsterUsecase.MonsterDeletion(c.Request().Context(), monsterID); err != nil {
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
+		}
+
+		if err = h.monsterUsecase.MonsterDeletion(c.Request().Context(), monsterID, version); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
 		}
 
 		return c.NoContent(http.StatusOK)
@@ -254,84 +461,153 @@ func (h *MonsterHandler) DeleteMonster() echo.HandlerFunc {
 // ListMonster godoc
 // @Summary Get monster list
 // @Description list of monster
-// @Tags Auth
-// @Accept json
+// @Tags Monster
 // @Produce json
+// @Param page query int false "page number"
+// @Param size query int false "page size"
+// @Param orderBy query string false "sort field"
+// @Param If-None-Match header string false "entity tag of the cached copy"
 // @Success 200 {object} domain.MonsterList
+// @Header 200 {string} ETag "entity tag of the representation"
+// @Success 304
+// @Failure 403 {object} httpErr.RestError
+// @Security BearerAuth
 // @Router /monster/list [get]
 func (h *MonsterHandler) ListMonster() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		paginationQuery, err := utils.GetPaginationFromCtx(c)
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
 		monsterList, err := h.monsterUsecase.GetMonsterList(c.Request().Context(), paginationQuery)
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
+		}
+
+		if utils.NotModified(c, monsterList.ETag()) {
+			return c.NoContent(http.StatusNotModified)
 		}
 
-		return c.JSON(http.StatusOK, monsterList)
+		return render.Respond(c, http.StatusOK, monsterList)
 	}
 }
 
 // DetailMonster godoc
 // @Summary Detail monster
 // @Description Get monster detail
-// @Tags Auth
-// @Accept json
-// @Param id path int true "id"
+// @Tags Monster
 // @Produce json
+// @Param id path string true "monster id"
+// @Param If-None-Match header string false "entity tag of the cached copy"
 // @Success 200 {object} domain.Monster
+// @Header 200 {string} ETag "entity tag of the representation"
+// @Success 304
+// @Failure 403 {object} httpErr.RestError
+// @Failure 404 {object} httpErr.RestError
+// @Security BearerAuth
 // @Router /monster/{id} [get]
 func (h *MonsterHandler) DetailMonster() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		monsterID, err := primitive.ObjectIDFromHex(c.Param("id"))
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
 		monster, err := h.monsterUsecase.GetByID(c.Request().Context(), monsterID)
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
+		}
+
+		if utils.NotModified(c, monster.ETag()) {
+			return c.NoContent(http.StatusNotModified)
 		}
 
-		return c.JSON(http.StatusOK, monster)
+		return render.Respond(c, http.StatusOK, monster)
 	}
 }
 
 // AddMonsterType godoc
 // @Summary Add monster type
-// @Description add monster type
-// @Tags Auth
+// @Description attach a monster type to the monster
+// @Tags Monster
 // @Accept json
-// @Param id path int true "id"
 // @Produce json
+// @Param id path string true "monster id"
+// @Param monsterType body domain.MonsterTypeBody true "monster type"
 // @Success 200 {object} domain.MonsterTypeBody
+// @Failure 403 {object} httpErr.RestError
+// @Failure 404 {object} httpErr.RestError
+// @Security BearerAuth
 // @Router /monster/{id} [post]
 func (h *MonsterHandler) AddMonsterType() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		monsterID, err := primitive.ObjectIDFromHex(c.Param("id"))
 		if err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
 		monsterType := &domain.MonsterTypeBody{}
 		if err := utils.ReadRequest(c, monsterType); err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
 		}
 
 		if err := h.monsterUsecase.AttachMonsterType(c.Request().Context(), monsterID, monsterType.MonsterTypeID); err != nil {
 			utils.LogResponseError(c, h.logger, err)
-			return c.JSON(httpErr.ErrorResponse(err))
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, monsterType)
+	}
+}
+
+// UploadMonsterImage godoc
+// @Summary Upload monster image
+// @Description upload monster image as multipart form field "image", generates thumbnails. Images over the configured size or pixel count are refused
+// @Tags Monster
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "monster id"
+// @Param image formData file true "image"
+// @Success 200 {object} domain.Monster
+// @Failure 403 {object} httpErr.RestError
+// @Failure 413 {object} httpErr.RestError
+// @Failure 415 {object} httpErr.RestError
+// @Security BearerAuth
+// @Router /monster/{id}/image [post]
+func (h *MonsterHandler) UploadMonsterImage() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		monsterID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		fileHeader, err := c.FormFile("image")
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, httpErr.NewBadRequestError(err))
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		defer file.Close()
+
+		monster, err := h.monsterUsecase.MonsterImageUpload(c.Request().Context(), monsterID, file)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
 		}
 
-		return c.JSON(http.StatusOK, monsterType)
+		return render.Respond(c, http.StatusOK, monster)
 	}
 }
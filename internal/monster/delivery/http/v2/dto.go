@@ -0,0 +1,179 @@
+package v2
+
+import (
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+type MonsterType struct {
+	ID        string    `json:"id" xml:"id"`
+	Name      string    `json:"name" xml:"name"`
+	Version   int64     `json:"version" xml:"version"`
+	CreatedAt time.Time `json:"created_at" xml:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" xml:"updated_at"`
+}
+
+type MonsterTypeRequest struct {
+	Name string `json:"name" xml:"name" validate:"required,lte=4"`
+}
+
+type Monster struct {
+	ID          string        `json:"id" xml:"id"`
+	Name        string        `json:"name" xml:"name"`
+	Description string        `json:"description" xml:"description"`
+	Types       []string      `json:"types" xml:"types>type"`
+	Size        float32       `json:"size" xml:"size"`
+	Weight      float32       `json:"weight" xml:"weight"`
+	Stats       MonsterStats  `json:"stats" xml:"stats"`
+	Image       *MonsterImage `json:"image" xml:"image,omitempty"`
+	Version     int64         `json:"version" xml:"version"`
+	CreatedAt   time.Time     `json:"created_at" xml:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at" xml:"updated_at"`
+}
+
+type MonsterStats struct {
+	Hp      int32 `json:"hp" xml:"hp"`
+	Attack  int32 `json:"attack" xml:"attack"`
+	Defense int32 `json:"defense" xml:"defense"`
+	Speed   int32 `json:"speed" xml:"speed"`
+}
+
+type MonsterImage struct {
+	Url        string             `json:"url" xml:"url"`
+	Thumbnails []MonsterThumbnail `json:"thumbnails" xml:"thumbnails>thumbnail"`
+}
+
+type MonsterThumbnail struct {
+	Width int    `json:"width" xml:"width"`
+	Url   string `json:"url" xml:"url"`
+}
+
+type MonsterRequest struct {
+	Name        string       `json:"name" xml:"name" validate:"required"`
+	Description string       `json:"description" xml:"description"`
+	Size        float32      `json:"size" xml:"size"`
+	Weight      float32      `json:"weight" xml:"weight"`
+	Stats       MonsterStats `json:"stats" xml:"stats"`
+}
+
+type MonsterTypeAttachment struct {
+	TypeID string `json:"type_id" xml:"type_id" validate:"required"`
+}
+
+func NewMonsterType(t *domain.MonsterType) *MonsterType {
+	return &MonsterType{
+		ID:        t.ID.Hex(),
+		Name:      t.Name,
+		Version:   t.Version,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+// Editable fields of the monster type, the document PATCH requests are applied to
+func NewMonsterTypeRequest(t *domain.MonsterType) *MonsterTypeRequest {
+	return &MonsterTypeRequest{Name: t.Name}
+}
+
+func NewMonsterTypePage(l *domain.MonsterTypeList) *utils.Page[*MonsterType] {
+	data := make([]*MonsterType, 0, len(l.MonsterTypes))
+	for _, t := range l.MonsterTypes {
+		data = append(data, NewMonsterType(t))
+	}
+
+	return &utils.Page[*MonsterType]{
+		Data: data,
+		Meta: utils.PageMeta{TotalCount: l.TotalCount, TotalPages: l.TotalPages, Page: l.Page, Size: l.Size, HasMore: l.HasMore},
+	}
+}
+
+func NewMonster(m *domain.Monster) *Monster {
+	types := make([]string, 0, len(m.MonsterTypes))
+	for _, id := range m.MonsterTypes {
+		types = append(types, id.Hex())
+	}
+
+	monster := &Monster{
+		ID:          m.ID.Hex(),
+		Name:        m.Name,
+		Description: m.Description,
+		Types:       types,
+		Size:        m.Size,
+		Weight:      m.Weight,
+		Stats: MonsterStats{
+			Hp:      m.Hp,
+			Attack:  m.Attack,
+			Defense: m.Defense,
+			Speed:   m.Speed,
+		},
+		Version:   m.Version,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+
+	if m.ImageUrl != "" {
+		thumbnails := make([]MonsterThumbnail, 0, len(m.Thumbnails))
+		for _, t := range m.Thumbnails {
+			thumbnails = append(thumbnails, MonsterThumbnail{Width: t.Width, Url: t.Url})
+		}
+		monster.Image = &MonsterImage{Url: m.ImageUrl, Thumbnails: thumbnails}
+	}
+
+	return monster
+}
+
+func NewMonsterPage(l *domain.MonsterList) *utils.Page[*Monster] {
+	data := make([]*Monster, 0, len(l.Monsters))
+	for _, m := range l.Monsters {
+		data = append(data, NewMonster(m))
+	}
+
+	return &utils.Page[*Monster]{
+		Data: data,
+		Meta: utils.PageMeta{TotalCount: l.TotalCount, TotalPages: l.TotalPages, Page: l.Page, Size: l.Size, HasMore: l.HasMore},
+	}
+}
+
+// Editable fields of the monster, the document PATCH requests are applied to
+func NewMonsterRequest(m *domain.Monster) *MonsterRequest {
+	return &MonsterRequest{
+		Name:        m.Name,
+		Description: m.Description,
+		Size:        m.Size,
+		Weight:      m.Weight,
+		Stats: MonsterStats{
+			Hp:      m.Hp,
+			Attack:  m.Attack,
+			Defense: m.Defense,
+			Speed:   m.Speed,
+		},
+	}
+}
+
+func (r *MonsterRequest) Monster() *domain.Monster {
+	return &domain.Monster{
+		Name:        r.Name,
+		Description: r.Description,
+		Size:        r.Size,
+		Weight:      r.Weight,
+		Hp:          r.Stats.Hp,
+		Attack:      r.Stats.Attack,
+		Defense:     r.Stats.Defense,
+		Speed:       r.Stats.Speed,
+	}
+}
+
+func (r *MonsterRequest) MonsterUpdate() *domain.MonsterUpdate {
+	return &domain.MonsterUpdate{
+		Name:        r.Name,
+		Description: r.Description,
+		Size:        r.Size,
+		Weight:      r.Weight,
+		Hp:          r.Stats.Hp,
+		Attack:      r.Stats.Attack,
+		Defense:     r.Stats.Defense,
+		Speed:       r.Stats.Speed,
+	}
+}
@@ -0,0 +1,119 @@
+package v2
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+)
+
+func TestNewMonster(t *testing.T) {
+	typeID := primitive.NewObjectID()
+	monster := &domain.Monster{
+		ID:           primitive.NewObjectID(),
+		MonsterTypes: []primitive.ObjectID{typeID},
+		Name:         "bulbasaur",
+		Hp:           45,
+		Attack:       49,
+		Defense:      49,
+		Speed:        45,
+		Version:      3,
+	}
+
+	tests := []struct {
+		name     string
+		imageUrl string
+		image    interface{}
+	}{
+		{name: "without an image", image: nil},
+		{name: "with an image", imageUrl: "https://cdn.example.com/bulbasaur.png", image: map[string]interface{}{
+			"url":        "https://cdn.example.com/bulbasaur.png",
+			"thumbnails": []interface{}{map[string]interface{}{"width": float64(64), "url": "https://cdn.example.com/bulbasaur-64.png"}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := *monster
+			m.ImageUrl = tt.imageUrl
+			if tt.imageUrl != "" {
+				m.Thumbnails = []domain.MonsterThumbnail{{Width: 64, Url: "https://cdn.example.com/bulbasaur-64.png", Key: "bulbasaur-64"}}
+			}
+
+			var got map[string]interface{}
+			body, err := json.Marshal(NewMonster(&m))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatal(err)
+			}
+
+			if got["id"] != m.ID.Hex() {
+				t.Errorf("id = %v, want %s", got["id"], m.ID.Hex())
+			}
+			if !reflect.DeepEqual(got["types"], []interface{}{typeID.Hex()}) {
+				t.Errorf("types = %v, want [%s]", got["types"], typeID.Hex())
+			}
+			wantStats := map[string]interface{}{"hp": float64(45), "attack": float64(49), "defense": float64(49), "speed": float64(45)}
+			if !reflect.DeepEqual(got["stats"], wantStats) {
+				t.Errorf("stats = %v, want %v", got["stats"], wantStats)
+			}
+			if !reflect.DeepEqual(got["image"], tt.image) {
+				t.Errorf("image = %v, want %v", got["image"], tt.image)
+			}
+			// The flat v1 fields are not repeated
+			for _, key := range []string{"_id", "monster_types", "hp", "image_url", "thumbnails"} {
+				if _, ok := got[key]; ok {
+					t.Errorf("v2 monster has the v1 field %q", key)
+				}
+			}
+		})
+	}
+}
+
+func TestNewMonsterPage(t *testing.T) {
+	list := &domain.MonsterList{
+		TotalCount: 3,
+		TotalPages: 2,
+		Page:       1,
+		Size:       2,
+		HasMore:    true,
+		Monsters:   []*domain.Monster{{ID: primitive.NewObjectID()}, {ID: primitive.NewObjectID()}},
+	}
+
+	var got map[string]interface{}
+	body, err := json.Marshal(NewMonsterPage(list))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ := got["data"].([]interface{}); len(data) != 2 {
+		t.Errorf("data = %v, want 2 monsters", got["data"])
+	}
+	wantMeta := map[string]interface{}{"total_count": float64(3), "total_pages": float64(2), "page": float64(1), "size": float64(2), "has_more": true}
+	if !reflect.DeepEqual(got["meta"], wantMeta) {
+		t.Errorf("meta = %v, want %v", got["meta"], wantMeta)
+	}
+	if _, ok := got["monsters"]; ok {
+		t.Error("v2 page has the v1 monsters field")
+	}
+}
+
+func TestMonsterRequest(t *testing.T) {
+	var req MonsterRequest
+	if err := json.Unmarshal([]byte(`{"name":"ivysaur","size":1,"weight":13,"stats":{"hp":60,"attack":62,"defense":63,"speed":60}}`), &req); err != nil {
+		t.Fatal(err)
+	}
+
+	got := req.Monster()
+	want := &domain.Monster{Name: "ivysaur", Size: 1, Weight: 13, Hp: 60, Attack: 62, Defense: 63, Speed: 60}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Monster() = %+v, want %+v", got, want)
+	}
+}
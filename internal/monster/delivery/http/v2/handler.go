@@ -0,0 +1,446 @@
+package v2
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/monster"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/render"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+// MonsterHandler serves the v2 representation of the monster routes
+type MonsterHandler struct {
+	cfg                *config.Config
+	monsterTypeUsecase monster.MonsterTypeUsecase
+	monsterUsecase     monster.MonsterUsecase
+	logger             logger.Logger
+}
+
+func NewMonsterHandler(cfg *config.Config, monsterTypeUsecase monster.MonsterTypeUsecase, monsterUsecase monster.MonsterUsecase, log logger.Logger) monster.DeliveryHandlers {
+	return &MonsterHandler{cfg: cfg, monsterTypeUsecase: monsterTypeUsecase, monsterUsecase: monsterUsecase, logger: log}
+}
+
+func (h *MonsterHandler) CreateMonsterType() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := &MonsterTypeRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		createdMonsterType, err := h.monsterTypeUsecase.MonsterTypeCreate(c.Request().Context(), &domain.MonsterType{Name: request.Name})
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusCreated, NewMonsterType(createdMonsterType))
+	}
+}
+
+func (h *MonsterHandler) UpdateMonsterType() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		monsterTypeID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		request := &MonsterTypeRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		ctx := c.Request().Context()
+		if _, err := h.monsterTypeUsecase.MonsterTypeUpdate(ctx, &domain.MonsterTypeUpdate{ID: monsterTypeID, Name: request.Name, Version: version}); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		// v2 answers with the stored resource rather than echoing the request
+		monsterType, err := h.monsterTypeUsecase.GetByID(ctx, monsterTypeID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if utils.NotModified(c, monsterType.ETag()) {
+			return c.NoContent(http.StatusNotModified)
+		}
+
+		c.Response().Header().Set(utils.HeaderETag, monsterType.ETag())
+
+		return render.Respond(c, http.StatusOK, NewMonsterType(monsterType))
+	}
+}
+
+func (h *MonsterHandler) PatchMonsterType() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		monsterTypeID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		ctx := c.Request().Context()
+		current, err := h.monsterTypeUsecase.GetByID(ctx, monsterTypeID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		if err := utils.MatchVersion(version, current.Version); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		request := &MonsterTypeRequest{}
+		if err := utils.ReadPatch(c, NewMonsterTypeRequest(current), request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		// The patch was computed against the fetched document, only save it while that is still stored
+		if _, err := h.monsterTypeUsecase.MonsterTypeUpdate(ctx, &domain.MonsterTypeUpdate{ID: monsterTypeID, Name: request.Name, Version: &current.Version}); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		monsterType, err := h.monsterTypeUsecase.GetByID(ctx, monsterTypeID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		c.Response().Header().Set(utils.HeaderETag, monsterType.ETag())
+
+		return render.Respond(c, http.StatusOK, NewMonsterType(monsterType))
+	}
+}
+
+func (h *MonsterHandler) DeleteMonsterType() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		monsterTypeID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.monsterTypeUsecase.MonsterTypeDeletion(c.Request().Context(), monsterTypeID, version); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (h *MonsterHandler) ListMonsterType() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		paginationQuery, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		monsterTypeList, err := h.monsterTypeUsecase.GetMonsterTypeList(c.Request().Context(), paginationQuery)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if utils.NotModified(c, monsterTypeList.ETag()) {
+			return c.NoContent(http.StatusNotModified)
+		}
+
+		return render.Respond(c, http.StatusOK, NewMonsterTypePage(monsterTypeList))
+	}
+}
+
+func (h *MonsterHandler) DetailMonsterType() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		monsterTypeID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		monsterType, err := h.monsterTypeUsecase.GetByID(c.Request().Context(), monsterTypeID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, NewMonsterType(monsterType))
+	}
+}
+
+func (h *MonsterHandler) CreateMonster() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		request := &MonsterRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		createdMonster, err := h.monsterUsecase.MonsterCreate(c.Request().Context(), request.Monster())
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusCreated, NewMonster(createdMonster))
+	}
+}
+
+func (h *MonsterHandler) UpdateMonster() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		monsterID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		request := &MonsterRequest{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		update := request.MonsterUpdate()
+		update.ID = monsterID
+		update.Version = version
+
+		ctx := c.Request().Context()
+		if _, err := h.monsterUsecase.MonsterUpdate(ctx, update); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		monster, err := h.monsterUsecase.GetByID(ctx, monsterID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		c.Response().Header().Set(utils.HeaderETag, monster.ETag())
+
+		return render.Respond(c, http.StatusOK, NewMonster(monster))
+	}
+}
+
+func (h *MonsterHandler) PatchMonster() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		monsterID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		ctx := c.Request().Context()
+		current, err := h.monsterUsecase.GetByID(ctx, monsterID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		if err := utils.MatchVersion(version, current.Version); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		request := &MonsterRequest{}
+		if err := utils.ReadPatch(c, NewMonsterRequest(current), request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		// The patch was computed against the fetched document, only save it while that is still stored
+		update := request.MonsterUpdate()
+		update.ID = monsterID
+		update.Version = &current.Version
+
+		if _, err := h.monsterUsecase.MonsterUpdate(ctx, update); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		monster, err := h.monsterUsecase.GetByID(ctx, monsterID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		c.Response().Header().Set(utils.HeaderETag, monster.ETag())
+
+		return render.Respond(c, http.StatusOK, NewMonster(monster))
+	}
+}
+
+func (h *MonsterHandler) DeleteMonster() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		monsterID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		version, err := utils.IfMatchVersion(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.monsterUsecase.MonsterDeletion(c.Request().Context(), monsterID, version); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func (h *MonsterHandler) ListMonster() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		paginationQuery, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		monsterList, err := h.monsterUsecase.GetMonsterList(c.Request().Context(), paginationQuery)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if utils.NotModified(c, monsterList.ETag()) {
+			return c.NoContent(http.StatusNotModified)
+		}
+
+		return render.Respond(c, http.StatusOK, NewMonsterPage(monsterList))
+	}
+}
+
+func (h *MonsterHandler) DetailMonster() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		monsterID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		monster, err := h.monsterUsecase.GetByID(c.Request().Context(), monsterID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if utils.NotModified(c, monster.ETag()) {
+			return c.NoContent(http.StatusNotModified)
+		}
+
+		return render.Respond(c, http.StatusOK, NewMonster(monster))
+	}
+}
+
+func (h *MonsterHandler) AddMonsterType() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		monsterID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		request := &MonsterTypeAttachment{}
+		if err := utils.ReadRequest(c, request); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		monsterTypeID, err := primitive.ObjectIDFromHex(request.TypeID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, "invalid type_id", err))
+		}
+
+		ctx := c.Request().Context()
+		if err := h.monsterUsecase.AttachMonsterType(ctx, monsterID, monsterTypeID); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		monster, err := h.monsterUsecase.GetByID(ctx, monsterID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, NewMonster(monster))
+	}
+}
+
+func (h *MonsterHandler) UploadMonsterImage() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		monsterID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		fileHeader, err := c.FormFile("image")
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, httpErr.NewBadRequestError(err))
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		defer file.Close()
+
+		monster, err := h.monsterUsecase.MonsterImageUpload(c.Request().Context(), monsterID, file)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, NewMonster(monster))
+	}
+}
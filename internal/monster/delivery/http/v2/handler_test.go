@@ -0,0 +1,256 @@
+package v2
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/monster"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+// Monster store checking expected versions the way mongodb.UpdateVersioned does
+type fakeMonsterUsecase struct {
+	monster.MonsterUsecase
+	mu       sync.Mutex
+	monsters map[primitive.ObjectID]*domain.Monster
+}
+
+func (u *fakeMonsterUsecase) GetByID(ctx context.Context, monsterID primitive.ObjectID) (*domain.Monster, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	stored, ok := u.monsters[monsterID]
+	if !ok {
+		return nil, httpErr.NewNotFoundError(errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound))
+	}
+	found := *stored
+
+	return &found, nil
+}
+
+func (u *fakeMonsterUsecase) GetMonsterList(ctx context.Context, pq *utils.PaginationQuery) (*domain.MonsterList, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	list := &domain.MonsterList{TotalCount: len(u.monsters), TotalPages: 1, Page: 1, Size: len(u.monsters)}
+	for _, m := range u.monsters {
+		found := *m
+		list.Monsters = append(list.Monsters, &found)
+	}
+
+	return list, nil
+}
+
+func (u *fakeMonsterUsecase) MonsterUpdate(ctx context.Context, update *domain.MonsterUpdate) (*domain.MonsterUpdate, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	stored, ok := u.monsters[update.ID]
+	if !ok {
+		return nil, errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+	if update.Version != nil && *update.Version != stored.Version {
+		return nil, errors.Wrapf(httpErr.PreconditionFailed, "version %d", *update.Version)
+	}
+	stored.Name = update.Name
+	stored.Hp = update.Hp
+	stored.Version++
+	version := stored.Version
+	update.Version = &version
+
+	return update, nil
+}
+
+func (u *fakeMonsterUsecase) MonsterDeletion(ctx context.Context, monsterID primitive.ObjectID, version *int64) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	stored, ok := u.monsters[monsterID]
+	if !ok {
+		return errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+	if version != nil && *version != stored.Version {
+		return errors.Wrapf(httpErr.PreconditionFailed, "version %d", *version)
+	}
+	delete(u.monsters, monsterID)
+
+	return nil
+}
+
+// The monster routes of the handler without authentication, serving one bulbasaur at version 3
+func newMonsterRoutes(t *testing.T) (*echo.Echo, *fakeMonsterUsecase, *domain.Monster) {
+	t.Helper()
+
+	cfg := &config.Config{Logger: config.Logger{Level: "fatal", Encoding: "console"}}
+	log := logger.NewLogger(cfg)
+	log.InitLogger()
+
+	bulbasaur := &domain.Monster{ID: primitive.NewObjectID(), Name: "bulbasaur", Hp: 45, Version: 3}
+	monsters := &fakeMonsterUsecase{monsters: map[primitive.ObjectID]*domain.Monster{bulbasaur.ID: bulbasaur}}
+	h := NewMonsterHandler(cfg, nil, monsters, log)
+
+	e := echo.New()
+	e.GET("/monster/list", h.ListMonster())
+	e.GET("/monster/:id", h.DetailMonster())
+	e.PUT("/monster/:id", h.UpdateMonster())
+	e.PATCH("/monster/:id", h.PatchMonster())
+	e.DELETE("/monster/:id", h.DeleteMonster())
+
+	found := *bulbasaur
+	return e, monsters, &found
+}
+
+func conditionalRequest(e *echo.Echo, method, target, body, header, value string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if method == http.MethodPatch {
+		req.Header.Set(echo.HeaderContentType, utils.MIMEMergePatch)
+	}
+	if value != "" {
+		req.Header.Set(header, value)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestMonsterIfMatch(t *testing.T) {
+	// Full representation for PUT, a merge patch for PATCH
+	const update = `{"name":"ivysaur","stats":{"hp":60}}`
+
+	tests := []struct {
+		name    string
+		method  string
+		body    string
+		ifMatch string
+		want    int
+	}{
+		{name: "put with the current version", method: http.MethodPut, body: update, ifMatch: `"3"`, want: http.StatusOK},
+		{name: "put with a stale version", method: http.MethodPut, body: update, ifMatch: `"2"`, want: http.StatusPreconditionFailed},
+		{name: "put with any version", method: http.MethodPut, body: update, ifMatch: "*", want: http.StatusOK},
+		{name: "put without If-Match", method: http.MethodPut, body: update, want: http.StatusOK},
+		{name: "put with a weak tag", method: http.MethodPut, body: update, ifMatch: `W/"3"`, want: http.StatusPreconditionFailed},
+		{name: "put with an unquoted tag", method: http.MethodPut, body: update, ifMatch: "3", want: http.StatusPreconditionFailed},
+		{name: "put with a negative version", method: http.MethodPut, body: update, ifMatch: `"-1"`, want: http.StatusPreconditionFailed},
+		{name: "patch with the current version", method: http.MethodPatch, body: update, ifMatch: `"3"`, want: http.StatusOK},
+		{name: "patch with a stale version", method: http.MethodPatch, body: update, ifMatch: `"4"`, want: http.StatusPreconditionFailed},
+		{name: "patch without If-Match", method: http.MethodPatch, body: update, want: http.StatusOK},
+		{name: "patch with a malformed tag", method: http.MethodPatch, body: update, ifMatch: `"three"`, want: http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, monsters, bulbasaur := newMonsterRoutes(t)
+
+			rec := conditionalRequest(e, tt.method, "/monster/"+bulbasaur.ID.Hex(), tt.body, utils.HeaderIfMatch, tt.ifMatch)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d (%s), want %d", rec.Code, rec.Body.String(), tt.want)
+			}
+
+			stored := monsters.monsters[bulbasaur.ID]
+			if tt.want != http.StatusOK {
+				if stored.Name != "bulbasaur" || stored.Version != 3 {
+					t.Errorf("refused update was applied: %s at version %d", stored.Name, stored.Version)
+				}
+				return
+			}
+			if stored.Name != "ivysaur" || stored.Hp != 60 {
+				t.Errorf("stored %s with hp %d, want the update applied", stored.Name, stored.Hp)
+			}
+			if got := rec.Header().Get(utils.HeaderETag); got != `"4"` {
+				t.Errorf("ETag = %q, want the new version", got)
+			}
+		})
+	}
+
+	t.Run("delete", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			ifMatch string
+			want    int
+		}{
+			{name: "current version", ifMatch: `"3"`, want: http.StatusNoContent},
+			{name: "stale version", ifMatch: `"2"`, want: http.StatusPreconditionFailed},
+			{name: "malformed tag", ifMatch: "3", want: http.StatusPreconditionFailed},
+			{name: "unconditional", want: http.StatusNoContent},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				e, monsters, bulbasaur := newMonsterRoutes(t)
+
+				rec := conditionalRequest(e, http.MethodDelete, "/monster/"+bulbasaur.ID.Hex(), "", utils.HeaderIfMatch, tt.ifMatch)
+				if rec.Code != tt.want {
+					t.Fatalf("status = %d (%s), want %d", rec.Code, rec.Body.String(), tt.want)
+				}
+				if _, kept := monsters.monsters[bulbasaur.ID]; kept != (tt.want != http.StatusNoContent) {
+					t.Errorf("kept = %v after status %d", kept, rec.Code)
+				}
+			})
+		}
+	})
+}
+
+func TestMonsterIfNoneMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        int
+	}{
+		{name: "current version", ifNoneMatch: `"3"`, want: http.StatusNotModified},
+		{name: "weak current version", ifNoneMatch: `W/"3"`, want: http.StatusNotModified},
+		{name: "among other tags", ifNoneMatch: `"1", "3"`, want: http.StatusNotModified},
+		{name: "any version", ifNoneMatch: "*", want: http.StatusNotModified},
+		{name: "stale version", ifNoneMatch: `"2"`, want: http.StatusOK},
+		{name: "malformed tag", ifNoneMatch: "3", want: http.StatusOK},
+		{name: "unconditional", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _, bulbasaur := newMonsterRoutes(t)
+
+			rec := conditionalRequest(e, http.MethodGet, "/monster/"+bulbasaur.ID.Hex(), "", utils.HeaderIfNoneMatch, tt.ifNoneMatch)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d (%s), want %d", rec.Code, rec.Body.String(), tt.want)
+			}
+			if got := rec.Header().Get(utils.HeaderETag); got != `"3"` {
+				t.Errorf("ETag = %q, want %q", got, `"3"`)
+			}
+			if tt.want == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("304 has a body: %q", rec.Body.String())
+			}
+		})
+	}
+
+	t.Run("list", func(t *testing.T) {
+		e, monsters, bulbasaur := newMonsterRoutes(t)
+
+		rec := conditionalRequest(e, http.MethodGet, "/monster/list", "", "", "")
+		etag := rec.Header().Get(utils.HeaderETag)
+		if rec.Code != http.StatusOK || etag == "" {
+			t.Fatalf("status = %d with ETag %q", rec.Code, etag)
+		}
+
+		if rec := conditionalRequest(e, http.MethodGet, "/monster/list", "", utils.HeaderIfNoneMatch, etag); rec.Code != http.StatusNotModified {
+			t.Errorf("status = %d for the current list, want %d", rec.Code, http.StatusNotModified)
+		}
+
+		// A changed monster changes the list
+		monsters.monsters[bulbasaur.ID].Version++
+		if rec := conditionalRequest(e, http.MethodGet, "/monster/list", "", utils.HeaderIfNoneMatch, etag); rec.Code != http.StatusOK {
+			t.Errorf("status = %d for a changed list, want %d", rec.Code, http.StatusOK)
+		}
+	})
+}
@@ -11,18 +11,21 @@ import (
 type MonsterTypeRepository interface {
 	CreateMonsterType(ctx context.Context, monsterType *domain.MonsterType) (*domain.MonsterType, error)
 	UpdateMonsterType(ctx context.Context, monsterType *domain.MonsterTypeUpdate) (*domain.MonsterTypeUpdate, error)
-	DeleteMonsterType(ctx context.Context, monsterTypeID primitive.ObjectID) error
+	DeleteMonsterType(ctx context.Context, monsterTypeID primitive.ObjectID, version *int64) error
 	FetchMonsterTypes(ctx context.Context, pq *utils.PaginationQuery) (*domain.MonsterTypeList, error)
 	FindByID(ctx context.Context, monsterTypeID primitive.ObjectID) (*domain.MonsterType, error)
+	FindByIDs(ctx context.Context, monsterTypeIDs []primitive.ObjectID) ([]*domain.MonsterType, error)
 	FindByName(ctx context.Context, monsterTypeName string) (*domain.MonsterType, error)
 }
 
 type MonsterRepository interface {
 	CreateMonster(ctx context.Context, monster *domain.Monster) (*domain.Monster, error)
 	UpdateMonster(ctx context.Context, monster *domain.MonsterUpdate) (*domain.MonsterUpdate, error)
-	DeleteMonster(ctx context.Context, monsterID primitive.ObjectID) error
+	DeleteMonster(ctx context.Context, monsterID primitive.ObjectID, version *int64) error
 	FetchMonsters(ctx context.Context, pq *utils.PaginationQuery) (*domain.MonsterList, error)
 	AddMonsterType(ctx context.Context, monsterID, monsterTypeID primitive.ObjectID) error
+	UpdateMonsterImage(ctx context.Context, monster *domain.Monster) error
 	FindByID(ctx context.Context, monsterID primitive.ObjectID) (*domain.Monster, error)
+	FindByIDs(ctx context.Context, monsterIDs []primitive.ObjectID) ([]*domain.Monster, error)
 	FindByName(ctx context.Context, monsterName string) (*domain.Monster, error)
 }
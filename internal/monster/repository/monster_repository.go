@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/iamaul/go-pokedex/internal/domain"
 	"github.com/iamaul/go-pokedex/internal/monster"
@@ -26,6 +27,8 @@ func NewMonsterRepo(db *mongo.Database) monster.MonsterRepository {
 }
 
 func (r *MonsterRepo) CreateMonster(ctx context.Context, monster *domain.Monster) (*domain.Monster, error) {
+	monster.Version = 1
+
 	result, err := r.db.InsertOne(ctx, monster)
 	if mongodb.IsDuplicate(err) {
 		return nil, errors.Wrap(err, httpErr.ErrUserAlreadyExists)
@@ -37,20 +40,27 @@ func (r *MonsterRepo) CreateMonster(ctx context.Context, monster *domain.Monster
 }
 
 func (r *MonsterRepo) UpdateMonster(ctx context.Context, monster *domain.MonsterUpdate) (*domain.MonsterUpdate, error) {
-	updateQuery := bson.M{}
-
-	if monster.Name != "" {
-		updateQuery["name"] = monster.Name
+	version, err := mongodb.UpdateVersioned(ctx, r.db, monster.ID, monster.Version, bson.M{"$set": bson.M{
+		"name":        monster.Name,
+		"description": monster.Description,
+		"size":        monster.Size,
+		"weight":      monster.Weight,
+		"hp":          monster.Hp,
+		"attack":      monster.Attack,
+		"defense":     monster.Defense,
+		"speed":       monster.Speed,
+		"updated_at":  time.Now(),
+	}})
+	if err != nil {
+		return nil, err
 	}
+	monster.Version = &version
 
-	_, err := r.db.UpdateOne(ctx, bson.M{"_id": monster.ID}, bson.M{"$set": updateQuery})
-	return monster, err
+	return monster, nil
 }
 
-func (r *MonsterRepo) DeleteMonster(ctx context.Context, monsterID primitive.ObjectID) error {
-	_, err := r.db.DeleteOne(ctx, bson.M{"_id": monsterID})
-
-	return err
+func (r *MonsterRepo) DeleteMonster(ctx context.Context, monsterID primitive.ObjectID, version *int64) error {
+	return mongodb.DeleteVersioned(ctx, r.db, monsterID, version)
 }
 
 func (r *MonsterRepo) FetchMonsters(ctx context.Context, pq *utils.PaginationQuery) (*domain.MonsterList, error) {
@@ -118,6 +128,29 @@ func (r *MonsterRepo) FindByID(ctx context.Context, monsterID primitive.ObjectID
 	return &monster, nil
 }
 
+func (r *MonsterRepo) FindByIDs(ctx context.Context, monsterIDs []primitive.ObjectID) ([]*domain.Monster, error) {
+	cursor, err := r.db.Find(ctx, bson.M{"_id": bson.M{"$in": monsterIDs}})
+	if err != nil {
+		return nil, errors.Wrap(err, "db.Find")
+	}
+	defer cursor.Close(ctx)
+
+	monsters := make([]*domain.Monster, 0, len(monsterIDs))
+	for cursor.Next(ctx) {
+		var monster domain.Monster
+		if err := cursor.Decode(&monster); err != nil {
+			return nil, errors.Wrap(err, "cursor.Decode")
+		}
+		monsters = append(monsters, &monster)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, errors.Wrap(err, "cursor.Err")
+	}
+
+	return monsters, nil
+}
+
 func (r *MonsterRepo) FindByName(ctx context.Context, monsterName string) (*domain.Monster, error) {
 	var monster domain.Monster
 
@@ -127,7 +160,28 @@ func (r *MonsterRepo) FindByName(ctx context.Context, monsterName string) (*doma
 }
 
 func (r *MonsterRepo) AddMonsterType(ctx context.Context, monsterID, monsterTypeID primitive.ObjectID) error {
-	_, err := r.db.UpdateOne(ctx, bson.M{"_id": monsterID}, bson.M{"$push": bson.M{"monster_types": monsterTypeID}})
+	_, err := r.db.UpdateOne(ctx, bson.M{"_id": monsterID}, bson.M{
+		"$push": bson.M{"monster_types": monsterTypeID},
+		"$inc":  bson.M{"version": 1},
+	})
 
 	return err
 }
+
+func (r *MonsterRepo) UpdateMonsterImage(ctx context.Context, monster *domain.Monster) error {
+	result, err := r.db.UpdateOne(ctx, bson.M{"_id": monster.ID}, bson.M{"$set": bson.M{
+		"image_url":  monster.ImageUrl,
+		"image_key":  monster.ImageKey,
+		"thumbnails": monster.Thumbnails,
+		"updated_at": monster.UpdatedAt,
+	}, "$inc": bson.M{"version": 1}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+	}
+
+	return nil
+}
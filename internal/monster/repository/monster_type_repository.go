@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/iamaul/go-pokedex/internal/domain"
 	"github.com/iamaul/go-pokedex/internal/monster"
@@ -26,6 +27,8 @@ func NewMonsterTypeRepo(db *mongo.Database) monster.MonsterTypeRepository {
 }
 
 func (r *MonsterTypeRepo) CreateMonsterType(ctx context.Context, monsterType *domain.MonsterType) (*domain.MonsterType, error) {
+	monsterType.Version = 1
+
 	result, err := r.db.InsertOne(ctx, monsterType)
 	if mongodb.IsDuplicate(err) {
 		return nil, errors.Wrap(err, httpErr.ErrUserAlreadyExists)
@@ -37,20 +40,20 @@ func (r *MonsterTypeRepo) CreateMonsterType(ctx context.Context, monsterType *do
 }
 
 func (r *MonsterTypeRepo) UpdateMonsterType(ctx context.Context, monsterType *domain.MonsterTypeUpdate) (*domain.MonsterTypeUpdate, error) {
-	updateQuery := bson.M{}
-
-	if monsterType.Name != "" {
-		updateQuery["name"] = monsterType.Name
+	version, err := mongodb.UpdateVersioned(ctx, r.db, monsterType.ID, monsterType.Version, bson.M{"$set": bson.M{
+		"name":       monsterType.Name,
+		"updated_at": time.Now(),
+	}})
+	if err != nil {
+		return nil, err
 	}
+	monsterType.Version = &version
 
-	_, err := r.db.UpdateOne(ctx, bson.M{"_id": monsterType.ID}, bson.M{"$set": updateQuery})
-	return monsterType, err
+	return monsterType, nil
 }
 
-func (r *MonsterTypeRepo) DeleteMonsterType(ctx context.Context, monsterTypeID primitive.ObjectID) error {
-	_, err := r.db.DeleteOne(ctx, bson.M{"_id": monsterTypeID})
-
-	return err
+func (r *MonsterTypeRepo) DeleteMonsterType(ctx context.Context, monsterTypeID primitive.ObjectID, version *int64) error {
+	return mongodb.DeleteVersioned(ctx, r.db, monsterTypeID, version)
 }
 
 func (r *MonsterTypeRepo) FetchMonsterTypes(ctx context.Context, pq *utils.PaginationQuery) (*domain.MonsterTypeList, error) {
@@ -118,6 +121,29 @@ func (r *MonsterTypeRepo) FindByID(ctx context.Context, monsterTypeID primitive.
 	return &monsterType, nil
 }
 
+func (r *MonsterTypeRepo) FindByIDs(ctx context.Context, monsterTypeIDs []primitive.ObjectID) ([]*domain.MonsterType, error) {
+	cursor, err := r.db.Find(ctx, bson.M{"_id": bson.M{"$in": monsterTypeIDs}})
+	if err != nil {
+		return nil, errors.Wrap(err, "db.Find")
+	}
+	defer cursor.Close(ctx)
+
+	monsterTypes := make([]*domain.MonsterType, 0, len(monsterTypeIDs))
+	for cursor.Next(ctx) {
+		var monsterType domain.MonsterType
+		if err := cursor.Decode(&monsterType); err != nil {
+			return nil, errors.Wrap(err, "cursor.Decode")
+		}
+		monsterTypes = append(monsterTypes, &monsterType)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, errors.Wrap(err, "cursor.Err")
+	}
+
+	return monsterTypes, nil
+}
+
 func (r *MonsterTypeRepo) FindByName(ctx context.Context, monsterTypeName string) (*domain.MonsterType, error) {
 	var monsterType domain.MonsterType
 
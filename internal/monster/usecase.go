@@ -2,6 +2,7 @@ package monster
 
 import (
 	"context"
+	"io"
 
 	"github.com/iamaul/go-pokedex/internal/domain"
 	"github.com/iamaul/go-pokedex/pkg/utils"
@@ -11,16 +12,19 @@ import (
 type MonsterTypeUsecase interface {
 	MonsterTypeCreate(ctx context.Context, monsterType *domain.MonsterType) (*domain.MonsterType, error)
 	MonsterTypeUpdate(ctx context.Context, monsterType *domain.MonsterTypeUpdate) (*domain.MonsterTypeUpdate, error)
-	MonsterTypeDeletion(ctx context.Context, monsterTypeID primitive.ObjectID) error
+	MonsterTypeDeletion(ctx context.Context, monsterTypeID primitive.ObjectID, version *int64) error
 	GetMonsterTypeList(ctx context.Context, pq *utils.PaginationQuery) (*domain.MonsterTypeList, error)
 	GetByID(ctx context.Context, monsterTypeID primitive.ObjectID) (*domain.MonsterType, error)
+	GetByIDs(ctx context.Context, monsterTypeIDs []primitive.ObjectID) ([]*domain.MonsterType, error)
 }
 
 type MonsterUsecase interface {
 	MonsterCreate(ctx context.Context, monster *domain.Monster) (*domain.Monster, error)
 	MonsterUpdate(ctx context.Context, monster *domain.MonsterUpdate) (*domain.MonsterUpdate, error)
-	MonsterDeletion(ctx context.Context, monsterID primitive.ObjectID) error
+	MonsterDeletion(ctx context.Context, monsterID primitive.ObjectID, version *int64) error
 	AttachMonsterType(ctx context.Context, monsterID, monsterTypeID primitive.ObjectID) error
+	MonsterImageUpload(ctx context.Context, monsterID primitive.ObjectID, image io.Reader) (*domain.Monster, error)
 	GetMonsterList(ctx context.Context, pq *utils.PaginationQuery) (*domain.MonsterList, error)
 	GetByID(ctx context.Context, monsterID primitive.ObjectID) (*domain.Monster, error)
+	GetByIDs(ctx context.Context, monsterIDs []primitive.ObjectID) ([]*domain.Monster, error)
 }
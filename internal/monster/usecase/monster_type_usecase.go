@@ -5,9 +5,11 @@ import (
 	"net/http"
 
 	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/audit"
 	"github.com/iamaul/go-pokedex/internal/domain"
 	"github.com/iamaul/go-pokedex/internal/monster"
 	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/events"
 	"github.com/iamaul/go-pokedex/pkg/logger"
 	"github.com/iamaul/go-pokedex/pkg/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -16,11 +18,13 @@ import (
 type MonsterTypeUsecase struct {
 	cfg             *config.Config
 	monsterTypeRepo monster.MonsterTypeRepository
+	auditUsecase    audit.Usecase
+	publisher       events.Publisher
 	logger          logger.Logger
 }
 
-func NewMonsterTypeUsecase(cfg *config.Config, monsterTypeRepo monster.MonsterTypeRepository, log logger.Logger) monster.MonsterTypeUsecase {
-	return &MonsterTypeUsecase{cfg: cfg, monsterTypeRepo: monsterTypeRepo, logger: log}
+func NewMonsterTypeUsecase(cfg *config.Config, monsterTypeRepo monster.MonsterTypeRepository, auditUsecase audit.Usecase, publisher events.Publisher, log logger.Logger) monster.MonsterTypeUsecase {
+	return &MonsterTypeUsecase{cfg: cfg, monsterTypeRepo: monsterTypeRepo, auditUsecase: auditUsecase, publisher: publisher, logger: log}
 }
 
 func (u *MonsterTypeUsecase) MonsterTypeCreate(ctx context.Context, monsterType *domain.MonsterType) (*domain.MonsterType, error) {
@@ -34,23 +38,57 @@ func (u *MonsterTypeUsecase) MonsterTypeCreate(ctx context.Context, monsterType
 		return nil, err
 	}
 
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditMonsterTypeCreated,
+		TargetType: domain.AuditTargetMonsterType,
+		TargetID:   createdMonsterType.ID.Hex(),
+	}, nil, createdMonsterType)
+
+	u.publisher.Publish(ctx, events.MonsterTypeCreated, createdMonsterType)
+
 	return createdMonsterType, nil
 }
 
 func (u *MonsterTypeUsecase) MonsterTypeUpdate(ctx context.Context, monsterType *domain.MonsterTypeUpdate) (*domain.MonsterTypeUpdate, error) {
+	before, err := u.monsterTypeRepo.FindByID(ctx, monsterType.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	updatedMonsterType, err := u.monsterTypeRepo.UpdateMonsterType(ctx, monsterType)
 	if err != nil {
 		return nil, err
 	}
 
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditMonsterTypeUpdated,
+		TargetType: domain.AuditTargetMonsterType,
+		TargetID:   monsterType.ID.Hex(),
+	}, before, updatedMonsterType)
+
+	u.publisher.Publish(ctx, events.MonsterTypeUpdated, updatedMonsterType)
+
 	return updatedMonsterType, nil
 }
 
-func (u *MonsterTypeUsecase) MonsterTypeDeletion(ctx context.Context, monsterTypeID primitive.ObjectID) error {
-	if err := u.monsterTypeRepo.DeleteMonsterType(ctx, monsterTypeID); err != nil {
+func (u *MonsterTypeUsecase) MonsterTypeDeletion(ctx context.Context, monsterTypeID primitive.ObjectID, version *int64) error {
+	before, err := u.monsterTypeRepo.FindByID(ctx, monsterTypeID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.monsterTypeRepo.DeleteMonsterType(ctx, monsterTypeID, version); err != nil {
 		return err
 	}
 
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditMonsterTypeDeleted,
+		TargetType: domain.AuditTargetMonsterType,
+		TargetID:   monsterTypeID.Hex(),
+	}, before, nil)
+
+	u.publisher.Publish(ctx, events.MonsterTypeDeleted, &domain.DeletedEntity{ID: monsterTypeID})
+
 	return nil
 }
 
@@ -66,3 +104,7 @@ func (u *MonsterTypeUsecase) GetByID(ctx context.Context, monsterTypeID primitiv
 
 	return monsterType, nil
 }
+
+func (u *MonsterTypeUsecase) GetByIDs(ctx context.Context, monsterTypeIDs []primitive.ObjectID) ([]*domain.MonsterType, error) {
+	return u.monsterTypeRepo.FindByIDs(ctx, monsterTypeIDs)
+}
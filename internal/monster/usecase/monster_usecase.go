@@ -1,15 +1,24 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/audit"
 	"github.com/iamaul/go-pokedex/internal/domain"
 	"github.com/iamaul/go-pokedex/internal/monster"
 	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/events"
+	"github.com/iamaul/go-pokedex/pkg/imaging"
 	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/storage"
 	"github.com/iamaul/go-pokedex/pkg/utils"
+	"github.com/pkg/errors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -17,11 +26,14 @@ type MonsterUsecase struct {
 	cfg             *config.Config
 	monsterRepo     monster.MonsterRepository
 	monsterTypeRepo monster.MonsterTypeRepository
+	storage         storage.Storage
+	auditUsecase    audit.Usecase
+	publisher       events.Publisher
 	logger          logger.Logger
 }
 
-func NewMonsterUsecase(cfg *config.Config, monsterRepo monster.MonsterRepository, monsterTypeRepo monster.MonsterTypeRepository, log logger.Logger) monster.MonsterUsecase {
-	return &MonsterUsecase{cfg: cfg, monsterRepo: monsterRepo, monsterTypeRepo: monsterTypeRepo, logger: log}
+func NewMonsterUsecase(cfg *config.Config, monsterRepo monster.MonsterRepository, monsterTypeRepo monster.MonsterTypeRepository, storage storage.Storage, auditUsecase audit.Usecase, publisher events.Publisher, log logger.Logger) monster.MonsterUsecase {
+	return &MonsterUsecase{cfg: cfg, monsterRepo: monsterRepo, monsterTypeRepo: monsterTypeRepo, storage: storage, auditUsecase: auditUsecase, publisher: publisher, logger: log}
 }
 
 func (u *MonsterUsecase) MonsterCreate(ctx context.Context, monster *domain.Monster) (*domain.Monster, error) {
@@ -35,23 +47,59 @@ func (u *MonsterUsecase) MonsterCreate(ctx context.Context, monster *domain.Mons
 		return nil, err
 	}
 
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditMonsterCreated,
+		TargetType: domain.AuditTargetMonster,
+		TargetID:   createdMonsterType.ID.Hex(),
+	}, nil, createdMonsterType)
+
+	u.publisher.Publish(ctx, events.MonsterCreated, createdMonsterType)
+
 	return createdMonsterType, nil
 }
 
 func (u *MonsterUsecase) MonsterUpdate(ctx context.Context, monster *domain.MonsterUpdate) (*domain.MonsterUpdate, error) {
+	before, err := u.monsterRepo.FindByID(ctx, monster.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	updatedMonster, err := u.monsterRepo.UpdateMonster(ctx, monster)
 	if err != nil {
 		return nil, err
 	}
 
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditMonsterUpdated,
+		TargetType: domain.AuditTargetMonster,
+		TargetID:   monster.ID.Hex(),
+	}, before, updatedMonster)
+
+	u.publisher.Publish(ctx, events.MonsterUpdated, updatedMonster)
+
 	return updatedMonster, nil
 }
 
-func (u *MonsterUsecase) MonsterDeletion(ctx context.Context, monsterID primitive.ObjectID) error {
-	if err := u.monsterRepo.DeleteMonster(ctx, monsterID); err != nil {
+func (u *MonsterUsecase) MonsterDeletion(ctx context.Context, monsterID primitive.ObjectID, version *int64) error {
+	monster, err := u.monsterRepo.FindByID(ctx, monsterID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.monsterRepo.DeleteMonster(ctx, monsterID, version); err != nil {
 		return err
 	}
 
+	u.deleteBlobs(ctx, monster.ImageKeys())
+
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditMonsterDeleted,
+		TargetType: domain.AuditTargetMonster,
+		TargetID:   monsterID.Hex(),
+	}, monster, nil)
+
+	u.publisher.Publish(ctx, events.MonsterDeleted, &domain.DeletedEntity{ID: monsterID})
+
 	return nil
 }
 
@@ -68,15 +116,139 @@ func (u *MonsterUsecase) GetByID(ctx context.Context, monsterID primitive.Object
 	return monster, nil
 }
 
+func (u *MonsterUsecase) GetByIDs(ctx context.Context, monsterIDs []primitive.ObjectID) ([]*domain.Monster, error) {
+	return u.monsterRepo.FindByIDs(ctx, monsterIDs)
+}
+
 func (u *MonsterUsecase) AttachMonsterType(ctx context.Context, monsterID primitive.ObjectID, monsterTypeID primitive.ObjectID) error {
 	monsterType, err := u.monsterTypeRepo.FindByID(ctx, monsterTypeID)
 	if err != nil {
 		return err
 	}
 
+	before, err := u.monsterRepo.FindByID(ctx, monsterID)
+	if err != nil {
+		return err
+	}
+
 	if err := u.monsterRepo.AddMonsterType(ctx, monsterID, monsterType.ID); err != nil {
 		return err
 	}
 
+	monster, err := u.monsterRepo.FindByID(ctx, monsterID)
+	if err != nil {
+		return err
+	}
+
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditMonsterUpdated,
+		TargetType: domain.AuditTargetMonster,
+		TargetID:   monsterID.Hex(),
+	}, before, monster)
+
+	u.publisher.Publish(ctx, events.MonsterUpdated, monster)
+
 	return nil
 }
+
+func (u *MonsterUsecase) MonsterImageUpload(ctx context.Context, monsterID primitive.ObjectID, image io.Reader) (*domain.Monster, error) {
+	monster, err := u.monsterRepo.FindByID(ctx, monsterID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(image, u.cfg.Storage.MaxImageSize+1))
+	if err != nil {
+		return nil, httpErr.NewBadRequestError(errors.Wrap(err, "MonsterUsecase.MonsterImageUpload.ReadAll"))
+	}
+	if int64(len(data)) > u.cfg.Storage.MaxImageSize {
+		return nil, httpErr.NewRestError(http.StatusRequestEntityTooLarge, "image too large", nil)
+	}
+
+	contentType, ext, ok := imaging.DetectContentType(data)
+	if !ok {
+		return nil, httpErr.NewRestError(http.StatusUnsupportedMediaType, httpErr.NotAllowedImageHeader.Error(), contentType)
+	}
+
+	// Only the header is read here, the pixels are decoded once the dimensions are known to be sane
+	imgConfig, err := imaging.DecodeConfig(data)
+	if err != nil {
+		return nil, httpErr.NewRestError(http.StatusUnsupportedMediaType, httpErr.NotAllowedImageHeader.Error(), err)
+	}
+	if int64(imgConfig.Width)*int64(imgConfig.Height) > utils.MaxImagePixels(u.cfg) {
+		return nil, httpErr.NewRestError(http.StatusRequestEntityTooLarge, "image dimensions too large", nil)
+	}
+
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return nil, httpErr.NewRestError(http.StatusUnsupportedMediaType, httpErr.NotAllowedImageHeader.Error(), err)
+	}
+
+	before := *monster
+	previousKeys := monster.ImageKeys()
+	prefix := fmt.Sprintf("monsters/%s/%s", monster.ID.Hex(), primitive.NewObjectID().Hex())
+
+	storedKeys := make([]string, 0, len(u.cfg.Storage.ThumbnailWidths)+1)
+	put := func(key string, body []byte, contentType string) error {
+		if err := u.storage.Put(ctx, key, bytes.NewReader(body), int64(len(body)), contentType); err != nil {
+			return err
+		}
+		storedKeys = append(storedKeys, key)
+		return nil
+	}
+
+	monster.ImageKey = fmt.Sprintf("%s/original.%s", prefix, ext)
+	if err := put(monster.ImageKey, data, contentType); err != nil {
+		u.deleteBlobs(ctx, storedKeys)
+		return nil, httpErr.NewInternalServerError(errors.Wrap(err, "MonsterUsecase.MonsterImageUpload.Put"))
+	}
+	monster.ImageUrl = u.storage.URL(monster.ImageKey)
+
+	monster.Thumbnails = make([]domain.MonsterThumbnail, 0, len(u.cfg.Storage.ThumbnailWidths))
+	for _, width := range u.cfg.Storage.ThumbnailWidths {
+		thumb, thumbType, thumbExt, err := imaging.Encode(imaging.Thumbnail(img, width), contentType)
+		if err != nil {
+			u.deleteBlobs(ctx, storedKeys)
+			return nil, httpErr.NewInternalServerError(errors.Wrap(err, "MonsterUsecase.MonsterImageUpload.Encode"))
+		}
+
+		key := fmt.Sprintf("%s/thumb_%d.%s", prefix, width, thumbExt)
+		if err := put(key, thumb, thumbType); err != nil {
+			u.deleteBlobs(ctx, storedKeys)
+			return nil, httpErr.NewInternalServerError(errors.Wrap(err, "MonsterUsecase.MonsterImageUpload.Put"))
+		}
+
+		monster.Thumbnails = append(monster.Thumbnails, domain.MonsterThumbnail{
+			Width: width,
+			Url:   u.storage.URL(key),
+			Key:   key,
+		})
+	}
+
+	monster.UpdatedAt = time.Now()
+	if err := u.monsterRepo.UpdateMonsterImage(ctx, monster); err != nil {
+		u.deleteBlobs(ctx, storedKeys)
+		return nil, err
+	}
+
+	u.deleteBlobs(ctx, previousKeys)
+
+	u.auditUsecase.Record(ctx, &domain.AuditEntry{
+		Action:     domain.AuditMonsterUpdated,
+		TargetType: domain.AuditTargetMonster,
+		TargetID:   monster.ID.Hex(),
+	}, &before, monster)
+
+	u.publisher.Publish(ctx, events.MonsterUpdated, monster)
+
+	return monster, nil
+}
+
+// Best effort blob cleanup, orphaned blobs are logged rather than failing the request
+func (u *MonsterUsecase) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := u.storage.Delete(ctx, key); err != nil {
+			u.logger.Errorf("MonsterUsecase.deleteBlobs key: %s, error: %s", key, err)
+		}
+	}
+}
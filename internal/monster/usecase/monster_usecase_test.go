@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/audit"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/monster"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+)
+
+type fakeMonsterRepo struct {
+	monster.MonsterRepository
+	monster *domain.Monster
+}
+
+func (r *fakeMonsterRepo) FindByID(ctx context.Context, monsterID primitive.ObjectID) (*domain.Monster, error) {
+	found := *r.monster
+	return &found, nil
+}
+
+func (r *fakeMonsterRepo) UpdateMonsterImage(ctx context.Context, m *domain.Monster) error {
+	r.monster = m
+	return nil
+}
+
+type fakeStorage struct {
+	keys []string
+}
+
+func (s *fakeStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+func (s *fakeStorage) Delete(ctx context.Context, key string) error {
+	return nil
+}
+
+func (s *fakeStorage) URL(key string) string {
+	return "/uploads/" + key
+}
+
+type fakeAuditUsecase struct {
+	audit.Usecase
+}
+
+func (fakeAuditUsecase) Record(ctx context.Context, entry *domain.AuditEntry, before, after interface{}) {
+}
+
+type fakePublisher struct{}
+
+func (fakePublisher) Publish(ctx context.Context, eventType string, data interface{}) {}
+
+// PNG of the given size, the header is rewritten so huge images stay small files
+func pngWithSize(t *testing.T, width, height uint32) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	// Signature, then the IHDR length and type, width and height lead its data
+	ihdr := data[8+8 : 8+8+13]
+	binary.BigEndian.PutUint32(ihdr[0:4], width)
+	binary.BigEndian.PutUint32(ihdr[4:8], height)
+	binary.BigEndian.PutUint32(data[8+8+13:], crc32.ChecksumIEEE(data[8+4:8+8+13]))
+
+	return data
+}
+
+func TestMonsterImageUpload(t *testing.T) {
+	tests := []struct {
+		name   string
+		image  []byte
+		status int
+	}{
+		{name: "accepts a small image", image: pngWithSize(t, 1, 1)},
+		{name: "refuses more pixels than allowed", image: pngWithSize(t, 5000, 5000), status: http.StatusRequestEntityTooLarge},
+		{name: "refuses a decompression bomb", image: pngWithSize(t, 1<<20, 1<<20), status: http.StatusRequestEntityTooLarge},
+		{name: "refuses what is not an image", image: []byte("not an image at all"), status: http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Logger:  config.Logger{Level: "fatal"},
+				Storage: config.Storage{MaxImageSize: 1 << 20, MaxImagePixels: 4000 * 4000},
+			}
+			log := logger.NewLogger(cfg)
+			log.InitLogger()
+
+			repo := &fakeMonsterRepo{monster: &domain.Monster{ID: primitive.NewObjectID(), Name: "pikachu"}}
+			storage := &fakeStorage{}
+			u := NewMonsterUsecase(cfg, repo, nil, storage, fakeAuditUsecase{}, fakePublisher{}, log)
+
+			_, err := u.MonsterImageUpload(context.Background(), repo.monster.ID, bytes.NewReader(tt.image))
+			if tt.status == 0 {
+				if err != nil {
+					t.Fatalf("MonsterImageUpload: %v", err)
+				}
+				if len(storage.keys) == 0 {
+					t.Error("image was not stored")
+				}
+				return
+			}
+
+			restErr, ok := err.(httpErr.RestErr)
+			if !ok || restErr.Status() != tt.status {
+				t.Fatalf("got %v, want status %d", err, tt.status)
+			}
+			if len(storage.keys) != 0 {
+				t.Errorf("stored %v for a refused image", storage.keys)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	authGrpc "github.com/iamaul/go-pokedex/internal/auth/delivery/grpc"
+	"github.com/iamaul/go-pokedex/internal/interceptors"
+	monsterGrpc "github.com/iamaul/go-pokedex/internal/monster/delivery/grpc"
+)
+
+// Map gRPC Services
+func (s *Server) MapGrpcServices() *grpc.Server {
+	im := interceptors.NewInterceptorManager(s.authUsecase, s.cfg, s.logger)
+
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(im.Logger, im.ClientIP, im.Auth))
+
+	authServer := authGrpc.NewAuthServer(s.cfg, s.authUsecase, s.logger)
+	monsterServer := monsterGrpc.NewMonsterServer(s.cfg, s.monsterTypeUsecase, s.monsterUsecase, s.logger)
+
+	authGrpc.AuthRoutes(server, authServer, im)
+	monsterGrpc.MonsterRoutes(server, monsterServer, im)
+
+	if s.cfg.Server.Mode != "production" {
+		reflection.Register(server)
+	}
+
+	return server
+}
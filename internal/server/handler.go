@@ -1,52 +1,229 @@
 package server
 
 import (
+	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	echoSwagger "github.com/swaggo/echo-swagger"
-	"github.com/swaggo/swag/example/basic/docs"
 
+	"github.com/iamaul/go-pokedex/cmd/app/docs"
+	auditHttp "github.com/iamaul/go-pokedex/internal/audit/delivery/http"
+	auditRepository "github.com/iamaul/go-pokedex/internal/audit/repository"
+	auditUseCase "github.com/iamaul/go-pokedex/internal/audit/usecase"
+	"github.com/iamaul/go-pokedex/internal/auth"
 	authHttp "github.com/iamaul/go-pokedex/internal/auth/delivery/http"
+	authHttpV2 "github.com/iamaul/go-pokedex/internal/auth/delivery/http/v2"
 	authRepository "github.com/iamaul/go-pokedex/internal/auth/repository"
 	authUseCase "github.com/iamaul/go-pokedex/internal/auth/usecase"
+	"github.com/iamaul/go-pokedex/internal/changestream"
+	"github.com/iamaul/go-pokedex/internal/graph"
 	monsterHttp "github.com/iamaul/go-pokedex/internal/monster/delivery/http"
+	monsterHttpV2 "github.com/iamaul/go-pokedex/internal/monster/delivery/http/v2"
 	monsterRepository "github.com/iamaul/go-pokedex/internal/monster/repository"
 	monsterUseCase "github.com/iamaul/go-pokedex/internal/monster/usecase"
+	streamHttp "github.com/iamaul/go-pokedex/internal/stream/delivery/http"
+	webhookHttp "github.com/iamaul/go-pokedex/internal/webhook/delivery/http"
+	webhookRepository "github.com/iamaul/go-pokedex/internal/webhook/repository"
+	webhookUseCase "github.com/iamaul/go-pokedex/internal/webhook/usecase"
 
 	apiMiddlewares "github.com/iamaul/go-pokedex/internal/middleware"
 	"github.com/iamaul/go-pokedex/pkg/csrf"
+	"github.com/iamaul/go-pokedex/pkg/events"
+	"github.com/iamaul/go-pokedex/pkg/keyring"
+	"github.com/iamaul/go-pokedex/pkg/notifier"
+	"github.com/iamaul/go-pokedex/pkg/oidc"
+	"github.com/iamaul/go-pokedex/pkg/openapi"
+	"github.com/iamaul/go-pokedex/pkg/storage"
 	"github.com/iamaul/go-pokedex/pkg/utils"
 )
 
-// Map Server Handlers
-func (s *Server) MapRouteHandlers(e *echo.Echo) error {
+// Map Server Usecases shared by the HTTP and gRPC deliveries
+func (s *Server) MapUsecases() error {
 	// Repositories
 	authRepo := authRepository.NewAuthRepo(s.db)
+	refreshTokenRepo := authRepository.NewRefreshTokenRepo(s.db)
+	tokenDenylistRepo := authRepository.NewTokenDenylistRepo(s.db)
+	passwordResetRepo := authRepository.NewPasswordResetRepo(s.db)
+	roleRepo := authRepository.NewRoleRepo(s.db)
+	inviteRepo := authRepository.NewInviteRepo(s.db)
+	apiKeyRepo := authRepository.NewAPIKeyRepo(s.db)
+	oidcStateRepo := authRepository.NewOIDCStateRepo(s.db)
+	mfaRepo := authRepository.NewMFARepo(s.db)
+	mfaChallengeRepo := authRepository.NewMFAChallengeRepo(s.db)
+	loginLockRepo := authRepository.NewLoginLockRepo(s.db)
+
+	// Sessions are kept in memory for single instance deployments, they end with the process
+	var sessionRepo auth.SessionRepository
+	if s.cfg.Session.Store == "memory" {
+		sessionRepo = authRepository.NewSessionMemoryRepo()
+	} else {
+		sessionRepo = authRepository.NewSessionRepo(s.db)
+	}
 	monsterTypeRepo := monsterRepository.NewMonsterTypeRepo(s.db)
 	monsterRepo := monsterRepository.NewMonsterRepo(s.db)
+	webhookRepo := webhookRepository.NewWebhookRepo(s.db)
+	auditRepo := auditRepository.NewAuditRepo(s.db)
+
+	// Revoked and reset tokens and invites expire from their collections through TTL indexes
+	setupCtx, cancel := context.WithTimeout(context.Background(), time.Second*s.cfg.Server.CtxDefaultTimeout)
+	defer cancel()
+	if err := authRepo.CreateIndexes(setupCtx); err != nil {
+		return err
+	}
+	if err := tokenDenylistRepo.CreateIndexes(setupCtx); err != nil {
+		return err
+	}
+	if err := passwordResetRepo.CreateIndexes(setupCtx); err != nil {
+		return err
+	}
+	if err := roleRepo.CreateIndexes(setupCtx); err != nil {
+		return err
+	}
+	if err := inviteRepo.CreateIndexes(setupCtx); err != nil {
+		return err
+	}
+	if err := apiKeyRepo.CreateIndexes(setupCtx); err != nil {
+		return err
+	}
+	if err := oidcStateRepo.CreateIndexes(setupCtx); err != nil {
+		return err
+	}
+	if err := mfaRepo.CreateIndexes(setupCtx); err != nil {
+		return err
+	}
+	if err := mfaChallengeRepo.CreateIndexes(setupCtx); err != nil {
+		return err
+	}
+	if err := loginLockRepo.CreateIndexes(setupCtx); err != nil {
+		return err
+	}
+	if err := sessionRepo.CreateIndexes(setupCtx); err != nil {
+		return err
+	}
+	if err := auditRepo.CreateIndexes(setupCtx); err != nil {
+		return err
+	}
+
+	// Password reset tokens are delivered through the notifier
+	userNotifier, err := notifier.NewNotifier(s.cfg, s.logger)
+	if err != nil {
+		return err
+	}
+
+	if err := utils.ValidateSecrets(s.cfg); err != nil {
+		return err
+	}
+
+	// Access tokens are signed with the active key of the ring, the public keys are served as JWKS
+	s.keyRing, err = keyring.NewKeyRing(s.cfg.JWT.Keys, s.cfg.Server.JwtSecretKey, utils.JWTKeyOverlap(s.cfg))
+	if err != nil {
+		return err
+	}
+
+	// Storage
+	blobStorage, err := storage.NewStorage(s.cfg)
+	if err != nil {
+		return err
+	}
+
+	// Events are streamed to connected clients and queued for webhooks
+	s.eventBus = events.NewBus(s.cfg.Events.BufferSize)
+	s.webhookUsecase = webhookUseCase.NewWebhookUsecase(s.cfg, webhookRepo, s.logger)
+	s.webhookDispatcher = webhookUseCase.NewDispatcher(s.cfg, webhookRepo, s.logger)
+	var publisher events.Publisher = events.Publishers{s.eventBus, s.webhookUsecase}
+
+	// Writes made outside the API are picked up from the change streams, which then own
+	// the catalog and user events so each write is only published once
+	if s.cfg.Events.ChangeStreams {
+		s.changeStreamWatcher = changestream.NewWatcher(s.db, publisher, s.logger)
+		publisher = s.changeStreamWatcher
+	}
 
 	// Usecases
-	authUsecase := authUseCase.NewAuthUsecase(s.cfg, authRepo, monsterRepo, s.logger)
-	monsterTypeUsecase := monsterUseCase.NewMonsterTypeUsecase(s.cfg, monsterTypeRepo, s.logger)
-	monsterUsecase := monsterUseCase.NewMonsterUsecase(s.cfg, monsterRepo, monsterTypeRepo, s.logger)
+	s.auditUsecase = auditUseCase.NewAuditUsecase(s.cfg, auditRepo, s.logger)
+	s.authUsecase = authUseCase.NewAuthUsecase(s.cfg, authUseCase.Repositories{
+		Auth:         authRepo,
+		RefreshToken: refreshTokenRepo,
+		Denylist:     tokenDenylistRepo,
+		Reset:        passwordResetRepo,
+		Role:         roleRepo,
+		Invite:       inviteRepo,
+		APIKey:       apiKeyRepo,
+		OIDCState:    oidcStateRepo,
+		MFA:          mfaRepo,
+		MFAChallenge: mfaChallengeRepo,
+		LoginLock:    loginLockRepo,
+		Session:      sessionRepo,
+		Monster:      monsterRepo,
+	}, authUseCase.Services{
+		Notifier:      userNotifier,
+		OIDCProviders: oidc.NewProviders(s.cfg),
+		KeyRing:       s.keyRing,
+		Audit:         s.auditUsecase,
+		Publisher:     publisher,
+	}, s.logger)
+	s.monsterTypeUsecase = monsterUseCase.NewMonsterTypeUsecase(s.cfg, monsterTypeRepo, s.auditUsecase, publisher, s.logger)
+	s.monsterUsecase = monsterUseCase.NewMonsterUsecase(s.cfg, monsterRepo, monsterTypeRepo, blobStorage, s.auditUsecase, publisher, s.logger)
+
+	// Users with the admin role keep the access they had before roles were stored
+	return s.authUsecase.RoleBootstrap(setupCtx)
+}
+
+// Map Server Handlers
+func (s *Server) MapRouteHandlers(e *echo.Echo) error {
+	authUsecase := s.authUsecase
 
 	// Handlers
 	authHandler := authHttp.NewAuthHandler(s.cfg, authUsecase, s.logger)
-	monsterTypeHandler := monsterHttp.NewMonsterHandler(s.cfg, monsterTypeUsecase, monsterUsecase, s.logger)
+	monsterTypeHandler := monsterHttp.NewMonsterHandler(s.cfg, s.monsterTypeUsecase, s.monsterUsecase, s.logger)
+	authHandlerV2 := authHttpV2.NewAuthHandler(s.cfg, authUsecase, s.logger)
+	monsterHandlerV2 := monsterHttpV2.NewMonsterHandler(s.cfg, s.monsterTypeUsecase, s.monsterUsecase, s.logger)
+	streamHandler := streamHttp.NewStreamHandler(s.cfg, s.eventBus, authUsecase, s.logger)
+	webhookHandler := webhookHttp.NewWebhookHandler(s.cfg, s.webhookUsecase, authUsecase, s.logger)
+	auditHandler := auditHttp.NewAuditHandler(s.cfg, s.auditUsecase, s.logger)
 
 	mw := apiMiddlewares.NewMiddlewareManager(authUsecase, s.cfg, []string{"*"}, s.logger)
 
+	// Forwarded headers can be set by anyone unless a proxy in front overwrites them
+	if s.cfg.Server.TrustProxy {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	} else {
+		e.IPExtractor = echo.ExtractIPDirect()
+	}
+
+	e.Pre(mw.APIVersionMiddleware)
 	e.Use(mw.RequestLoggerMiddleware)
 
 	docs.SwaggerInfo.Title = "go-pokedex API Docs"
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
+	// OpenAPI 3 description converted from the generated Swagger 2.0 docs
+	spec, err := openapi.Load([]byte(docs.SwaggerInfo.ReadDoc()))
+	if err != nil {
+		return err
+	}
+	e.GET("/openapi.json", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, spec)
+	})
+
+	// Verifiers refetch the keys when a token names one they do not know
+	e.GET("/.well-known/jwks.json", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+		return c.JSON(http.StatusOK, s.keyRing.JWKS())
+	})
+
+	if storage.IsLocal(s.cfg) {
+		e.Static("/uploads", s.cfg.Storage.LocalPath)
+	}
+
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"*"},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderXRequestID, csrf.CSRFHeader},
+		AllowOrigins:  []string{"*"},
+		AllowHeaders:  []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderXRequestID, csrf.CSRFHeader, utils.HeaderAPIKey, utils.HeaderIfMatch, utils.HeaderIfNoneMatch},
+		ExposeHeaders: []string{"Deprecation", "Sunset", "Link", utils.HeaderETag},
 	}))
 	e.Use(middleware.RecoverWithConfig(middleware.RecoverConfig{
 		StackSize:         1 << 10, // 1 KB
@@ -54,11 +231,13 @@ func (s *Server) MapRouteHandlers(e *echo.Echo) error {
 		DisableStackAll:   true,
 	}))
 	e.Use(middleware.RequestID())
+	e.Use(mw.ClientIPMiddleware)
+	e.Use(mw.SessionMiddleware)
 
 	e.Use(middleware.GzipWithConfig(middleware.GzipConfig{
 		Level: 5,
 		Skipper: func(c echo.Context) bool {
-			return strings.Contains(c.Request().URL.Path, "swagger")
+			return strings.Contains(c.Request().URL.Path, "swagger") || strings.Contains(c.Request().URL.Path, "/events")
 		},
 	}))
 	e.Use(middleware.Secure())
@@ -67,19 +246,41 @@ func (s *Server) MapRouteHandlers(e *echo.Echo) error {
 		e.Use(mw.DebugMiddleware)
 	}
 
-	v1 := e.Group("/api/v1")
+	health := func(c echo.Context) error {
+		s.logger.Infof("Health check requestId: %s", utils.GetRequestID(c))
+		return c.JSON(http.StatusOK, map[string]string{"status": "OK"})
+	}
 
-	health := v1.Group("/health")
-	authGroup := v1.Group("/auth")
-	monsterGroup := v1.Group("/monster")
+	// v1 is kept for existing clients until its sunset, v2 serves the reshaped auth and monster representations
+	v1 := e.Group("/api/v1", mw.DeprecationMiddleware(s.cfg.API.V1Deprecation, s.cfg.API.V1Sunset, "/api/v2"))
+	if s.cfg.Server.OpenAPIValidation {
+		validator, err := openapi.NewValidator(spec)
+		if err != nil {
+			return err
+		}
+		v1.Use(mw.OpenAPIValidatorMiddleware(validator))
+	}
+	v2 := e.Group("/api/v2")
 
-	authHttp.AuthRoutes(authGroup, authHandler, authUsecase, s.cfg, mw)
-	monsterHttp.MonsterRoutes(monsterGroup, monsterTypeHandler, authUsecase, s.cfg, mw)
+	authHttp.AuthRoutes(v1.Group("/auth"), authHandler, authUsecase, s.cfg, mw)
+	monsterHttp.MonsterRoutes(v1.Group("/monster"), monsterTypeHandler, authUsecase, s.cfg, mw)
+	authHttp.AuthRoutes(v2.Group("/auth"), authHandlerV2, authUsecase, s.cfg, mw)
+	monsterHttp.MonsterRoutes(v2.Group("/monster"), monsterHandlerV2, authUsecase, s.cfg, mw)
 
-	health.GET("", func(c echo.Context) error {
-		s.logger.Infof("Health check requestId: %s", utils.GetRequestID(c))
-		return c.JSON(http.StatusOK, map[string]string{"status": "OK"})
-	})
+	// Unchanged between versions
+	for _, version := range []*echo.Group{v1, v2} {
+		streamHttp.StreamRoutes(version.Group("/events"), streamHandler, authUsecase, s.cfg, mw)
+		webhookHttp.WebhookRoutes(version.Group("/webhook"), webhookHandler, authUsecase, s.cfg, mw)
+		auditHttp.AuditRoutes(version.Group("/audit"), auditHandler, authUsecase, s.cfg, mw)
+		version.GET("/health", health)
+	}
+
+	resolver := graph.NewResolver(s.cfg, authUsecase, s.monsterTypeUsecase, s.monsterUsecase, s.logger)
+	schema, err := graph.NewSchema(resolver)
+	if err != nil {
+		return err
+	}
+	e.POST("/graphql", resolver.Handler(schema), mw.AuthJWTMiddleware(authUsecase, s.cfg))
 
 	return nil
 }
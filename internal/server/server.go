@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
@@ -13,6 +14,14 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/audit"
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/changestream"
+	"github.com/iamaul/go-pokedex/internal/monster"
+	"github.com/iamaul/go-pokedex/internal/webhook"
+	webhookUseCase "github.com/iamaul/go-pokedex/internal/webhook/usecase"
+	"github.com/iamaul/go-pokedex/pkg/events"
+	"github.com/iamaul/go-pokedex/pkg/keyring"
 	"github.com/iamaul/go-pokedex/pkg/logger"
 )
 
@@ -26,6 +35,17 @@ type Server struct {
 	cfg    *config.Config
 	db     *mongo.Database
 	logger logger.Logger
+
+	auditUsecase       audit.Usecase
+	authUsecase        auth.Usecase
+	monsterTypeUsecase monster.MonsterTypeUsecase
+	monsterUsecase     monster.MonsterUsecase
+	keyRing            *keyring.KeyRing
+	eventBus           *events.Bus
+	webhookUsecase     webhook.Usecase
+	webhookDispatcher  *webhookUseCase.Dispatcher
+
+	changeStreamWatcher *changestream.Watcher
 }
 
 func NewServer(cfg *config.Config, db *mongo.Database, logger logger.Logger) *Server {
@@ -33,6 +53,31 @@ func NewServer(cfg *config.Config, db *mongo.Database, logger logger.Logger) *Se
 }
 
 func (s *Server) Run() error {
+	if err := s.MapUsecases(); err != nil {
+		return err
+	}
+
+	dispatcherCtx, stopDispatcher := context.WithCancel(context.Background())
+	defer stopDispatcher()
+	go s.webhookDispatcher.Run(dispatcherCtx)
+	if s.changeStreamWatcher != nil {
+		go s.changeStreamWatcher.Run(dispatcherCtx)
+	}
+
+	grpcServer := s.MapGrpcServices()
+	grpcListener, err := net.Listen("tcp", s.cfg.Server.GrpcPort)
+	if err != nil {
+		return err
+	}
+	defer grpcServer.GracefulStop()
+
+	go func() {
+		s.logger.Infof("gRPC server is listening on port: %s", s.cfg.Server.GrpcPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			s.logger.Fatalf("Error starting gRPC server: ", err)
+		}
+	}()
+
 	if s.cfg.Server.SSL {
 		if err := s.MapRouteHandlers(s.echo); err != nil {
 			return err
@@ -0,0 +1,10 @@
+package stream
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+type DeliveryHandlers interface {
+	Events() echo.HandlerFunc
+	WebSocket() echo.HandlerFunc
+}
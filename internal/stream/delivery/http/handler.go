@@ -0,0 +1,292 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/stream"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/events"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/render"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+)
+
+const (
+	lastEventIDHeader = "Last-Event-ID"
+	writeWait         = 10 * time.Second
+)
+
+type StreamHandler struct {
+	cfg         *config.Config
+	bus         *events.Bus
+	authUsecase auth.Usecase
+	upgrader    websocket.Upgrader
+	logger      logger.Logger
+}
+
+func NewStreamHandler(cfg *config.Config, bus *events.Bus, authUsecase auth.Usecase, log logger.Logger) stream.DeliveryHandlers {
+	return &StreamHandler{
+		cfg:         cfg,
+		bus:         bus,
+		authUsecase: authUsecase,
+		upgrader: websocket.Upgrader{
+			// Cross origin connections must carry a bearer token, cookie sessions stay same origin
+			CheckOrigin: func(r *http.Request) bool {
+				return r.Header.Get(echo.HeaderAuthorization) != "" || sameOrigin(r)
+			},
+		},
+		logger: log,
+	}
+}
+
+// Events godoc
+// @Summary Stream catalog and catch events
+// @Description Server-Sent Events stream, resumable with the Last-Event-ID header. The user topic needs the user:read permission.
+// @Tags Events
+// @Param topics query string false "comma separated topics: monster, monster_type, user"
+// @Produce text/event-stream
+// @Success 200 {object} events.Event
+// @Router /events [get]
+func (h *StreamHandler) Events() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		topics, err := h.subscriptionTopics(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		lastEventID := c.Request().Header.Get(lastEventIDHeader)
+		if lastEventID == "" {
+			lastEventID = c.QueryParam("last_event_id")
+		}
+
+		// The stream outlives the server write timeout
+		if err := http.NewResponseController(c.Response().Writer).SetWriteDeadline(time.Time{}); err != nil {
+			h.logger.Errorf("StreamHandler.Events SetWriteDeadline RequestID: %s, Error: %s", utils.GetRequestID(c), err)
+		}
+
+		sub, replay := h.bus.Subscribe(topics, lastEventID)
+		defer sub.Close()
+
+		res := c.Response()
+		res.Header().Set(echo.HeaderContentType, "text/event-stream")
+		res.Header().Set(echo.HeaderCacheControl, "no-cache")
+		res.Header().Set(echo.HeaderConnection, "keep-alive")
+		res.Header().Set("X-Accel-Buffering", "no")
+		res.WriteHeader(http.StatusOK)
+
+		for _, event := range replay {
+			if err := writeSSE(res, event); err != nil {
+				return nil
+			}
+		}
+		res.Flush()
+
+		heartbeat := time.NewTicker(h.heartbeatInterval())
+		defer heartbeat.Stop()
+
+		ctx := c.Request().Context()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case event, ok := <-sub.C:
+				if !ok {
+					// Dropped for falling behind, the client resumes with Last-Event-ID
+					return nil
+				}
+				if err := writeSSE(res, event); err != nil {
+					return nil
+				}
+				res.Flush()
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+					return nil
+				}
+				res.Flush()
+			}
+		}
+	}
+}
+
+// WebSocket godoc
+// @Summary Stream catalog and catch events over WebSocket
+// @Description JSON event per message, resumable with the last_event_id query parameter. The user topic needs the user:read permission.
+// @Tags Events
+// @Param topics query string false "comma separated topics: monster, monster_type, user"
+// @Param last_event_id query string false "resume after this event id"
+// @Success 101 {object} events.Event
+// @Router /events/ws [get]
+func (h *StreamHandler) WebSocket() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		topics, err := h.subscriptionTopics(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		lastEventID := c.QueryParam("last_event_id")
+		if lastEventID == "" {
+			lastEventID = c.Request().Header.Get(lastEventIDHeader)
+		}
+
+		conn, err := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
+		if err != nil {
+			h.logger.Errorf("StreamHandler.WebSocket Upgrade RequestID: %s, Error: %s", utils.GetRequestID(c), err)
+			return nil
+		}
+		defer conn.Close()
+
+		sub, replay := h.bus.Subscribe(topics, lastEventID)
+		defer sub.Close()
+
+		interval := h.heartbeatInterval()
+		closed := make(chan struct{})
+
+		// Clients only send control frames, the read loop keeps pongs and close frames flowing
+		conn.SetReadLimit(512)
+		_ = conn.SetReadDeadline(time.Now().Add(2 * interval))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(2 * interval))
+		})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		write := func(event events.Event) error {
+			_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+			return conn.WriteJSON(event)
+		}
+
+		for _, event := range replay {
+			if err := write(event); err != nil {
+				return nil
+			}
+		}
+
+		heartbeat := time.NewTicker(interval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-closed:
+				return nil
+			case event, ok := <-sub.C:
+				if !ok {
+					_ = conn.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "subscriber fell behind"),
+						time.Now().Add(writeWait))
+					return nil
+				}
+				if err := write(event); err != nil {
+					return nil
+				}
+			case <-heartbeat.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// Requested topics, user events only reach those allowed to read users. Without the
+// permission an explicit user topic is refused and the default leaves it out.
+func (h *StreamHandler) subscriptionTopics(c echo.Context) ([]string, error) {
+	topics, err := parseTopics(c.QueryParam("topics"))
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := h.canReadUsers(c)
+	if err != nil || allowed {
+		return topics, err
+	}
+
+	if len(topics) == 0 {
+		for _, topic := range events.Topics {
+			if topic != events.TopicUser {
+				topics = append(topics, topic)
+			}
+		}
+		return topics, nil
+	}
+
+	for _, topic := range topics {
+		if topic == events.TopicUser {
+			return nil, httpErr.NewForbiddenError(httpErr.PermissionDenied)
+		}
+	}
+
+	return topics, nil
+}
+
+func (h *StreamHandler) canReadUsers(c echo.Context) (bool, error) {
+	user, ok := c.Get("user").(*domain.User)
+	if !ok {
+		return false, nil
+	}
+	if key, ok := c.Get("api_key").(*domain.APIKey); ok && !key.Grants(domain.PermUserRead) {
+		return false, nil
+	}
+
+	return h.authUsecase.HasPermission(c.Request().Context(), user, domain.PermUserRead)
+}
+
+func (h *StreamHandler) heartbeatInterval() time.Duration {
+	if h.cfg.Events.HeartbeatInterval <= 0 {
+		return 15 * time.Second
+	}
+	return time.Second * h.cfg.Events.HeartbeatInterval
+}
+
+func writeSSE(w http.ResponseWriter, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", strconv.FormatUint(event.ID, 10), event.Type, data)
+	return err
+}
+
+func parseTopics(query string) ([]string, error) {
+	if query == "" {
+		return nil, nil
+	}
+
+	topics := make([]string, 0)
+	for _, topic := range strings.Split(query, ",") {
+		topic = strings.TrimSpace(topic)
+		if !events.ValidTopic(topic) {
+			return nil, httpErr.NewRestErrorWithMessage(http.StatusBadRequest, fmt.Sprintf("unknown topic %q", topic), nil)
+		}
+		topics = append(topics, topic)
+	}
+
+	return topics, nil
+}
+
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get(echo.HeaderOrigin)
+	if origin == "" {
+		return true
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(origin, "https://"), "http://") == r.Host
+}
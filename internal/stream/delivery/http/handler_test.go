@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/events"
+)
+
+// Auth usecase granting the permissions of the admin and default roles
+type fakeAuthUsecase struct {
+	auth.Usecase
+}
+
+func (u fakeAuthUsecase) HasPermission(ctx context.Context, user *domain.User, permission string) (bool, error) {
+	permissions := domain.DefaultPermissions
+	if user.Role != nil && *user.Role == domain.AdminRole {
+		permissions = domain.Permissions
+	}
+	for _, p := range permissions {
+		if p == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestSubscriptionTopics(t *testing.T) {
+	admin, trainer := domain.AdminRole, domain.DefaultRole
+	catalogKey := &domain.APIKey{Scopes: []string{domain.ScopeCatalogRead}}
+
+	tests := []struct {
+		name   string
+		role   *string
+		apiKey *domain.APIKey
+		query  string
+		want   []string
+		status int
+	}{
+		{name: "admin keeps the default of every topic", role: &admin},
+		{name: "admin subscribes to users", role: &admin, query: "user", want: []string{events.TopicUser}},
+		{name: "trainer gets the catalog by default", role: &trainer, want: []string{events.TopicMonster, events.TopicMonsterType}},
+		{name: "trainer subscribes to the catalog", role: &trainer, query: "monster", want: []string{events.TopicMonster}},
+		{name: "trainer refused the user topic", role: &trainer, query: "monster,user", status: http.StatusForbidden},
+		{name: "API key of an admin refused the user topic", role: &admin, apiKey: catalogKey, query: "user", status: http.StatusForbidden},
+		{name: "API key of an admin gets the catalog by default", role: &admin, apiKey: catalogKey, want: []string{events.TopicMonster, events.TopicMonsterType}},
+		{name: "unknown topic", role: &admin, query: "roles", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewStreamHandler(&config.Config{}, events.NewBus(1), fakeAuthUsecase{}, nil).(*StreamHandler)
+
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/events?topics="+tt.query, nil), httptest.NewRecorder())
+			c.Set("user", &domain.User{Username: "ash", Role: tt.role})
+			if tt.apiKey != nil {
+				c.Set("api_key", tt.apiKey)
+			}
+
+			topics, err := h.subscriptionTopics(c)
+			if tt.status != 0 {
+				restErr, ok := err.(httpErr.RestErr)
+				if !ok || restErr.Status() != tt.status {
+					t.Fatalf("got %v, want status %d", err, tt.status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("subscriptionTopics: %v", err)
+			}
+			if strings.Join(topics, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("subscribed to %v, want %v", topics, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package http
+
+import (
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/middleware"
+	"github.com/iamaul/go-pokedex/internal/stream"
+	"github.com/labstack/echo/v4"
+)
+
+func StreamRoutes(eventGroup *echo.Group, h stream.DeliveryHandlers, au auth.Usecase, cfg *config.Config, mw *middleware.MiddlewareManager) {
+	eventGroup.GET("", h.Events(), mw.TokenFromQueryMiddleware, mw.AuthJWTMiddleware(au, cfg))
+	eventGroup.GET("/ws", h.WebSocket(), mw.TokenFromQueryMiddleware, mw.AuthJWTMiddleware(au, cfg))
+}
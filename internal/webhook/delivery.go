@@ -0,0 +1,15 @@
+package webhook
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+type DeliveryHandlers interface {
+	CreateWebhook() echo.HandlerFunc
+	UpdateWebhook() echo.HandlerFunc
+	DeleteWebhook() echo.HandlerFunc
+	ListWebhook() echo.HandlerFunc
+	DetailWebhook() echo.HandlerFunc
+	ListDelivery() echo.HandlerFunc
+	Redeliver() echo.HandlerFunc
+}
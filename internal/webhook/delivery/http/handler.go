@@ -0,0 +1,263 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/webhook"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/events"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/render"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WebhookHandler struct {
+	cfg            *config.Config
+	webhookUsecase webhook.Usecase
+	authUsecase    auth.Usecase
+	logger         logger.Logger
+}
+
+func NewWebhookHandler(cfg *config.Config, webhookUsecase webhook.Usecase, authUsecase auth.Usecase, log logger.Logger) webhook.DeliveryHandlers {
+	return &WebhookHandler{cfg: cfg, webhookUsecase: webhookUsecase, authUsecase: authUsecase, logger: log}
+}
+
+// CreateWebhook godoc
+// @Summary Register a webhook endpoint
+// @Description returns the webhook including its signing secret, which is only shown once. Subscribing to user events needs the user:read permission.
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Success 201 {object} domain.Webhook
+// @Router /webhook [post]
+func (h *WebhookHandler) CreateWebhook() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		webhook := &domain.Webhook{Active: true}
+		if err := utils.ReadRequest(c, webhook); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err := h.checkEvents(c, webhook.Events); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		createdWebhook, err := h.webhookUsecase.WebhookCreate(c.Request().Context(), webhook)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusCreated, createdWebhook)
+	}
+}
+
+// UpdateWebhook godoc
+// @Summary Update webhook
+// @Description update url, description, event filter or active flag. Subscribing to user events needs the user:read permission.
+// @Tags Webhook
+// @Accept json
+// @Param id path string true "id"
+// @Produce json
+// @Success 200 {object} domain.WebhookUpdate
+// @Router /webhook/{id} [put]
+func (h *WebhookHandler) UpdateWebhook() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		webhookID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		webhook := &domain.WebhookUpdate{}
+		if err := utils.ReadRequest(c, webhook); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+		webhook.ID = webhookID
+
+		if err := h.checkEvents(c, webhook.Events); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		updatedWebhook, err := h.webhookUsecase.WebhookUpdate(c.Request().Context(), webhook)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, updatedWebhook)
+	}
+}
+
+// DeleteWebhook godoc
+// @Summary Delete webhook
+// @Description delete webhook and its delivery history
+// @Tags Webhook
+// @Param id path string true "id"
+// @Success 200 {string} string	"ok"
+// @Router /webhook/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		webhookID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		if err = h.webhookUsecase.WebhookDeletion(c.Request().Context(), webhookID); err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+// ListWebhook godoc
+// @Summary Get webhook list
+// @Description list of registered webhooks
+// @Tags Webhook
+// @Produce json
+// @Success 200 {object} domain.WebhookList
+// @Router /webhook/list [get]
+func (h *WebhookHandler) ListWebhook() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		paginationQuery, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		webhookList, err := h.webhookUsecase.GetWebhookList(c.Request().Context(), paginationQuery)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, webhookList)
+	}
+}
+
+// DetailWebhook godoc
+// @Summary Detail webhook
+// @Description Get webhook detail
+// @Tags Webhook
+// @Param id path string true "id"
+// @Produce json
+// @Success 200 {object} domain.Webhook
+// @Router /webhook/{id} [get]
+func (h *WebhookHandler) DetailWebhook() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		webhookID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		webhook, err := h.webhookUsecase.GetByID(c.Request().Context(), webhookID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, webhook)
+	}
+}
+
+// ListDelivery godoc
+// @Summary Get webhook delivery history
+// @Description deliveries of the webhook, newest first, with their attempts
+// @Tags Webhook
+// @Param id path string true "id"
+// @Produce json
+// @Success 200 {object} domain.WebhookDeliveryList
+// @Router /webhook/{id}/deliveries [get]
+func (h *WebhookHandler) ListDelivery() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		webhookID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		paginationQuery, err := utils.GetPaginationFromCtx(c)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		deliveryList, err := h.webhookUsecase.GetDeliveryList(c.Request().Context(), webhookID, paginationQuery)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusOK, deliveryList)
+	}
+}
+
+// Redeliver godoc
+// @Summary Redeliver a webhook delivery
+// @Description queue the delivery again with a fresh retry budget
+// @Tags Webhook
+// @Param id path string true "id"
+// @Param delivery_id path string true "delivery id"
+// @Produce json
+// @Success 202 {object} domain.WebhookDelivery
+// @Router /webhook/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *WebhookHandler) Redeliver() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		webhookID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		deliveryID, err := primitive.ObjectIDFromHex(c.Param("delivery_id"))
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		delivery, err := h.webhookUsecase.Redeliver(c.Request().Context(), webhookID, deliveryID)
+		if err != nil {
+			utils.LogResponseError(c, h.logger, err)
+			return render.Error(c, err)
+		}
+
+		return render.Respond(c, http.StatusAccepted, delivery)
+	}
+}
+
+// Filters that match user events, the catch-all included, need the permission to read users
+func (h *WebhookHandler) checkEvents(c echo.Context, filter []string) error {
+	for _, event := range filter {
+		if event != "*" && events.TopicOf(event) != events.TopicUser {
+			continue
+		}
+
+		user, ok := c.Get("user").(*domain.User)
+		if !ok {
+			return httpErr.NewUnauthorizedError(httpErr.Unauthorized)
+		}
+		allowed, err := h.authUsecase.HasPermission(c.Request().Context(), user, domain.PermUserRead)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return httpErr.NewForbiddenError(httpErr.PermissionDenied)
+		}
+
+		return nil
+	}
+
+	return nil
+}
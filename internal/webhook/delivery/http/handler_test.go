@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/webhook"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+)
+
+// Auth usecase granting the webhook permissions to everyone and user:read to admins only
+type fakeAuthUsecase struct {
+	auth.Usecase
+}
+
+func (u fakeAuthUsecase) HasPermission(ctx context.Context, user *domain.User, permission string) (bool, error) {
+	return permission != domain.PermUserRead || (user.Role != nil && *user.Role == domain.AdminRole), nil
+}
+
+// Webhook usecase accepting every create and update
+type fakeWebhookUsecase struct {
+	webhook.Usecase
+	calls int
+}
+
+func (u *fakeWebhookUsecase) WebhookCreate(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	u.calls++
+	return webhook, nil
+}
+
+func (u *fakeWebhookUsecase) WebhookUpdate(ctx context.Context, webhook *domain.WebhookUpdate) (*domain.WebhookUpdate, error) {
+	u.calls++
+	return webhook, nil
+}
+
+func TestUserEventSubscription(t *testing.T) {
+	admin, operator := domain.AdminRole, "operator"
+
+	tests := []struct {
+		name   string
+		role   *string
+		events string
+		status int
+	}{
+		{name: "catalog events", role: &operator, events: `["monster.*","monster_type.created"]`, status: http.StatusOK},
+		{name: "user event without user:read", role: &operator, events: `["monster.*","user.created"]`, status: http.StatusForbidden},
+		{name: "user wildcard without user:read", role: &operator, events: `["user.*"]`, status: http.StatusForbidden},
+		{name: "catch-all without user:read", role: &operator, events: `["*"]`, status: http.StatusForbidden},
+		{name: "user events with user:read", role: &admin, events: `["user.*","*"]`, status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		for _, method := range []string{http.MethodPost, http.MethodPut} {
+			t.Run(tt.name+" "+method, func(t *testing.T) {
+				cfg := &config.Config{Logger: config.Logger{Level: "fatal"}}
+				log := logger.NewLogger(cfg)
+				log.InitLogger()
+
+				usecase := &fakeWebhookUsecase{}
+				h := NewWebhookHandler(cfg, usecase, fakeAuthUsecase{}, log)
+
+				body := `{"url":"https://example.com/hook","events":` + tt.events + `}`
+				req := httptest.NewRequest(method, "/api/v1/webhook", strings.NewReader(body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				rec := httptest.NewRecorder()
+				c := echo.New().NewContext(req, rec)
+				c.Set("user", &domain.User{Username: "ash", Role: tt.role})
+
+				handler := h.CreateWebhook()
+				if method == http.MethodPut {
+					c.SetParamNames("id")
+					c.SetParamValues(primitive.NewObjectID().Hex())
+					handler = h.UpdateWebhook()
+				}
+				if err := handler(c); err != nil {
+					t.Fatal(err)
+				}
+
+				status := rec.Code
+				if status == http.StatusCreated {
+					status = http.StatusOK
+				}
+				if status != tt.status {
+					t.Errorf("got status %d, want %d: %s", rec.Code, tt.status, rec.Body)
+				}
+				if saved := usecase.calls == 1; saved != (tt.status == http.StatusOK) {
+					t.Errorf("usecase called %d times", usecase.calls)
+				}
+			})
+		}
+	}
+}
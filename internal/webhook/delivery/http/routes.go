@@ -0,0 +1,20 @@
+package http
+
+import (
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/auth"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/middleware"
+	"github.com/iamaul/go-pokedex/internal/webhook"
+	"github.com/labstack/echo/v4"
+)
+
+func WebhookRoutes(webhookGroup *echo.Group, h webhook.DeliveryHandlers, au auth.Usecase, cfg *config.Config, mw *middleware.MiddlewareManager) {
+	webhookGroup.POST("", h.CreateWebhook(), mw.AuthJWTMiddleware(au, cfg), mw.RequirePermission(domain.PermWebhookWrite))
+	webhookGroup.GET("/list", h.ListWebhook(), mw.AuthJWTMiddleware(au, cfg), mw.RequirePermission(domain.PermWebhookRead))
+	webhookGroup.PUT("/:id", h.UpdateWebhook(), mw.AuthJWTMiddleware(au, cfg), mw.RequirePermission(domain.PermWebhookWrite))
+	webhookGroup.DELETE("/:id", h.DeleteWebhook(), mw.AuthJWTMiddleware(au, cfg), mw.RequirePermission(domain.PermWebhookWrite))
+	webhookGroup.GET("/:id", h.DetailWebhook(), mw.AuthJWTMiddleware(au, cfg), mw.RequirePermission(domain.PermWebhookRead))
+	webhookGroup.GET("/:id/deliveries", h.ListDelivery(), mw.AuthJWTMiddleware(au, cfg), mw.RequirePermission(domain.PermWebhookRead))
+	webhookGroup.POST("/:id/deliveries/:delivery_id/redeliver", h.Redeliver(), mw.AuthJWTMiddleware(au, cfg), mw.RequirePermission(domain.PermWebhookWrite))
+}
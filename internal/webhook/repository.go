@@ -0,0 +1,25 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Repository interface {
+	CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook *domain.WebhookUpdate) (*domain.WebhookUpdate, error)
+	DeleteWebhook(ctx context.Context, webhookID primitive.ObjectID) error
+	FetchWebhooks(ctx context.Context, pq *utils.PaginationQuery) (*domain.WebhookList, error)
+	FindByID(ctx context.Context, webhookID primitive.ObjectID) (*domain.Webhook, error)
+	FindSubscribed(ctx context.Context, eventType string) ([]*domain.Webhook, error)
+	CreateDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error
+	FetchDeliveries(ctx context.Context, webhookID primitive.ObjectID, pq *utils.PaginationQuery) (*domain.WebhookDeliveryList, error)
+	FindDeliveryByID(ctx context.Context, deliveryID primitive.ObjectID) (*domain.WebhookDelivery, error)
+	ClaimDueDelivery(ctx context.Context, now time.Time, lease time.Duration) (*domain.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery, attempt domain.WebhookDeliveryAttempt) error
+	RequeueDelivery(ctx context.Context, deliveryID primitive.ObjectID, now time.Time) error
+}
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/webhook"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/events"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Attempts kept per delivery, older ones are trimmed
+const maxAttemptHistory = 20
+
+type WebhookRepo struct {
+	db         *mongo.Collection
+	deliveries *mongo.Collection
+}
+
+func NewWebhookRepo(db *mongo.Database) webhook.Repository {
+	return &WebhookRepo{
+		db:         db.Collection("webhooks"),
+		deliveries: db.Collection("webhook_deliveries"),
+	}
+}
+
+func (r *WebhookRepo) CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	result, err := r.db.InsertOne(ctx, webhook)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.InsertOne")
+	}
+
+	webhook.ID = result.InsertedID.(primitive.ObjectID)
+
+	return webhook, nil
+}
+
+func (r *WebhookRepo) UpdateWebhook(ctx context.Context, webhook *domain.WebhookUpdate) (*domain.WebhookUpdate, error) {
+	updateQuery := bson.M{"updated_at": time.Now()}
+
+	if webhook.URL != "" {
+		updateQuery["url"] = webhook.URL
+	}
+	if webhook.Description != nil {
+		updateQuery["description"] = *webhook.Description
+	}
+	if len(webhook.Events) > 0 {
+		updateQuery["events"] = webhook.Events
+	}
+	if webhook.Active != nil {
+		updateQuery["active"] = *webhook.Active
+	}
+
+	result, err := r.db.UpdateOne(ctx, bson.M{"_id": webhook.ID}, bson.M{"$set": updateQuery})
+	if err != nil {
+		return nil, errors.Wrap(err, "db.UpdateOne")
+	}
+	if result.MatchedCount == 0 {
+		return nil, httpErr.NewNotFoundError(errors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound))
+	}
+
+	return webhook, nil
+}
+
+func (r *WebhookRepo) DeleteWebhook(ctx context.Context, webhookID primitive.ObjectID) error {
+	if _, err := r.db.DeleteOne(ctx, bson.M{"_id": webhookID}); err != nil {
+		return errors.Wrap(err, "db.DeleteOne")
+	}
+
+	if _, err := r.deliveries.DeleteMany(ctx, bson.M{"webhook_id": webhookID}); err != nil {
+		return errors.Wrap(err, "db.DeleteMany")
+	}
+
+	return nil
+}
+
+func (r *WebhookRepo) FetchWebhooks(ctx context.Context, pq *utils.PaginationQuery) (*domain.WebhookList, error) {
+	totalCount, err := r.db.CountDocuments(ctx, bson.D{})
+	if err != nil {
+		return nil, errors.Wrap(err, "db.CountDocuments")
+	}
+
+	if totalCount == 0 {
+		return &domain.WebhookList{
+			TotalCount: 0,
+			TotalPages: 0,
+			Page:       0,
+			Size:       0,
+			HasMore:    false,
+			Webhooks:   make([]*domain.Webhook, 0),
+		}, nil
+	}
+
+	limit := int64(pq.GetLimit())
+	skip := int64(pq.GetOffset())
+	cursor, err := r.db.Find(ctx, bson.D{}, &options.FindOptions{
+		Limit: &limit,
+		Skip:  &skip,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "db.Find")
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := make([]*domain.Webhook, 0, pq.GetSize())
+	for cursor.Next(ctx) {
+		var webhook domain.Webhook
+		if err := cursor.Decode(&webhook); err != nil {
+			return nil, errors.Wrap(err, "cursor.Decode")
+		}
+		webhooks = append(webhooks, &webhook)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, errors.Wrap(err, "cursor.Err")
+	}
+
+	return &domain.WebhookList{
+		TotalCount: int(totalCount),
+		TotalPages: utils.GetTotalPages(int(totalCount), pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), int(totalCount), pq.GetSize()),
+		Webhooks:   webhooks,
+	}, nil
+}
+
+func (r *WebhookRepo) FindByID(ctx context.Context, webhookID primitive.ObjectID) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+
+	if err := r.db.FindOne(ctx, bson.M{"_id": webhookID}).Decode(&webhook); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, httpErr.NewNotFoundError(errors.Wrap(err, httpErr.ErrNotFound))
+		}
+
+		return nil, errors.Wrap(err, "db.FindOne")
+	}
+
+	return &webhook, nil
+}
+
+// Active webhooks whose filter matches the event type exactly, by topic wildcard or catch-all
+func (r *WebhookRepo) FindSubscribed(ctx context.Context, eventType string) ([]*domain.Webhook, error) {
+	filter := bson.M{
+		"active": true,
+		"events": bson.M{"$in": bson.A{eventType, events.TopicOf(eventType) + ".*", "*"}},
+	}
+
+	cursor, err := r.db.Find(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.Find")
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := make([]*domain.Webhook, 0)
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, errors.Wrap(err, "cursor.All")
+	}
+
+	return webhooks, nil
+}
+
+func (r *WebhookRepo) CreateDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	docs := make([]interface{}, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		docs = append(docs, delivery)
+	}
+
+	result, err := r.deliveries.InsertMany(ctx, docs)
+	if err != nil {
+		return errors.Wrap(err, "db.InsertMany")
+	}
+
+	for i, id := range result.InsertedIDs {
+		deliveries[i].ID = id.(primitive.ObjectID)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepo) FetchDeliveries(ctx context.Context, webhookID primitive.ObjectID, pq *utils.PaginationQuery) (*domain.WebhookDeliveryList, error) {
+	filter := bson.M{"webhook_id": webhookID}
+
+	totalCount, err := r.deliveries.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.CountDocuments")
+	}
+
+	if totalCount == 0 {
+		return &domain.WebhookDeliveryList{
+			TotalCount: 0,
+			TotalPages: 0,
+			Page:       0,
+			Size:       0,
+			HasMore:    false,
+			Deliveries: make([]*domain.WebhookDelivery, 0),
+		}, nil
+	}
+
+	limit := int64(pq.GetLimit())
+	skip := int64(pq.GetOffset())
+	cursor, err := r.deliveries.Find(ctx, filter, &options.FindOptions{
+		Limit: &limit,
+		Skip:  &skip,
+		Sort:  bson.D{{Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "db.Find")
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := make([]*domain.WebhookDelivery, 0, pq.GetSize())
+	for cursor.Next(ctx) {
+		var delivery domain.WebhookDelivery
+		if err := cursor.Decode(&delivery); err != nil {
+			return nil, errors.Wrap(err, "cursor.Decode")
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, errors.Wrap(err, "cursor.Err")
+	}
+
+	return &domain.WebhookDeliveryList{
+		TotalCount: int(totalCount),
+		TotalPages: utils.GetTotalPages(int(totalCount), pq.GetSize()),
+		Page:       pq.GetPage(),
+		Size:       pq.GetSize(),
+		HasMore:    utils.GetHasMore(pq.GetPage(), int(totalCount), pq.GetSize()),
+		Deliveries: deliveries,
+	}, nil
+}
+
+func (r *WebhookRepo) FindDeliveryByID(ctx context.Context, deliveryID primitive.ObjectID) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+
+	if err := r.deliveries.FindOne(ctx, bson.M{"_id": deliveryID}).Decode(&delivery); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, httpErr.NewNotFoundError(errors.Wrap(err, httpErr.ErrNotFound))
+		}
+
+		return nil, errors.Wrap(err, "db.FindOne")
+	}
+
+	return &delivery, nil
+}
+
+// Atomically take the oldest due delivery, pushing its next attempt past the lease so a
+// crashed worker's delivery is picked up again. Returns nil when nothing is due.
+func (r *WebhookRepo) ClaimDueDelivery(ctx context.Context, now time.Time, lease time.Duration) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+
+	err := r.deliveries.FindOneAndUpdate(ctx,
+		bson.M{"status": domain.WebhookDeliveryPending, "next_attempt_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&delivery)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "db.FindOneAndUpdate")
+	}
+
+	return &delivery, nil
+}
+
+func (r *WebhookRepo) RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery, attempt domain.WebhookDeliveryAttempt) error {
+	_, err := r.deliveries.UpdateOne(ctx, bson.M{"_id": delivery.ID}, bson.M{
+		"$set": bson.M{
+			"status":          delivery.Status,
+			"retry_count":     delivery.RetryCount,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"updated_at":      delivery.UpdatedAt,
+		},
+		"$push": bson.M{
+			"attempts": bson.M{"$each": bson.A{attempt}, "$slice": -maxAttemptHistory},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "db.UpdateOne")
+	}
+
+	return nil
+}
+
+func (r *WebhookRepo) RequeueDelivery(ctx context.Context, deliveryID primitive.ObjectID, now time.Time) error {
+	_, err := r.deliveries.UpdateOne(ctx, bson.M{"_id": deliveryID}, bson.M{"$set": bson.M{
+		"status":          domain.WebhookDeliveryPending,
+		"retry_count":     0,
+		"next_attempt_at": now,
+		"updated_at":      now,
+	}})
+	if err != nil {
+		return errors.Wrap(err, "db.UpdateOne")
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Usecase interface {
+	WebhookCreate(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error)
+	WebhookUpdate(ctx context.Context, webhook *domain.WebhookUpdate) (*domain.WebhookUpdate, error)
+	WebhookDeletion(ctx context.Context, webhookID primitive.ObjectID) error
+	GetWebhookList(ctx context.Context, pq *utils.PaginationQuery) (*domain.WebhookList, error)
+	GetByID(ctx context.Context, webhookID primitive.ObjectID) (*domain.Webhook, error)
+	GetDeliveryList(ctx context.Context, webhookID primitive.ObjectID, pq *utils.PaginationQuery) (*domain.WebhookDeliveryList, error)
+	Redeliver(ctx context.Context, webhookID, deliveryID primitive.ObjectID) (*domain.WebhookDelivery, error)
+	Publish(ctx context.Context, eventType string, data interface{})
+}
@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/webhook"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+const (
+	EventHeader     = "X-Pokedex-Event"
+	DeliveryHeader  = "X-Pokedex-Delivery"
+	SignatureHeader = "X-Pokedex-Signature"
+
+	// Response bodies are drained but never stored
+	maxResponseBody = 64 << 10
+
+	defaultTimeout = 10 * time.Second
+)
+
+var errForbiddenAddress = errors.New("webhook address is not publicly routable")
+
+// Dispatcher delivers queued webhook payloads in the background, retrying failures
+// with exponential backoff until MaxAttempts is reached
+type Dispatcher struct {
+	cfg         *config.Config
+	webhookRepo webhook.Repository
+	client      *http.Client
+	logger      logger.Logger
+}
+
+// Webhook dispatcher constructor
+func NewDispatcher(cfg *config.Config, webhookRepo webhook.Repository, log logger.Logger) *Dispatcher {
+	dialer := &net.Dialer{
+		Timeout: defaultTimeout,
+		Control: refusePrivateAddress,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// The guard runs on the address dialed, so receivers resolving to internal hosts are refused too
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Dispatcher{
+		cfg:         cfg,
+		webhookRepo: webhookRepo,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout(cfg),
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		logger: log,
+	}
+}
+
+// Time allowed for a delivery request, receivers that never answer would otherwise hold a worker forever
+func timeout(cfg *config.Config) time.Duration {
+	if cfg.Webhook.Timeout <= 0 {
+		return defaultTimeout
+	}
+	return time.Second * cfg.Webhook.Timeout
+}
+
+// Dialer control refusing loopback, private, link-local and unspecified addresses
+func refusePrivateAddress(network, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !publicAddress(ip) {
+		return errForbiddenAddress
+	}
+	return nil
+}
+
+func publicAddress(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+// Run starts the configured number of workers and blocks until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	workers := d.cfg.Webhook.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (d *Dispatcher) work(ctx context.Context) {
+	pollInterval := time.Second * d.cfg.Webhook.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+
+	for {
+		processed, err := d.ProcessNext(ctx)
+		if err != nil {
+			d.logger.Errorf("Dispatcher.ProcessNext error: %s", err)
+		}
+		if processed && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// ProcessNext claims and attempts a single due delivery, reporting whether one was found
+func (d *Dispatcher) ProcessNext(ctx context.Context) (bool, error) {
+	lease := 2 * d.client.Timeout
+	if lease <= 0 {
+		lease = time.Minute
+	}
+
+	delivery, err := d.webhookRepo.ClaimDueDelivery(ctx, time.Now(), lease)
+	if err != nil || delivery == nil {
+		return false, err
+	}
+
+	attempt := d.attempt(ctx, delivery)
+
+	now := time.Now()
+	delivery.RetryCount++
+	delivery.UpdatedAt = now
+	switch {
+	case attempt.Error == "":
+		delivery.Status = domain.WebhookDeliverySucceeded
+	case delivery.RetryCount >= d.cfg.Webhook.MaxAttempts:
+		delivery.Status = domain.WebhookDeliveryFailed
+	default:
+		delivery.Status = domain.WebhookDeliveryPending
+		delivery.NextAttemptAt = now.Add(d.backoff(delivery.RetryCount))
+	}
+
+	return true, d.webhookRepo.RecordAttempt(ctx, delivery, attempt)
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, delivery *domain.WebhookDelivery) domain.WebhookDeliveryAttempt {
+	start := time.Now()
+	attempt := domain.WebhookDeliveryAttempt{At: start}
+
+	hook, err := d.webhookRepo.FindByID(ctx, delivery.WebhookID)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	if !hook.Active {
+		attempt.Error = "webhook is inactive"
+		return attempt
+	}
+
+	if err := validateURL(hook.URL); err != nil {
+		attempt.Error = "webhook url must be an absolute http or https url"
+		return attempt
+	}
+
+	payload := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-pokedex-webhook/"+d.cfg.Server.AppVersion)
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.ID.Hex())
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, start.Unix(), payload))
+
+	res, err := d.client.Do(req)
+	attempt.Duration = time.Since(start)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, maxResponseBody))
+
+	attempt.StatusCode = res.StatusCode
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		attempt.Error = fmt.Sprintf("unexpected status %d", res.StatusCode)
+	}
+
+	return attempt
+}
+
+// Delay before the next attempt, doubling from BackoffBase up to BackoffMax
+func (d *Dispatcher) backoff(retryCount int) time.Duration {
+	delay := time.Second * d.cfg.Webhook.BackoffBase
+	max := time.Second * d.cfg.Webhook.BackoffMax
+
+	for i := 1; i < retryCount && delay < max; i++ {
+		delay *= 2
+	}
+	if max > 0 && delay > max {
+		delay = max
+	}
+
+	return delay
+}
+
+// Sign builds the signature header value: t=<unix>,v1=<hex HMAC-SHA256 of "<unix>.<payload>">.
+// Receivers recompute the HMAC with their endpoint secret and reject stale timestamps.
+func Sign(secret string, timestamp int64, payload []byte) string {
+	ts := strconv.FormatInt(timestamp, 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
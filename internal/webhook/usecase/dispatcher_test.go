@@ -0,0 +1,337 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/webhook"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+)
+
+const testSecret = "whsec_test"
+
+// Webhook repository holding a single hook and its deliveries in memory
+type fakeWebhookRepo struct {
+	webhook.Repository
+
+	mu         sync.Mutex
+	hook       *domain.Webhook
+	deliveries map[primitive.ObjectID]*domain.WebhookDelivery
+}
+
+func newFakeWebhookRepo(url string) *fakeWebhookRepo {
+	return &fakeWebhookRepo{
+		hook:       &domain.Webhook{ID: primitive.NewObjectID(), URL: url, Secret: testSecret, Active: true},
+		deliveries: make(map[primitive.ObjectID]*domain.WebhookDelivery),
+	}
+}
+
+func (r *fakeWebhookRepo) queue(retryCount int) *domain.WebhookDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delivery := &domain.WebhookDelivery{
+		ID:            primitive.NewObjectID(),
+		WebhookID:     r.hook.ID,
+		EventType:     "monster.created",
+		Payload:       `{"type":"monster.created"}`,
+		Status:        domain.WebhookDeliveryPending,
+		RetryCount:    retryCount,
+		NextAttemptAt: time.Now(),
+	}
+	r.deliveries[delivery.ID] = delivery
+
+	return delivery
+}
+
+func (r *fakeWebhookRepo) delivery(deliveryID primitive.ObjectID) domain.WebhookDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return *r.deliveries[deliveryID]
+}
+
+func (r *fakeWebhookRepo) FindByID(ctx context.Context, webhookID primitive.ObjectID) (*domain.Webhook, error) {
+	found := *r.hook
+	return &found, nil
+}
+
+func (r *fakeWebhookRepo) FindDeliveryByID(ctx context.Context, deliveryID primitive.ObjectID) (*domain.WebhookDelivery, error) {
+	found := r.delivery(deliveryID)
+	return &found, nil
+}
+
+func (r *fakeWebhookRepo) ClaimDueDelivery(ctx context.Context, now time.Time, lease time.Duration) (*domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range r.deliveries {
+		if delivery.Status == domain.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			delivery.NextAttemptAt = now.Add(lease)
+			claimed := *delivery
+			return &claimed, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeWebhookRepo) RecordAttempt(ctx context.Context, delivery *domain.WebhookDelivery, attempt domain.WebhookDeliveryAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recorded := *delivery
+	recorded.Attempts = append(r.deliveries[delivery.ID].Attempts, attempt)
+	r.deliveries[delivery.ID] = &recorded
+	return nil
+}
+
+func (r *fakeWebhookRepo) RequeueDelivery(ctx context.Context, deliveryID primitive.ObjectID, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delivery := r.deliveries[deliveryID]
+	delivery.Status = domain.WebhookDeliveryPending
+	delivery.RetryCount = 0
+	delivery.NextAttemptAt = now
+	return nil
+}
+
+// Receiver answering with status and reporting whether the signature of each request verified
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests int
+	verified bool
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	payload, _ := io.ReadAll(r.Body)
+
+	signature := r.Header.Get(SignatureHeader)
+	ts, _, _ := strings.Cut(strings.TrimPrefix(signature, "t="), ",")
+	timestamp, err := strconv.ParseInt(ts, 10, 64)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.requests++
+	rc.verified = err == nil && signature == Sign(testSecret, timestamp, payload) &&
+		time.Since(time.Unix(timestamp, 0)).Abs() < time.Minute
+
+	w.WriteHeader(rc.status)
+}
+
+func testConfig() *config.Config {
+	return &config.Config{
+		Logger:  config.Logger{Level: "fatal"},
+		Webhook: config.Webhook{Timeout: 5, MaxAttempts: 3, BackoffBase: 5, BackoffMax: 60},
+	}
+}
+
+// Dispatcher delivering to a receiver on loopback, which the production dialer refuses
+func newTestDispatcher(t *testing.T, status int) (*Dispatcher, *fakeWebhookRepo, *receiver) {
+	t.Helper()
+
+	rc := &receiver{status: status}
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+
+	cfg := testConfig()
+	log := logger.NewLogger(cfg)
+	log.InitLogger()
+
+	repo := newFakeWebhookRepo(server.URL)
+	d := NewDispatcher(cfg, repo, log)
+	d.client.Transport = server.Client().Transport
+
+	return d, repo, rc
+}
+
+func TestDispatcherDelivery(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		retryCount int
+		want       string
+		retry      bool
+	}{
+		{name: "2xx succeeds", status: http.StatusNoContent, want: domain.WebhookDeliverySucceeded},
+		{name: "non-2xx is retried", status: http.StatusInternalServerError, want: domain.WebhookDeliveryPending, retry: true},
+		{name: "backoff grows with the retries", status: http.StatusBadGateway, retryCount: 1, want: domain.WebhookDeliveryPending, retry: true},
+		{name: "redirects are not followed", status: http.StatusFound, want: domain.WebhookDeliveryPending, retry: true},
+		{name: "fails after MaxAttempts", status: http.StatusInternalServerError, retryCount: 2, want: domain.WebhookDeliveryFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, repo, rc := newTestDispatcher(t, tt.status)
+			queued := repo.queue(tt.retryCount)
+
+			before := time.Now()
+			processed, err := d.ProcessNext(context.Background())
+			if !processed || err != nil {
+				t.Fatalf("ProcessNext: %v, %v", processed, err)
+			}
+
+			if rc.requests != 1 || !rc.verified {
+				t.Errorf("receiver got %d requests, signature verified %v", rc.requests, rc.verified)
+			}
+
+			delivery := repo.delivery(queued.ID)
+			if delivery.Status != tt.want || delivery.RetryCount != tt.retryCount+1 {
+				t.Errorf("got status %s after %d attempts, want %s", delivery.Status, delivery.RetryCount, tt.want)
+			}
+			if len(delivery.Attempts) != 1 || delivery.Attempts[0].StatusCode != tt.status {
+				t.Errorf("attempts %+v", delivery.Attempts)
+			}
+
+			if tt.retry {
+				wait := d.backoff(delivery.RetryCount)
+				if delivery.NextAttemptAt.Before(before.Add(wait)) || delivery.NextAttemptAt.After(time.Now().Add(wait)) {
+					t.Errorf("next attempt at %s, want %s after the attempt", delivery.NextAttemptAt, wait)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := &Dispatcher{cfg: testConfig()}
+
+	tests := []struct {
+		retryCount int
+		want       time.Duration
+	}{
+		{retryCount: 1, want: 5 * time.Second},
+		{retryCount: 2, want: 10 * time.Second},
+		{retryCount: 4, want: 40 * time.Second},
+		{retryCount: 5, want: 60 * time.Second},
+		{retryCount: 50, want: 60 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := d.backoff(tt.retryCount); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.retryCount, got, tt.want)
+		}
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	d, repo, rc := newTestDispatcher(t, http.StatusInternalServerError)
+	queued := repo.queue(2)
+
+	if _, err := d.ProcessNext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if delivery := repo.delivery(queued.ID); delivery.Status != domain.WebhookDeliveryFailed {
+		t.Fatalf("got status %s, want %s", delivery.Status, domain.WebhookDeliveryFailed)
+	}
+
+	u := NewWebhookUsecase(d.cfg, repo, d.logger)
+	if _, err := u.Redeliver(context.Background(), primitive.NewObjectID(), queued.ID); err == nil {
+		t.Error("redelivered through another webhook")
+	}
+	if _, err := u.Redeliver(context.Background(), repo.hook.ID, queued.ID); err != nil {
+		t.Fatalf("Redeliver: %v", err)
+	}
+
+	rc.mu.Lock()
+	rc.status = http.StatusOK
+	rc.mu.Unlock()
+
+	if processed, err := d.ProcessNext(context.Background()); !processed || err != nil {
+		t.Fatalf("ProcessNext: %v, %v", processed, err)
+	}
+
+	delivery := repo.delivery(queued.ID)
+	if delivery.Status != domain.WebhookDeliverySucceeded || delivery.RetryCount != 1 {
+		t.Errorf("got status %s after %d attempts", delivery.Status, delivery.RetryCount)
+	}
+	if len(delivery.Attempts) != 2 {
+		t.Errorf("attempt history has %d entries, want 2", len(delivery.Attempts))
+	}
+}
+
+func TestDispatcherRefusesInternalAddresses(t *testing.T) {
+	rc := &receiver{status: http.StatusOK}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{name: "loopback receiver", url: server.URL},
+		{name: "localhost", url: strings.Replace(server.URL, "127.0.0.1", "localhost", 1)},
+		{name: "cloud metadata", url: "http://169.254.169.254/latest/meta-data"},
+		{name: "unspecified", url: "http://0.0.0.0:" + server.URL[strings.LastIndex(server.URL, ":")+1:]},
+		{name: "not http", url: "file:///etc/passwd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			log := logger.NewLogger(cfg)
+			log.InitLogger()
+
+			repo := newFakeWebhookRepo(tt.url)
+			queued := repo.queue(0)
+			d := NewDispatcher(cfg, repo, log)
+
+			if _, err := d.ProcessNext(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			delivery := repo.delivery(queued.ID)
+			if len(delivery.Attempts) != 1 || delivery.Attempts[0].Error == "" || delivery.Status == domain.WebhookDeliverySucceeded {
+				t.Errorf("delivered to %s: %+v", tt.url, delivery.Attempts)
+			}
+		})
+	}
+
+	if rc.requests != 0 {
+		t.Errorf("loopback receiver got %d requests", rc.requests)
+	}
+}
+
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		ip     string
+		public bool
+	}{
+		{ip: "93.184.216.34", public: true},
+		{ip: "2606:4700::1111", public: true},
+		{ip: "127.0.0.1"},
+		{ip: "::1"},
+		{ip: "10.1.2.3"},
+		{ip: "172.16.0.1"},
+		{ip: "192.168.1.1"},
+		{ip: "169.254.169.254"},
+		{ip: "fe80::1"},
+		{ip: "fd00::1"},
+		{ip: "0.0.0.0"},
+		{ip: "::ffff:127.0.0.1"},
+	}
+	for _, tt := range tests {
+		if got := publicAddress(net.ParseIP(tt.ip)); got != tt.public {
+			t.Errorf("publicAddress(%s) = %v, want %v", tt.ip, got, tt.public)
+		}
+	}
+}
+
+func TestDefaultTimeout(t *testing.T) {
+	cfg := testConfig()
+	cfg.Webhook.Timeout = 0
+
+	if d := NewDispatcher(cfg, nil, nil); d.client.Timeout != defaultTimeout {
+		t.Errorf("client timeout %s, want %s", d.client.Timeout, defaultTimeout)
+	}
+}
@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/internal/webhook"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/events"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/utils"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const secretPrefix = "whsec_"
+
+type WebhookUsecase struct {
+	cfg         *config.Config
+	webhookRepo webhook.Repository
+	logger      logger.Logger
+}
+
+func NewWebhookUsecase(cfg *config.Config, webhookRepo webhook.Repository, log logger.Logger) webhook.Usecase {
+	return &WebhookUsecase{cfg: cfg, webhookRepo: webhookRepo, logger: log}
+}
+
+func (u *WebhookUsecase) WebhookCreate(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	if err := validateURL(webhook.URL); err != nil {
+		return nil, err
+	}
+	if err := validateEvents(webhook.Events); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, httpErr.NewInternalServerError(errors.Wrap(err, "WebhookUsecase.WebhookCreate.generateSecret"))
+	}
+
+	now := time.Now()
+	webhook.ID = primitive.NilObjectID
+	webhook.Secret = secret
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+
+	return u.webhookRepo.CreateWebhook(ctx, webhook)
+}
+
+func (u *WebhookUsecase) WebhookUpdate(ctx context.Context, webhook *domain.WebhookUpdate) (*domain.WebhookUpdate, error) {
+	if webhook.URL != "" {
+		if err := validateURL(webhook.URL); err != nil {
+			return nil, err
+		}
+	}
+	if len(webhook.Events) > 0 {
+		if err := validateEvents(webhook.Events); err != nil {
+			return nil, err
+		}
+	}
+
+	return u.webhookRepo.UpdateWebhook(ctx, webhook)
+}
+
+func (u *WebhookUsecase) WebhookDeletion(ctx context.Context, webhookID primitive.ObjectID) error {
+	return u.webhookRepo.DeleteWebhook(ctx, webhookID)
+}
+
+func (u *WebhookUsecase) GetWebhookList(ctx context.Context, pq *utils.PaginationQuery) (*domain.WebhookList, error) {
+	webhookList, err := u.webhookRepo.FetchWebhooks(ctx, pq)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, webhook := range webhookList.Webhooks {
+		webhook.SanitizeSecret()
+	}
+
+	return webhookList, nil
+}
+
+func (u *WebhookUsecase) GetByID(ctx context.Context, webhookID primitive.ObjectID) (*domain.Webhook, error) {
+	webhook, err := u.webhookRepo.FindByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.SanitizeSecret()
+
+	return webhook, nil
+}
+
+func (u *WebhookUsecase) GetDeliveryList(ctx context.Context, webhookID primitive.ObjectID, pq *utils.PaginationQuery) (*domain.WebhookDeliveryList, error) {
+	if _, err := u.webhookRepo.FindByID(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	return u.webhookRepo.FetchDeliveries(ctx, webhookID, pq)
+}
+
+// Queue the delivery again with a fresh retry budget, its attempt history is kept
+func (u *WebhookUsecase) Redeliver(ctx context.Context, webhookID, deliveryID primitive.ObjectID) (*domain.WebhookDelivery, error) {
+	delivery, err := u.webhookRepo.FindDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.WebhookID != webhookID {
+		return nil, httpErr.NewNotFoundError(errors.New("WebhookUsecase.Redeliver: delivery does not belong to webhook"))
+	}
+
+	now := time.Now()
+	if err := u.webhookRepo.RequeueDelivery(ctx, deliveryID, now); err != nil {
+		return nil, err
+	}
+
+	delivery.Status = domain.WebhookDeliveryPending
+	delivery.RetryCount = 0
+	delivery.NextAttemptAt = now
+	delivery.UpdatedAt = now
+
+	return delivery, nil
+}
+
+// Publish enqueues a delivery for every active webhook subscribed to the event.
+// Failures are logged so they never fail the usecase that produced the event.
+func (u *WebhookUsecase) Publish(ctx context.Context, eventType string, data interface{}) {
+	webhooks, err := u.webhookRepo.FindSubscribed(ctx, eventType)
+	if err != nil {
+		u.logger.Errorf("WebhookUsecase.Publish.FindSubscribed event: %s, error: %s", eventType, err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		u.logger.Errorf("WebhookUsecase.Publish.Marshal event: %s, error: %s", eventType, err)
+		return
+	}
+
+	now := time.Now()
+	event := &domain.WebhookEvent{
+		ID:        primitive.NewObjectID(),
+		Type:      eventType,
+		Source:    events.SourceFromCtx(ctx),
+		Data:      payload,
+		CreatedAt: now.UTC(),
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		u.logger.Errorf("WebhookUsecase.Publish.Marshal event: %s, error: %s", eventType, err)
+		return
+	}
+
+	deliveries := make([]*domain.WebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		deliveries = append(deliveries, &domain.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     eventType,
+			Payload:       string(body),
+			Status:        domain.WebhookDeliveryPending,
+			NextAttemptAt: now,
+			Attempts:      make([]domain.WebhookDeliveryAttempt, 0),
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+
+	if err := u.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		u.logger.Errorf("WebhookUsecase.Publish.CreateDeliveries event: %s, error: %s", eventType, err)
+	}
+}
+
+func validateURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return httpErr.NewRestErrorWithMessage(http.StatusBadRequest, "webhook url must be an absolute http or https url", rawURL)
+	}
+	return nil
+}
+
+// Accept exact event types, topic wildcards such as monster.* and the catch-all *
+func validateEvents(filter []string) error {
+	if len(filter) == 0 {
+		return httpErr.NewRestErrorWithMessage(http.StatusBadRequest, "webhook must subscribe to at least one event", nil)
+	}
+
+	for _, event := range filter {
+		if event == "*" {
+			continue
+		}
+		if topic, ok := strings.CutSuffix(event, ".*"); ok && events.ValidTopic(topic) {
+			continue
+		}
+		if validType(event) {
+			continue
+		}
+		return httpErr.NewRestErrorWithMessage(http.StatusBadRequest, "unknown webhook event "+event, nil)
+	}
+
+	return nil
+}
+
+func validType(eventType string) bool {
+	for _, t := range events.Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return secretPrefix + hex.EncodeToString(b), nil
+}
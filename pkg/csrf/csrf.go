@@ -1,29 +1,72 @@
 package csrf
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
-	"io"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/iamaul/go-pokedex/pkg/logger"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
 )
 
 const (
 	CSRFHeader = "X-CSRF-Token"
-	csrfSalt   = "KbWaoi5xtDC3GEfBa9ovQdzOzXsuVU9I"
+	nonceSize  = 16
 )
 
-func MakeToken(sid string, logger logger.Logger) string {
-	hash := sha256.New()
-	_, err := io.WriteString(hash, csrfSalt+sid)
+// Make a token for the session ID that expires at the given time, formatted as
+// nonce.expiry.mac where mac is the HMAC-SHA256 of the other parts and the session ID.
+// The random nonce makes every token differ, even for the same session.
+func MakeToken(secret []byte, sid string, expiresAt time.Time) (string, error) {
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(nonce) + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(sign(secret, payload, sid)), nil
+}
+
+// Validate the token was made for the session ID and has not expired, the MAC is compared in constant time
+func ValidateToken(secret []byte, token string, sid string, now time.Time) error {
+	if token == "" {
+		return httpErr.CSRFNotPresented
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return httpErr.WrongCSRFToken
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(parts[2])
 	if err != nil {
-		logger.Errorf("Make CSRF Token", err)
+		return httpErr.WrongCSRFToken
 	}
-	token := base64.RawStdEncoding.EncodeToString(hash.Sum(nil))
-	return token
+	if !hmac.Equal(mac, sign(secret, parts[0]+"."+parts[1], sid)) {
+		return httpErr.WrongCSRFToken
+	}
+
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return httpErr.WrongCSRFToken
+	}
+	if !now.Before(time.Unix(expiresAt, 0)) {
+		return httpErr.ExpiredCSRFError
+	}
+
+	return nil
+}
+
+// Compare the token of the header with the one of the cookie in constant time
+func Equal(token string, cookieToken string) bool {
+	return hmac.Equal([]byte(token), []byte(cookieToken))
 }
 
-func ValidateToken(token string, sid string, logger logger.Logger) bool {
-	trueToken := MakeToken(sid, logger)
-	return token == trueToken
+func sign(secret []byte, payload string, sid string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload + "." + sid))
+	return mac.Sum(nil)
 }
@@ -0,0 +1,89 @@
+package csrf
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+)
+
+func TestValidateToken(t *testing.T) {
+	secret := []byte("csrf-secret")
+	sid := "session-a"
+	now := time.Unix(1_700_000_000, 0)
+	expiresAt := now.Add(time.Hour)
+
+	token, err := MakeToken(secret, sid, expiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSession, err := MakeToken(secret, "session-b", expiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(token, ".")
+
+	// Correctly signed, so only the payload itself can make it fail
+	signed := func(payload string) string {
+		return payload + "." + base64.RawURLEncoding.EncodeToString(sign(secret, payload, sid))
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		secret []byte
+		now    time.Time
+		want   error
+	}{
+		{name: "valid token", token: token, now: now},
+		{name: "just before expiry", token: token, now: expiresAt.Add(-time.Second)},
+		{name: "at expiry", token: token, now: expiresAt, want: httpErr.ExpiredCSRFError},
+		{name: "after expiry", token: token, now: expiresAt.Add(time.Minute), want: httpErr.ExpiredCSRFError},
+		{name: "MAC of another session", token: otherSession, now: now, want: httpErr.WrongCSRFToken},
+		{name: "MAC of another session moved onto this payload", token: parts[0] + "." + parts[1] + "." + strings.Split(otherSession, ".")[2], now: now, want: httpErr.WrongCSRFToken},
+		{name: "another secret", token: token, secret: []byte("other-secret"), now: now, want: httpErr.WrongCSRFToken},
+		{name: "extended expiry", token: parts[0] + "." + "9999999999" + "." + parts[2], now: now, want: httpErr.WrongCSRFToken},
+		{name: "changed nonce", token: "AAAAAAAAAAAAAAAAAAAAAA." + parts[1] + "." + parts[2], now: now, want: httpErr.WrongCSRFToken},
+		{name: "empty", token: "", now: now, want: httpErr.CSRFNotPresented},
+		{name: "no separators", token: "garbage", now: now, want: httpErr.WrongCSRFToken},
+		{name: "missing MAC", token: parts[0] + "." + parts[1], now: now, want: httpErr.WrongCSRFToken},
+		{name: "extra part", token: token + ".x", now: now, want: httpErr.WrongCSRFToken},
+		{name: "MAC not base64", token: parts[0] + "." + parts[1] + ".!!!", now: now, want: httpErr.WrongCSRFToken},
+		{name: "empty MAC", token: parts[0] + "." + parts[1] + ".", now: now, want: httpErr.WrongCSRFToken},
+		{name: "signed expiry that is not a number", token: signed(parts[0] + ".soon"), now: now, want: httpErr.WrongCSRFToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := secret
+			if tt.secret != nil {
+				key = tt.secret
+			}
+
+			if err := ValidateToken(key, tt.token, sid, tt.now); err != tt.want {
+				t.Errorf("ValidateToken(%q) = %v, want %v", tt.token, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeTokenIsUnique(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+
+	first, err := MakeToken([]byte("csrf-secret"), "session-a", expiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := MakeToken([]byte("csrf-secret"), "session-a", expiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first == second {
+		t.Error("two tokens of the same session are equal")
+	}
+	if Equal(first, second) || !Equal(first, first) || Equal(first, "") {
+		t.Error("Equal compares tokens wrongly")
+	}
+}
@@ -6,6 +6,10 @@ import (
 	"time"
 
 	"github.com/iamaul/go-pokedex/config"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	pkgErrors "github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -54,3 +58,86 @@ func IsDuplicate(err error) bool {
 
 	return false
 }
+
+// Change streams need a replica set or sharded cluster, standalone servers reject $changeStream
+func IsChangeStreamUnsupported(err error) bool {
+	var e mongo.CommandError
+	if errors.As(err, &e) {
+		return e.Code == 40573 || e.Code == 40324
+	}
+
+	return false
+}
+
+// The resume token can no longer be used, e.g. it fell off the oplog
+func IsResumeTokenInvalid(err error) bool {
+	var e mongo.CommandError
+	if errors.As(err, &e) {
+		return e.Code == 260 || e.Code == 280 || e.Code == 286
+	}
+
+	return false
+}
+
+// Match the document by id and, when given, the version it is expected to be at.
+// Documents written before versioning have no version field and count as version 0.
+func VersionFilter(id primitive.ObjectID, version *int64) bson.M {
+	filter := bson.M{"_id": id}
+	if version != nil {
+		if *version == 0 {
+			filter["version"] = bson.M{"$in": bson.A{0, nil}}
+		} else {
+			filter["version"] = *version
+		}
+	}
+
+	return filter
+}
+
+// Apply the update and bump the document version in one atomic operation, returning the new version.
+// Fails with httpErr.PreconditionFailed when the document moved past the expected version.
+func UpdateVersioned(ctx context.Context, coll *mongo.Collection, id primitive.ObjectID, version *int64, update bson.M) (int64, error) {
+	update["$inc"] = bson.M{"version": 1}
+
+	var updated struct {
+		Version int64 `bson:"version"`
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After).SetProjection(bson.M{"version": 1})
+	if err := coll.FindOneAndUpdate(ctx, VersionFilter(id, version), update, opts).Decode(&updated); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, versionMismatch(ctx, coll, id, version)
+		}
+		return 0, pkgErrors.Wrap(err, "db.FindOneAndUpdate")
+	}
+
+	return updated.Version, nil
+}
+
+// Delete the document if it is still at the expected version, nil skips the check
+func DeleteVersioned(ctx context.Context, coll *mongo.Collection, id primitive.ObjectID, version *int64) error {
+	result, err := coll.DeleteOne(ctx, VersionFilter(id, version))
+	if err != nil {
+		return pkgErrors.Wrap(err, "db.DeleteOne")
+	}
+
+	if result.DeletedCount == 0 && version != nil {
+		return versionMismatch(ctx, coll, id, version)
+	}
+
+	return nil
+}
+
+// Tell a missing document apart from one at another version
+func versionMismatch(ctx context.Context, coll *mongo.Collection, id primitive.ObjectID, version *int64) error {
+	if version != nil {
+		count, err := coll.CountDocuments(ctx, bson.M{"_id": id}, options.Count().SetLimit(1))
+		if err != nil {
+			return pkgErrors.Wrap(err, "db.CountDocuments")
+		}
+		if count > 0 {
+			return pkgErrors.Wrapf(httpErr.PreconditionFailed, "version %d", *version)
+		}
+	}
+
+	return pkgErrors.Wrap(mongo.ErrNoDocuments, httpErr.ErrNotFound)
+}
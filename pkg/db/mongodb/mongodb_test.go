@@ -0,0 +1,31 @@
+package mongodb
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestVersionFilter(t *testing.T) {
+	id := primitive.NewObjectID()
+	version := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name    string
+		version *int64
+		want    bson.M
+	}{
+		{name: "unconditional", version: nil, want: bson.M{"_id": id}},
+		{name: "expected version", version: version(3), want: bson.M{"_id": id, "version": int64(3)}},
+		{name: "documents from before versioning", version: version(0), want: bson.M{"_id": id, "version": bson.M{"$in": bson.A{0, nil}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VersionFilter(id, tt.version); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("VersionFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,18 +7,28 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 )
 
 const (
-	ErrBadRequest           = "bad request"
-	ErrUserAlreadyExists    = "user with given username already exists"
-	ErrMonsterAlreadyExists = "monster already exists"
-	ErrNoSuchUser           = "user not found"
-	ErrWrongCredentials     = "wrong credentials"
-	ErrNotFound             = "not found"
-	ErrUnauthorized         = "unauthorized"
-	ErrForbidden            = "forbidden"
-	ErrBadQueryParams       = "invalid query params"
+	ErrBadRequest            = "bad request"
+	ErrUserAlreadyExists     = "user with given username already exists"
+	ErrMonsterAlreadyExists  = "monster already exists"
+	ErrNoSuchUser            = "user not found"
+	ErrWrongCredentials      = "wrong credentials"
+	ErrNotFound              = "not found"
+	ErrUnauthorized          = "unauthorized"
+	ErrForbidden             = "forbidden"
+	ErrBadQueryParams        = "invalid query params"
+	ErrPreconditionFailed    = "precondition failed"
+	ErrRoleAlreadyExists     = "role already exists"
+	ErrIdentityAlreadyLinked = "identity is already linked to another user"
+	ErrMFAAlreadyEnabled     = "two-factor authentication is already enabled"
+	ErrMFANotEnabled         = "two-factor authentication is not enabled"
+	ErrMFANotPending         = "no pending two-factor enrollment"
+	ErrMFARequired           = "two-factor authentication is required for this account"
+	ErrMFASignInOverHTTP     = "two-factor authentication is enabled, sign in over HTTP"
+	ErrTooManyLoginAttempts  = "too many failed sign ins, retry later"
 )
 
 var (
@@ -38,8 +48,16 @@ var (
 	ExistsEmailError      = errors.New("user with given username already exists")
 	InvalidJWTToken       = errors.New("invalid JWT token")
 	InvalidJWTClaims      = errors.New("invalid JWT claims")
+	RevokedJWTToken       = errors.New("revoked JWT token")
+	InvalidInviteCode     = errors.New("invalid or expired invite code")
+	InvalidAPIKey         = errors.New("invalid or expired API key")
+	InvalidOIDCState      = errors.New("invalid or expired OIDC state")
+	InvalidMFACode        = errors.New("invalid two-factor code")
+	InvalidMFAChallenge   = errors.New("invalid or expired two-factor challenge")
+	InvalidSession        = errors.New("invalid or expired session")
 	NotAllowedImageHeader = errors.New("not allowed image header")
 	NoCookie              = errors.New("not found cookie header")
+	PreconditionFailed    = errors.New("precondition failed")
 )
 
 // RestErr methods interface
@@ -50,9 +68,9 @@ type RestErr interface {
 }
 
 type RestError struct {
-	ErrStatus int         `json:"status,omitempty"`
-	ErrError  string      `json:"error,omitempty"`
-	ErrCauses interface{} `json:"-"`
+	ErrStatus int         `json:"status,omitempty" xml:"status,omitempty"`
+	ErrError  string      `json:"error,omitempty" xml:"error,omitempty"`
+	ErrCauses interface{} `json:"-" xml:"-"`
 }
 
 // RestError func methods
@@ -85,6 +103,45 @@ func NewRestErrorWithMessage(status int, err string, causes interface{}) RestErr
 	}
 }
 
+// A single reason a request or response was rejected
+type Violation struct {
+	In      string `json:"in,omitempty" xml:"in,omitempty"`
+	Field   string `json:"field,omitempty" xml:"field,omitempty"`
+	Message string `json:"message" xml:"message"`
+}
+
+// RestError listing every violation to the client
+type ViolationError struct {
+	RestError
+	Violations []Violation `json:"violations" xml:"violations>violation"`
+}
+
+// ViolationError constructor
+func NewViolationError(status int, err string, violations []Violation) RestErr {
+	return ViolationError{
+		RestError:  RestError{ErrStatus: status, ErrError: err, ErrCauses: violations},
+		Violations: violations,
+	}
+}
+
+// RestError telling the client how long to wait, rendered with a Retry-After header
+type RetryAfterError struct {
+	RestError
+	RetryAfter time.Duration `json:"-" xml:"-"`
+}
+
+// Whole seconds to wait, rounded up so clients never retry early
+func (e RetryAfterError) RetryAfterSeconds() int {
+	return int((e.RetryAfter + time.Second - 1) / time.Second)
+}
+
+func NewTooManyRequestsError(err string, retryAfter time.Duration, causes interface{}) RestErr {
+	return RetryAfterError{
+		RestError:  RestError{ErrStatus: http.StatusTooManyRequests, ErrError: err, ErrCauses: causes},
+		RetryAfter: retryAfter,
+	}
+}
+
 func NewRestErrorFromBytes(bytes []byte) (RestErr, error) {
 	var apiErr RestError
 	if err := json.Unmarshal(bytes, &apiErr); err != nil {
@@ -125,6 +182,14 @@ func NewForbiddenError(causes interface{}) RestErr {
 	}
 }
 
+func NewPreconditionFailedError(causes interface{}) RestErr {
+	return RestError{
+		ErrStatus: http.StatusPreconditionFailed,
+		ErrError:  PreconditionFailed.Error(),
+		ErrCauses: causes,
+	}
+}
+
 func NewInternalServerError(causes interface{}) RestErr {
 	result := RestError{
 		ErrStatus: http.StatusInternalServerError,
@@ -135,7 +200,15 @@ func NewInternalServerError(causes interface{}) RestErr {
 }
 
 func ParseErrors(err error) RestErr {
+	// Errors built with an explicit status keep it, their messages and causes may mention
+	// anything matched below, e.g. a token or a field
+	if restErr, ok := err.(RestErr); ok {
+		return restErr
+	}
+
 	switch {
+	case errors.Is(err, PreconditionFailed):
+		return NewPreconditionFailedError(err)
 	case errors.Is(err, context.DeadlineExceeded):
 		return NewRestError(http.StatusRequestTimeout, RequestTimeoutError.Error(), err)
 	case strings.Contains(err.Error(), "Field validation"):
@@ -151,9 +224,6 @@ func ParseErrors(err error) RestErr {
 	case strings.Contains(strings.ToLower(err.Error()), "bcrypt"):
 		return NewRestError(http.StatusBadRequest, BadRequest.Error(), err)
 	default:
-		if restErr, ok := err.(RestErr); ok {
-			return restErr
-		}
 		return NewInternalServerError(err)
 	}
 }
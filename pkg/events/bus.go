@@ -0,0 +1,221 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	TopicMonster     = "monster"
+	TopicMonsterType = "monster_type"
+	TopicUser        = "user"
+
+	MonsterCreated     = "monster.created"
+	MonsterUpdated     = "monster.updated"
+	MonsterDeleted     = "monster.deleted"
+	MonsterTypeCreated = "monster_type.created"
+	MonsterTypeUpdated = "monster_type.updated"
+	MonsterTypeDeleted = "monster_type.deleted"
+	UserCreated        = "user.created"
+	UserUpdated        = "user.updated"
+	UserDeleted        = "user.deleted"
+	UserMonsterCaught  = "user.monster_caught"
+
+	// Where an event was observed
+	SourceAPI          = "api"
+	SourceChangeStream = "change_stream"
+
+	subscriberBuffer = 64
+)
+
+// Topics a client may subscribe to
+var Topics = []string{TopicMonster, TopicMonsterType, TopicUser}
+
+// Every event type published by the usecases
+var Types = []string{
+	MonsterCreated, MonsterUpdated, MonsterDeleted,
+	MonsterTypeCreated, MonsterTypeUpdated, MonsterTypeDeleted,
+	UserCreated, UserUpdated, UserDeleted, UserMonsterCaught,
+}
+
+type Event struct {
+	ID     uint64          `json:"id" xml:"id"`
+	Topic  string          `json:"topic" xml:"topic"`
+	Type   string          `json:"type" xml:"type"`
+	Source string          `json:"source" xml:"source"`
+	Data   json.RawMessage `json:"data" xml:"-" swaggertype:"object"`
+	Time   time.Time       `json:"time" xml:"time"`
+}
+
+type sourceCtxKey struct{}
+
+// Mark events published with ctx as coming from source
+func WithSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, sourceCtxKey{}, source)
+}
+
+// Source of events published with ctx, the API unless marked otherwise
+func SourceFromCtx(ctx context.Context) string {
+	if source, ok := ctx.Value(sourceCtxKey{}).(string); ok {
+		return source
+	}
+	return SourceAPI
+}
+
+// Publisher is the side of the bus the usecases depend on
+type Publisher interface {
+	Publish(ctx context.Context, eventType string, data interface{})
+}
+
+// Publishers fans every event out to several publishers
+type Publishers []Publisher
+
+func (p Publishers) Publish(ctx context.Context, eventType string, data interface{}) {
+	for _, publisher := range p {
+		publisher.Publish(ctx, eventType, data)
+	}
+}
+
+// Bus fans events out to subscribers and keeps the latest ones in a bounded
+// ring buffer so reconnecting clients can resume from their last event id
+type Bus struct {
+	mu          sync.Mutex
+	nextID      uint64
+	buffer      []Event
+	start       int
+	size        int
+	subscribers map[*Subscription]struct{}
+}
+
+type Subscription struct {
+	C      chan Event
+	topics map[string]bool
+	bus    *Bus
+	closed bool
+}
+
+// Event bus constructor
+func NewBus(bufferSize int) *Bus {
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+
+	return &Bus{
+		nextID:      1,
+		buffer:      make([]Event, bufferSize),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish stamps the event with the next id, buffers it and delivers it to matching subscribers.
+// Subscribers that fall behind are dropped and are expected to reconnect with Last-Event-ID.
+func (b *Bus) Publish(ctx context.Context, eventType string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	event := Event{
+		ID:     b.nextID,
+		Topic:  TopicOf(eventType),
+		Type:   eventType,
+		Source: SourceFromCtx(ctx),
+		Data:   payload,
+		Time:   time.Now().UTC(),
+	}
+	b.nextID++
+
+	b.buffer[(b.start+b.size)%len(b.buffer)] = event
+	if b.size < len(b.buffer) {
+		b.size++
+	} else {
+		b.start = (b.start + 1) % len(b.buffer)
+	}
+
+	for sub := range b.subscribers {
+		if !sub.topics[event.Topic] {
+			continue
+		}
+		select {
+		case sub.C <- event:
+		default:
+			b.unsubscribe(sub)
+		}
+	}
+}
+
+// Subscribe registers a subscriber for topics (all topics when empty) and returns the
+// buffered events after lastEventID, registered atomically so nothing is missed in between
+func (b *Bus) Subscribe(topics []string, lastEventID string) (*Subscription, []Event) {
+	sub := &Subscription{
+		C:      make(chan Event, subscriberBuffer),
+		topics: make(map[string]bool),
+		bus:    b,
+	}
+
+	if len(topics) == 0 {
+		topics = Topics
+	}
+	for _, topic := range topics {
+		sub.topics[topic] = true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []Event
+	if lastID, err := strconv.ParseUint(lastEventID, 10, 64); err == nil {
+		for i := 0; i < b.size; i++ {
+			event := b.buffer[(b.start+i)%len(b.buffer)]
+			if event.ID > lastID && sub.topics[event.Topic] {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	b.subscribers[sub] = struct{}{}
+
+	return sub, replay
+}
+
+// Close removes the subscription from the bus, it is safe to call more than once
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	s.bus.unsubscribe(s)
+}
+
+func (b *Bus) unsubscribe(sub *Subscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(b.subscribers, sub)
+	close(sub.C)
+}
+
+// Check topic names sent by a client
+func ValidTopic(topic string) bool {
+	for _, t := range Topics {
+		if t == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// Topic of an event type, e.g. monster for monster.created
+func TopicOf(eventType string) string {
+	if i := strings.LastIndex(eventType, "."); i >= 0 {
+		return eventType[:i]
+	}
+	return eventType
+}
@@ -0,0 +1,64 @@
+package grpcerr
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+)
+
+// RestErr status mapped to the closest gRPC code
+var codeMap = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusNotAcceptable:         codes.InvalidArgument,
+	http.StatusRequestTimeout:        codes.DeadlineExceeded,
+	http.StatusConflict:              codes.AlreadyExists,
+	http.StatusPreconditionFailed:    codes.FailedPrecondition,
+	http.StatusRequestEntityTooLarge: codes.InvalidArgument,
+	http.StatusUnsupportedMediaType:  codes.InvalidArgument,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+	http.StatusInternalServerError:   codes.Internal,
+	http.StatusNotImplemented:        codes.Unimplemented,
+	http.StatusServiceUnavailable:    codes.Unavailable,
+}
+
+// Map RestErr status to gRPC code
+func Code(httpStatus int) codes.Code {
+	if code, ok := codeMap[httpStatus]; ok {
+		return code
+	}
+
+	switch {
+	case httpStatus >= 500:
+		return codes.Internal
+	case httpStatus >= 400:
+		return codes.FailedPrecondition
+	default:
+		return codes.Unknown
+	}
+}
+
+// Convert usecase error to gRPC status error
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	restErr := httpErr.ParseErrors(err)
+
+	message := http.StatusText(restErr.Status())
+	if e, ok := restErr.(httpErr.RestError); ok && e.ErrError != "" {
+		message = e.ErrError
+	}
+
+	return status.Error(Code(restErr.Status()), message)
+}
@@ -0,0 +1,72 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const jpegQuality = 85
+
+// Allowed upload content types mapped to their file extension
+var allowedTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// Sniff content type from the file content, ignoring any client supplied header
+func DetectContentType(data []byte) (contentType string, ext string, ok bool) {
+	contentType = http.DetectContentType(data)
+	ext, ok = allowedTypes[contentType]
+	return contentType, ext, ok
+}
+
+// Read the dimensions from the image header without decoding the pixels
+func DecodeConfig(data []byte) (image.Config, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	return cfg, err
+}
+
+// Decode image bytes
+func Decode(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// Resize image to the given width keeping the aspect ratio, images narrower
+// than width are returned unchanged
+func Thumbnail(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+	if bounds.Dx() <= width {
+		return src
+	}
+
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+	return dst
+}
+
+// Encode thumbnail, JPEG sources stay JPEG and everything else becomes PNG to keep transparency
+func Encode(img image.Image, sourceType string) (data []byte, contentType string, ext string, err error) {
+	var buf bytes.Buffer
+	if sourceType == "image/jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+		return buf.Bytes(), "image/jpeg", "jpg", err
+	}
+
+	err = png.Encode(&buf, img)
+	return buf.Bytes(), "image/png", "png", err
+}
@@ -0,0 +1,68 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+)
+
+// The JWK Set served at /.well-known/jwks.json, RFC 7517
+type JSONWebKeySet struct {
+	Keys []*JSONWebKey `json:"keys"`
+}
+
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+func newJSONWebKey(key *signingKey) *JSONWebKey {
+	jwk := &JSONWebKey{Kid: key.id, Use: "sig", Alg: key.method.Alg()}
+
+	switch public := key.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+
+	return jwk
+}
+
+// RFC 7638 thumbprint, the hash of the required members in lexicographic order
+func (k *JSONWebKey) thumbprint() string {
+	var members interface{}
+	switch k.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{k.E, k.Kty, k.N}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{k.Crv, k.Kty, k.X}
+	}
+
+	// Marshalling strings never fails
+	b, _ := json.Marshal(members)
+	sum := sha256.Sum256(b)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
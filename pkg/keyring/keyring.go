@@ -0,0 +1,220 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/iamaul/go-pokedex/config"
+)
+
+// A private key of the ring, it signs from ActiveFrom until the next key takes over
+type signingKey struct {
+	id         string
+	method     jwt.SigningMethod
+	private    interface{}
+	public     interface{}
+	activeFrom time.Time
+	// When the next key took over, zero while this one is the newest
+	replacedAt time.Time
+}
+
+// Keys signing and verifying access tokens. Without configured keys tokens are
+// signed with the shared secret, which has no key ID and is never published.
+type KeyRing struct {
+	keys    []*signingKey
+	secret  []byte
+	overlap time.Duration
+}
+
+// Load the configured keys, overlap is how long a replaced key keeps verifying tokens
+func NewKeyRing(keys []config.JWTKey, secret string, overlap time.Duration) (*KeyRing, error) {
+	r := &KeyRing{secret: []byte(secret), overlap: overlap}
+
+	ids := make(map[string]bool, len(keys))
+	for i, cfg := range keys {
+		key, err := loadKey(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("keyring: key %d: %w", i, err)
+		}
+		if ids[key.id] {
+			return nil, fmt.Errorf("keyring: duplicate key ID %q", key.id)
+		}
+		ids[key.id] = true
+
+		r.keys = append(r.keys, key)
+	}
+
+	// Keys take over in the order they become active
+	sort.SliceStable(r.keys, func(i, j int) bool {
+		return r.keys[i].activeFrom.Before(r.keys[j].activeFrom)
+	})
+	for i := 1; i < len(r.keys); i++ {
+		// Keys active from the same time would both claim to be the newest
+		if r.keys[i-1].activeFrom.Equal(r.keys[i].activeFrom) {
+			if r.keys[i].activeFrom.IsZero() {
+				return nil, fmt.Errorf("keyring: keys %q and %q have no ActiveFrom", r.keys[i-1].id, r.keys[i].id)
+			}
+			return nil, fmt.Errorf("keyring: keys %q and %q are both active from %s", r.keys[i-1].id, r.keys[i].id, r.keys[i].activeFrom)
+		}
+		r.keys[i-1].replacedAt = r.keys[i].activeFrom
+	}
+
+	if len(r.keys) == 0 && len(r.secret) == 0 {
+		return nil, errors.New("keyring: no keys and no secret to sign tokens with")
+	}
+
+	return r, nil
+}
+
+// Sign the claims with the key active now, the token names it in its kid header
+func (r *KeyRing) Sign(claims jwt.Claims) (string, error) {
+	if len(r.keys) == 0 {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(r.secret)
+	}
+
+	key := r.signingKey(time.Now())
+	if key == nil {
+		return "", errors.New("keyring: no key is active yet")
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+
+	return token.SignedString(key.private)
+}
+
+// Key verifying the token, selected by its kid. The algorithm has to be the one of the key,
+// and keys replaced longer than the overlap ago no longer verify.
+func (r *KeyRing) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if len(r.keys) == 0 {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signin method %v", token.Header["alg"])
+		}
+		return r.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	now := time.Now()
+	for _, key := range r.keys {
+		if key.id != kid {
+			continue
+		}
+		if !r.verifies(key, now) {
+			return nil, fmt.Errorf("keyring: key %q is not in use", kid)
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("unexpected signin method %v", token.Header["alg"])
+		}
+
+		return key.public, nil
+	}
+
+	return nil, fmt.Errorf("keyring: unknown key %q", kid)
+}
+
+// Algorithms tokens may be signed with
+func (r *KeyRing) Methods() []string {
+	if len(r.keys) == 0 {
+		return []string{jwt.SigningMethodHS256.Alg()}
+	}
+
+	seen := make(map[string]bool)
+	methods := make([]string, 0, 2)
+	for _, key := range r.keys {
+		if alg := key.method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+
+	return methods
+}
+
+// Public keys that verify tokens now, and the ones taking over within the overlap
+// so verifiers caching the set know them before the first token they sign
+func (r *KeyRing) JWKS() *JSONWebKeySet {
+	now := time.Now()
+
+	set := &JSONWebKeySet{Keys: make([]*JSONWebKey, 0, len(r.keys))}
+	for _, key := range r.keys {
+		upcoming := key.activeFrom.After(now) && !key.activeFrom.After(now.Add(r.overlap))
+		if upcoming || r.verifies(key, now) {
+			set.Keys = append(set.Keys, newJSONWebKey(key))
+		}
+	}
+
+	return set
+}
+
+func (r *KeyRing) signingKey(now time.Time) *signingKey {
+	var active *signingKey
+	for _, key := range r.keys {
+		if key.activeFrom.After(now) {
+			break
+		}
+		active = key
+	}
+
+	return active
+}
+
+func (r *KeyRing) verifies(key *signingKey, now time.Time) bool {
+	if key.activeFrom.After(now) {
+		return false
+	}
+
+	return key.replacedAt.IsZero() || now.Before(key.replacedAt.Add(r.overlap))
+}
+
+func loadKey(cfg config.JWTKey) (*signingKey, error) {
+	data, err := os.ReadFile(cfg.PrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s holds no PEM block", cfg.PrivateKeyFile)
+	}
+
+	var private interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &signingKey{id: cfg.ID, private: private, activeFrom: cfg.ActiveFrom}
+	switch private := private.(type) {
+	case *rsa.PrivateKey:
+		if private.N.BitLen() < 2048 {
+			return nil, errors.New("RSA keys need at least 2048 bits")
+		}
+		key.method, key.public = jwt.SigningMethodRS256, &private.PublicKey
+	case ed25519.PrivateKey:
+		key.method, key.public = jwt.SigningMethodEdDSA, private.Public()
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", private)
+	}
+
+	if key.id == "" {
+		key.id = newJSONWebKey(key).thumbprint()
+	}
+
+	return key, nil
+}
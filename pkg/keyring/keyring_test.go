@@ -0,0 +1,296 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/iamaul/go-pokedex/config"
+)
+
+var (
+	rsaOnce sync.Once
+	rsaKey  *rsa.PrivateKey
+)
+
+// PEM file of a fresh key, RSA keys are generated once per run as that is slow
+func writeKey(t *testing.T, kind string) string {
+	t.Helper()
+
+	var block *pem.Block
+	switch kind {
+	case "rsa":
+		rsaOnce.Do(func() {
+			var err error
+			if rsaKey, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+				panic(err)
+			}
+		})
+		block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}
+	case "rsa-1024":
+		key, err := rsa.GenerateKey(rand.Reader, 1024)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	case "ed25519":
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	}
+
+	file, err := os.CreateTemp(t.TempDir(), kind+"-*.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := pem.Encode(file, block); err != nil {
+		t.Fatal(err)
+	}
+
+	return file.Name()
+}
+
+func TestNewKeyRing(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	tests := []struct {
+		name   string
+		keys   func(t *testing.T) []config.JWTKey
+		secret string
+		ok     bool
+	}{
+		{name: "secret only", secret: "secret", ok: true},
+		{name: "neither keys nor secret"},
+		{
+			name: "single key without ActiveFrom",
+			keys: func(t *testing.T) []config.JWTKey {
+				return []config.JWTKey{{ID: "a", PrivateKeyFile: writeKey(t, "ed25519")}}
+			},
+			ok: true,
+		},
+		{
+			name: "first key without ActiveFrom and a dated one",
+			keys: func(t *testing.T) []config.JWTKey {
+				return []config.JWTKey{
+					{ID: "b", PrivateKeyFile: writeKey(t, "rsa"), ActiveFrom: now},
+					{ID: "a", PrivateKeyFile: writeKey(t, "ed25519")},
+				}
+			},
+			ok: true,
+		},
+		{
+			name: "two keys without ActiveFrom",
+			keys: func(t *testing.T) []config.JWTKey {
+				return []config.JWTKey{
+					{ID: "a", PrivateKeyFile: writeKey(t, "ed25519")},
+					{ID: "b", PrivateKeyFile: writeKey(t, "ed25519")},
+				}
+			},
+		},
+		{
+			name: "two keys active from the same time",
+			keys: func(t *testing.T) []config.JWTKey {
+				return []config.JWTKey{
+					{ID: "a", PrivateKeyFile: writeKey(t, "ed25519"), ActiveFrom: now},
+					{ID: "c", PrivateKeyFile: writeKey(t, "ed25519"), ActiveFrom: now.Add(time.Hour)},
+					{ID: "b", PrivateKeyFile: writeKey(t, "rsa"), ActiveFrom: now.In(time.FixedZone("UTC+2", 7200))},
+				}
+			},
+		},
+		{
+			name: "duplicate key ID",
+			keys: func(t *testing.T) []config.JWTKey {
+				return []config.JWTKey{
+					{ID: "a", PrivateKeyFile: writeKey(t, "ed25519"), ActiveFrom: now},
+					{ID: "a", PrivateKeyFile: writeKey(t, "ed25519"), ActiveFrom: now.Add(time.Hour)},
+				}
+			},
+		},
+		{
+			name: "RSA key too short",
+			keys: func(t *testing.T) []config.JWTKey {
+				return []config.JWTKey{{ID: "a", PrivateKeyFile: writeKey(t, "rsa-1024")}}
+			},
+		},
+		{
+			name: "missing key file",
+			keys: func(t *testing.T) []config.JWTKey {
+				return []config.JWTKey{{ID: "a", PrivateKeyFile: filepath.Join(t.TempDir(), "missing.pem")}}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []config.JWTKey
+			if tt.keys != nil {
+				keys = tt.keys(t)
+			}
+
+			_, err := NewKeyRing(keys, tt.secret, time.Hour)
+			if tt.ok && err != nil {
+				t.Errorf("refused: %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Error("accepted")
+			}
+		})
+	}
+}
+
+// Ring of a key replaced 30 minutes ago, the current key and one taking over in 20 minutes
+func rotatingKeyRing(t *testing.T, overlap time.Duration) *KeyRing {
+	t.Helper()
+
+	now := time.Now()
+	r, err := NewKeyRing([]config.JWTKey{
+		{ID: "next", PrivateKeyFile: writeKey(t, "ed25519"), ActiveFrom: now.Add(20 * time.Minute)},
+		{ID: "old", PrivateKeyFile: writeKey(t, "rsa"), ActiveFrom: now.Add(-2 * time.Hour)},
+		{ID: "current", PrivateKeyFile: writeKey(t, "ed25519"), ActiveFrom: now.Add(-30 * time.Minute)},
+	}, "secret", overlap)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return r
+}
+
+func (r *KeyRing) key(id string) *signingKey {
+	for _, key := range r.keys {
+		if key.id == id {
+			return key
+		}
+	}
+	return nil
+}
+
+func signWith(t *testing.T, method jwt.SigningMethod, kid string, private interface{}) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, jwt.RegisteredClaims{Subject: "ash", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func TestKeyRingRotation(t *testing.T) {
+	r := rotatingKeyRing(t, time.Hour)
+
+	token, err := r.Sign(jwt.RegisteredClaims{Subject: "ash"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid := parsed.Header["kid"]; kid != "current" || parsed.Method.Alg() != jwt.SigningMethodEdDSA.Alg() {
+		t.Fatalf("signed with %v (%s), want the current key", kid, parsed.Method.Alg())
+	}
+
+	old, current, next := r.key("old"), r.key("current"), r.key("next")
+
+	tests := []struct {
+		name  string
+		ring  *KeyRing
+		token string
+		ok    bool
+	}{
+		{name: "token of the ring", ring: r, token: token, ok: true},
+		{name: "replaced key within the overlap", ring: r, token: signWith(t, jwt.SigningMethodRS256, "old", old.private), ok: true},
+		{name: "replaced key after the overlap", ring: rotatingKeyRing(t, 10*time.Minute), token: signWith(t, jwt.SigningMethodRS256, "old", old.private)},
+		{name: "key not active yet", ring: r, token: signWith(t, jwt.SigningMethodEdDSA, "next", next.private)},
+		{name: "unknown kid", ring: r, token: signWith(t, jwt.SigningMethodEdDSA, "other", current.private)},
+		{name: "no kid", ring: r, token: signWith(t, jwt.SigningMethodEdDSA, "", current.private)},
+		{name: "kid of another key", ring: r, token: signWith(t, jwt.SigningMethodEdDSA, "current", next.private)},
+		{name: "algorithm of another key", ring: r, token: signWith(t, jwt.SigningMethodEdDSA, "old", current.private)},
+		{name: "shared secret while keys are configured", ring: r, token: signWith(t, jwt.SigningMethodHS256, "current", []byte("secret"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := jwt.Parse(tt.token, tt.ring.Keyfunc, jwt.WithValidMethods(tt.ring.Methods()))
+			if tt.ok && err != nil {
+				t.Errorf("refused: %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Error("accepted")
+			}
+		})
+	}
+}
+
+func TestKeyRingJWKS(t *testing.T) {
+	tests := []struct {
+		name    string
+		overlap time.Duration
+		want    []string
+	}{
+		{name: "replaced and upcoming keys within the overlap", overlap: time.Hour, want: []string{"old", "current", "next"}},
+		{name: "only the current key outside the overlap", overlap: 10 * time.Minute, want: []string{"current"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := rotatingKeyRing(t, tt.overlap).JWKS()
+
+			kids := make([]string, 0, len(set.Keys))
+			for _, key := range set.Keys {
+				kids = append(kids, key.Kid)
+			}
+			if len(kids) != len(tt.want) {
+				t.Fatalf("published %v, want %v", kids, tt.want)
+			}
+			for i := range kids {
+				if kids[i] != tt.want[i] {
+					t.Fatalf("published %v, want %v", kids, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSecretKeyRing(t *testing.T) {
+	r, err := NewKeyRing(nil, "secret", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := r.Sign(jwt.RegisteredClaims{Subject: "ash"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jwt.Parse(token, r.Keyfunc, jwt.WithValidMethods(r.Methods())); err != nil {
+		t.Errorf("own token refused: %v", err)
+	}
+
+	if _, err := jwt.Parse(signWith(t, jwt.SigningMethodHS256, "", []byte("other")), r.Keyfunc); err == nil {
+		t.Error("token of another secret accepted")
+	}
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jwt.Parse(signWith(t, jwt.SigningMethodEdDSA, "", private), r.Keyfunc); err == nil {
+		t.Error("EdDSA token accepted by a secret ring")
+	}
+}
@@ -0,0 +1,39 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// Appends messages as JSON lines to a file
+type FileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+func (n *FileNotifier) Notify(ctx context.Context, msg *Message) error {
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,21 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/iamaul/go-pokedex/pkg/logger"
+)
+
+// Writes messages to the application log, for local development only
+type LogNotifier struct {
+	logger logger.Logger
+}
+
+func NewLogNotifier(log logger.Logger) *LogNotifier {
+	return &LogNotifier{logger: log}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, msg *Message) error {
+	n.logger.Infof("notification to: %s, subject: %s, body: %s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
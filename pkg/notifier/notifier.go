@@ -0,0 +1,33 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/pkg/logger"
+)
+
+// Message addressed to a user, users have no email so To is the username
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Notifier methods interface
+type Notifier interface {
+	Notify(ctx context.Context, msg *Message) error
+}
+
+// Notifier constructor based on the configured driver
+func NewNotifier(cfg *config.Config, log logger.Logger) (Notifier, error) {
+	switch cfg.Notifier.Driver {
+	case "", "log":
+		return NewLogNotifier(log), nil
+	case "file":
+		return NewFileNotifier(cfg.Notifier.FilePath), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier driver %q", cfg.Notifier.Driver)
+	}
+}
@@ -0,0 +1,166 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Keys are refetched when a token names one we do not know, providers rotate them,
+// but at most this often so forged key IDs can't make us hammer the provider
+const minKeyRefreshInterval = time.Minute
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// Signing keys of a provider, fetched from its JWKS endpoint
+type keySet struct {
+	uri    string
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+func newKeySet(uri string, client *http.Client) *keySet {
+	return &keySet{uri: uri, client: client}
+}
+
+func (s *keySet) key(ctx context.Context, kid string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+
+	if s.keys != nil && time.Since(s.fetchedAt) < minKeyRefreshInterval {
+		return nil, fmt.Errorf("oidc: unknown signing key %q", kid)
+	}
+
+	keys, err := s.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.keys = keys
+	s.fetchedAt = time.Now()
+
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("oidc: unknown signing key %q", kid)
+}
+
+// Tokens without a key ID are accepted when the provider has a single key
+func (s *keySet) lookup(kid string) (interface{}, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+func (s *keySet) fetch(ctx context.Context) (map[string]interface{}, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJSON(ctx, s.client, s.uri, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Keys of unsupported types are skipped, the others may still verify tokens
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (k *jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("oidc: RSA exponent is too large")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("oidc: unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("oidc: EC point is not on the curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("oidc: unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("oidc: empty key parameter")
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,12 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// S256 code challenge of a PKCE code verifier (RFC 7636)
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,280 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/iamaul/go-pokedex/config"
+)
+
+// Responses of the provider are small, anything bigger is not read
+const maxResponseSize = 1 << 20
+
+// Only asymmetric algorithms, the client secret is never used to verify ID tokens
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+var ErrUnknownProvider = errors.New("unknown OIDC provider")
+
+// Claims of a verified ID token
+type Identity struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+	Name              string
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string `json:"nonce"`
+	AuthorizedParty   string `json:"azp"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	PreferredUsername string `json:"preferred_username"`
+	Name              string `json:"name"`
+}
+
+// Endpoints from the provider's discovery document
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Time users have to sign in at the provider, ten minutes unless configured
+func StateTTL(cfg *config.Config) time.Duration {
+	if cfg.OIDC.StateTTL <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Second * cfg.OIDC.StateTTL
+}
+
+// Configured providers by name
+type Providers map[string]*Provider
+
+func NewProviders(cfg *config.Config) Providers {
+	providers := make(Providers, len(cfg.OIDC.Providers))
+	for name, providerCfg := range cfg.OIDC.Providers {
+		providers[name] = NewProvider(name, providerCfg)
+	}
+
+	return providers
+}
+
+func (p Providers) Get(name string) (*Provider, error) {
+	provider, ok := p[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	return provider, nil
+}
+
+// OpenID Connect provider signing users in with the authorization code flow and PKCE
+type Provider struct {
+	name   string
+	cfg    config.OIDCProvider
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *metadata
+	keys     *keySet
+}
+
+func NewProvider(name string, cfg config.OIDCProvider) *Provider {
+	return &Provider{name: name, cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// URL of the provider's login page, the user comes back to the redirect URL with a code
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	m, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(m.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(p.scopes(), " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", CodeChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+// Redeem the authorization code and verify the ID token issued with it
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	m, keys, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	// Public clients have no secret and identify with their ID only
+	if p.cfg.ClientSecret == "" {
+		form.Set("client_id", p.cfg.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var token tokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&token); err != nil {
+		return nil, fmt.Errorf("oidc: decode token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || token.Error != "" {
+		return nil, fmt.Errorf("oidc: token endpoint returned %d: %s %s", resp.StatusCode, token.Error, token.ErrorDescription)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("oidc: token response has no id_token")
+	}
+
+	return p.verify(ctx, keys, token.IDToken, nonce)
+}
+
+func (p *Provider) verify(ctx context.Context, keys *keySet, rawIDToken, nonce string) (*Identity, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return keys.key(ctx, kid)
+	},
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(p.cfg.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: verify id_token: %w", err)
+	}
+
+	switch {
+	case claims.ExpiresAt == nil:
+		return nil, errors.New("oidc: id_token has no expiry")
+	case claims.Subject == "":
+		return nil, errors.New("oidc: id_token has no subject")
+	case claims.Nonce != nonce:
+		return nil, errors.New("oidc: id_token nonce does not match")
+	case len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientID:
+		return nil, errors.New("oidc: id_token was issued to another party")
+	}
+
+	return &Identity{
+		Issuer:            claims.Issuer,
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		EmailVerified:     claims.EmailVerified,
+		PreferredUsername: claims.PreferredUsername,
+		Name:              claims.Name,
+	}, nil
+}
+
+// Discovery is done on first use, so a provider that is down does not keep the API from starting
+func (p *Provider) discover(ctx context.Context) (*metadata, *keySet, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, p.keys, nil
+	}
+
+	m := &metadata{}
+	if err := getJSON(ctx, p.client, strings.TrimSuffix(p.cfg.Issuer, "/")+"/.well-known/openid-configuration", m); err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case m.Issuer != p.cfg.Issuer:
+		return nil, nil, fmt.Errorf("oidc: discovery issuer %q does not match %q", m.Issuer, p.cfg.Issuer)
+	case m.AuthorizationEndpoint == "" || m.TokenEndpoint == "" || m.JWKSURI == "":
+		return nil, nil, errors.New("oidc: discovery document is missing endpoints")
+	}
+
+	p.metadata = m
+	p.keys = newKeySet(m.JWKSURI, p.client)
+
+	return p.metadata, p.keys, nil
+}
+
+func (p *Provider) scopes() []string {
+	if len(p.cfg.Scopes) == 0 {
+		return []string{"openid", "profile", "email"}
+	}
+
+	for _, scope := range p.cfg.Scopes {
+		if scope == "openid" {
+			return p.cfg.Scopes
+		}
+	}
+
+	return append([]string{"openid"}, p.cfg.Scopes...)
+}
+
+func getJSON(ctx context.Context, client *http.Client, rawURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc: GET %s returned %d", rawURL, resp.StatusCode)
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v)
+}
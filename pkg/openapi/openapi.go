@@ -0,0 +1,191 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/pkg/errors"
+
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+)
+
+// Swag has no writeOnly tag, fields marked with this extension only appear in requests
+const writeOnlyExtension = "x-write-only"
+
+// Load converts the Swagger 2.0 document generated by swag into OpenAPI 3
+func Load(swagger []byte) (*openapi3.T, error) {
+	var doc2 openapi2.T
+	if err := json.Unmarshal(swagger, &doc2); err != nil {
+		return nil, errors.Wrap(err, "openapi.Load.Unmarshal")
+	}
+
+	doc, err := openapi2conv.ToV3(&doc2)
+	if err != nil {
+		return nil, errors.Wrap(err, "openapi.Load.ToV3")
+	}
+
+	// Without a host the base path is dropped by the conversion, keep it as a relative server
+	if len(doc.Servers) == 0 && doc2.BasePath != "" {
+		doc.AddServer(&openapi3.Server{URL: doc2.BasePath})
+	}
+
+	for _, schema := range doc.Components.Schemas {
+		for _, property := range schema.Value.Properties {
+			if property.Value == nil {
+				continue
+			}
+			if _, ok := property.Value.Extensions[writeOnlyExtension]; ok {
+				property.Value.WriteOnly = true
+				delete(property.Value.Extensions, writeOnlyExtension)
+			}
+		}
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, errors.Wrap(err, "openapi.Load.Validate")
+	}
+
+	return doc, nil
+}
+
+// Validator checks requests and responses against an OpenAPI 3 document
+type Validator struct {
+	router  routers.Router
+	options *openapi3filter.Options
+}
+
+// Validator constructor
+func NewValidator(doc *openapi3.T) (*Validator, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "openapi.NewValidator.NewRouter")
+	}
+
+	return &Validator{
+		router: router,
+		options: &openapi3filter.Options{
+			MultiError: true,
+			// Credentials are checked by the auth middleware, the spec only documents them
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}, nil
+}
+
+// ValidateRequest returns the input to validate the response with, nil when the
+// route is not described by the document. Bodies are only validated for JSON and
+// multipart requests, the other negotiated formats are left to the handlers.
+func (v *Validator) ValidateRequest(r *http.Request) (*openapi3filter.RequestValidationInput, error) {
+	route, pathParams, err := v.router.FindRoute(r)
+	if err != nil {
+		return nil, nil
+	}
+
+	options := *v.options
+	options.ExcludeRequestBody = !decodable(r.Header.Get("Content-Type"))
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    &options,
+	}
+
+	if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+		return input, violationError(http.StatusBadRequest, "request does not match the API specification", "", err)
+	}
+
+	return input, nil
+}
+
+// ValidateResponse checks a buffered response of a request validated with ValidateRequest
+func (v *Validator) ValidateResponse(ctx context.Context, input *openapi3filter.RequestValidationInput, status int, header http.Header, body []byte) error {
+	responseInput := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 status,
+		Header:                 header,
+		Options:                v.options,
+	}
+	responseInput.SetBodyBytes(body)
+
+	if err := openapi3filter.ValidateResponse(ctx, responseInput); err != nil {
+		return violationError(http.StatusInternalServerError, "response does not match the API specification", "response", err)
+	}
+
+	return nil
+}
+
+// List every violation reported by the validation error
+func violationError(status int, message string, in string, err error) httpErr.RestErr {
+	var multi openapi3.MultiError
+	if !errors.As(err, &multi) {
+		multi = openapi3.MultiError{err}
+	}
+
+	violations := make([]httpErr.Violation, 0, len(multi))
+	for _, e := range multi {
+		v := violation(e)
+		if v.In == "" {
+			v.In = in
+		}
+		violations = append(violations, v)
+	}
+
+	return httpErr.NewViolationError(status, message, violations)
+}
+
+func violation(err error) httpErr.Violation {
+	var (
+		requestErr  *openapi3filter.RequestError
+		responseErr *openapi3filter.ResponseError
+		securityErr *openapi3filter.SecurityRequirementsError
+		schemaErr   *openapi3.SchemaError
+	)
+
+	v := httpErr.Violation{Message: err.Error()}
+
+	switch {
+	case errors.As(err, &requestErr):
+		v.Message = requestErr.Reason
+		switch {
+		case requestErr.Parameter != nil:
+			v.In = requestErr.Parameter.In
+			v.Field = requestErr.Parameter.Name
+		case requestErr.RequestBody != nil:
+			v.In = "body"
+		}
+	case errors.As(err, &responseErr):
+		v.In = "response"
+		v.Message = responseErr.Reason
+	case errors.As(err, &securityErr):
+		v.In = "security"
+		v.Message = "security requirements are not met"
+	}
+
+	if errors.As(err, &schemaErr) {
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+			v.Field = strings.Join(append([]string{v.Field}, pointer...), "/")
+			v.Field = strings.TrimPrefix(v.Field, "/")
+		}
+		v.Message = schemaErr.Reason
+	}
+
+	if v.Message == "" {
+		v.Message = err.Error()
+	}
+
+	return v
+}
+
+func decodable(contentType string) bool {
+	return contentType == "" ||
+		strings.HasPrefix(contentType, "application/json") ||
+		strings.HasPrefix(contentType, "multipart/form-data")
+}
@@ -0,0 +1,192 @@
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/vmihailenco/msgpack/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+)
+
+const (
+	MIMEApplicationMsgpack = "application/msgpack"
+	MIMETextCSV            = "text/csv"
+
+	structTag = "json"
+)
+
+// Aliases clients commonly send for MessagePack
+var msgpackTypes = map[string]bool{
+	MIMEApplicationMsgpack:      true,
+	"application/x-msgpack":     true,
+	"application/vnd.msgpack":   true,
+	"application/x-messagepack": true,
+}
+
+// CSVMarshaler is implemented by list envelopes that can be rendered as CSV
+type CSVMarshaler interface {
+	MarshalCSV() (header []string, records [][]string)
+}
+
+// Encode object ids as hex strings like the JSON representation does
+func init() {
+	msgpack.Register(primitive.ObjectID{}, func(e *msgpack.Encoder, v reflect.Value) error {
+		return e.EncodeString(v.Interface().(primitive.ObjectID).Hex())
+	}, nil)
+}
+
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// Respond writes i in the representation selected from the Accept header,
+// responding 406 when none of the acceptable types can represent i
+func Respond(c echo.Context, code int, i interface{}) error {
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+
+	mediaType, ok := Negotiate(c.Request().Header.Get(echo.HeaderAccept), i)
+	if !ok {
+		notAcceptable := httpErr.NewRestError(http.StatusNotAcceptable, http.StatusText(http.StatusNotAcceptable), nil)
+		return c.JSON(notAcceptable.Status(), notAcceptable)
+	}
+
+	return write(c, mediaType, code, i)
+}
+
+// Error responds with the parsed RestErr, falling back to JSON when the
+// negotiated type cannot represent an error body
+func Error(c echo.Context, err error) error {
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+
+	status, body := httpErr.ErrorResponse(err)
+	if retryErr, ok := body.(httpErr.RetryAfterError); ok {
+		c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(retryErr.RetryAfterSeconds()))
+	}
+
+	mediaType, ok := Negotiate(c.Request().Header.Get(echo.HeaderAccept), body)
+	if !ok {
+		mediaType = echo.MIMEApplicationJSON
+	}
+
+	return write(c, mediaType, status, body)
+}
+
+// Negotiate picks the representation for i from an Accept header value
+func Negotiate(accept string, i interface{}) (string, bool) {
+	_, csvable := i.(CSVMarshaler)
+
+	for _, r := range parseAccept(accept) {
+		switch {
+		case r.mediaType == "*/*" || r.mediaType == "application/*" || r.mediaType == echo.MIMEApplicationJSON || strings.HasSuffix(r.mediaType, "+json"):
+			return echo.MIMEApplicationJSON, true
+		case r.mediaType == echo.MIMEApplicationXML || r.mediaType == echo.MIMETextXML:
+			return echo.MIMEApplicationXML, true
+		case msgpackTypes[r.mediaType]:
+			return MIMEApplicationMsgpack, true
+		case (r.mediaType == MIMETextCSV || r.mediaType == "text/*") && csvable:
+			return MIMETextCSV, true
+		}
+	}
+
+	return "", false
+}
+
+// Bind decodes the request body according to its Content-Type
+func Bind(c echo.Context, i interface{}) error {
+	req := c.Request()
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+	if !msgpackTypes[mediaType] {
+		if err := c.Bind(i); err != nil {
+			if he, ok := err.(*echo.HTTPError); ok && he.Code == http.StatusUnsupportedMediaType {
+				return httpErr.NewRestError(http.StatusUnsupportedMediaType, http.StatusText(http.StatusUnsupportedMediaType), err)
+			}
+			return err
+		}
+		return nil
+	}
+
+	if req.ContentLength == 0 {
+		return nil
+	}
+
+	dec := msgpack.NewDecoder(req.Body)
+	dec.SetCustomStructTag(structTag)
+	if err := dec.Decode(i); err != nil {
+		return httpErr.NewBadRequestError(err)
+	}
+
+	return nil
+}
+
+func write(c echo.Context, mediaType string, code int, i interface{}) error {
+	switch mediaType {
+	case echo.MIMEApplicationXML:
+		return c.XML(code, i)
+	case MIMEApplicationMsgpack:
+		var buf bytes.Buffer
+		enc := msgpack.NewEncoder(&buf)
+		enc.SetCustomStructTag(structTag)
+		if err := enc.Encode(i); err != nil {
+			return err
+		}
+		return c.Blob(code, MIMEApplicationMsgpack, buf.Bytes())
+	case MIMETextCSV:
+		header, records := i.(CSVMarshaler).MarshalCSV()
+
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := w.Write(header); err != nil {
+			return err
+		}
+		if err := w.WriteAll(records); err != nil {
+			return err
+		}
+		return c.Blob(code, MIMETextCSV+"; charset=utf-8", buf.Bytes())
+	default:
+		return c.JSON(code, i)
+	}
+}
+
+// Parse Accept header into media ranges ordered by preference, an empty header accepts anything
+func parseAccept(accept string) []acceptRange {
+	if strings.TrimSpace(accept) == "" {
+		return []acceptRange{{mediaType: "*/*", q: 1}}
+	}
+
+	ranges := make([]acceptRange, 0)
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+
+	return ranges
+}
@@ -0,0 +1,271 @@
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/vmihailenco/msgpack/v5"
+
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+)
+
+type item struct {
+	Name string `json:"name" xml:"name"`
+}
+
+type itemList struct {
+	Items []item `json:"items" xml:"items>item"`
+}
+
+func (l *itemList) MarshalCSV() ([]string, [][]string) {
+	records := make([][]string, 0, len(l.Items))
+	for _, i := range l.Items {
+		records = append(records, []string{i.Name})
+	}
+	return []string{"name"}, records
+}
+
+func newContext(method, accept string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, "/", nil)
+	if accept != "" {
+		req.Header.Set(echo.HeaderAccept, accept)
+	}
+	rec := httptest.NewRecorder()
+
+	return echo.New().NewContext(req, rec), rec
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		i      interface{}
+		want   string
+		wantOK bool
+	}{
+		{name: "no accept header", accept: "", i: item{}, want: echo.MIMEApplicationJSON, wantOK: true},
+		{name: "anything", accept: "*/*", i: item{}, want: echo.MIMEApplicationJSON, wantOK: true},
+		{name: "json suffix", accept: "application/problem+json", i: item{}, want: echo.MIMEApplicationJSON, wantOK: true},
+		{name: "xml", accept: "text/xml", i: item{}, want: echo.MIMEApplicationXML, wantOK: true},
+		{name: "msgpack alias", accept: "application/x-msgpack", i: item{}, want: MIMEApplicationMsgpack, wantOK: true},
+		{name: "csv of a list", accept: "text/csv", i: &itemList{}, want: MIMETextCSV, wantOK: true},
+		{name: "csv of a single item", accept: "text/csv", i: item{}, wantOK: false},
+		{name: "csv falls back to a later range", accept: "text/csv, application/json;q=0.5", i: item{}, want: echo.MIMEApplicationJSON, wantOK: true},
+		{name: "highest quality wins", accept: "application/json;q=0.5, application/xml", i: item{}, want: echo.MIMEApplicationXML, wantOK: true},
+		{name: "zero quality is refused", accept: "application/xml;q=0", i: item{}, wantOK: false},
+		{name: "unsupported type", accept: "image/png", i: item{}, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Negotiate(tt.accept, tt.i)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("Negotiate(%q) = %q, %v, want %q, %v", tt.accept, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRespond(t *testing.T) {
+	list := &itemList{Items: []item{{Name: "bulbasaur"}, {Name: "ivysaur"}}}
+
+	tests := []struct {
+		name        string
+		accept      string
+		status      int
+		contentType string
+		body        string
+	}{
+		{name: "json", accept: "application/json", status: http.StatusOK, contentType: echo.MIMEApplicationJSON, body: `"name":"bulbasaur"`},
+		{name: "xml", accept: "application/xml", status: http.StatusOK, contentType: echo.MIMEApplicationXML, body: "<name>ivysaur</name>"},
+		{name: "csv", accept: "text/csv", status: http.StatusOK, contentType: MIMETextCSV, body: "name\nbulbasaur\nivysaur\n"},
+		{name: "not acceptable", accept: "image/png", status: http.StatusNotAcceptable, contentType: echo.MIMEApplicationJSON, body: `"status":406`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newContext(http.MethodGet, tt.accept)
+
+			if err := Respond(c, http.StatusOK, list); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get(echo.HeaderContentType); !strings.HasPrefix(got, tt.contentType) {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			if got := rec.Header().Get(echo.HeaderVary); got != echo.HeaderAccept {
+				t.Errorf("Vary = %q, want %q", got, echo.HeaderAccept)
+			}
+			if !strings.Contains(rec.Body.String(), tt.body) {
+				t.Errorf("body %q does not contain %q", rec.Body.String(), tt.body)
+			}
+		})
+	}
+
+	t.Run("msgpack", func(t *testing.T) {
+		c, rec := newContext(http.MethodGet, MIMEApplicationMsgpack)
+
+		if err := Respond(c, http.StatusOK, list); err != nil {
+			t.Fatal(err)
+		}
+
+		var got itemList
+		dec := msgpack.NewDecoder(rec.Body)
+		dec.SetCustomStructTag(structTag)
+		if err := dec.Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Items) != 2 || got.Items[0].Name != "bulbasaur" {
+			t.Errorf("decoded %+v", got)
+		}
+	})
+}
+
+func TestError(t *testing.T) {
+	t.Run("maps errors to statuses", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			err    error
+			status int
+			error  string
+		}{
+			{name: "rest error keeps its status", err: httpErr.NewForbiddenError(errors.New("token")), status: http.StatusForbidden, error: httpErr.Forbidden.Error()},
+			{name: "precondition failed", err: errors.Wrap(httpErr.PreconditionFailed, "UpdateVersioned"), status: http.StatusPreconditionFailed, error: httpErr.PreconditionFailed.Error()},
+			{name: "deadline exceeded", err: errors.Wrap(context.DeadlineExceeded, "db.Find"), status: http.StatusRequestTimeout, error: httpErr.RequestTimeoutError.Error()},
+			{name: "token", err: errors.New("token is expired"), status: http.StatusUnauthorized, error: httpErr.Unauthorized.Error()},
+			{name: "unmarshal", err: errors.New("json: Unmarshal failed"), status: http.StatusBadRequest, error: httpErr.BadRequest.Error()},
+			{name: "anything else", err: errors.New("db.InsertOne: connection reset"), status: http.StatusInternalServerError, error: httpErr.InternalServerError.Error()},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				c, rec := newContext(http.MethodGet, "")
+
+				if err := Error(c, tt.err); err != nil {
+					t.Fatal(err)
+				}
+
+				if rec.Code != tt.status {
+					t.Errorf("status = %d, want %d", rec.Code, tt.status)
+				}
+				var body map[string]interface{}
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+					t.Fatal(err)
+				}
+				// Causes stay in the logs, clients only see the status and the error
+				if len(body) != 2 || body["status"] != float64(tt.status) || body["error"] != tt.error {
+					t.Errorf("body = %v, want status %d and error %q only", body, tt.status, tt.error)
+				}
+			})
+		}
+	})
+
+	t.Run("in the negotiated representation", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			accept      string
+			contentType string
+			body        string
+		}{
+			{name: "json", accept: "application/json", contentType: echo.MIMEApplicationJSON, body: `{"status":404,"error":"not found"}`},
+			{name: "xml", accept: "application/xml", contentType: echo.MIMEApplicationXML, body: "<status>404</status><error>not found</error>"},
+			{name: "csv falls back to json", accept: "text/csv", contentType: echo.MIMEApplicationJSON, body: `{"status":404,"error":"not found"}`},
+			{name: "unsupported falls back to json", accept: "image/png", contentType: echo.MIMEApplicationJSON, body: `{"status":404,"error":"not found"}`},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				c, rec := newContext(http.MethodGet, tt.accept)
+
+				if err := Error(c, httpErr.NewNotFoundError(nil)); err != nil {
+					t.Fatal(err)
+				}
+
+				if rec.Code != http.StatusNotFound {
+					t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+				}
+				if got := rec.Header().Get(echo.HeaderContentType); !strings.HasPrefix(got, tt.contentType) {
+					t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+				}
+				if !strings.Contains(rec.Body.String(), tt.body) {
+					t.Errorf("body %q does not contain %q", rec.Body.String(), tt.body)
+				}
+			})
+		}
+
+		c, rec := newContext(http.MethodGet, MIMEApplicationMsgpack)
+		if err := Error(c, httpErr.NewNotFoundError(nil)); err != nil {
+			t.Fatal(err)
+		}
+		var body map[string]interface{}
+		if err := msgpack.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if body["error"] != httpErr.NotFound.Error() {
+			t.Errorf("msgpack body = %v", body)
+		}
+	})
+
+	t.Run("tells when to retry", func(t *testing.T) {
+		c, rec := newContext(http.MethodGet, "")
+
+		if err := Error(c, httpErr.NewTooManyRequestsError(httpErr.ErrTooManyLoginAttempts, 1500*time.Millisecond, nil)); err != nil {
+			t.Fatal(err)
+		}
+
+		if rec.Code != http.StatusTooManyRequests {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+		}
+		if got := rec.Header().Get(echo.HeaderRetryAfter); got != "2" {
+			t.Errorf("Retry-After = %q, want rounded up to 2", got)
+		}
+	})
+}
+
+func TestBind(t *testing.T) {
+	t.Run("msgpack", func(t *testing.T) {
+		body, err := msgpack.Marshal(map[string]string{"name": "pikachu"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(body)))
+		req.Header.Set(echo.HeaderContentType, "application/vnd.msgpack")
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+
+		var got item
+		if err := Bind(c, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Name != "pikachu" {
+			t.Errorf("name = %q, want pikachu", got.Name)
+		}
+	})
+
+	t.Run("malformed msgpack", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("\xc1"))
+		req.Header.Set(echo.HeaderContentType, MIMEApplicationMsgpack)
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+
+		err := Bind(c, &item{})
+		if restErr, ok := err.(httpErr.RestErr); !ok || restErr.Status() != http.StatusBadRequest {
+			t.Errorf("Bind = %v, want status %d", err, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("unsupported content type", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name: pikachu"))
+		req.Header.Set(echo.HeaderContentType, "application/yaml")
+		c := echo.New().NewContext(req, httptest.NewRecorder())
+
+		err := Bind(c, &item{})
+		if restErr, ok := err.(httpErr.RestErr); !ok || restErr.Status() != http.StatusUnsupportedMediaType {
+			t.Errorf("Bind = %v, want status %d", err, http.StatusUnsupportedMediaType)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type LocalStorage struct {
+	root      string
+	publicURL string
+}
+
+// Local filesystem storage constructor
+func NewLocalStorage(root, publicURL string) *LocalStorage {
+	return &LocalStorage{root: root, publicURL: strings.TrimRight(publicURL, "/")}
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	dst := filepath.Join(s.root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never observe a partial blob
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), dst)
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filepath.Join(s.root, filepath.FromSlash(key))); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStorage) URL(key string) string {
+	return s.publicURL + "/" + strings.TrimPrefix(key, "/")
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/iamaul/go-pokedex/config"
+)
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Blob storage methods interface
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// Files are kept on the local disk, the default driver. The server serves them itself.
+func IsLocal(cfg *config.Config) bool {
+	return cfg.Storage.Driver == "" || cfg.Storage.Driver == "local"
+}
+
+// Storage constructor based on the configured driver
+func NewStorage(cfg *config.Config) (Storage, error) {
+	if IsLocal(cfg) {
+		return NewLocalStorage(cfg.Storage.LocalPath, cfg.Storage.PublicURL), nil
+	}
+
+	switch cfg.Storage.Driver {
+	case "s3":
+		return NewS3Storage(cfg.Storage.S3, cfg.Storage.PublicURL), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
+
+// Clean key and reject anything escaping the storage root
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != strings.TrimPrefix(key, "/") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
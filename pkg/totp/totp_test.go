@@ -0,0 +1,103 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Secret of the RFC 4226 appendix D test vectors
+var testSecret = encoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+
+	for step, code := range want {
+		got, err := Code(testSecret, int64(step))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != code {
+			t.Errorf("Code(step %d) = %s, want %s", step, got, code)
+		}
+	}
+
+	if got, _ := Code(strings.ToLower(testSecret), 0); got != want[0] {
+		t.Errorf("lower case secret gave %s, want %s", got, want[0])
+	}
+	if _, err := Code("not base32!", 0); err == nil {
+		t.Error("invalid secret accepted")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1_700_000_015, 0)
+	current := Step(now)
+
+	code := func(step int64) string {
+		c, err := Code(testSecret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name string
+		code string
+		step int64
+		ok   bool
+	}{
+		{name: "current step", code: code(current), step: current, ok: true},
+		{name: "previous step within the skew", code: code(current - 1), step: current - 1, ok: true},
+		{name: "next step within the skew", code: code(current + 1), step: current + 1, ok: true},
+		{name: "two steps behind", code: code(current - 2)},
+		{name: "two steps ahead", code: code(current + 2)},
+		{name: "spaces are ignored", code: " " + code(current)[:3] + " " + code(current)[3:] + " ", step: current, ok: true},
+		{name: "too short", code: code(current)[:5]},
+		{name: "too long", code: code(current) + "0"},
+		{name: "empty", code: ""},
+		{name: "not digits", code: "abcdef"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(testSecret, tt.code, now)
+			if ok != tt.ok || step != tt.step {
+				t.Errorf("Validate(%q) = %d, %v, want %d, %v", tt.code, step, ok, tt.step, tt.ok)
+			}
+		})
+	}
+
+	t.Run("step boundaries", func(t *testing.T) {
+		start := time.Unix(current*period, 0)
+		end := start.Add(period*time.Second - time.Second)
+
+		for _, at := range []time.Time{start, end} {
+			if step, ok := Validate(testSecret, code(current), at); !ok || step != current {
+				t.Errorf("code of step %d refused at %s", current, at)
+			}
+		}
+		if _, ok := Validate(testSecret, code(current), end.Add(period*time.Second+time.Second)); ok {
+			t.Error("code accepted two steps later")
+		}
+	})
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := RecoveryCodes(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' || seen[code] {
+			t.Errorf("recovery code %q", code)
+		}
+		seen[code] = true
+
+		if normalized := NormalizeRecoveryCode(" " + strings.ToUpper(code) + " "); normalized != strings.ReplaceAll(code, "-", "") {
+			t.Errorf("NormalizeRecoveryCode(%q) = %q", code, normalized)
+		}
+	}
+}
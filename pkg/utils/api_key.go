@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/domain"
+)
+
+const (
+	// Header service clients send their API key in
+	HeaderAPIKey = "X-API-Key"
+
+	// Every API key starts with this, so leaked keys are easy to recognize
+	apiKeyPrefix = "pkx_"
+
+	// Characters of the key kept in the clear to tell keys apart
+	apiKeyVisibleLength = len(apiKeyPrefix) + 8
+)
+
+// APIKeyCtxKey is a key used for the API key a request authenticated with
+type APIKeyCtxKey struct{}
+
+// Lifetime of API keys created without an expiry, 90 days unless configured
+func APIKeyTTL(config *config.Config) time.Duration {
+	if config.Server.APIKeyTTL <= 0 {
+		return time.Hour * 24 * 90
+	}
+	return time.Second * config.Server.APIKeyTTL
+}
+
+// Generate a new API key, returns the key and its visible prefix
+func GenerateAPIKey() (string, string, error) {
+	token, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	key := apiKeyPrefix + token
+	return key, key[:apiKeyVisibleLength], nil
+}
+
+// Get the API key the request authenticated with, if it did not use a JWT
+func GetAPIKeyFromCtx(ctx context.Context) (*domain.APIKey, bool) {
+	key, ok := ctx.Value(APIKeyCtxKey{}).(*domain.APIKey)
+	return key, ok
+}
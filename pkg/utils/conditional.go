@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+)
+
+const (
+	HeaderETag        = "ETag"
+	HeaderIfMatch     = "If-Match"
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+// Set the entity tag of the response and report whether the copy named by If-None-Match
+// is still current, in which case the handler answers 304 without a body
+func NotModified(c echo.Context, etag string) bool {
+	c.Response().Header().Set(HeaderETag, etag)
+
+	ifNoneMatch := c.Request().Header.Get(HeaderIfNoneMatch)
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Version the document must be at according to If-Match, nil when the request is unconditional.
+// Tags that are not a single strong version tag can never match and fail with 412.
+func IfMatchVersion(c echo.Context) (*int64, error) {
+	ifMatch := strings.TrimSpace(c.Request().Header.Get(HeaderIfMatch))
+	if ifMatch == "" || ifMatch == "*" {
+		return nil, nil
+	}
+
+	version, ok := domain.ParseVersionETag(ifMatch)
+	if !ok {
+		return nil, httpErr.NewPreconditionFailedError(ifMatch)
+	}
+
+	return &version, nil
+}
+
+// Fail with 412 when If-Match named another version than the current one the request is applied to
+func MatchVersion(expected *int64, current int64) error {
+	if expected != nil && *expected != current {
+		return httpErr.NewPreconditionFailedError(domain.VersionETag(current))
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/internal/domain"
+	"github.com/iamaul/go-pokedex/pkg/csrf"
+)
+
+const CSRFModeDoubleSubmit = "double-submit"
+
+// Key of the CSRF token HMAC
+func CSRFSecret(config *config.Config) []byte {
+	return []byte(config.CSRF.Secret)
+}
+
+// Lifetime of a CSRF token, an hour unless configured
+func CSRFTokenTTL(config *config.Config) time.Duration {
+	if config.CSRF.TTL <= 0 {
+		return time.Hour
+	}
+	return time.Second * config.CSRF.TTL
+}
+
+// Tokens are compared with the CSRF cookie instead of being bound to a session
+func CSRFDoubleSubmit(config *config.Config) bool {
+	return config.CSRF.Mode == CSRFModeDoubleSubmit
+}
+
+func CSRFCookieName(config *config.Config) string {
+	if config.CSRF.CookieName == "" {
+		return "csrf-token"
+	}
+	return config.CSRF.CookieName
+}
+
+// Make a CSRF token bound to the session ID, empty in double submit mode
+func GenerateCSRFToken(config *config.Config, sid string) (*domain.CSRFToken, error) {
+	expiresAt := time.Now().Add(CSRFTokenTTL(config))
+	token, err := csrf.MakeToken(CSRFSecret(config), sid, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CSRFToken{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// Set the CSRF cookie of double submit mode, scripts read it to send the token back in the header
+func SetCSRFCookie(c echo.Context, config *config.Config, token *domain.CSRFToken) {
+	c.SetCookie(&http.Cookie{
+		Name:     CSRFCookieName(config),
+		Value:    token.Token,
+		Path:     "/",
+		MaxAge:   int(CSRFTokenTTL(config).Seconds()),
+		Secure:   config.Cookie.Secure,
+		HttpOnly: false,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
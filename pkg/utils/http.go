@@ -11,6 +11,7 @@ import (
 	"github.com/iamaul/go-pokedex/internal/domain"
 	httpErr "github.com/iamaul/go-pokedex/pkg/error"
 	"github.com/iamaul/go-pokedex/pkg/logger"
+	"github.com/iamaul/go-pokedex/pkg/render"
 )
 
 // Get RequestID from echo context
@@ -33,6 +34,12 @@ func GetRequestCtx(c echo.Context) context.Context {
 	return context.WithValue(c.Request().Context(), ReqIDCtxKey{}, GetRequestID(c))
 }
 
+// Get request id from context, empty when unknown
+func GetRequestIDFromCtx(ctx context.Context) string {
+	requestID, _ := ctx.Value(ReqIDCtxKey{}).(string)
+	return requestID
+}
+
 // Get config path for local or docker
 func GetConfigPath(configPath string) string {
 	if configPath == "docker" {
@@ -55,22 +62,27 @@ func ConfigureJWTCookie(cfg *config.Config, jwtToken string) *http.Cookie {
 	}
 }
 
-// Configure JWT Cookie
+// Configure session cookie, scripts never need the session ID
 func CreateSessionCookie(cfg *config.Config, session string) *http.Cookie {
 	return &http.Cookie{
-		Name:  cfg.Cookie.Name,
-		Value: session,
-		Path:  "/",
-		// Domain: "/",
-		// Expires:    time.Now().Add(1 * time.Minute),
-		RawExpires: "",
-		MaxAge:     cfg.Session.Expire,
-		Secure:     cfg.Cookie.Secure,
-		HttpOnly:   cfg.Cookie.HttpOnly,
-		SameSite:   0,
+		Name:     cfg.Session.Name,
+		Value:    session,
+		Path:     "/",
+		MaxAge:   int(SessionTTL(cfg).Seconds()),
+		Secure:   cfg.Cookie.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
 	}
 }
 
+// Set the session cookie of a sign in, nothing when no session was started
+func SetSessionCookie(c echo.Context, cfg *config.Config, session string) {
+	if session == "" {
+		return
+	}
+	c.SetCookie(CreateSessionCookie(cfg, session))
+}
+
 // Delete session
 func DeleteSessionCookie(c echo.Context, sessionName string) {
 	c.SetCookie(&http.Cookie{
@@ -94,9 +106,18 @@ func GetUserFromCtx(ctx context.Context) (*domain.User, error) {
 	return user, nil
 }
 
-// Get user ip address
+// Get user ip address, taken from the proxy headers only when the server trusts them
 func GetIPAddress(c echo.Context) string {
-	return c.Request().RemoteAddr
+	return c.RealIP()
+}
+
+// ClientIPCtxKey is a key used for the client IP address in the context
+type ClientIPCtxKey struct{}
+
+// Get client IP address from context, empty when unknown
+func GetClientIPFromCtx(ctx context.Context) string {
+	ip, _ := ctx.Value(ClientIPCtxKey{}).(string)
+	return ip
 }
 
 // Error response with logging error for echo context
@@ -107,7 +128,7 @@ func ErrResponseWithLog(ctx echo.Context, logger logger.Logger, err error) error
 		GetIPAddress(ctx),
 		err,
 	)
-	return ctx.JSON(httpErr.ErrorResponse(err))
+	return render.Error(ctx, err)
 }
 
 // Error response with logging error for echo context
@@ -120,9 +141,9 @@ func LogResponseError(ctx echo.Context, logger logger.Logger, err error) {
 	)
 }
 
-// Read request body and validate
+// Read request body according to its Content-Type and validate
 func ReadRequest(ctx echo.Context, request interface{}) error {
-	if err := ctx.Bind(request); err != nil {
+	if err := render.Bind(ctx, request); err != nil {
 		return err
 	}
 	return validate.StructCtx(ctx.Request().Context(), request)
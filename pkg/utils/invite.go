@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/iamaul/go-pokedex/config"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+)
+
+// Keeps invite signatures apart from anything else signed with the invite secret
+const inviteSignaturePrefix = "invite:"
+
+// Lifetime of invite codes, a week unless configured
+func InviteTTL(config *config.Config) time.Duration {
+	if config.Server.InviteTTL <= 0 {
+		return time.Hour * 24 * 7
+	}
+	return time.Second * config.Server.InviteTTL
+}
+
+// Sign the invite ID and expiry, codes can be checked before the invite is looked up
+func SignInviteCode(inviteID primitive.ObjectID, expiresAt time.Time, config *config.Config) string {
+	payload := make([]byte, 0, len(inviteID)+8)
+	payload = append(payload, inviteID[:]...)
+	payload = binary.BigEndian.AppendUint64(payload, uint64(expiresAt.Unix()))
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(inviteSignature(payload, config))
+}
+
+// Verify the signature and expiry of an invite code, returns the invite ID
+func ParseInviteCode(code string, config *config.Config) (primitive.ObjectID, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(code, ".")
+	if !ok {
+		return primitive.NilObjectID, httpErr.InvalidInviteCode
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != len(primitive.NilObjectID)+8 {
+		return primitive.NilObjectID, httpErr.InvalidInviteCode
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, inviteSignature(payload, config)) {
+		return primitive.NilObjectID, httpErr.InvalidInviteCode
+	}
+
+	var inviteID primitive.ObjectID
+	copy(inviteID[:], payload)
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[len(inviteID):])), 0)
+	if time.Now().After(expiresAt) {
+		return primitive.NilObjectID, httpErr.InvalidInviteCode
+	}
+
+	return inviteID, nil
+}
+
+func inviteSignature(payload []byte, config *config.Config) []byte {
+	mac := hmac.New(sha256.New, []byte(config.Server.InviteSecret))
+	mac.Write([]byte(inviteSignaturePrefix))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
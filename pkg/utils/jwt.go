@@ -1,6 +1,10 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"html"
 	"net/http"
@@ -11,34 +15,49 @@ import (
 
 	"github.com/iamaul/go-pokedex/config"
 	"github.com/iamaul/go-pokedex/internal/domain"
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+	"github.com/iamaul/go-pokedex/pkg/keyring"
 )
 
-// JWT Claims struct
+// JWT Claims struct, the user ID is the subject
 type Claims struct {
-	Username string `json:"username"`
-	ID       string `json:"id"`
+	Username  string `json:"username"`
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// Generate new JWT Token
-func GenerateJWTToken(user *domain.User, config *config.Config) (string, error) {
+// The jti claim
+func (c *Claims) TokenID() string {
+	return c.ID
+}
+
+// Generate new JWT Token for the session, signed with the active key of the ring
+func GenerateJWTToken(user *domain.User, sessionID string, keys *keyring.KeyRing, config *config.Config) (string, error) {
+	// The jti lets a single token be revoked before it expires
+	jti, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
 	// Register the JWT claims, which includes the username and expiry time
+	now := time.Now()
 	claims := &Claims{
-		Username: user.Username,
-		ID:       user.ID.Hex(),
+		Username:  user.Username,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer: user.ID.Hex(),
+			ID:       jti,
+			Issuer:   JWTIssuer(config),
+			Subject:  user.ID.Hex(),
+			Audience: jwt.ClaimStrings{JWTAudience(config)},
+			IssuedAt: jwt.NewNumericDate(now),
 			ExpiresAt: &jwt.NumericDate{
-				Time: time.Now().Add(time.Minute * 60),
+				Time: now.Add(AccessTokenTTL(config)),
 			},
 		},
 	}
 
-	// Declare the token with the algorithm used for signing, and the claims
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
 	// Register the JWT string
-	tokenString, err := token.SignedString([]byte(config.Server.JwtSecretKey))
+	tokenString, err := keys.Sign(claims)
 	if err != nil {
 		return "", err
 	}
@@ -46,6 +65,96 @@ func GenerateJWTToken(user *domain.User, config *config.Config) (string, error)
 	return tokenString, nil
 }
 
+// The iss claim of access tokens, go-pokedex unless configured
+func JWTIssuer(config *config.Config) string {
+	if config.JWT.Issuer == "" {
+		return "go-pokedex"
+	}
+	return config.JWT.Issuer
+}
+
+// The aud claim of access tokens, go-pokedex unless configured
+func JWTAudience(config *config.Config) string {
+	if config.JWT.Audience == "" {
+		return "go-pokedex"
+	}
+	return config.JWT.Audience
+}
+
+// How long a replaced signing key keeps verifying tokens, the access token lifetime unless configured
+func JWTKeyOverlap(config *config.Config) time.Duration {
+	if config.JWT.Overlap <= 0 {
+		return AccessTokenTTL(config)
+	}
+	return time.Second * config.JWT.Overlap
+}
+
+// Lifetime of access tokens, an hour unless configured
+func AccessTokenTTL(config *config.Config) time.Duration {
+	if config.Server.AccessTokenTTL <= 0 {
+		return time.Minute * 60
+	}
+	return time.Second * config.Server.AccessTokenTTL
+}
+
+// Lifetime of refresh tokens, 30 days unless configured
+func RefreshTokenTTL(config *config.Config) time.Duration {
+	if config.Server.RefreshTokenTTL <= 0 {
+		return time.Hour * 24 * 30
+	}
+	return time.Second * config.Server.RefreshTokenTTL
+}
+
+// Lifetime of password reset tokens, an hour unless configured
+func PasswordResetTTL(config *config.Config) time.Duration {
+	if config.Server.PasswordResetTTL <= 0 {
+		return time.Hour
+	}
+	return time.Second * config.Server.PasswordResetTTL
+}
+
+// Generate a random opaque token, e.g. a refresh token
+func GenerateOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Opaque tokens are only stored as their SHA-256
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Parse and verify JWT Token with the key of the ring its kid names, returns the token claims
+func ParseJWTToken(tokenString string, keys *keyring.KeyRing, config *config.Config) (*Claims, error) {
+	if tokenString == "" {
+		return nil, httpErr.InvalidJWTToken
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, keys.Keyfunc,
+		jwt.WithValidMethods(keys.Methods()),
+		jwt.WithIssuer(JWTIssuer(config)),
+		jwt.WithAudience(JWTAudience(config)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid {
+		return nil, httpErr.InvalidJWTToken
+	}
+
+	if claims.Subject == "" || claims.ExpiresAt == nil {
+		return nil, httpErr.InvalidJWTClaims
+	}
+
+	return claims, nil
+}
+
 // Extract JWT From Request
 func ExtractJWTFromRequest(r *http.Request) (map[string]interface{}, error) {
 	// Get the JWT string
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/iamaul/go-pokedex/config"
+)
+
+// Failed sign ins of a username before it is locked, 5 unless configured
+func LockoutUsernameThreshold(config *config.Config) int {
+	if config.Lockout.UsernameThreshold <= 0 {
+		return 5
+	}
+	return config.Lockout.UsernameThreshold
+}
+
+// Failed sign ins from a client IP before it is locked, 20 unless configured. Higher than
+// for a username, clients behind one NAT share it.
+func LockoutIPThreshold(config *config.Config) int {
+	if config.Lockout.IPThreshold <= 0 {
+		return 20
+	}
+	return config.Lockout.IPThreshold
+}
+
+// Wait before the next attempt after the given failures, none for the free attempts and
+// then doubling from the configured delay, a second unless configured
+func LoginDelay(config *config.Config, failures int) time.Duration {
+	free := config.Lockout.FreeAttempts
+	if free <= 0 {
+		free = 3
+	}
+	if failures <= free {
+		return 0
+	}
+
+	delay := time.Second
+	if config.Lockout.Delay > 0 {
+		delay = time.Second * config.Lockout.Delay
+	}
+
+	return doubled(delay, failures-free-1, LockoutDuration(config, 0))
+}
+
+// Length of a lockout after the given lockouts in a row, doubling from 15 minutes and
+// capped at a day unless configured
+func LockoutDuration(config *config.Config, lockouts int) time.Duration {
+	duration := time.Minute * 15
+	if config.Lockout.Duration > 0 {
+		duration = time.Second * config.Lockout.Duration
+	}
+
+	max := time.Hour * 24
+	if config.Lockout.MaxDuration > 0 {
+		max = time.Second * config.Lockout.MaxDuration
+	}
+
+	return doubled(duration, lockouts, max)
+}
+
+// Time without failures after which they are forgotten, 15 minutes unless configured
+func LockoutWindow(config *config.Config) time.Duration {
+	if config.Lockout.Window <= 0 {
+		return time.Minute * 15
+	}
+	return time.Second * config.Lockout.Window
+}
+
+func doubled(d time.Duration, times int, max time.Duration) time.Duration {
+	for i := 0; i < times && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
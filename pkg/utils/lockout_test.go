@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iamaul/go-pokedex/config"
+)
+
+func TestLoginDelay(t *testing.T) {
+	configured := &config.Config{Lockout: config.Lockout{FreeAttempts: 2, Delay: 5, Duration: 60}}
+
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		failures int
+		want     time.Duration
+	}{
+		{name: "no failures", cfg: &config.Config{}, failures: 0, want: 0},
+		{name: "last free attempt by default", cfg: &config.Config{}, failures: 3, want: 0},
+		{name: "first delay by default", cfg: &config.Config{}, failures: 4, want: time.Second},
+		{name: "doubles by default", cfg: &config.Config{}, failures: 6, want: 4 * time.Second},
+		{name: "capped at the lockout by default", cfg: &config.Config{}, failures: 100, want: 15 * time.Minute},
+		{name: "last free attempt", cfg: configured, failures: 2, want: 0},
+		{name: "configured first delay", cfg: configured, failures: 3, want: 5 * time.Second},
+		{name: "configured delay doubles", cfg: configured, failures: 5, want: 20 * time.Second},
+		{name: "capped at the configured lockout", cfg: configured, failures: 7, want: time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LoginDelay(tt.cfg, tt.failures); got != tt.want {
+				t.Errorf("LoginDelay(%d) = %s, want %s", tt.failures, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockoutDuration(t *testing.T) {
+	configured := &config.Config{Lockout: config.Lockout{Duration: 60, MaxDuration: 300}}
+
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		lockouts int
+		want     time.Duration
+	}{
+		{name: "first lockout by default", cfg: &config.Config{}, lockouts: 0, want: 15 * time.Minute},
+		{name: "doubles by default", cfg: &config.Config{}, lockouts: 2, want: time.Hour},
+		{name: "capped at a day by default", cfg: &config.Config{}, lockouts: 10, want: 24 * time.Hour},
+		{name: "configured first lockout", cfg: configured, lockouts: 0, want: time.Minute},
+		{name: "configured doubling", cfg: configured, lockouts: 2, want: 4 * time.Minute},
+		{name: "capped at the configured maximum", cfg: configured, lockouts: 3, want: 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LockoutDuration(tt.cfg, tt.lockouts); got != tt.want {
+				t.Errorf("LockoutDuration(%d) = %s, want %s", tt.lockouts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockoutThresholds(t *testing.T) {
+	defaults := &config.Config{}
+	if got := LockoutUsernameThreshold(defaults); got != 5 {
+		t.Errorf("default username threshold %d", got)
+	}
+	if got := LockoutIPThreshold(defaults); got != 20 {
+		t.Errorf("default IP threshold %d", got)
+	}
+
+	configured := &config.Config{Lockout: config.Lockout{UsernameThreshold: 3, IPThreshold: 7}}
+	if got := LockoutUsernameThreshold(configured); got != 3 {
+		t.Errorf("configured username threshold %d", got)
+	}
+	if got := LockoutIPThreshold(configured); got != 7 {
+		t.Errorf("configured IP threshold %d", got)
+	}
+}
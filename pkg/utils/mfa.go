@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/iamaul/go-pokedex/config"
+)
+
+// Lifetime of the challenge between password and two-factor code, five minutes unless configured
+func MFAChallengeTTL(config *config.Config) time.Duration {
+	if config.MFA.ChallengeTTL <= 0 {
+		return time.Minute * 5
+	}
+	return time.Second * config.MFA.ChallengeTTL
+}
+
+// Name authenticator apps list the account under
+func MFAIssuer(config *config.Config) string {
+	if config.MFA.Issuer == "" {
+		return "go-pokedex"
+	}
+	return config.MFA.Issuer
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/iamaul/go-pokedex/config"
+	"github.com/iamaul/go-pokedex/pkg/oidc"
+)
+
+// Holds the state of the sign-in the browser started, callbacks are only accepted with it
+const OIDCStateCookieName = "oidc-state"
+
+// Bind the state to the browser. Lax so it is sent along with the provider's redirect back.
+func SetOIDCStateCookie(c echo.Context, config *config.Config, state string) {
+	c.SetCookie(&http.Cookie{
+		Name:     OIDCStateCookieName,
+		Value:    state,
+		Path:     "/",
+		MaxAge:   int(oidc.StateTTL(config).Seconds()),
+		Secure:   config.Cookie.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// State of the sign-in the browser started, empty without the cookie
+func OIDCStateFromCookie(c echo.Context) string {
+	cookie, err := c.Cookie(OIDCStateCookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"encoding/xml"
 	"fmt"
 	"math"
 	"strconv"
@@ -12,6 +13,22 @@ const (
 	defaultSize = 10
 )
 
+// Paginated list envelope of the v2 API
+type Page[T any] struct {
+	XMLName xml.Name `json:"-" xml:"page"`
+	Data    []T      `json:"data" xml:"data>item"`
+	Meta    PageMeta `json:"meta" xml:"meta"`
+}
+
+// Position of a page within the whole list
+type PageMeta struct {
+	TotalCount int  `json:"total_count" xml:"total_count"`
+	TotalPages int  `json:"total_pages" xml:"total_pages"`
+	Page       int  `json:"page" xml:"page"`
+	Size       int  `json:"size" xml:"size"`
+	HasMore    bool `json:"has_more" xml:"has_more"`
+}
+
 // Pagination query params
 type PaginationQuery struct {
 	Size    int    `json:"size,omitempty"`
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+)
+
+const (
+	MIMEMergePatch = "application/merge-patch+json"
+	MIMEJSONPatch  = "application/json-patch+json"
+)
+
+// Apply the JSON Merge Patch (RFC 7386) or JSON Patch (RFC 6902) in the request body to the
+// JSON representation of current, then decode the patched document into target and validate it.
+// Members the patch removes are left at their zero value in target.
+func ReadPatch(c echo.Context, current interface{}, target interface{}) error {
+	req := c.Request()
+
+	patch, err := io.ReadAll(req.Body)
+	if err != nil {
+		return httpErr.NewBadRequestError(err)
+	}
+
+	document, err := json.Marshal(current)
+	if err != nil {
+		return httpErr.NewInternalServerError(err)
+	}
+
+	var patched []byte
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+	switch mediaType {
+	case MIMEMergePatch:
+		if !json.Valid(patch) {
+			return httpErr.NewRestErrorWithMessage(http.StatusBadRequest, "invalid merge patch", nil)
+		}
+		if patched, err = jsonpatch.MergePatch(document, patch); err != nil {
+			return httpErr.NewRestErrorWithMessage(http.StatusUnprocessableEntity, "merge patch cannot be applied", err)
+		}
+	case MIMEJSONPatch:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return httpErr.NewRestErrorWithMessage(http.StatusBadRequest, "invalid JSON patch", err)
+		}
+		if patched, err = operations.Apply(document); err != nil {
+			// A failed test operation means the document is not in the state the client expected
+			if errors.Is(err, jsonpatch.ErrTestFailed) {
+				return httpErr.NewRestErrorWithMessage(http.StatusConflict, "JSON patch test failed", err)
+			}
+			return httpErr.NewRestErrorWithMessage(http.StatusUnprocessableEntity, "JSON patch cannot be applied", err)
+		}
+	default:
+		c.Response().Header().Set("Accept-Patch", MIMEMergePatch+", "+MIMEJSONPatch)
+		return httpErr.NewRestError(http.StatusUnsupportedMediaType, http.StatusText(http.StatusUnsupportedMediaType), mediaType)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(target); err != nil {
+		return httpErr.NewRestErrorWithMessage(http.StatusUnprocessableEntity, "patched document is not valid", err)
+	}
+
+	return validate.StructCtx(req.Context(), target)
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	httpErr "github.com/iamaul/go-pokedex/pkg/error"
+)
+
+// Editable fields of a resource, id and version are not among them
+type patchable struct {
+	Name  string   `json:"name" validate:"required"`
+	Level int      `json:"level"`
+	Moves []string `json:"moves"`
+}
+
+func TestReadPatch(t *testing.T) {
+	current := &patchable{Name: "bulbasaur", Level: 5, Moves: []string{"tackle"}}
+
+	tests := []struct {
+		name        string
+		contentType string
+		patch       string
+		want        *patchable
+		status      int
+	}{
+		{name: "merge patch", contentType: MIMEMergePatch, patch: `{"level":16}`, want: &patchable{Name: "bulbasaur", Level: 16, Moves: []string{"tackle"}}},
+		{name: "merge patch removing a member", contentType: MIMEMergePatch, patch: `{"moves":null}`, want: &patchable{Name: "bulbasaur", Level: 5}},
+		{name: "merge patch with parameters", contentType: MIMEMergePatch + "; charset=utf-8", patch: `{"level":16}`, want: &patchable{Name: "bulbasaur", Level: 16, Moves: []string{"tackle"}}},
+		{name: "JSON patch", contentType: MIMEJSONPatch, patch: `[{"op":"test","path":"/level","value":5},{"op":"add","path":"/moves/-","value":"vine whip"}]`, want: &patchable{Name: "bulbasaur", Level: 5, Moves: []string{"tackle", "vine whip"}}},
+		{name: "failed JSON patch test", contentType: MIMEJSONPatch, patch: `[{"op":"test","path":"/level","value":6},{"op":"replace","path":"/level","value":16}]`, status: http.StatusConflict},
+		{name: "JSON patch of a missing member", contentType: MIMEJSONPatch, patch: `[{"op":"replace","path":"/hp","value":45}]`, status: http.StatusUnprocessableEntity},
+		{name: "malformed merge patch", contentType: MIMEMergePatch, patch: `{"level":`, status: http.StatusBadRequest},
+		{name: "malformed JSON patch", contentType: MIMEJSONPatch, patch: `{"op":"replace"}`, status: http.StatusBadRequest},
+		{name: "merge patch adding an unknown member", contentType: MIMEMergePatch, patch: `{"hp":45}`, status: http.StatusUnprocessableEntity},
+		{name: "merge patch setting the id", contentType: MIMEMergePatch, patch: `{"id":"000000000000000000000000"}`, status: http.StatusUnprocessableEntity},
+		{name: "merge patch setting the version", contentType: MIMEMergePatch, patch: `{"version":99}`, status: http.StatusUnprocessableEntity},
+		{name: "JSON patch adding the version", contentType: MIMEJSONPatch, patch: `[{"op":"add","path":"/version","value":99}]`, status: http.StatusUnprocessableEntity},
+		{name: "merge patch of the wrong type", contentType: MIMEMergePatch, patch: `{"level":"sixteen"}`, status: http.StatusUnprocessableEntity},
+		{name: "removing a required member", contentType: MIMEMergePatch, patch: `{"name":null}`, status: http.StatusBadRequest},
+		{name: "plain JSON", contentType: echo.MIMEApplicationJSON, patch: `{"level":16}`, status: http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(tt.patch))
+			req.Header.Set(echo.HeaderContentType, tt.contentType)
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+
+			got := &patchable{}
+			err := ReadPatch(c, current, got)
+
+			if tt.status != 0 {
+				if err == nil {
+					t.Fatalf("patched to %+v, want status %d", got, tt.status)
+				}
+				if restErr, ok := err.(httpErr.RestErr); ok {
+					if restErr.Status() != tt.status {
+						t.Errorf("status = %d (%v), want %d", restErr.Status(), err, tt.status)
+					}
+				} else if status, _ := httpErr.ErrorResponse(err); status != tt.status {
+					t.Errorf("status = %d (%v), want %d", status, err, tt.status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadPatch: %v", err)
+			}
+			if got.Name != tt.want.Name || got.Level != tt.want.Level || strings.Join(got.Moves, ",") != strings.Join(tt.want.Moves, ",") {
+				t.Errorf("patched to %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("announces the patch formats", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		_ = ReadPatch(echo.New().NewContext(req, rec), current, &patchable{})
+
+		if got := rec.Header().Get("Accept-Patch"); got != MIMEMergePatch+", "+MIMEJSONPatch {
+			t.Errorf("Accept-Patch = %q", got)
+		}
+	})
+
+	t.Run("leaves current untouched", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`[{"op":"add","path":"/moves/-","value":"growl"}]`))
+		req.Header.Set(echo.HeaderContentType, MIMEJSONPatch)
+
+		if err := ReadPatch(echo.New().NewContext(req, httptest.NewRecorder()), current, &patchable{}); err != nil {
+			t.Fatal(err)
+		}
+		if len(current.Moves) != 1 || current.Level != 5 {
+			t.Errorf("current changed to %+v", current)
+		}
+	})
+}
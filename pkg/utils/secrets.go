@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"errors"
+
+	"github.com/iamaul/go-pokedex/config"
+)
+
+const developmentMode = "development"
+
+// Refuse to start without the invite and CSRF secrets, or with one secret keying several
+// HMACs. Outside development access tokens must be signed with JWT keys, anyone holding
+// the shared HS256 secret could mint them.
+func ValidateSecrets(config *config.Config) error {
+	if config.Server.InviteSecret == "" {
+		return errors.New("config: server.InviteSecret is required")
+	}
+	if config.CSRF.Secret == "" {
+		return errors.New("config: csrf.Secret is required")
+	}
+
+	secrets := []string{config.Server.InviteSecret, config.CSRF.Secret}
+	if config.Server.JwtSecretKey != "" {
+		secrets = append(secrets, config.Server.JwtSecretKey)
+	}
+	for i := range secrets {
+		for _, other := range secrets[i+1:] {
+			if secrets[i] == other {
+				return errors.New("config: server.JwtSecretKey, server.InviteSecret and csrf.Secret must differ")
+			}
+		}
+	}
+
+	if len(config.JWT.Keys) == 0 && config.Server.Mode != developmentMode {
+		return errors.New("config: jwt.Keys is required outside development mode, server.JwtSecretKey only signs tokens in development")
+	}
+
+	return nil
+}
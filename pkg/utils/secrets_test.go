@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/iamaul/go-pokedex/config"
+)
+
+func TestValidateSecrets(t *testing.T) {
+	secrets := func(mode, jwt, invite, csrf string, keys ...config.JWTKey) *config.Config {
+		return &config.Config{
+			Server: config.ServerConfig{Mode: mode, JwtSecretKey: jwt, InviteSecret: invite},
+			CSRF:   config.CSRF{Secret: csrf},
+			JWT:    config.JWT{Keys: keys},
+		}
+	}
+	key := config.JWTKey{ID: "2026-10", PrivateKeyFile: "jwt-2026-10.pem"}
+
+	tests := []struct {
+		name    string
+		cfg     *config.Config
+		wantErr bool
+	}{
+		{name: "hs secret in development", cfg: secrets("development", "jwt", "invite", "csrf")},
+		{name: "keys in production", cfg: secrets("production", "", "invite", "csrf", key)},
+		{name: "keys and jwt secret in production", cfg: secrets("production", "jwt", "invite", "csrf", key)},
+		{name: "hs secret only in production", cfg: secrets("production", "jwt", "invite", "csrf"), wantErr: true},
+		{name: "no invite secret", cfg: secrets("development", "jwt", "", "csrf"), wantErr: true},
+		{name: "no csrf secret", cfg: secrets("development", "jwt", "invite", ""), wantErr: true},
+		{name: "invite secret is the jwt secret", cfg: secrets("development", "jwt", "jwt", "csrf"), wantErr: true},
+		{name: "csrf secret is the jwt secret", cfg: secrets("development", "jwt", "invite", "jwt"), wantErr: true},
+		{name: "csrf secret is the invite secret", cfg: secrets("production", "", "shared", "shared", key), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSecrets(tt.cfg); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSecrets() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/iamaul/go-pokedex/config"
+)
+
+// Time a session lasts without being used, a day unless configured
+func SessionTTL(config *config.Config) time.Duration {
+	if config.Session.Expire <= 0 {
+		return time.Hour * 24
+	}
+	return time.Second * time.Duration(config.Session.Expire)
+}
+
+// Generate a session ID for the cookie, starting with the configured prefix
+func GenerateSessionToken(config *config.Config) (string, error) {
+	token, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	if config.Session.Prefix == "" {
+		return token, nil
+	}
+	return config.Session.Prefix + "." + token, nil
+}
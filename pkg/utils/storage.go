@@ -0,0 +1,13 @@
+package utils
+
+import (
+	"github.com/iamaul/go-pokedex/config"
+)
+
+// Pixels an uploaded image may decode to, 16 megapixels unless configured
+func MaxImagePixels(config *config.Config) int64 {
+	if config.Storage.MaxImagePixels <= 0 {
+		return 16_000_000
+	}
+	return config.Storage.MaxImagePixels
+}
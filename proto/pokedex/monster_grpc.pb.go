@@ -0,0 +1,480 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.23.4
+// source: pokedex/monster.proto
+
+package pokedex
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	MonsterService_CreateMonsterType_FullMethodName = "/pokedex.MonsterService/CreateMonsterType"
+	MonsterService_UpdateMonsterType_FullMethodName = "/pokedex.MonsterService/UpdateMonsterType"
+	MonsterService_DeleteMonsterType_FullMethodName = "/pokedex.MonsterService/DeleteMonsterType"
+	MonsterService_ListMonsterTypes_FullMethodName  = "/pokedex.MonsterService/ListMonsterTypes"
+	MonsterService_GetMonsterType_FullMethodName    = "/pokedex.MonsterService/GetMonsterType"
+	MonsterService_CreateMonster_FullMethodName     = "/pokedex.MonsterService/CreateMonster"
+	MonsterService_UpdateMonster_FullMethodName     = "/pokedex.MonsterService/UpdateMonster"
+	MonsterService_DeleteMonster_FullMethodName     = "/pokedex.MonsterService/DeleteMonster"
+	MonsterService_ListMonsters_FullMethodName      = "/pokedex.MonsterService/ListMonsters"
+	MonsterService_GetMonster_FullMethodName        = "/pokedex.MonsterService/GetMonster"
+	MonsterService_AttachMonsterType_FullMethodName = "/pokedex.MonsterService/AttachMonsterType"
+)
+
+// MonsterServiceClient is the client API for MonsterService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MonsterServiceClient interface {
+	CreateMonsterType(ctx context.Context, in *CreateMonsterTypeRequest, opts ...grpc.CallOption) (*MonsterType, error)
+	UpdateMonsterType(ctx context.Context, in *UpdateMonsterTypeRequest, opts ...grpc.CallOption) (*MonsterType, error)
+	DeleteMonsterType(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListMonsterTypes(ctx context.Context, in *PaginationRequest, opts ...grpc.CallOption) (*MonsterTypeList, error)
+	GetMonsterType(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (*MonsterType, error)
+	CreateMonster(ctx context.Context, in *CreateMonsterRequest, opts ...grpc.CallOption) (*Monster, error)
+	UpdateMonster(ctx context.Context, in *UpdateMonsterRequest, opts ...grpc.CallOption) (*Monster, error)
+	DeleteMonster(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListMonsters(ctx context.Context, in *PaginationRequest, opts ...grpc.CallOption) (*MonsterList, error)
+	GetMonster(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (*Monster, error)
+	AttachMonsterType(ctx context.Context, in *AttachMonsterTypeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type monsterServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMonsterServiceClient(cc grpc.ClientConnInterface) MonsterServiceClient {
+	return &monsterServiceClient{cc}
+}
+
+func (c *monsterServiceClient) CreateMonsterType(ctx context.Context, in *CreateMonsterTypeRequest, opts ...grpc.CallOption) (*MonsterType, error) {
+	out := new(MonsterType)
+	err := c.cc.Invoke(ctx, MonsterService_CreateMonsterType_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monsterServiceClient) UpdateMonsterType(ctx context.Context, in *UpdateMonsterTypeRequest, opts ...grpc.CallOption) (*MonsterType, error) {
+	out := new(MonsterType)
+	err := c.cc.Invoke(ctx, MonsterService_UpdateMonsterType_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monsterServiceClient) DeleteMonsterType(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, MonsterService_DeleteMonsterType_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monsterServiceClient) ListMonsterTypes(ctx context.Context, in *PaginationRequest, opts ...grpc.CallOption) (*MonsterTypeList, error) {
+	out := new(MonsterTypeList)
+	err := c.cc.Invoke(ctx, MonsterService_ListMonsterTypes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monsterServiceClient) GetMonsterType(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (*MonsterType, error) {
+	out := new(MonsterType)
+	err := c.cc.Invoke(ctx, MonsterService_GetMonsterType_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monsterServiceClient) CreateMonster(ctx context.Context, in *CreateMonsterRequest, opts ...grpc.CallOption) (*Monster, error) {
+	out := new(Monster)
+	err := c.cc.Invoke(ctx, MonsterService_CreateMonster_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monsterServiceClient) UpdateMonster(ctx context.Context, in *UpdateMonsterRequest, opts ...grpc.CallOption) (*Monster, error) {
+	out := new(Monster)
+	err := c.cc.Invoke(ctx, MonsterService_UpdateMonster_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monsterServiceClient) DeleteMonster(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, MonsterService_DeleteMonster_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monsterServiceClient) ListMonsters(ctx context.Context, in *PaginationRequest, opts ...grpc.CallOption) (*MonsterList, error) {
+	out := new(MonsterList)
+	err := c.cc.Invoke(ctx, MonsterService_ListMonsters_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monsterServiceClient) GetMonster(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (*Monster, error) {
+	out := new(Monster)
+	err := c.cc.Invoke(ctx, MonsterService_GetMonster_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *monsterServiceClient) AttachMonsterType(ctx context.Context, in *AttachMonsterTypeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, MonsterService_AttachMonsterType_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MonsterServiceServer is the server API for MonsterService service.
+// All implementations must embed UnimplementedMonsterServiceServer
+// for forward compatibility
+type MonsterServiceServer interface {
+	CreateMonsterType(context.Context, *CreateMonsterTypeRequest) (*MonsterType, error)
+	UpdateMonsterType(context.Context, *UpdateMonsterTypeRequest) (*MonsterType, error)
+	DeleteMonsterType(context.Context, *IDRequest) (*emptypb.Empty, error)
+	ListMonsterTypes(context.Context, *PaginationRequest) (*MonsterTypeList, error)
+	GetMonsterType(context.Context, *IDRequest) (*MonsterType, error)
+	CreateMonster(context.Context, *CreateMonsterRequest) (*Monster, error)
+	UpdateMonster(context.Context, *UpdateMonsterRequest) (*Monster, error)
+	DeleteMonster(context.Context, *IDRequest) (*emptypb.Empty, error)
+	ListMonsters(context.Context, *PaginationRequest) (*MonsterList, error)
+	GetMonster(context.Context, *IDRequest) (*Monster, error)
+	AttachMonsterType(context.Context, *AttachMonsterTypeRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedMonsterServiceServer()
+}
+
+// UnimplementedMonsterServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedMonsterServiceServer struct {
+}
+
+func (UnimplementedMonsterServiceServer) CreateMonsterType(context.Context, *CreateMonsterTypeRequest) (*MonsterType, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMonsterType not implemented")
+}
+func (UnimplementedMonsterServiceServer) UpdateMonsterType(context.Context, *UpdateMonsterTypeRequest) (*MonsterType, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMonsterType not implemented")
+}
+func (UnimplementedMonsterServiceServer) DeleteMonsterType(context.Context, *IDRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMonsterType not implemented")
+}
+func (UnimplementedMonsterServiceServer) ListMonsterTypes(context.Context, *PaginationRequest) (*MonsterTypeList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMonsterTypes not implemented")
+}
+func (UnimplementedMonsterServiceServer) GetMonsterType(context.Context, *IDRequest) (*MonsterType, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMonsterType not implemented")
+}
+func (UnimplementedMonsterServiceServer) CreateMonster(context.Context, *CreateMonsterRequest) (*Monster, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMonster not implemented")
+}
+func (UnimplementedMonsterServiceServer) UpdateMonster(context.Context, *UpdateMonsterRequest) (*Monster, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMonster not implemented")
+}
+func (UnimplementedMonsterServiceServer) DeleteMonster(context.Context, *IDRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMonster not implemented")
+}
+func (UnimplementedMonsterServiceServer) ListMonsters(context.Context, *PaginationRequest) (*MonsterList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMonsters not implemented")
+}
+func (UnimplementedMonsterServiceServer) GetMonster(context.Context, *IDRequest) (*Monster, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMonster not implemented")
+}
+func (UnimplementedMonsterServiceServer) AttachMonsterType(context.Context, *AttachMonsterTypeRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AttachMonsterType not implemented")
+}
+func (UnimplementedMonsterServiceServer) mustEmbedUnimplementedMonsterServiceServer() {}
+
+// UnsafeMonsterServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MonsterServiceServer will
+// result in compilation errors.
+type UnsafeMonsterServiceServer interface {
+	mustEmbedUnimplementedMonsterServiceServer()
+}
+
+func RegisterMonsterServiceServer(s grpc.ServiceRegistrar, srv MonsterServiceServer) {
+	s.RegisterService(&MonsterService_ServiceDesc, srv)
+}
+
+func _MonsterService_CreateMonsterType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMonsterTypeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonsterServiceServer).CreateMonsterType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonsterService_CreateMonsterType_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonsterServiceServer).CreateMonsterType(ctx, req.(*CreateMonsterTypeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonsterService_UpdateMonsterType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMonsterTypeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonsterServiceServer).UpdateMonsterType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonsterService_UpdateMonsterType_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonsterServiceServer).UpdateMonsterType(ctx, req.(*UpdateMonsterTypeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonsterService_DeleteMonsterType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonsterServiceServer).DeleteMonsterType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonsterService_DeleteMonsterType_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonsterServiceServer).DeleteMonsterType(ctx, req.(*IDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonsterService_ListMonsterTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaginationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonsterServiceServer).ListMonsterTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonsterService_ListMonsterTypes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonsterServiceServer).ListMonsterTypes(ctx, req.(*PaginationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonsterService_GetMonsterType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonsterServiceServer).GetMonsterType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonsterService_GetMonsterType_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonsterServiceServer).GetMonsterType(ctx, req.(*IDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonsterService_CreateMonster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMonsterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonsterServiceServer).CreateMonster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonsterService_CreateMonster_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonsterServiceServer).CreateMonster(ctx, req.(*CreateMonsterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonsterService_UpdateMonster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMonsterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonsterServiceServer).UpdateMonster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonsterService_UpdateMonster_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonsterServiceServer).UpdateMonster(ctx, req.(*UpdateMonsterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonsterService_DeleteMonster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonsterServiceServer).DeleteMonster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonsterService_DeleteMonster_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonsterServiceServer).DeleteMonster(ctx, req.(*IDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonsterService_ListMonsters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaginationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonsterServiceServer).ListMonsters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonsterService_ListMonsters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonsterServiceServer).ListMonsters(ctx, req.(*PaginationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonsterService_GetMonster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonsterServiceServer).GetMonster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonsterService_GetMonster_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonsterServiceServer).GetMonster(ctx, req.(*IDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MonsterService_AttachMonsterType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttachMonsterTypeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MonsterServiceServer).AttachMonsterType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MonsterService_AttachMonsterType_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MonsterServiceServer).AttachMonsterType(ctx, req.(*AttachMonsterTypeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MonsterService_ServiceDesc is the grpc.ServiceDesc for MonsterService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MonsterService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pokedex.MonsterService",
+	HandlerType: (*MonsterServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateMonsterType",
+			Handler:    _MonsterService_CreateMonsterType_Handler,
+		},
+		{
+			MethodName: "UpdateMonsterType",
+			Handler:    _MonsterService_UpdateMonsterType_Handler,
+		},
+		{
+			MethodName: "DeleteMonsterType",
+			Handler:    _MonsterService_DeleteMonsterType_Handler,
+		},
+		{
+			MethodName: "ListMonsterTypes",
+			Handler:    _MonsterService_ListMonsterTypes_Handler,
+		},
+		{
+			MethodName: "GetMonsterType",
+			Handler:    _MonsterService_GetMonsterType_Handler,
+		},
+		{
+			MethodName: "CreateMonster",
+			Handler:    _MonsterService_CreateMonster_Handler,
+		},
+		{
+			MethodName: "UpdateMonster",
+			Handler:    _MonsterService_UpdateMonster_Handler,
+		},
+		{
+			MethodName: "DeleteMonster",
+			Handler:    _MonsterService_DeleteMonster_Handler,
+		},
+		{
+			MethodName: "ListMonsters",
+			Handler:    _MonsterService_ListMonsters_Handler,
+		},
+		{
+			MethodName: "GetMonster",
+			Handler:    _MonsterService_GetMonster_Handler,
+		},
+		{
+			MethodName: "AttachMonsterType",
+			Handler:    _MonsterService_AttachMonsterType_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pokedex/monster.proto",
+}